@@ -0,0 +1,68 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateComponentNamespaces(t *testing.T) {
+	tests := []struct {
+		name        string
+		component   ComponentConfig
+		errContains string
+	}{
+		{
+			name:      "Custom namespace - valid",
+			component: ComponentConfig{Name: Search, Enabled: true, Namespace: "search"},
+		},
+		{
+			name:      "Hub namespace - valid",
+			component: ComponentConfig{Name: ClusterBackup, Enabled: true, Namespace: "open-cluster-management"},
+		},
+		{
+			name:        "Component installed into its own namespace",
+			component:   ComponentConfig{Name: MultiClusterObservability, Enabled: true, Namespace: "observability"},
+			errContains: "spec.overrides.components[0].namespace: component \"multicluster-observability\" cannot",
+		},
+		{
+			name:        "Invalid namespace name",
+			component:   ComponentConfig{Name: Insights, Enabled: true, Namespace: "Insights_NS"},
+			errContains: "is not a valid namespace name",
+		},
+		{
+			name:        "Reserved namespace",
+			component:   ComponentConfig{Name: GRC, Enabled: true, Namespace: "open-cluster-management-backup"},
+			errContains: "namespace \"open-cluster-management-backup\" is reserved",
+		},
+		{
+			name:        "Platform namespace",
+			component:   ComponentConfig{Name: Console, Enabled: true, Namespace: "openshift-console"},
+			errContains: "namespaces starting with \"openshift\" cannot be used",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mch := &MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+				Spec: MultiClusterHubSpec{
+					Overrides: &Overrides{Components: []ComponentConfig{tt.component}},
+				},
+			}
+			err := validateComponentNamespaces(mch)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("validateComponentNamespaces() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("validateComponentNamespaces() error = %v, want error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
@@ -43,20 +43,21 @@ var _ = Describe("V1 API Methods", func() {
 		})
 
 		It("correctly indicates if a component is enabled", func() {
-			Expect(mch.Enabled(api.Search)).To(BeFalse())
+			Expect(mch.Enabled(api.Search)).To(BeTrue())
+			Expect(mch.Enabled(api.ClusterBackup)).To(BeFalse())
 		})
 
 		It("enables a component", func() {
-			Expect(mch.ComponentPresent(api.Search)).To(BeFalse())
-			Expect(mch.Enabled(api.Search)).To(BeFalse())
-			mch.Enable(api.Search)
-			Expect(mch.ComponentPresent(api.Search)).To(BeTrue())
-			Expect(mch.Enabled(api.Search)).To(BeTrue())
+			Expect(mch.ComponentPresent(api.ClusterBackup)).To(BeFalse())
+			Expect(mch.Enabled(api.ClusterBackup)).To(BeFalse())
+			mch.Enable(api.ClusterBackup)
+			Expect(mch.ComponentPresent(api.ClusterBackup)).To(BeTrue())
+			Expect(mch.Enabled(api.ClusterBackup)).To(BeTrue())
 		})
 
 		It("disables a component", func() {
 			Expect(mch.ComponentPresent(api.Search)).To(BeFalse())
-			Expect(mch.Enabled(api.Search)).To(BeFalse())
+			Expect(mch.Enabled(api.Search)).To(BeTrue())
 			mch.Disable(api.Search)
 			Expect(mch.ComponentPresent(api.Search)).To(BeTrue())
 			Expect(mch.Enabled(api.Search)).To(BeFalse())
@@ -76,20 +77,21 @@ var _ = Describe("V1 API Methods", func() {
 		})
 
 		It("correctly indicates if a component is enabled", func() {
-			Expect(mch.Enabled(api.Search)).To(BeFalse())
+			Expect(mch.Enabled(api.Search)).To(BeTrue())
+			Expect(mch.Enabled(api.ClusterBackup)).To(BeFalse())
 		})
 
 		It("enables a component", func() {
-			Expect(mch.ComponentPresent(api.Search)).To(BeFalse())
-			Expect(mch.Enabled(api.Search)).To(BeFalse())
-			mch.Enable(api.Search)
-			Expect(mch.ComponentPresent(api.Search)).To(BeTrue())
-			Expect(mch.Enabled(api.Search)).To(BeTrue())
+			Expect(mch.ComponentPresent(api.ClusterBackup)).To(BeFalse())
+			Expect(mch.Enabled(api.ClusterBackup)).To(BeFalse())
+			mch.Enable(api.ClusterBackup)
+			Expect(mch.ComponentPresent(api.ClusterBackup)).To(BeTrue())
+			Expect(mch.Enabled(api.ClusterBackup)).To(BeTrue())
 		})
 
 		It("disables a component", func() {
 			Expect(mch.ComponentPresent(api.Search)).To(BeFalse())
-			Expect(mch.Enabled(api.Search)).To(BeFalse())
+			Expect(mch.Enabled(api.Search)).To(BeTrue())
 			mch.Disable(api.Search)
 			Expect(mch.ComponentPresent(api.Search)).To(BeTrue())
 			Expect(mch.Enabled(api.Search)).To(BeFalse())
@@ -129,6 +131,37 @@ var _ = Describe("V1 API Methods", func() {
 		})
 	})
 
+	Context("when the component is not configured and the profile is not resolved", func() {
+		It("follows the default enabled components", func() {
+			mch := makeMCH(config(api.GRC, false))
+			Expect(mch.Enabled(api.Search)).To(BeTrue())
+			Expect(mch.Enabled(api.ClusterBackup)).To(BeFalse())
+			Expect(mch.Enabled(api.GRC)).To(BeFalse())
+		})
+	})
+
+	Context("when the component is not configured", func() {
+		var mch *api.MultiClusterHub
+
+		BeforeEach(func() {
+			mch = makeMCH(config(api.GRC, false))
+			mch.Status.Profile = &api.ProfileStatus{
+				Name:              api.ProfileGovernance,
+				EnabledComponents: []string{api.GRC, api.Search},
+			}
+		})
+
+		It("follows the resolved profile", func() {
+			Expect(mch.Enabled(api.Search)).To(BeTrue())
+			Expect(mch.Enabled(api.Insights)).To(BeFalse())
+			Expect(mch.ComponentPresent(api.Search)).To(BeFalse())
+		})
+
+		It("prefers the configured setting", func() {
+			Expect(mch.Enabled(api.GRC)).To(BeFalse())
+		})
+	})
+
 	It("correctly validates a component name", func() {
 		Expect(api.ValidComponent(config(api.Search, true), api.MCHComponents)).To(BeTrue())
 		Expect(api.ValidComponent(config("invalid", true), api.MCHComponents)).To(BeFalse())
@@ -0,0 +1,7 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+// Hub marks v1 as the conversion hub. It is also the storage version, so other API versions convert to and
+// from v1 through the operator's conversion webhook.
+func (*MultiClusterHub) Hub() {}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"fmt"
+	"strings"
 )
 
 type ResourceGVK struct {
@@ -81,6 +82,24 @@ var MCHComponents = []string{
 	Volsync,
 }
 
+/*
+CustomNamespaceComponents are the components that can be installed into a namespace other than the namespace of the
+MultiClusterHub. The other components are installed into the hub namespace or into a namespace of their own.
+*/
+var CustomNamespaceComponents = []string{
+	Appsub,
+	ClusterLifecycle,
+	Console,
+	FineGrainedRbac,
+	MTVIntegrations,
+	GRC,
+	Insights,
+	Search,
+	SiteConfig,
+	SubmarinerAddon,
+	Volsync,
+}
+
 // MCEComponents is a slice containing component names specific to the "MCE" category.
 var MCEComponents = []string{
 	MCEAssistedService,
@@ -188,6 +207,32 @@ func GetDefaultDisabledComponents() ([]string, error) {
 	return defaultDisabledComponents, nil
 }
 
+/*
+GetProfileEnabledComponents returns the components a hub profile enables. The profile disables the other default
+enabled and default disabled components. The full profile enables the default enabled components.
+*/
+func GetProfileEnabledComponents(profile HubProfileName) ([]string, error) {
+	switch profile {
+	case ProfileFull:
+		return GetDefaultEnabledComponents()
+	case ProfileEdgeRAN:
+		return []string{ClusterLifecycle, Console, GRC, MultiClusterEngine, SiteConfig}, nil
+	case ProfileGovernance:
+		return []string{ClusterLifecycle, Console, GRC, MultiClusterEngine}, nil
+	case ProfileObservability:
+		return []string{ClusterLifecycle, Console, Insights, MultiClusterEngine, MultiClusterObservability, Search}, nil
+	}
+	return nil, fmt.Errorf("unknown hub profile: %s", profile)
+}
+
+// GetProfileAvailabilityConfig returns the availability a hub profile sets, edge hubs run on a single node
+func GetProfileAvailabilityConfig(profile HubProfileName) AvailabilityType {
+	if profile == ProfileEdgeRAN {
+		return HABasic
+	}
+	return HAHigh
+}
+
 // GetClusterManagementAddonName returns the name of the ClusterManagementAddOn based on the provided component name.
 func GetClusterManagementAddonName(component string) (string, error) {
 	if val, ok := ClusterManagementAddOns[component]; !ok {
@@ -213,18 +258,55 @@ func (mch *MultiClusterHub) ComponentPresent(s string) bool {
 	return false
 }
 
-// Enabled checks if a specific component is enabled based on the provided component name in the MultiClusterHub struct.
+/*
+Enabled checks if a specific component is enabled based on the provided component name in the MultiClusterHub struct.
+Components that are not configured in the overrides are enabled when the resolved profile in the status enables them,
+or, before a profile is resolved, when they are enabled by default.
+*/
 func (mch *MultiClusterHub) Enabled(s string) bool {
-	if mch.Spec.Overrides == nil {
-		return false
+	if mch.Spec.Overrides != nil {
+		for _, c := range mch.Spec.Overrides.Components {
+			if c.Name == s {
+				return c.Enabled
+			}
+		}
+	}
+
+	if mch.Status.Profile == nil {
+		defaultEnabledComponents, _ := GetDefaultEnabledComponents()
+		return contains(defaultEnabledComponents, s)
+	}
+	return contains(mch.Status.Profile.EnabledComponents, s)
+}
+
+/*
+EffectiveAvailabilityConfig returns the availability the hub runs with: the availability of the spec when it is set,
+and otherwise the availability of the resolved profile in the status, or High before a profile is resolved.
+*/
+func (mch *MultiClusterHub) EffectiveAvailabilityConfig() AvailabilityType {
+	if mch.Spec.AvailabilityConfig != "" {
+		return mch.Spec.AvailabilityConfig
+	}
+	if mch.Status.Profile != nil && mch.Status.Profile.AvailabilityConfig != "" {
+		return mch.Status.Profile.AvailabilityConfig
+	}
+	return HAHigh
+}
+
+/*
+ComponentNamespace returns the namespace a component is installed into: the namespace of its component config when the
+component supports custom namespaces, and the namespace of the MultiClusterHub otherwise.
+*/
+func (mch *MultiClusterHub) ComponentNamespace(s string) string {
+	if mch.Spec.Overrides == nil || !contains(CustomNamespaceComponents, s) {
+		return mch.Namespace
 	}
 	for _, c := range mch.Spec.Overrides.Components {
-		if c.Name == s {
-			return c.Enabled
+		if c.Name == s && c.Namespace != "" {
+			return c.Namespace
 		}
 	}
-
-	return false
+	return mch.Namespace
 }
 
 // Enable enables a specific component based on the provided component name in the MultiClusterHub struct.
@@ -310,3 +392,110 @@ func AvailabilityConfigIsValid(config AvailabilityType) bool {
 		return false
 	}
 }
+
+// EffectiveProbeSettings returns the probe tuning for a deployment of a component after merging the global,
+// component and deployment scopes of spec.probes, most specific last.
+func (mch *MultiClusterHub) EffectiveProbeSettings(component, deployment string) ProbeSettings {
+	effective := ProbeSettings{}
+	probes := mch.Spec.Probes
+	if probes == nil {
+		return effective
+	}
+
+	if probes.Global != nil {
+		effective = effective.merge(*probes.Global)
+	}
+	for _, c := range probes.Components {
+		if c.Name != component {
+			continue
+		}
+		effective = effective.merge(c.ProbeSettings)
+		for _, d := range c.Deployments {
+			if d.Name == deployment {
+				effective = effective.merge(d.ProbeSettings)
+			}
+		}
+	}
+	return effective
+}
+
+func (s ProbeSettings) merge(override ProbeSettings) ProbeSettings {
+	return ProbeSettings{
+		Liveness:  mergeProbeTuning(s.Liveness, override.Liveness),
+		Readiness: mergeProbeTuning(s.Readiness, override.Readiness),
+	}
+}
+
+func mergeProbeTuning(base, override *ProbeTuning) *ProbeTuning {
+	if override == nil {
+		return base.DeepCopy()
+	}
+	if base == nil {
+		return override.DeepCopy()
+	}
+
+	merged := base.DeepCopy()
+	o := override.DeepCopy()
+	if o.InitialDelaySeconds != nil {
+		merged.InitialDelaySeconds = o.InitialDelaySeconds
+	}
+	if o.PeriodSeconds != nil {
+		merged.PeriodSeconds = o.PeriodSeconds
+	}
+	if o.TimeoutSeconds != nil {
+		merged.TimeoutSeconds = o.TimeoutSeconds
+	}
+	if o.FailureThreshold != nil {
+		merged.FailureThreshold = o.FailureThreshold
+	}
+	if o.SuccessThreshold != nil {
+		merged.SuccessThreshold = o.SuccessThreshold
+	}
+	return merged
+}
+
+/*
+ParseFieldPath splits a field path such as spec.replicas or data["config.yaml"] into its fields. A leading $ or dot
+is ignored. Keys containing dots are written in brackets, with or without quotes.
+*/
+func ParseFieldPath(path string) ([]string, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	var fields []string
+	for rest != "" {
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid field path %q: missing ]", path)
+			}
+			key := strings.Trim(rest[1:end], `"'`)
+			if key == "" {
+				return nil, fmt.Errorf("invalid field path %q: empty key", path)
+			}
+			fields = append(fields, key)
+			rest = rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid field path %q: empty field", path)
+			}
+			fields = append(fields, rest[:end])
+			rest = rest[end:]
+		}
+
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, fmt.Errorf("invalid field path %q: empty field", path)
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid field path %q: no fields", path)
+	}
+	return fields, nil
+}
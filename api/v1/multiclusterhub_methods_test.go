@@ -3,6 +3,8 @@ package v1
 import (
 	"reflect"
 	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMultiClusterHub_Prune(t *testing.T) {
@@ -149,3 +151,67 @@ func TestGetClusterManagementAddonName(t *testing.T) {
 		})
 	}
 }
+
+func TestComponentNamespace(t *testing.T) {
+	mch := &MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+		Spec: MultiClusterHubSpec{
+			Overrides: &Overrides{
+				Components: []ComponentConfig{
+					{Name: Search, Enabled: true, Namespace: "search"},
+					{Name: GRC, Enabled: true},
+					{Name: ClusterBackup, Enabled: true, Namespace: "backup"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		component string
+		want      string
+	}{
+		{component: Search, want: "search"},
+		{component: GRC, want: "open-cluster-management"},
+		{component: Insights, want: "open-cluster-management"},
+		// The namespace of components installed into their own namespace cannot be changed
+		{component: ClusterBackup, want: "open-cluster-management"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.component, func(t *testing.T) {
+			if got := mch.ComponentNamespace(tt.component); got != tt.want {
+				t.Errorf("ComponentNamespace(%s) = %s, want %s", tt.component, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProfileEnabledComponents(t *testing.T) {
+	enabled, _ := GetDefaultEnabledComponents()
+	disabled, _ := GetDefaultDisabledComponents()
+	defaulted := append(enabled, disabled...)
+
+	for _, profile := range []HubProfileName{ProfileFull, ProfileEdgeRAN, ProfileGovernance, ProfileObservability} {
+		t.Run(string(profile), func(t *testing.T) {
+			got, err := GetProfileEnabledComponents(profile)
+			if err != nil {
+				t.Fatalf("GetProfileEnabledComponents(%s) error = %v", profile, err)
+			}
+			// Profiles only decide the components that are defaulted
+			for _, c := range got {
+				if !contains(defaulted, c) {
+					t.Errorf("GetProfileEnabledComponents(%s) enables %s, which is not a defaulted component", profile, c)
+				}
+			}
+			if !contains(got, MultiClusterEngine) {
+				t.Errorf("GetProfileEnabledComponents(%s) = %v, want %s enabled", profile, got, MultiClusterEngine)
+			}
+		})
+	}
+
+	if _, err := GetProfileEnabledComponents("unknown"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+	if got := GetProfileAvailabilityConfig(ProfileEdgeRAN); got != HABasic {
+		t.Errorf("GetProfileAvailabilityConfig(%s) = %s, want %s", ProfileEdgeRAN, got, HABasic)
+	}
+}
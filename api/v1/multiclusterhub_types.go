@@ -77,6 +77,188 @@ type MultiClusterHubSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NetworkPolicies Configuration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	// +optional
 	NetworkPolicies *NetworkPoliciesConfig `json:"networkPolicies,omitempty"`
+
+	// Probes tunes the liveness and readiness probes of component deployments
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Probe Configuration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	Probes *ProbesConfig `json:"probes,omitempty"`
+
+	// TrustedCA adds CA certificates to the trust bundle propagated to the hub components
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trusted CA Configuration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	TrustedCA *TrustedCAConfig `json:"trustedCA,omitempty"`
+
+	// Adoption configures how existing resources that are not labeled by the installer are adopted
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Adoption",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	Adoption *AdoptionConfig `json:"adoption,omitempty"`
+
+	// LocalCluster configures the add-ons and the ManagedCluster of the self-managed local-cluster
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Local Cluster Configuration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	LocalCluster *LocalClusterConfig `json:"localCluster,omitempty"`
+
+	// Profile expands into the components and settings of a common hub shape. Components configured in
+	// spec.overrides.components take precedence over the profile.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Hub Profile",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +optional
+	Profile *HubProfile `json:"profile,omitempty"`
+
+	// NewComponentPolicy decides whether components an upgrade enables by default are installed: Enable (default)
+	// installs them, Disable leaves them disabled and Acknowledge leaves them disabled and lists them in
+	// status.profile.pendingComponents until they are configured in spec.overrides.components.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="New Component Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:select:Enable","urn:alm:descriptor:com.tectonic.ui:select:Disable","urn:alm:descriptor:com.tectonic.ui:select:Acknowledge"}
+	// +optional
+	NewComponentPolicy NewComponentPolicy `json:"newComponentPolicy,omitempty"`
+}
+
+// NewComponentPolicy decides whether components an upgrade enables by default are installed
+// +kubebuilder:validation:Enum=Enable;Disable;Acknowledge
+type NewComponentPolicy string
+
+const (
+	// NewComponentEnable installs the components an upgrade enables by default
+	NewComponentEnable NewComponentPolicy = "Enable"
+	// NewComponentDisable leaves the components an upgrade enables by default disabled
+	NewComponentDisable NewComponentPolicy = "Disable"
+	// NewComponentAcknowledge leaves the components an upgrade enables by default disabled until they are configured
+	NewComponentAcknowledge NewComponentPolicy = "Acknowledge"
+)
+
+// HubProfileName names a hub profile
+// +kubebuilder:validation:Enum=full;edge-ran;governance;observability
+type HubProfileName string
+
+const (
+	// ProfileFull enables the default components
+	ProfileFull HubProfileName = "full"
+	// ProfileEdgeRAN enables the components of a single node hub provisioning RAN clusters with siteconfig
+	ProfileEdgeRAN HubProfileName = "edge-ran"
+	// ProfileGovernance enables cluster lifecycle and policy management
+	ProfileGovernance HubProfileName = "governance"
+	// ProfileObservability enables cluster lifecycle, search, insights and observability
+	ProfileObservability HubProfileName = "observability"
+)
+
+// HubProfile selects the profile the hub components and settings are expanded from
+type HubProfile struct {
+	// Name of the profile: full, edge-ran, governance or observability
+	Name HubProfileName `json:"name"`
+
+	// Pinned keeps the profile to the components it expanded to. Components a new release adds to the profile stay
+	// disabled and are listed in status.profile.pendingComponents until they are configured in
+	// spec.overrides.components or the profile is unpinned, as with the Acknowledge new component policy.
+	// +optional
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// TrustedCAConfig references additional CA certificates trusted by the hub components
+type TrustedCAConfig struct {
+	// SecretName names a Secret in the MultiClusterHub namespace holding PEM encoded CA certificates under the
+	// ca-bundle.crt key. The certificates are appended to the cluster trust bundle.
+	SecretName string `json:"secretName"`
+}
+
+// ResourceAdoptionPolicy controls whether existing resources without installer labels are adopted
+// +kubebuilder:validation:Enum=Strict;Adopt
+type ResourceAdoptionPolicy string
+
+const (
+	// AdoptionStrict only manages resources that carry the installer labels
+	AdoptionStrict ResourceAdoptionPolicy = "Strict"
+	// AdoptionAdopt adopts existing unlabeled resources
+	AdoptionAdopt ResourceAdoptionPolicy = "Adopt"
+)
+
+// AdoptionConfig configures the adoption of existing resources rendered by the hub
+type AdoptionConfig struct {
+	// Rules set the adoption policy by kind and namespace. The first matching rule applies; resources no rule
+	// matches follow the resource adoption policy annotation.
+	// +optional
+	Rules []AdoptionRule `json:"rules,omitempty"`
+
+	// AdoptNow adopts the unowned and partially labeled resources once, except those a Strict rule matches. Set a
+	// new value to adopt again; the last value acted on is recorded in status.adoption.lastAdoptNow.
+	// +optional
+	AdoptNow string `json:"adoptNow,omitempty"`
+}
+
+// AdoptionRule sets the adoption policy for the resources of a kind and namespace
+type AdoptionRule struct {
+	// Kind of the resources the rule applies to. Applies to all kinds when empty.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Namespace of the resources the rule applies to. Applies to all namespaces when empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Policy is the adoption policy of the matching resources
+	Policy ResourceAdoptionPolicy `json:"policy"`
+}
+
+// LocalClusterConfig configures the add-ons and the ManagedCluster of the self-managed local-cluster. Add-ons that
+// are not configured keep their defaults: the application manager is enabled, the policy controllers follow the grc
+// component and the search collector is disabled.
+type LocalClusterConfig struct {
+	// ApplicationManager configures the application manager add-on
+	// +optional
+	ApplicationManager *LocalClusterAddonConfig `json:"applicationManager,omitempty"`
+
+	// CertPolicyController configures the certificate policy controller add-on
+	// +optional
+	CertPolicyController *LocalClusterAddonConfig `json:"certPolicyController,omitempty"`
+
+	// PolicyController configures the configuration policy controller and governance policy framework add-ons
+	// +optional
+	PolicyController *LocalClusterAddonConfig `json:"policyController,omitempty"`
+
+	// SearchCollector configures the search collector add-on
+	// +optional
+	SearchCollector *LocalClusterAddonConfig `json:"searchCollector,omitempty"`
+
+	// ProxyConfig is the proxy used by the add-ons with the CustomProxy proxy policy
+	// +optional
+	ProxyConfig *LocalClusterProxyConfig `json:"proxyConfig,omitempty"`
+
+	// Labels are added to the local-cluster ManagedCluster
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ClusterSet is the ManagedClusterSet the local-cluster joins
+	// +optional
+	ClusterSet string `json:"clusterSet,omitempty"`
+}
+
+// LocalClusterAddonConfig configures an add-on of the local-cluster
+type LocalClusterAddonConfig struct {
+	// Enabled runs the add-on on the local-cluster. Keeps the default of the add-on when unset.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// InstallNamespace is the namespace the add-on agent is installed in
+	// +optional
+	InstallNamespace string `json:"installNamespace,omitempty"`
+
+	// ProxyPolicy selects the proxy used by the add-on agent
+	// +kubebuilder:validation:Enum=Disabled;OCPGlobalProxy;CustomProxy
+	// +optional
+	ProxyPolicy string `json:"proxyPolicy,omitempty"`
+}
+
+// LocalClusterProxyConfig is the proxy configuration of the local-cluster add-ons
+type LocalClusterProxyConfig struct {
+	// HTTPProxy is the URL of the proxy for HTTP requests
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for HTTPS requests
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma-separated list of hosts that are not proxied
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
 }
 
 // Overrides provides developer overrides for MCH installation
@@ -90,6 +272,32 @@ type Overrides struct {
 	// +listType=map
 	// +listMapKey=name
 	Components []ComponentConfig `json:"components,omitempty"`
+
+	// PreservedFields lists fields of rendered resources that keep their value from the cluster while the rest of
+	// the resource is reconciled
+	// +optional
+	PreservedFields []PreservedFields `json:"preservedFields,omitempty"`
+}
+
+// PreservedFields lists fields of a rendered resource that keep their value from the cluster
+type PreservedFields struct {
+	// APIVersion of the resource. Matches every API version when empty.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// Namespace of the resource. Matches every namespace when empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource
+	Name string `json:"name"`
+
+	// Paths of the preserved fields, such as spec.replicas or data["config.yaml"]. Fields inside lists cannot be
+	// selected; preserve the whole list instead.
+	Paths []string `json:"paths"`
 }
 
 // ComponentConfig provides optional configuration items for individual components
@@ -100,6 +308,16 @@ type ComponentConfig struct {
 	// Name denotes the name of the component being configured.
 	Name string `json:"name"`
 
+	// Namespace is the namespace the component is installed into, it defaults to the namespace of the
+	// MultiClusterHub. The namespace is created if it does not exist, and it is not deleted when the component is
+	// moved or disabled. Changing it moves an installed component: the component is removed from its current namespace
+	// before it is installed into the new one. The cluster-backup and multicluster-observability components are always
+	// installed into their own namespaces.
+	// +optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Namespace string `json:"namespace,omitempty"`
+
 	// ConfigOverrides contains optional configuration overrides for deployments and containers.
 	ConfigOverrides ConfigOverride `json:"configOverrides,omitempty"`
 }
@@ -145,6 +363,83 @@ type NetworkPoliciesConfig struct {
 	Enabled bool `json:"enabled"`
 }
 
+// ProbesConfig tunes the probes of component deployments. Settings are merged field by field, with deployment
+// settings taking precedence over component settings, which take precedence over global settings. Only probes
+// already defined by a deployment are tuned; probes are never added.
+type ProbesConfig struct {
+	// Global applies to every component deployment
+	// +optional
+	Global *ProbeSettings `json:"global,omitempty"`
+
+	// Components overrides the global settings for individual components and their deployments
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Components []ComponentProbeConfig `json:"components,omitempty"`
+}
+
+// ComponentProbeConfig tunes the probes of a single component
+type ComponentProbeConfig struct {
+	// Name of the component, as listed in spec.overrides.components
+	Name string `json:"name"`
+
+	ProbeSettings `json:",inline"`
+
+	// Deployments overrides the component settings for individual deployments of the component
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Deployments []DeploymentProbeConfig `json:"deployments,omitempty"`
+}
+
+// DeploymentProbeConfig tunes the probes of a single deployment
+type DeploymentProbeConfig struct {
+	// Name of the deployment
+	Name string `json:"name"`
+
+	ProbeSettings `json:",inline"`
+}
+
+// ProbeSettings holds separate tuning for liveness and readiness probes
+type ProbeSettings struct {
+	// Liveness tunes the liveness probe of every container that defines one
+	// +optional
+	Liveness *ProbeTuning `json:"liveness,omitempty"`
+
+	// Readiness tunes the readiness probe of every container that defines one
+	// +optional
+	Readiness *ProbeTuning `json:"readiness,omitempty"`
+}
+
+// ProbeTuning holds the probe timing fields that can be overridden. Unset fields keep the value from the chart.
+type ProbeTuning struct {
+	// InitialDelaySeconds is the number of seconds after the container starts before the probe runs
+	// +optional
+	//+kubebuilder:validation:Minimum=0
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// PeriodSeconds is how often the probe runs
+	// +optional
+	//+kubebuilder:validation:Minimum=1
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// TimeoutSeconds is the number of seconds after which the probe times out
+	// +optional
+	//+kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// FailureThreshold is the number of consecutive failures before the probe is considered failed
+	// +optional
+	//+kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// SuccessThreshold is the number of consecutive successes before the probe is considered successful after
+	// having failed. Must be 1 for liveness probes.
+	// +optional
+	//+kubebuilder:validation:Minimum=1
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+}
+
 type HubPhaseType string
 
 const (
@@ -194,6 +489,662 @@ type MultiClusterHubStatus struct {
 
 	// MCEVersionCompliance tracks whether the MCE version meets the required channel version
 	MCEVersionCompliance *MCEVersionComplianceStatus `json:"mceVersionCompliance,omitempty"`
+
+	// MCEOLMMigration tracks the progress of moving the managed MCE between OLM v0 and OLM v1
+	MCEOLMMigration *MCEOLMMigrationStatus `json:"mceOLMMigration,omitempty"`
+
+	// Capabilities lists the optional cluster APIs the operator has detected and is currently using
+	Capabilities *ClusterCapabilitiesStatus `json:"capabilities,omitempty"`
+
+	// ComponentReconcile records the result of the last render and apply of each enabled component
+	// +optional
+	ComponentReconcile map[string]ComponentReconcileStatus `json:"componentReconcile,omitempty"`
+
+	// TrustBundle reports the trust bundle and proxy configuration propagated to the hub components
+	// +optional
+	TrustBundle *TrustBundleStatus `json:"trustBundle,omitempty"`
+
+	// Adoption reports the rendered resources that exist without being owned by this hub
+	// +optional
+	Adoption *AdoptionStatus `json:"adoption,omitempty"`
+
+	// RejectedTemplateOverrides lists the template overrides that are not applied because no component chart
+	// supports the key or the value does not match the chart schema
+	// +optional
+	RejectedTemplateOverrides []RejectedTemplateOverride `json:"rejectedTemplateOverrides,omitempty"`
+
+	// OverrideRevisions reports the content of the image and template override ConfigMaps in use
+	// +optional
+	OverrideRevisions *OverrideRevisionsStatus `json:"overrideRevisions,omitempty"`
+
+	// ComponentRollout tracks the progressive rollout of the component deployments to a new release on highly
+	// available hubs
+	// +optional
+	ComponentRollout *ComponentRolloutStatus `json:"componentRollout,omitempty"`
+
+	// Health summarizes the availability of the hub components and of the hub over time
+	// +optional
+	Health *HubHealthStatus `json:"health,omitempty"`
+
+	// TLSProfile is the TLS security profile the operator serves its webhook and metrics endpoints with
+	// +optional
+	TLSProfile *TLSProfileStatus `json:"tlsProfile,omitempty"`
+
+	// Profile reports the components and settings the hub profile expanded to. Components that are not configured in
+	// spec.overrides.components are enabled when they are listed in status.profile.enabledComponents.
+	// +optional
+	Profile *ProfileStatus `json:"profile,omitempty"`
+
+	// RBACAudit reports the permissions the rendered component charts need that the operator is not granted, and the
+	// wildcard rules of the ClusterRoles the charts install
+	// +optional
+	RBACAudit *RBACAuditStatus `json:"rbacAudit,omitempty"`
+
+	// Restricted reports the resources an admin applies for the hub when the operator runs in restricted mode
+	// +optional
+	Restricted *RestrictedModeStatus `json:"restricted,omitempty"`
+
+	// CRDLifecycle reports the storage migrations of the hub CRDs and the CRDs of disabled components that are kept
+	// +optional
+	CRDLifecycle *CRDLifecycleStatus `json:"crdLifecycle,omitempty"`
+
+	// ServiceBackends reports whether the services the API server calls for the conversion webhooks of the hub CRDs
+	// and for the APIServices of the components can be reached
+	// +optional
+	ServiceBackends []ServiceBackendStatus `json:"serviceBackends,omitempty"`
+}
+
+// ProfileStatus reports the expansion of the hub profile, the full profile when the hub sets none
+type ProfileStatus struct {
+	// Name of the expanded profile
+	Name HubProfileName `json:"name"`
+
+	// EnabledComponents lists the components the profile enables
+	// +optional
+	EnabledComponents []string `json:"enabledComponents,omitempty"`
+
+	// DisabledComponents lists the components the profile disables
+	// +optional
+	DisabledComponents []string `json:"disabledComponents,omitempty"`
+
+	// OverriddenComponents lists the components whose setting in spec.overrides.components differs from the profile
+	// +optional
+	OverriddenComponents []string `json:"overriddenComponents,omitempty"`
+
+	// PendingComponents lists the components an upgrade enables by default that wait for a decision. They stay
+	// disabled until they are configured in spec.overrides.components.
+	// +optional
+	PendingComponents []string `json:"pendingComponents,omitempty"`
+
+	// AvailabilityConfig is the availability the profile sets
+	// +optional
+	AvailabilityConfig AvailabilityType `json:"availabilityConfig,omitempty"`
+}
+
+// ServiceBackendStatus reports whether the API server can reach the service of a CRD conversion webhook or an
+// APIService. When it cannot, requests for the resources served through the service fail.
+type ServiceBackendStatus struct {
+	// Kind of the resource the service is called for, CustomResourceDefinition or APIService
+	Kind string `json:"kind"`
+
+	// Name of the CRD or APIService
+	Name string `json:"name"`
+
+	// Components the resource is installed for, empty for the resources that are always installed
+	// +optional
+	Components []string `json:"components,omitempty"`
+
+	// Service is the service called by the API server, as <namespace>/<name>
+	Service string `json:"service"`
+
+	// Ready is true when the service exists, has ready endpoints and a CA bundle is injected
+	Ready bool `json:"ready"`
+
+	// Reason is why the service cannot be reached: ServiceNotFound, NoReadyEndpoints, MissingCABundle or
+	// Unavailable when the API server reports the APIService unavailable
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human-readable message indicating details about why the service cannot be reached.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time the service changed from ready to not ready or back
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// CRDLifecycleStatus reports the lifecycle of the CRDs the operator installs
+type CRDLifecycleStatus struct {
+	// Migrations lists the CRDs whose custom resources were migrated to the storage version before versions were
+	// removed from the CRD
+	// +optional
+	Migrations []CRDStorageMigration `json:"migrations,omitempty"`
+
+	// RetainedCRDs lists the CRDs of disabled components that are kept while custom resources remain
+	// +optional
+	RetainedCRDs []RetainedCRD `json:"retainedCRDs,omitempty"`
+}
+
+// CRDStorageMigration is the migration of the custom resources of a CRD to its storage version
+type CRDStorageMigration struct {
+	// Name of the CRD
+	Name string `json:"name"`
+
+	// StoredVersions are the versions the custom resources were stored in when the migration started
+	// +optional
+	StoredVersions []string `json:"storedVersions,omitempty"`
+
+	// StorageVersion is the version the custom resources are migrated to
+	StorageVersion string `json:"storageVersion"`
+
+	// Status is True when the migration has finished, False when it failed, and Unknown while it is running.
+	Status metav1.ConditionStatus `json:"status"`
+
+	// Message is a human-readable message indicating details about the migration.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time the status of the migration changed
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// RetainedCRD is a CRD of a disabled component that is not removed because custom resources remain
+type RetainedCRD struct {
+	// Name of the CRD
+	Name string `json:"name"`
+
+	// Component the CRD is installed for
+	Component string `json:"component"`
+}
+
+// RestrictedModeStatus reports the resources the operator does not apply in restricted mode: the cluster-scoped
+// resources and the resources of namespaces it does not track. They are published in ConfigMaps of the hub namespace
+// for an admin to apply.
+type RestrictedModeStatus struct {
+	// Bundles lists the resources published for the hub and for each enabled component
+	// +optional
+	Bundles []RestrictedModeBundle `json:"bundles,omitempty"`
+}
+
+// RestrictedModeBundle is a set of resources an admin applies for the hub or for a component
+type RestrictedModeBundle struct {
+	// Name of the component, or crds, namespaces and hub for the resources of the hub itself
+	Name string `json:"name"`
+
+	// ConfigMaps lists the ConfigMaps of the hub namespace that hold the resources
+	// +optional
+	ConfigMaps []string `json:"configMaps,omitempty"`
+
+	// Missing lists the resources of the bundle that are not present in the cluster, lack the labels of the bundle or
+	// cannot be read by the operator
+	// +optional
+	Missing []FailingObjectReference `json:"missing,omitempty"`
+}
+
+// RBACAuditStatus reports the last comparison of the permissions of the rendered component charts with the
+// permissions granted to the operator
+type RBACAuditStatus struct {
+	// LastAuditTime is when the granted permissions were last compared with the rendered charts
+	// +optional
+	LastAuditTime metav1.Time `json:"lastAuditTime,omitempty"`
+
+	// Components lists the components whose charts have findings
+	// +optional
+	Components []ComponentRBACAudit `json:"components,omitempty"`
+}
+
+// ComponentRBACAudit reports the RBAC findings of the chart of a component
+type ComponentRBACAudit struct {
+	// Name of the component
+	Name string `json:"name"`
+
+	// MissingPermissions lists the permissions the chart needs that the ClusterRoles bound to the operator do not
+	// grant, as "<verb> <resource>.<group>"
+	// +optional
+	MissingPermissions []string `json:"missingPermissions,omitempty"`
+
+	// WildcardRules lists the rules of the ClusterRoles of the chart that grant every api group, resource or verb
+	// +optional
+	WildcardRules []string `json:"wildcardRules,omitempty"`
+}
+
+// TLSProfileStatus is the TLS security profile in effect, read from the cluster APIServer resource
+type TLSProfileStatus struct {
+	// Type is the type of the profile: Old, Intermediate, Modern or Custom
+	Type string `json:"type"`
+
+	// MinTLSVersion is the minimum TLS version accepted
+	MinTLSVersion string `json:"minTLSVersion"`
+
+	// Ciphers are the cipher suites of the profile. Cipher suites of TLS 1.3 are not configurable.
+	// +optional
+	Ciphers []string `json:"ciphers,omitempty"`
+
+	// LastTransitionTime is when the profile in effect last changed
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+type HubHealthState string
+
+const (
+	HubHealthy     HubHealthState = "Healthy"
+	HubDegraded    HubHealthState = "Degraded"
+	HubUnavailable HubHealthState = "Unavailable"
+)
+
+// HubHealthStatus summarizes the availability of the hub components and of the hub over time. The hub is available
+// while the components it cannot run without (MCE, cluster lifecycle, application and policy management) are.
+type HubHealthStatus struct {
+	// Score is the weighted share of available components from 0 to 100. Components the hub cannot run without weigh
+	// more than optional add-ons.
+	Score int32 `json:"score"`
+
+	// State is Healthy when all components are available, Degraded when only optional components are unavailable
+	// and Unavailable when a component the hub cannot run without is unavailable
+	State HubHealthState `json:"state"`
+
+	// UnavailableComponents lists the components that are not available
+	// +optional
+	UnavailableComponents []string `json:"unavailableComponents,omitempty"`
+
+	// Objective is the share of time the hub is expected to be available, in percent
+	Objective string `json:"objective"`
+
+	// SLO reports the availability of the hub and the burn rate of its error budget over each window
+	// +optional
+	SLO []HubSLOWindow `json:"slo,omitempty"`
+}
+
+// HubSLOWindow is the availability of the hub over a time window
+type HubSLOWindow struct {
+	// Window is the length of the window, for example 6h
+	Window string `json:"window"`
+
+	// Availability is the share of the window the hub was available, in percent
+	Availability string `json:"availability"`
+
+	// BurnRate is how fast the error budget is consumed over the window, at 1 it lasts exactly the window
+	BurnRate string `json:"burnRate"`
+}
+
+type ComponentRolloutPhase string
+
+const (
+	ComponentRolloutProgressing ComponentRolloutPhase = "Progressing"
+	ComponentRolloutCompleted   ComponentRolloutPhase = "Completed"
+	ComponentRolloutBlocked     ComponentRolloutPhase = "Blocked"
+)
+
+// ComponentRolloutStatus tracks the progressive rollout of the component deployments to a new release. Deployments are
+// updated one at a time and the next one is only updated once the previous one is ready.
+type ComponentRolloutStatus struct {
+	// TargetVersion is the release the deployments are rolled out to
+	TargetVersion string `json:"targetVersion"`
+
+	// Phase is the overall state of the rollout
+	// +optional
+	Phase ComponentRolloutPhase `json:"phase,omitempty"`
+
+	// Current is the deployment being rolled out
+	// +optional
+	Current *RolloutDeployment `json:"current,omitempty"`
+
+	// Updated lists the deployments that were rolled out to the target version and became ready
+	// +optional
+	Updated []RolloutDeployment `json:"updated,omitempty"`
+
+	// RolledBackComponents lists the components restored to their previously applied manifests after their rollout
+	// failed
+	// +optional
+	RolledBackComponents []string `json:"rolledBackComponents,omitempty"`
+
+	// Message describes why the rollout is blocked
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastRetry is the last rollout-retry annotation value acted on
+	// +optional
+	LastRetry string `json:"lastRetry,omitempty"`
+}
+
+// RolloutDeployment identifies a component deployment in a rollout
+type RolloutDeployment struct {
+	// Component is the hub component the deployment belongs to
+	Component string `json:"component"`
+
+	// Namespace of the deployment
+	Namespace string `json:"namespace"`
+
+	// Name of the deployment
+	Name string `json:"name"`
+
+	// StartTime is when the deployment was updated to the target version
+	// +optional
+	StartTime metav1.Time `json:"startTime,omitempty"`
+}
+
+// OverrideRevisionsStatus reports the content of the image and template override ConfigMaps in use
+type OverrideRevisionsStatus struct {
+	// ImageOverrides is the revision of the image override ConfigMap in use
+	// +optional
+	ImageOverrides *OverrideRevision `json:"imageOverrides,omitempty"`
+
+	// TemplateOverrides is the revision of the template override ConfigMap in use
+	// +optional
+	TemplateOverrides *OverrideRevision `json:"templateOverrides,omitempty"`
+
+	// LastRollback is the last overrides-rollback annotation value acted on
+	// +optional
+	LastRollback string `json:"lastRollback,omitempty"`
+}
+
+// OverrideRevision identifies the content of an override ConfigMap in use
+type OverrideRevision struct {
+	// ConfigMap is the name of the override ConfigMap
+	ConfigMap string `json:"configMap"`
+
+	// Revision is a hash of the ConfigMap content in use
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// ResourceVersion is the resource version of the ConfigMap at the last reconcile
+	// +optional
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// AppliedTime is when the revision in use was first applied
+	// +optional
+	AppliedTime metav1.Time `json:"appliedTime,omitempty"`
+
+	// PreviousRevision is the revision restored by a rollback
+	// +optional
+	PreviousRevision string `json:"previousRevision,omitempty"`
+
+	// Message describes why the current ConfigMap content is not in use
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RejectedTemplateOverride is a template override that is not applied to any component
+type RejectedTemplateOverride struct {
+	// Key is the template-override key
+	Key string `json:"key"`
+
+	// Reason describes why the override is rejected
+	Reason string `json:"reason"`
+}
+
+// AdoptionState describes why an existing resource is not owned by the hub
+type AdoptionState string
+
+const (
+	// AdoptionStateUnowned resources have no installer labels
+	AdoptionStateUnowned AdoptionState = "Unowned"
+	// AdoptionStatePartiallyLabeled resources have only one of the installer labels
+	AdoptionStatePartiallyLabeled AdoptionState = "PartiallyLabeled"
+	// AdoptionStateOwnedByOtherHub resources are labeled by another MultiClusterHub
+	AdoptionStateOwnedByOtherHub AdoptionState = "OwnedByOtherHub"
+)
+
+// AdoptionAction is what the operator did with an existing resource it does not own
+type AdoptionAction string
+
+const (
+	// AdoptionActionAdopted resources were labeled and are managed by the hub
+	AdoptionActionAdopted AdoptionAction = "Adopted"
+	// AdoptionActionSkipped resources are left untouched
+	AdoptionActionSkipped AdoptionAction = "Skipped"
+	// AdoptionActionManaged resources are managed by the hub without being relabeled
+	AdoptionActionManaged AdoptionAction = "Managed"
+)
+
+// AdoptionStatus reports the existing resources rendered by the hub that it does not own
+type AdoptionStatus struct {
+	// Candidates lists the resources found at the last complete reconcile
+	// +optional
+	Candidates []AdoptionCandidate `json:"candidates,omitempty"`
+
+	// LastAdoptNow records the last spec.adoption.adoptNow request acted on
+	// +optional
+	LastAdoptNow *AdoptNowStatus `json:"lastAdoptNow,omitempty"`
+}
+
+// AdoptionCandidate is an existing resource rendered by the hub that it does not own
+type AdoptionCandidate struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// State describes why the resource is not owned by the hub
+	State AdoptionState `json:"state"`
+
+	// Owner is the namespace/name of the MultiClusterHub whose labels the resource carries
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// Action is what the operator did with the resource
+	Action AdoptionAction `json:"action"`
+}
+
+// AdoptNowStatus records a handled adopt-now request
+type AdoptNowStatus struct {
+	// Token is the spec.adoption.adoptNow value that was acted on
+	Token string `json:"token"`
+
+	// Time is when the adoption completed
+	Time metav1.Time `json:"time"`
+
+	// Adopted is the number of resources adopted
+	Adopted int `json:"adopted"`
+}
+
+// TrustBundleStatus reports the trust bundle and proxy configuration propagated to the hub components
+type TrustBundleStatus struct {
+	// Hash identifies the content of the trust bundle propagated to the component namespaces
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// ClusterBundle is true when the trust bundle includes the cluster trust bundle injected by the cluster network
+	// operator. Only then does the bundle replace the system trust store of the component containers.
+	// +optional
+	ClusterBundle bool `json:"clusterBundle,omitempty"`
+
+	// CustomCA is true when the certificates of spec.trustedCA are included in the trust bundle
+	// +optional
+	CustomCA bool `json:"customCA,omitempty"`
+
+	// Proxy is the cluster proxy configuration propagated to the component deployments
+	// +optional
+	Proxy *ProxyStatus `json:"proxy,omitempty"`
+
+	// Namespaces lists the component namespaces the trust bundle is replicated to
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// DriftedNamespaces lists the component namespaces whose trust bundle did not match the hub trust bundle at the
+	// last sync. The operator restores the bundle in these namespaces.
+	// +optional
+	DriftedNamespaces []string `json:"driftedNamespaces,omitempty"`
+
+	// LastSyncTime is the last time the trust bundle was synced to the component namespaces
+	// +optional
+	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Message describes why the trust configuration could not be fully read or propagated
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ProxyStatus is the cluster-wide proxy configuration used by the hub components
+type ProxyStatus struct {
+	// HTTPProxy is the URL of the proxy for HTTP requests
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for HTTPS requests
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma-separated list of hosts that are not proxied
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// ComponentReconcileStatus reports the last render and apply results of a component
+type ComponentReconcileStatus struct {
+	// LastRender is the result of the last render of the component chart
+	// +optional
+	LastRender *ComponentOperationResult `json:"lastRender,omitempty"`
+
+	// LastApply is the result of the last apply of the rendered component resources
+	// +optional
+	LastApply *ComponentOperationResult `json:"lastApply,omitempty"`
+
+	// TemplateOverrides are the template overrides applied to the component chart at the last render
+	// +optional
+	TemplateOverrides map[string]string `json:"templateOverrides,omitempty"`
+
+	// StatusWorkloads are the workloads of the rendered component chart whose readiness is reported in the
+	// hub status. They are reported until the chart is rendered again, for example after the operator restarts.
+	// +optional
+	StatusWorkloads []StatusWorkloadReference `json:"statusWorkloads,omitempty"`
+}
+
+// StatusWorkloadReference identifies a workload whose readiness is reported in the hub status
+type StatusWorkloadReference struct {
+	// Kind of the workload: Deployment, StatefulSet, DaemonSet or Job
+	Kind string `json:"kind"`
+
+	// Namespace of the workload
+	Namespace string `json:"namespace"`
+
+	// Name of the workload
+	Name string `json:"name"`
+}
+
+// Failed returns true if the last render or the last apply of the component failed
+func (s ComponentReconcileStatus) Failed() bool {
+	return (s.LastRender != nil && !s.LastRender.Succeeded) || (s.LastApply != nil && !s.LastApply.Succeeded)
+}
+
+// ComponentOperationResult is the result of rendering or applying a component
+type ComponentOperationResult struct {
+	// Succeeded is true if the operation completed without errors
+	Succeeded bool `json:"succeeded"`
+
+	// LastTransitionTime is the last time the operation changed from succeeding to failing or back
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Category classifies the error of a failed operation
+	// +optional
+	Category ComponentErrorCategory `json:"category,omitempty"`
+
+	// Message describes the error of a failed operation
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// FailingObjects references the resources that could not be applied
+	// +optional
+	FailingObjects []FailingObjectReference `json:"failingObjects,omitempty"`
+}
+
+// FailingObjectReference identifies a component resource that could not be applied
+type FailingObjectReference struct {
+	// APIVersion of the resource
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// Namespace of the resource, empty for cluster scoped resources
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource
+	Name string `json:"name"`
+}
+
+// ComponentErrorCategory classifies why rendering or applying a component failed
+// +kubebuilder:validation:Enum=Render;Validation;Conflict;Forbidden;MissingCRD;Unknown
+type ComponentErrorCategory string
+
+const (
+	// ComponentErrorRender means the component chart could not be rendered
+	ComponentErrorRender ComponentErrorCategory = "Render"
+	// ComponentErrorValidation means the API server rejected a rendered resource as invalid
+	ComponentErrorValidation ComponentErrorCategory = "Validation"
+	// ComponentErrorConflict means a rendered resource conflicts with the resource on the cluster
+	ComponentErrorConflict ComponentErrorCategory = "Conflict"
+	// ComponentErrorForbidden means the operator is not allowed to manage a rendered resource
+	ComponentErrorForbidden ComponentErrorCategory = "Forbidden"
+	// ComponentErrorMissingCRD means the API of a rendered resource is not served by the cluster
+	ComponentErrorMissingCRD ComponentErrorCategory = "MissingCRD"
+	// ComponentErrorUnknown is used for all other errors
+	ComponentErrorUnknown ComponentErrorCategory = "Unknown"
+)
+
+// ClusterCapabilitiesStatus reports which optional cluster APIs are available to the operator. It is kept current
+// as CRDs and APIServices are added to or removed from the cluster.
+type ClusterCapabilitiesStatus struct {
+	// OLMVersion is the OLM version used to manage operator installs: v0, v1, or empty when OLM is not present
+	OLMVersion string `json:"olmVersion,omitempty"`
+
+	// Available lists the names of the optional capabilities currently served by the cluster, such as OLMv1,
+	// MultiClusterEngine, Console, ConsoleNotification and ServiceMonitor
+	Available []string `json:"available,omitempty"`
+}
+
+type OLMMigrationPhase string
+
+const (
+	OLMMigrationPending    OLMMigrationPhase = "Pending"
+	OLMMigrationInProgress OLMMigrationPhase = "InProgress"
+	OLMMigrationCompleted  OLMMigrationPhase = "Completed"
+	OLMMigrationBlocked    OLMMigrationPhase = "Blocked"
+	OLMMigrationRolledBack OLMMigrationPhase = "RolledBack"
+)
+
+// MCEOLMMigrationStatus records the migration of the managed MCE installation from one OLM version to another
+type MCEOLMMigrationStatus struct {
+	// SourceOLMVersion is the OLM version MCE was installed with when the migration started
+	SourceOLMVersion string `json:"sourceOLMVersion,omitempty"`
+
+	// TargetOLMVersion is the OLM version MCE is being moved to
+	TargetOLMVersion string `json:"targetOLMVersion,omitempty"`
+
+	// Phase is the overall state of the migration. A Pending migration was detected but is not started until the OLM
+	// version is requested explicitly, as it removes the resources installed through the source OLM version.
+	Phase OLMMigrationPhase `json:"phase,omitempty"`
+
+	// PreviousCSV is the name of the MCE ClusterServiceVersion that was installed through OLM v0. It is used to
+	// pin the restored Subscription when rolling back.
+	PreviousCSV string `json:"previousCSV,omitempty"`
+
+	// PinnedVersion is the MCE bundle version held constant while ownership is handed over
+	PinnedVersion string `json:"pinnedVersion,omitempty"`
+
+	// Steps lists each migration step and its outcome, in the order they were run
+	Steps []OLMMigrationStep `json:"steps,omitempty"`
+}
+
+// OLMMigrationStep contains the outcome of a single migration step
+type OLMMigrationStep struct {
+	// Name of the step
+	Name string `json:"name"`
+
+	// Status is True when the step has finished, False when it failed, and Unknown while it is running.
+	Status metav1.ConditionStatus `json:"status"`
+
+	// Message is a human-readable message indicating details about the step.
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time the step changed from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 // StatusCondition contains condition information.
@@ -245,7 +1196,8 @@ const (
 	// Bocked means there is something preventing an update from occurring
 	Blocked HubConditionType = "Blocked"
 
-	// ComponentFailure means a deployment failed during an Apply
+	// ComponentFailure means at least one component failed to render or apply. The failing components are listed in
+	// status.componentReconcile.
 	ComponentFailure HubConditionType = "ComponentFailure"
 )
 
@@ -277,6 +1229,7 @@ type HubCondition struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=multiclusterhubs,scope=Namespaced,shortName=mch
+//+kubebuilder:storageversion
 
 // MulticlusterHub defines the configuration
 // for an instance of a multicluster hub, a central point for managing multiple
@@ -306,11 +1259,17 @@ type MultiClusterHubList struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ihc
+
+// InternalHubComponent is the handshake between the hub operator and the operand operator of a component. The hub
+// operator writes the desired state to the spec, and the operand reports what it observed in the status.
 // +operator-sdk:csv:customresourcedefinitions:displayName="InternalHubComponent"
 type InternalHubComponent struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              InternalHubComponentSpec `json:"spec,omitempty"`
+	Spec              InternalHubComponentSpec   `json:"spec,omitempty"`
+	Status            InternalHubComponentStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -320,7 +1279,85 @@ type InternalHubComponentList struct {
 	Items           []InternalHubComponent `json:"items"`
 }
 
-type InternalHubComponentSpec struct{}
+// InternalHubComponentSpec is the desired state of a hub component, written by the hub operator
+type InternalHubComponentSpec struct {
+	// DesiredVersion is the hub version the component is expected to run
+	// +optional
+	DesiredVersion string `json:"desiredVersion,omitempty"`
+
+	// ConfigHash is a hash of the rendered component configuration. It changes whenever the configuration
+	// applied to the component changes.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// Placement describes where the component runs
+	// +optional
+	Placement *ComponentPlacement `json:"placement,omitempty"`
+}
+
+// ComponentPlacement describes where a hub component runs
+type ComponentPlacement struct {
+	// Namespace the component workloads run in
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// NodeSelector applied to the component workloads
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations applied to the component workloads
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// InternalHubComponentStatus is the observed state of a hub component, reported by its operand operator
+type InternalHubComponentStatus struct {
+	// ObservedGeneration is the spec generation the status was reported for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ObservedVersion is the version the component is running
+	// +optional
+	ObservedVersion string `json:"observedVersion,omitempty"`
+
+	// Conditions report the component readiness. The Ready condition decides whether the component is available.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Cleanup reports the progress of the component finalization once the InternalHubComponent is deleted
+	// +optional
+	Cleanup *ComponentCleanupStatus `json:"cleanup,omitempty"`
+}
+
+// ComponentCleanupStatus reports the progress of a component finalization
+type ComponentCleanupStatus struct {
+	// Phase of the cleanup
+	// +kubebuilder:validation:Enum=InProgress;Complete;Failed
+	// +optional
+	Phase CleanupPhase `json:"phase,omitempty"`
+
+	// RemainingResources is the number of resources left to remove
+	// +optional
+	RemainingResources int32 `json:"remainingResources,omitempty"`
+
+	// Message is a human-readable description of the cleanup progress
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CleanupPhase is the phase of a component finalization
+type CleanupPhase string
+
+const (
+	CleanupInProgress CleanupPhase = "InProgress"
+	CleanupComplete   CleanupPhase = "Complete"
+	CleanupFailed     CleanupPhase = "Failed"
+)
+
+// ComponentReady is the InternalHubComponent condition reporting whether the component is ready
+const ComponentReady = "Ready"
 
 func init() {
 	SchemeBuilder.Register(&MultiClusterHub{}, &MultiClusterHubList{})
@@ -20,11 +20,17 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	mcev1 "github.com/stolostron/backplane-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/overrideschema"
 	admissionregistration "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
 	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -32,6 +38,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -44,17 +52,25 @@ import (
 
 const (
 	// Current annotation keys
-	annotationIgnoreOCPVersion = "installer.open-cluster-management.io/ignore-ocp-version"
-	annotationImageOverridesCM = "installer.open-cluster-management.io/image-overrides-configmap"
-	annotationImageRepo        = "installer.open-cluster-management.io/image-repository"
-	annotationKubeconfig       = "installer.open-cluster-management.io/kubeconfig"
-	annotationMCHPause         = "installer.open-cluster-management.io/pause"
+	annotationIgnoreOCPVersion    = "installer.open-cluster-management.io/ignore-ocp-version"
+	annotationImageOverridesCM    = "installer.open-cluster-management.io/image-overrides-configmap"
+	annotationImageRepo           = "installer.open-cluster-management.io/image-repository"
+	annotationKubeconfig          = "installer.open-cluster-management.io/kubeconfig"
+	annotationMCHPause            = "installer.open-cluster-management.io/pause"
+	annotationTemplateOverridesCM = "installer.open-cluster-management.io/template-override-configmap"
 
 	// OLM version-specific annotations
 	annotationMCESubscriptionSpec      = "installer.open-cluster-management.io/mce-subscription-spec"
 	annotationMCEClusterExtensionSpec  = "installer.open-cluster-management.io/mce-clusterextension-spec"
 	annotationOADPSubscriptionSpec     = "installer.open-cluster-management.io/oadp-subscription-spec"
 	annotationOADPClusterExtensionSpec = "installer.open-cluster-management.io/oadp-clusterextension-spec"
+	annotationMCEOLMVersion            = "installer.open-cluster-management.io/mce-olm-version"
+	annotationOADPOLMVersion           = "installer.open-cluster-management.io/oadp-olm-version"
+
+	// Probe annotations, replaced by spec.probes
+	annotationProbeTimeoutSeconds   = "installer.open-cluster-management.io/probe-timeout-seconds"
+	annotationProbeFailureThreshold = "installer.open-cluster-management.io/probe-failure-threshold"
+	annotationProbeSuccessThreshold = "installer.open-cluster-management.io/probe-success-threshold"
 
 	// Deprecated annotation keys
 	deprecatedAnnotationIgnoreOCPVersion = "ignoreOCPVersion"
@@ -164,6 +180,20 @@ func (r *MultiClusterHub) ValidateCreate(ctx context.Context, obj *MultiClusterH
 		return warnings, fmt.Errorf("invalid AvailabilityConfig given")
 	}
 
+	if err := validateProbes(obj); err != nil {
+		return warnings, err
+	}
+
+	if err := validatePreservedFields(obj); err != nil {
+		return warnings, err
+	}
+
+	templateWarnings, err := validateTemplateOverrides(ctx, obj)
+	warnings = append(warnings, templateWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+
 	// Validate components
 	if obj.Spec.Overrides != nil {
 		for _, c := range obj.Spec.Overrides.Components {
@@ -173,6 +203,10 @@ func (r *MultiClusterHub) ValidateCreate(ctx context.Context, obj *MultiClusterH
 		}
 	}
 
+	if err := validateComponentNamespaces(obj); err != nil {
+		return warnings, err
+	}
+
 	// validate local-cluster name length
 	if err := validateLocalClusterNameLength(obj.Spec.LocalClusterName); err != nil {
 		return warnings, err
@@ -210,6 +244,7 @@ func (r *MultiClusterHub) ValidateUpdate(ctx context.Context, oldObj, newObj *Mu
 
 	// Check for deprecated annotations and collect warnings
 	warnings := checkDeprecatedAnnotations(newObj)
+	warnings = append(warnings, rbacAuditWarnings(oldObj, newObj)...)
 
 	// Validate OLM version-specific annotations
 	if err := validateOLMAnnotations(ctx, newObj); err != nil {
@@ -226,6 +261,23 @@ func (r *MultiClusterHub) ValidateUpdate(ctx context.Context, oldObj, newObj *Mu
 		return warnings, fmt.Errorf("invalid AvailabilityConfig given")
 	}
 
+	if err := validateProbes(newObj); err != nil {
+		return warnings, err
+	}
+
+	if err := validatePreservedFields(newObj); err != nil {
+		return warnings, err
+	}
+
+	// Only a new template override ConfigMap is validated, so that existing hubs can still be updated
+	if oldMCH.GetAnnotations()[annotationTemplateOverridesCM] != newObj.GetAnnotations()[annotationTemplateOverridesCM] {
+		templateWarnings, err := validateTemplateOverrides(ctx, newObj)
+		warnings = append(warnings, templateWarnings...)
+		if err != nil {
+			return warnings, err
+		}
+	}
+
 	// Validate components
 	if newObj.Spec.Overrides != nil {
 		for _, c := range newObj.Spec.Overrides.Components {
@@ -235,6 +287,10 @@ func (r *MultiClusterHub) ValidateUpdate(ctx context.Context, oldObj, newObj *Mu
 		}
 	}
 
+	if err := validateComponentNamespaces(newObj); err != nil {
+		return warnings, err
+	}
+
 	// Validate that cnv-mtv-integrations is not enabled when disableHubSelfManagement is true
 	if err := validateMTVAndSelfManagement(newObj); err != nil {
 		return warnings, err
@@ -391,6 +447,25 @@ func ValidatingWebhook(namespace string) *admissionregistration.ValidatingWebhoo
 	}
 }
 
+// CRDConversion returns the conversion settings of the multiclusterhub CRD, pointing the API server at the
+// conversion webhook served from the provided namespace
+func CRDConversion(namespace string) *apixv1.CustomResourceConversion {
+	path := "/convert"
+	return &apixv1.CustomResourceConversion{
+		Strategy: apixv1.WebhookConverter,
+		Webhook: &apixv1.WebhookConversion{
+			ClientConfig: &apixv1.WebhookClientConfig{
+				Service: &apixv1.ServiceReference{
+					Name:      "multiclusterhub-operator-webhook",
+					Namespace: namespace,
+					Path:      &path,
+				},
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+}
+
 // validateMTVAndSelfManagement returns an error if cnv-mtv-integrations is enabled
 // while disableHubSelfManagement is true, as this combination is unsupported
 // and will cause the MCH to be stuck in Pending.
@@ -405,6 +480,108 @@ func validateMTVAndSelfManagement(r *MultiClusterHub) error {
 	return nil
 }
 
+// reservedComponentNamespaces are the namespaces components cannot be installed into
+var reservedComponentNamespaces = []string{
+	"default",
+	"open-cluster-management-backup",
+	"open-cluster-management-observability",
+	"open-cluster-management-hub",
+	"multicluster-engine",
+}
+
+// reservedComponentNamespacePrefixes are the prefixes of the namespaces components cannot be installed into
+var reservedComponentNamespacePrefixes = []string{"kube-", "openshift", "open-cluster-management-agent"}
+
+/*
+validateComponentNamespaces rejects custom namespaces for components that are always installed into their own
+namespace, and namespaces that belong to the platform or to other hub components.
+*/
+func validateComponentNamespaces(mch *MultiClusterHub) error {
+	if mch.Spec.Overrides == nil {
+		return nil
+	}
+	for i, c := range mch.Spec.Overrides.Components {
+		if c.Namespace == "" || c.Namespace == mch.Namespace {
+			continue
+		}
+
+		path := fmt.Sprintf("spec.overrides.components[%d].namespace", i)
+		if !contains(CustomNamespaceComponents, c.Name) {
+			return fmt.Errorf("%s: component %q cannot be installed into a custom namespace", path, c.Name)
+		}
+		if errs := validation.IsDNS1123Label(c.Namespace); len(errs) > 0 {
+			return fmt.Errorf("%s: %q is not a valid namespace name: %s", path, c.Namespace, strings.Join(errs, ", "))
+		}
+		if contains(reservedComponentNamespaces, c.Namespace) {
+			return fmt.Errorf("%s: namespace %q is reserved", path, c.Namespace)
+		}
+		for _, prefix := range reservedComponentNamespacePrefixes {
+			if strings.HasPrefix(c.Namespace, prefix) {
+				return fmt.Errorf("%s: namespace %q is reserved, namespaces starting with %q cannot be used", path,
+					c.Namespace, prefix)
+			}
+		}
+	}
+	return nil
+}
+
+/*
+validateTemplateOverrides rejects a template override ConfigMap with keys that no component chart supports, according
+to the template-override schemas published by the charts. Values that do not match the chart schema are returned as
+warnings, since the operator ignores them.
+*/
+func validateTemplateOverrides(ctx context.Context, mch *MultiClusterHub) (admission.Warnings, error) {
+	name := mch.GetAnnotations()[annotationTemplateOverridesCM]
+	if name == "" {
+		return nil, nil
+	}
+
+	schemas, err := overrideschema.LoadToggleCharts()
+	if err != nil {
+		// Don't block when the charts are not available - the operator reports rejected overrides in the status
+		mchlog.Error(err, "Failed to read the template override schemas for validation")
+		return nil, nil
+	}
+
+	configmap := &corev1.ConfigMap{}
+	if err := Client.Get(ctx, types.NamespacedName{Name: name, Namespace: mch.GetNamespace()}, configmap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return admission.Warnings{fmt.Sprintf("template override configmap %s/%s does not exist",
+				mch.GetNamespace(), name)}, nil
+		}
+		return nil, fmt.Errorf("unable to get template override configmap %s/%s: %w", mch.GetNamespace(), name, err)
+	}
+
+	var warnings admission.Warnings
+	var unknown []string
+	for _, data := range configmap.Data {
+		var manifest struct {
+			TemplateOverrides map[string]interface{} `json:"templateOverrides"`
+		}
+		if err := json.Unmarshal([]byte(data), &manifest); err != nil {
+			return nil, fmt.Errorf("template override configmap %s is not valid JSON: %w", name, err)
+		}
+
+		for key, value := range manifest.TemplateOverrides {
+			p, ok := schemas.Lookup(key)
+			if !ok {
+				unknown = append(unknown, key)
+				continue
+			}
+			if err := p.Validate(fmt.Sprint(value)); err != nil {
+				warnings = append(warnings, fmt.Sprintf("template override %s is ignored: %s", key, err))
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return warnings, fmt.Errorf("template override configmap %s has keys that no component supports: %s", name,
+			strings.Join(unknown, ", "))
+	}
+	return warnings, nil
+}
+
 func contains(list []string, s string) bool {
 	for _, v := range list {
 		if v == s {
@@ -429,15 +606,35 @@ func validateOLMAnnotations(ctx context.Context, mch *MultiClusterHub) error {
 		return nil
 	}
 
+	// An explicit MCE OLM version migrates MCE to that OLM version, so MCE annotations must match it instead
+	mceOLMVersion := olmVersion
+	if v, ok := annotations[annotationMCEOLMVersion]; ok {
+		if v != "v0" && v != "v1" {
+			return fmt.Errorf("annotation %q must be one of \"v0\" or \"v1\"", annotationMCEOLMVersion)
+		}
+		mceOLMVersion = v
+	}
+
 	// Validate MCE annotations
-	if err := validateOLMAnnotationPair(olmVersion, "", annotations,
+	if err := validateOLMAnnotationPair(mceOLMVersion, "", annotations,
 		annotationMCESubscriptionSpec, annotationMCEClusterExtensionSpec); err != nil {
 		return fmt.Errorf("validating MCE OLM annotations: %w", err)
 	}
 
-	// OADP always uses v0 Subscription until OADP team ships OLM v1-ready bundles.
-	if err := validateOLMAnnotationPair("v0",
-		"OADP requires a v0 Subscription and does not yet support OLM v1",
+	// OADP uses a v0 Subscription unless OLM v1 is explicitly requested, since not every OADP bundle supports it
+	oadpOLMVersion, reason := "v0", fmt.Sprintf("OADP requires a v0 Subscription unless %q is set to \"v1\"",
+		annotationOADPOLMVersion)
+	if v, ok := annotations[annotationOADPOLMVersion]; ok {
+		if v != "v0" && v != "v1" {
+			return fmt.Errorf("annotation %q must be one of \"v0\" or \"v1\"", annotationOADPOLMVersion)
+		}
+		if v == "v1" && olmVersion != "v1" {
+			return fmt.Errorf("annotation %q is set to \"v1\", but this cluster does not serve the OLM v1 API",
+				annotationOADPOLMVersion)
+		}
+		oadpOLMVersion, reason = v, ""
+	}
+	if err := validateOLMAnnotationPair(oadpOLMVersion, reason,
 		annotations, annotationOADPSubscriptionSpec, annotationOADPClusterExtensionSpec); err != nil {
 		return fmt.Errorf("validating OADP OLM annotations: %w", err)
 	}
@@ -532,7 +729,161 @@ func checkDeprecatedAnnotations(r *MultiClusterHub) admission.Warnings {
 				warnings = append(warnings, warning)
 			}
 		}
+
+		for _, key := range []string{annotationProbeTimeoutSeconds, annotationProbeFailureThreshold,
+			annotationProbeSuccessThreshold} {
+			if _, exists := annotations[key]; exists {
+				warnings = append(warnings, fmt.Sprintf(
+					"annotation '%s' is deprecated and will be moved to spec.probes.global automatically", key))
+			}
+		}
 	}
 
 	return warnings
 }
+
+// maxRBACAuditPermissions is the number of missing permissions listed in the warning of a component
+const maxRBACAuditPermissions = 5
+
+/*
+rbacAuditWarnings warns about the findings of the last RBAC audit of the components the updated hub enables: the
+permissions their charts need that the operator is not granted and the wildcard rules of their ClusterRoles.
+*/
+func rbacAuditWarnings(oldObj, newObj *MultiClusterHub) admission.Warnings {
+	var warnings admission.Warnings
+	if oldObj.Status.RBACAudit == nil {
+		return warnings
+	}
+
+	for _, audit := range oldObj.Status.RBACAudit.Components {
+		if !newObj.Enabled(audit.Name) {
+			continue
+		}
+		if missing := audit.MissingPermissions; len(missing) > 0 {
+			listed := strings.Join(missing, ", ")
+			if len(missing) > maxRBACAuditPermissions {
+				listed = fmt.Sprintf("%s and %d more", strings.Join(missing[:maxRBACAuditPermissions], ", "),
+					len(missing)-maxRBACAuditPermissions)
+			}
+			warnings = append(warnings, fmt.Sprintf("component %s needs permissions the operator is not granted: %s",
+				audit.Name, listed))
+		}
+		for _, rule := range audit.WildcardRules {
+			warnings = append(warnings, fmt.Sprintf("component %s installs a wildcard rule: %s", audit.Name, rule))
+		}
+	}
+	return warnings
+}
+
+// validatePreservedFields rejects spec.overrides.preservedFields paths the operator cannot parse
+func validatePreservedFields(mch *MultiClusterHub) error {
+	if mch.Spec.Overrides == nil {
+		return nil
+	}
+	base := field.NewPath("spec", "overrides", "preservedFields")
+	for i, rule := range mch.Spec.Overrides.PreservedFields {
+		for j, path := range rule.Paths {
+			if _, err := ParseFieldPath(path); err != nil {
+				return field.Invalid(base.Index(i).Child("paths").Index(j), path, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+/*
+validateProbes rejects probe tuning that the kubelet would refuse or that targets components the hub does not
+manage. The deprecated probe annotations are checked too, since they are migrated into spec.probes.
+*/
+func validateProbes(mch *MultiClusterHub) error {
+	for _, key := range []string{annotationProbeTimeoutSeconds, annotationProbeFailureThreshold,
+		annotationProbeSuccessThreshold} {
+		val, ok := mch.GetAnnotations()[key]
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseInt(val, 10, 32); err != nil || n <= 0 {
+			return fmt.Errorf("annotation %q must be a positive integer, got %q", key, val)
+		}
+	}
+
+	probes := mch.Spec.Probes
+	if probes == nil {
+		return nil
+	}
+
+	if probes.Global != nil {
+		if err := validateProbeSettings("spec.probes.global", *probes.Global); err != nil {
+			return err
+		}
+	}
+
+	components := map[string]bool{}
+	for i, c := range probes.Components {
+		path := fmt.Sprintf("spec.probes.components[%d]", i)
+		if !contains(MCHComponents, c.Name) {
+			return fmt.Errorf("%s.name: %q is not a known component", path, c.Name)
+		}
+		if components[c.Name] {
+			return fmt.Errorf("%s.name: component %q is listed more than once", path, c.Name)
+		}
+		components[c.Name] = true
+
+		if err := validateProbeSettings(path, c.ProbeSettings); err != nil {
+			return err
+		}
+
+		deployments := map[string]bool{}
+		for j, d := range c.Deployments {
+			dpath := fmt.Sprintf("%s.deployments[%d]", path, j)
+			if d.Name == "" {
+				return fmt.Errorf("%s.name: must not be empty", dpath)
+			}
+			if deployments[d.Name] {
+				return fmt.Errorf("%s.name: deployment %q is listed more than once", dpath, d.Name)
+			}
+			deployments[d.Name] = true
+
+			if err := validateProbeSettings(dpath, d.ProbeSettings); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateProbeSettings(path string, settings ProbeSettings) error {
+	if err := validateProbeTuning(path+".liveness", settings.Liveness); err != nil {
+		return err
+	}
+	if l := settings.Liveness; l != nil && l.SuccessThreshold != nil && *l.SuccessThreshold != 1 {
+		return fmt.Errorf("%s.liveness.successThreshold: must be 1 for liveness probes, got %d", path,
+			*l.SuccessThreshold)
+	}
+	return validateProbeTuning(path+".readiness", settings.Readiness)
+}
+
+func validateProbeTuning(path string, t *ProbeTuning) error {
+	if t == nil {
+		return nil
+	}
+	if t.InitialDelaySeconds != nil && *t.InitialDelaySeconds < 0 {
+		return fmt.Errorf("%s.initialDelaySeconds: must not be negative, got %d", path, *t.InitialDelaySeconds)
+	}
+
+	positive := []struct {
+		field string
+		value *int32
+	}{
+		{"periodSeconds", t.PeriodSeconds},
+		{"timeoutSeconds", t.TimeoutSeconds},
+		{"failureThreshold", t.FailureThreshold},
+		{"successThreshold", t.SuccessThreshold},
+	}
+	for _, p := range positive {
+		if p.value != nil && *p.value < 1 {
+			return fmt.Errorf("%s.%s: must be at least 1, got %d", path, p.field, *p.value)
+		}
+	}
+	return nil
+}
@@ -413,6 +413,32 @@ var _ = Describe("Multiclusterhub webhook", func() {
 			// No annotations set - should always pass
 			Expect(validateOLMAnnotations(ctx, mch)).To(Succeed())
 		})
+
+		It("Should validate the OADP OLM version annotation", func() {
+			mch := &MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-oadp-olm-version",
+					Namespace:   "default",
+					Annotations: map[string]string{annotationOADPOLMVersion: "v2"},
+				},
+				Spec: MultiClusterHubSpec{
+					LocalClusterName: "test-cluster",
+				},
+			}
+			err := validateOLMAnnotations(ctx, mch)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must be one of"))
+
+			// The test environment does not serve the ClusterExtension API
+			mch.Annotations[annotationOADPOLMVersion] = "v1"
+			err = validateOLMAnnotations(ctx, mch)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not serve the OLM v1 API"))
+
+			mch.Annotations[annotationOADPOLMVersion] = "v0"
+			mch.Annotations[annotationOADPSubscriptionSpec] = `{"channel": "stable-1.4"}`
+			Expect(validateOLMAnnotations(ctx, mch)).To(Succeed())
+		})
 	})
 
 	Context("validateOLMAnnotationPair", func() {
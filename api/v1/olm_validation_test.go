@@ -82,6 +82,40 @@ func TestValidateOLMAnnotations(t *testing.T) {
 			wantErr:     true,
 			errContains: "requires OLM v1, but no OLM detected",
 		},
+		{
+			name: "V1 annotation on v0 cluster migrating MCE to v1 - valid",
+			annotations: map[string]string{
+				annotationMCEOLMVersion:           "v1",
+				annotationMCEClusterExtensionSpec: `{"channels": ["stable-2.6"]}`,
+			},
+			olmVersion: "v0",
+			setupEnv: func() {
+				os.Setenv("OPERATOR_CONDITION_NAME", "multiclusterhub-operator")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("OPERATOR_CONDITION_NAME")
+			},
+			wantErr: false,
+		},
+		{
+			name: "V0 annotation after migrating MCE to v1 - invalid",
+			annotations: map[string]string{
+				annotationMCEOLMVersion:       "v1",
+				annotationMCESubscriptionSpec: `{"channel": "stable-2.6"}`,
+			},
+			olmVersion:  "v0",
+			wantErr:     true,
+			errContains: "only valid for OLM v0 clusters",
+		},
+		{
+			name: "Unknown MCE OLM version - invalid",
+			annotations: map[string]string{
+				annotationMCEOLMVersion: "v2",
+			},
+			olmVersion:  "v1",
+			wantErr:     true,
+			errContains: "must be one of",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,84 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{path: "spec.replicas", want: []string{"spec", "replicas"}},
+		{path: ".spec.replicas", want: []string{"spec", "replicas"}},
+		{path: "$.spec.replicas", want: []string{"spec", "replicas"}},
+		{path: `data["config.yaml"]`, want: []string{"data", "config.yaml"}},
+		{path: "data['config.yaml']", want: []string{"data", "config.yaml"}},
+		{path: "metadata.annotations[example.com/owner].x", want: []string{"metadata", "annotations",
+			"example.com/owner", "x"}},
+		{path: "", wantErr: true},
+		{path: "spec..replicas", wantErr: true},
+		{path: "spec.", wantErr: true},
+		{path: "data[config.yaml", wantErr: true},
+		{path: "data[]", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParseFieldPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFieldPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFieldPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePreservedFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   *Overrides
+		errContains string
+	}{
+		{
+			name: "No overrides - valid",
+		},
+		{
+			name: "Valid paths",
+			overrides: &Overrides{PreservedFields: []PreservedFields{
+				{Kind: "Deployment", Name: "console", Paths: []string{"spec.replicas", `data["config.yaml"]`}},
+			}},
+		},
+		{
+			name: "Invalid path",
+			overrides: &Overrides{PreservedFields: []PreservedFields{
+				{Kind: "Deployment", Name: "console", Paths: []string{"spec.replicas"}},
+				{Kind: "ConfigMap", Name: "config", Paths: []string{"data", "data[config.yaml"}},
+			}},
+			errContains: "spec.overrides.preservedFields[1].paths[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mch := &MultiClusterHub{Spec: MultiClusterHubSpec{Overrides: tt.overrides}}
+			err := validatePreservedFields(mch)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("validatePreservedFields() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("validatePreservedFields() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
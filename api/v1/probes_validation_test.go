@@ -0,0 +1,153 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func TestValidateProbes(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		probes      *ProbesConfig
+		errContains string
+	}{
+		{
+			name: "No probes - valid",
+		},
+		{
+			name: "Global and per-deployment tuning - valid",
+			probes: &ProbesConfig{
+				Global: &ProbeSettings{
+					Liveness:  &ProbeTuning{TimeoutSeconds: int32Ptr(5), InitialDelaySeconds: int32Ptr(0)},
+					Readiness: &ProbeTuning{SuccessThreshold: int32Ptr(2)},
+				},
+				Components: []ComponentProbeConfig{
+					{
+						Name: GRC,
+						Deployments: []DeploymentProbeConfig{
+							{Name: "grc-policy-propagator", ProbeSettings: ProbeSettings{
+								Readiness: &ProbeTuning{PeriodSeconds: int32Ptr(30)},
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Liveness success threshold other than 1",
+			probes: &ProbesConfig{
+				Global: &ProbeSettings{Liveness: &ProbeTuning{SuccessThreshold: int32Ptr(2)}},
+			},
+			errContains: "spec.probes.global.liveness.successThreshold: must be 1",
+		},
+		{
+			name: "Zero period",
+			probes: &ProbesConfig{
+				Components: []ComponentProbeConfig{
+					{Name: Search, ProbeSettings: ProbeSettings{Readiness: &ProbeTuning{PeriodSeconds: int32Ptr(0)}}},
+				},
+			},
+			errContains: "spec.probes.components[0].readiness.periodSeconds: must be at least 1",
+		},
+		{
+			name: "Negative initial delay",
+			probes: &ProbesConfig{
+				Global: &ProbeSettings{Readiness: &ProbeTuning{InitialDelaySeconds: int32Ptr(-1)}},
+			},
+			errContains: "initialDelaySeconds: must not be negative",
+		},
+		{
+			name: "Unknown component",
+			probes: &ProbesConfig{
+				Components: []ComponentProbeConfig{{Name: "not-a-component"}},
+			},
+			errContains: "is not a known component",
+		},
+		{
+			name: "Duplicate deployment",
+			probes: &ProbesConfig{
+				Components: []ComponentProbeConfig{
+					{Name: GRC, Deployments: []DeploymentProbeConfig{{Name: "a"}, {Name: "a"}}},
+				},
+			},
+			errContains: "spec.probes.components[0].deployments[1].name: deployment \"a\" is listed more than once",
+		},
+		{
+			name:        "Invalid probe annotation",
+			annotations: map[string]string{annotationProbeTimeoutSeconds: "abc"},
+			errContains: "must be a positive integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mch := &MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       MultiClusterHubSpec{Probes: tt.probes},
+			}
+			err := validateProbes(mch)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("validateProbes() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("validateProbes() error = %v, want error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestEffectiveProbeSettings(t *testing.T) {
+	mch := &MultiClusterHub{
+		Spec: MultiClusterHubSpec{
+			Probes: &ProbesConfig{
+				Global: &ProbeSettings{
+					Liveness:  &ProbeTuning{TimeoutSeconds: int32Ptr(5), FailureThreshold: int32Ptr(3)},
+					Readiness: &ProbeTuning{TimeoutSeconds: int32Ptr(5)},
+				},
+				Components: []ComponentProbeConfig{
+					{
+						Name: GRC,
+						ProbeSettings: ProbeSettings{
+							Liveness: &ProbeTuning{TimeoutSeconds: int32Ptr(10)},
+						},
+						Deployments: []DeploymentProbeConfig{
+							{Name: "grc-policy-propagator", ProbeSettings: ProbeSettings{
+								Liveness: &ProbeTuning{FailureThreshold: int32Ptr(6)},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := mch.EffectiveProbeSettings(GRC, "grc-policy-propagator")
+	if *got.Liveness.TimeoutSeconds != 10 || *got.Liveness.FailureThreshold != 6 {
+		t.Errorf("liveness = %+v, want timeout from component (10) and failure threshold from deployment (6)",
+			*got.Liveness)
+	}
+	if *got.Readiness.TimeoutSeconds != 5 {
+		t.Errorf("readiness timeout = %d, want global value 5", *got.Readiness.TimeoutSeconds)
+	}
+
+	other := mch.EffectiveProbeSettings(Search, "search-v2-operator")
+	if *other.Liveness.TimeoutSeconds != 5 || *other.Liveness.FailureThreshold != 3 {
+		t.Errorf("liveness for other component = %+v, want global settings", *other.Liveness)
+	}
+
+	// Merging must not alias the spec
+	*got.Liveness.TimeoutSeconds = 99
+	if *mch.Spec.Probes.Components[0].Liveness.TimeoutSeconds != 10 {
+		t.Error("EffectiveProbeSettings returned settings sharing memory with the spec")
+	}
+}
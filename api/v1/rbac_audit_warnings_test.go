@@ -0,0 +1,54 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRBACAuditWarnings(t *testing.T) {
+	old := &MultiClusterHub{
+		Status: MultiClusterHubStatus{
+			RBACAudit: &RBACAuditStatus{
+				Components: []ComponentRBACAudit{
+					{
+						Name:               Console,
+						MissingPermissions: []string{"a", "b", "c", "d", "e", "f", "g"},
+					},
+					{
+						Name:          GRC,
+						WildcardRules: []string{"ClusterRole grc: apiGroups=[*] resources=[*] verbs=[get]"},
+					},
+					{
+						Name:               Search,
+						MissingPermissions: []string{"create deployments.apps"},
+					},
+				},
+			},
+		},
+	}
+	hub := &MultiClusterHub{
+		Spec: MultiClusterHubSpec{
+			Overrides: &Overrides{
+				Components: []ComponentConfig{
+					{Name: Console, Enabled: true},
+					{Name: GRC, Enabled: true},
+					{Name: Search, Enabled: false},
+				},
+			},
+		},
+	}
+
+	want := []string{
+		"component console needs permissions the operator is not granted: a, b, c, d, e and 2 more",
+		"component grc installs a wildcard rule: ClusterRole grc: apiGroups=[*] resources=[*] verbs=[get]",
+	}
+	if got := rbacAuditWarnings(old, hub); !reflect.DeepEqual([]string(got), want) {
+		t.Errorf("rbacAuditWarnings() = %v, want %v", got, want)
+	}
+
+	if got := rbacAuditWarnings(&MultiClusterHub{}, hub); len(got) != 0 {
+		t.Errorf("rbacAuditWarnings() without an audit = %v, want none", got)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateTemplateOverrides(t *testing.T) {
+	t.Setenv("TEMPLATES_PATH", "../../pkg/templates")
+
+	tests := []struct {
+		name         string
+		configmap    string
+		data         string
+		wantErr      bool
+		errContains  string
+		wantWarnings int
+	}{
+		{
+			name:      "No template override configmap",
+			configmap: "",
+		},
+		{
+			name:      "Supported keys",
+			configmap: "overrides",
+			data:      `{"templateOverrides": {"console_deployment_container_memory_limit": "1Gi"}}`,
+		},
+		{
+			name:        "Unknown key",
+			configmap:   "overrides",
+			data:        `{"templateOverrides": {"console_deployment_container_memory_limt": "1Gi"}}`,
+			wantErr:     true,
+			errContains: "console_deployment_container_memory_limt",
+		},
+		{
+			name:         "Invalid value is a warning",
+			configmap:    "overrides",
+			data:         `{"templateOverrides": {"console_deployment_container_cpu_limit": "fast"}}`,
+			wantWarnings: 1,
+		},
+		{
+			name:         "Missing configmap is a warning",
+			configmap:    "missing",
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+
+			objects := []runtime.Object{}
+			if tt.data != "" {
+				objects = append(objects, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: tt.configmap, Namespace: "open-cluster-management"},
+					Data:       map[string]string{"overrides.json": tt.data},
+				})
+			}
+			Client = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(objects...).
+				Build()
+
+			mch := &MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+			}
+			if tt.configmap != "" {
+				mch.SetAnnotations(map[string]string{annotationTemplateOverridesCM: tt.configmap})
+			}
+
+			warnings, err := validateTemplateOverrides(context.Background(), mch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateTemplateOverrides() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("validateTemplateOverrides() error = %v, want it to contain %q", err, tt.errContains)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("validateTemplateOverrides() warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
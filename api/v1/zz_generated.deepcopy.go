@@ -22,298 +22,251 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BlockDeletionResource) DeepCopyInto(out *BlockDeletionResource) {
+func (in *AdoptNowStatus) DeepCopyInto(out *AdoptNowStatus) {
 	*out = *in
-	out.GVK = in.GVK
-	if in.NameExceptions != nil {
-		in, out := &in.NameExceptions, &out.NameExceptions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.LabelExceptions != nil {
-		in, out := &in.LabelExceptions, &out.LabelExceptions
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockDeletionResource.
-func (in *BlockDeletionResource) DeepCopy() *BlockDeletionResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptNowStatus.
+func (in *AdoptNowStatus) DeepCopy() *AdoptNowStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(BlockDeletionResource)
+	out := new(AdoptNowStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentConfig) DeepCopyInto(out *ComponentConfig) {
+func (in *AdoptionCandidate) DeepCopyInto(out *AdoptionCandidate) {
 	*out = *in
-	in.ConfigOverrides.DeepCopyInto(&out.ConfigOverrides)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentConfig.
-func (in *ComponentConfig) DeepCopy() *ComponentConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptionCandidate.
+func (in *AdoptionCandidate) DeepCopy() *AdoptionCandidate {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentConfig)
+	out := new(AdoptionCandidate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigOverride) DeepCopyInto(out *ConfigOverride) {
+func (in *AdoptionConfig) DeepCopyInto(out *AdoptionConfig) {
 	*out = *in
-	if in.Deployments != nil {
-		in, out := &in.Deployments, &out.Deployments
-		*out = make([]DeploymentConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]AdoptionRule, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigOverride.
-func (in *ConfigOverride) DeepCopy() *ConfigOverride {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptionConfig.
+func (in *AdoptionConfig) DeepCopy() *AdoptionConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigOverride)
+	out := new(AdoptionConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerConfig) DeepCopyInto(out *ContainerConfig) {
+func (in *AdoptionRule) DeepCopyInto(out *AdoptionRule) {
 	*out = *in
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]EnvConfig, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerConfig.
-func (in *ContainerConfig) DeepCopy() *ContainerConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptionRule.
+func (in *AdoptionRule) DeepCopy() *AdoptionRule {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerConfig)
+	out := new(AdoptionRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeploymentConfig) DeepCopyInto(out *DeploymentConfig) {
+func (in *AdoptionStatus) DeepCopyInto(out *AdoptionStatus) {
 	*out = *in
-	if in.Containers != nil {
-		in, out := &in.Containers, &out.Containers
-		*out = make([]ContainerConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]AdoptionCandidate, len(*in))
+		copy(*out, *in)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfig.
-func (in *DeploymentConfig) DeepCopy() *DeploymentConfig {
-	if in == nil {
-		return nil
+	if in.LastAdoptNow != nil {
+		in, out := &in.LastAdoptNow, &out.LastAdoptNow
+		*out = new(AdoptNowStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(DeploymentConfig)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvConfig) DeepCopyInto(out *EnvConfig) {
-	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvConfig.
-func (in *EnvConfig) DeepCopy() *EnvConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptionStatus.
+func (in *AdoptionStatus) DeepCopy() *AdoptionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvConfig)
+	out := new(AdoptionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HubCondition) DeepCopyInto(out *HubCondition) {
+func (in *BlockDeletionResource) DeepCopyInto(out *BlockDeletionResource) {
 	*out = *in
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	out.GVK = in.GVK
+	if in.NameExceptions != nil {
+		in, out := &in.NameExceptions, &out.NameExceptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelExceptions != nil {
+		in, out := &in.LabelExceptions, &out.LabelExceptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HubCondition.
-func (in *HubCondition) DeepCopy() *HubCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockDeletionResource.
+func (in *BlockDeletionResource) DeepCopy() *BlockDeletionResource {
 	if in == nil {
 		return nil
 	}
-	out := new(HubCondition)
+	out := new(BlockDeletionResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalHubComponent) DeepCopyInto(out *InternalHubComponent) {
+func (in *CRDLifecycleStatus) DeepCopyInto(out *CRDLifecycleStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]CRDStorageMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RetainedCRDs != nil {
+		in, out := &in.RetainedCRDs, &out.RetainedCRDs
+		*out = make([]RetainedCRD, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalHubComponent.
-func (in *InternalHubComponent) DeepCopy() *InternalHubComponent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CRDLifecycleStatus.
+func (in *CRDLifecycleStatus) DeepCopy() *CRDLifecycleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalHubComponent)
+	out := new(CRDLifecycleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InternalHubComponent) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalHubComponentList) DeepCopyInto(out *InternalHubComponentList) {
+func (in *CRDStorageMigration) DeepCopyInto(out *CRDStorageMigration) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]InternalHubComponent, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.StoredVersions != nil {
+		in, out := &in.StoredVersions, &out.StoredVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalHubComponentList.
-func (in *InternalHubComponentList) DeepCopy() *InternalHubComponentList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CRDStorageMigration.
+func (in *CRDStorageMigration) DeepCopy() *CRDStorageMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalHubComponentList)
+	out := new(CRDStorageMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *InternalHubComponentList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InternalHubComponentSpec) DeepCopyInto(out *InternalHubComponentSpec) {
+func (in *ClusterCapabilitiesStatus) DeepCopyInto(out *ClusterCapabilitiesStatus) {
 	*out = *in
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalHubComponentSpec.
-func (in *InternalHubComponentSpec) DeepCopy() *InternalHubComponentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapabilitiesStatus.
+func (in *ClusterCapabilitiesStatus) DeepCopy() *ClusterCapabilitiesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InternalHubComponentSpec)
+	out := new(ClusterCapabilitiesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCEVersionComplianceStatus) DeepCopyInto(out *MCEVersionComplianceStatus) {
+func (in *ComponentCleanupStatus) DeepCopyInto(out *ComponentCleanupStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCEVersionComplianceStatus.
-func (in *MCEVersionComplianceStatus) DeepCopy() *MCEVersionComplianceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentCleanupStatus.
+func (in *ComponentCleanupStatus) DeepCopy() *ComponentCleanupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MCEVersionComplianceStatus)
+	out := new(ComponentCleanupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiClusterHub) DeepCopyInto(out *MultiClusterHub) {
+func (in *ComponentConfig) DeepCopyInto(out *ComponentConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ConfigOverrides.DeepCopyInto(&out.ConfigOverrides)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterHub.
-func (in *MultiClusterHub) DeepCopy() *MultiClusterHub {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentConfig.
+func (in *ComponentConfig) DeepCopy() *ComponentConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiClusterHub)
+	out := new(ComponentConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MultiClusterHub) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiClusterHubList) DeepCopyInto(out *MultiClusterHubList) {
+func (in *ComponentOperationResult) DeepCopyInto(out *ComponentOperationResult) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]MultiClusterHub, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.FailingObjects != nil {
+		in, out := &in.FailingObjects, &out.FailingObjects
+		*out = make([]FailingObjectReference, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterHubList.
-func (in *MultiClusterHubList) DeepCopy() *MultiClusterHubList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentOperationResult.
+func (in *ComponentOperationResult) DeepCopy() *ComponentOperationResult {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiClusterHubList)
+	out := new(ComponentOperationResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MultiClusterHubList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiClusterHubSpec) DeepCopyInto(out *MultiClusterHubSpec) {
+func (in *ComponentPlacement) DeepCopyInto(out *ComponentPlacement) {
 	*out = *in
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
@@ -329,106 +282,1086 @@ func (in *MultiClusterHubSpec) DeepCopyInto(out *MultiClusterHubSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Overrides != nil {
-		in, out := &in.Overrides, &out.Overrides
-		*out = new(Overrides)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NetworkPolicies != nil {
-		in, out := &in.NetworkPolicies, &out.NetworkPolicies
-		*out = new(NetworkPoliciesConfig)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterHubSpec.
-func (in *MultiClusterHubSpec) DeepCopy() *MultiClusterHubSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPlacement.
+func (in *ComponentPlacement) DeepCopy() *ComponentPlacement {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiClusterHubSpec)
+	out := new(ComponentPlacement)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiClusterHubStatus) DeepCopyInto(out *MultiClusterHubStatus) {
+func (in *ComponentProbeConfig) DeepCopyInto(out *ComponentProbeConfig) {
 	*out = *in
-	if in.HubConditions != nil {
-		in, out := &in.HubConditions, &out.HubConditions
-		*out = make([]HubCondition, len(*in))
+	in.ProbeSettings.DeepCopyInto(&out.ProbeSettings)
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make([]DeploymentProbeConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Components != nil {
-		in, out := &in.Components, &out.Components
-		*out = make(map[string]StatusCondition, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.MCEVersionCompliance != nil {
-		in, out := &in.MCEVersionCompliance, &out.MCEVersionCompliance
-		*out = new(MCEVersionComplianceStatus)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterHubStatus.
-func (in *MultiClusterHubStatus) DeepCopy() *MultiClusterHubStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentProbeConfig.
+func (in *ComponentProbeConfig) DeepCopy() *ComponentProbeConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiClusterHubStatus)
+	out := new(ComponentProbeConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkPoliciesConfig) DeepCopyInto(out *NetworkPoliciesConfig) {
+func (in *ComponentRBACAudit) DeepCopyInto(out *ComponentRBACAudit) {
 	*out = *in
+	if in.MissingPermissions != nil {
+		in, out := &in.MissingPermissions, &out.MissingPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WildcardRules != nil {
+		in, out := &in.WildcardRules, &out.WildcardRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPoliciesConfig.
-func (in *NetworkPoliciesConfig) DeepCopy() *NetworkPoliciesConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentRBACAudit.
+func (in *ComponentRBACAudit) DeepCopy() *ComponentRBACAudit {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkPoliciesConfig)
+	out := new(ComponentRBACAudit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Overrides) DeepCopyInto(out *Overrides) {
+func (in *ComponentReconcileStatus) DeepCopyInto(out *ComponentReconcileStatus) {
 	*out = *in
-	if in.Components != nil {
-		in, out := &in.Components, &out.Components
-		*out = make([]ComponentConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.LastRender != nil {
+		in, out := &in.LastRender, &out.LastRender
+		*out = new(ComponentOperationResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastApply != nil {
+		in, out := &in.LastApply, &out.LastApply
+		*out = new(ComponentOperationResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TemplateOverrides != nil {
+		in, out := &in.TemplateOverrides, &out.TemplateOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
+	if in.StatusWorkloads != nil {
+		in, out := &in.StatusWorkloads, &out.StatusWorkloads
+		*out = make([]StatusWorkloadReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Overrides.
-func (in *Overrides) DeepCopy() *Overrides {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentReconcileStatus.
+func (in *ComponentReconcileStatus) DeepCopy() *ComponentReconcileStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Overrides)
+	out := new(ComponentReconcileStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceGVK) DeepCopyInto(out *ResourceGVK) {
+func (in *ComponentRolloutStatus) DeepCopyInto(out *ComponentRolloutStatus) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceGVK.
-func (in *ResourceGVK) DeepCopy() *ResourceGVK {
+	if in.Current != nil {
+		in, out := &in.Current, &out.Current
+		*out = new(RolloutDeployment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Updated != nil {
+		in, out := &in.Updated, &out.Updated
+		*out = make([]RolloutDeployment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RolledBackComponents != nil {
+		in, out := &in.RolledBackComponents, &out.RolledBackComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentRolloutStatus.
+func (in *ComponentRolloutStatus) DeepCopy() *ComponentRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigOverride) DeepCopyInto(out *ConfigOverride) {
+	*out = *in
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make([]DeploymentConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigOverride.
+func (in *ConfigOverride) DeepCopy() *ConfigOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerConfig) DeepCopyInto(out *ContainerConfig) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]EnvConfig, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerConfig.
+func (in *ContainerConfig) DeepCopy() *ContainerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentConfig) DeepCopyInto(out *DeploymentConfig) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ContainerConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfig.
+func (in *DeploymentConfig) DeepCopy() *DeploymentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentProbeConfig) DeepCopyInto(out *DeploymentProbeConfig) {
+	*out = *in
+	in.ProbeSettings.DeepCopyInto(&out.ProbeSettings)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentProbeConfig.
+func (in *DeploymentProbeConfig) DeepCopy() *DeploymentProbeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentProbeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvConfig) DeepCopyInto(out *EnvConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvConfig.
+func (in *EnvConfig) DeepCopy() *EnvConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailingObjectReference) DeepCopyInto(out *FailingObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailingObjectReference.
+func (in *FailingObjectReference) DeepCopy() *FailingObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(FailingObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HubCondition) DeepCopyInto(out *HubCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HubCondition.
+func (in *HubCondition) DeepCopy() *HubCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(HubCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HubHealthStatus) DeepCopyInto(out *HubHealthStatus) {
+	*out = *in
+	if in.UnavailableComponents != nil {
+		in, out := &in.UnavailableComponents, &out.UnavailableComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = make([]HubSLOWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HubHealthStatus.
+func (in *HubHealthStatus) DeepCopy() *HubHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HubHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HubProfile) DeepCopyInto(out *HubProfile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HubProfile.
+func (in *HubProfile) DeepCopy() *HubProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(HubProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HubSLOWindow) DeepCopyInto(out *HubSLOWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HubSLOWindow.
+func (in *HubSLOWindow) DeepCopy() *HubSLOWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(HubSLOWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalHubComponent) DeepCopyInto(out *InternalHubComponent) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalHubComponent.
+func (in *InternalHubComponent) DeepCopy() *InternalHubComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalHubComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InternalHubComponent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalHubComponentList) DeepCopyInto(out *InternalHubComponentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InternalHubComponent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalHubComponentList.
+func (in *InternalHubComponentList) DeepCopy() *InternalHubComponentList {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalHubComponentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InternalHubComponentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalHubComponentSpec) DeepCopyInto(out *InternalHubComponentSpec) {
+	*out = *in
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(ComponentPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalHubComponentSpec.
+func (in *InternalHubComponentSpec) DeepCopy() *InternalHubComponentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalHubComponentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalHubComponentStatus) DeepCopyInto(out *InternalHubComponentStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(ComponentCleanupStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalHubComponentStatus.
+func (in *InternalHubComponentStatus) DeepCopy() *InternalHubComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalHubComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalClusterAddonConfig) DeepCopyInto(out *LocalClusterAddonConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalClusterAddonConfig.
+func (in *LocalClusterAddonConfig) DeepCopy() *LocalClusterAddonConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalClusterAddonConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalClusterConfig) DeepCopyInto(out *LocalClusterConfig) {
+	*out = *in
+	if in.ApplicationManager != nil {
+		in, out := &in.ApplicationManager, &out.ApplicationManager
+		*out = new(LocalClusterAddonConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertPolicyController != nil {
+		in, out := &in.CertPolicyController, &out.CertPolicyController
+		*out = new(LocalClusterAddonConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyController != nil {
+		in, out := &in.PolicyController, &out.PolicyController
+		*out = new(LocalClusterAddonConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SearchCollector != nil {
+		in, out := &in.SearchCollector, &out.SearchCollector
+		*out = new(LocalClusterAddonConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProxyConfig != nil {
+		in, out := &in.ProxyConfig, &out.ProxyConfig
+		*out = new(LocalClusterProxyConfig)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalClusterConfig.
+func (in *LocalClusterConfig) DeepCopy() *LocalClusterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalClusterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalClusterProxyConfig) DeepCopyInto(out *LocalClusterProxyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalClusterProxyConfig.
+func (in *LocalClusterProxyConfig) DeepCopy() *LocalClusterProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalClusterProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCEOLMMigrationStatus) DeepCopyInto(out *MCEOLMMigrationStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]OLMMigrationStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCEOLMMigrationStatus.
+func (in *MCEOLMMigrationStatus) DeepCopy() *MCEOLMMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCEOLMMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCEVersionComplianceStatus) DeepCopyInto(out *MCEVersionComplianceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCEVersionComplianceStatus.
+func (in *MCEVersionComplianceStatus) DeepCopy() *MCEVersionComplianceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCEVersionComplianceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterHub) DeepCopyInto(out *MultiClusterHub) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterHub.
+func (in *MultiClusterHub) DeepCopy() *MultiClusterHub {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterHub)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MultiClusterHub) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterHubList) DeepCopyInto(out *MultiClusterHubList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MultiClusterHub, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterHubList.
+func (in *MultiClusterHubList) DeepCopy() *MultiClusterHubList {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterHubList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MultiClusterHubList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterHubSpec) DeepCopyInto(out *MultiClusterHubSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(Overrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = new(NetworkPoliciesConfig)
+		**out = **in
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustedCA != nil {
+		in, out := &in.TrustedCA, &out.TrustedCA
+		*out = new(TrustedCAConfig)
+		**out = **in
+	}
+	if in.Adoption != nil {
+		in, out := &in.Adoption, &out.Adoption
+		*out = new(AdoptionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalCluster != nil {
+		in, out := &in.LocalCluster, &out.LocalCluster
+		*out = new(LocalClusterConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(HubProfile)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterHubSpec.
+func (in *MultiClusterHubSpec) DeepCopy() *MultiClusterHubSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterHubSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiClusterHubStatus) DeepCopyInto(out *MultiClusterHubStatus) {
+	*out = *in
+	if in.HubConditions != nil {
+		in, out := &in.HubConditions, &out.HubConditions
+		*out = make([]HubCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make(map[string]StatusCondition, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.MCEVersionCompliance != nil {
+		in, out := &in.MCEVersionCompliance, &out.MCEVersionCompliance
+		*out = new(MCEVersionComplianceStatus)
+		**out = **in
+	}
+	if in.MCEOLMMigration != nil {
+		in, out := &in.MCEOLMMigration, &out.MCEOLMMigration
+		*out = new(MCEOLMMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ClusterCapabilitiesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentReconcile != nil {
+		in, out := &in.ComponentReconcile, &out.ComponentReconcile
+		*out = make(map[string]ComponentReconcileStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.TrustBundle != nil {
+		in, out := &in.TrustBundle, &out.TrustBundle
+		*out = new(TrustBundleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Adoption != nil {
+		in, out := &in.Adoption, &out.Adoption
+		*out = new(AdoptionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RejectedTemplateOverrides != nil {
+		in, out := &in.RejectedTemplateOverrides, &out.RejectedTemplateOverrides
+		*out = make([]RejectedTemplateOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.OverrideRevisions != nil {
+		in, out := &in.OverrideRevisions, &out.OverrideRevisions
+		*out = new(OverrideRevisionsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentRollout != nil {
+		in, out := &in.ComponentRollout, &out.ComponentRollout
+		*out = new(ComponentRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(HubHealthStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSProfile != nil {
+		in, out := &in.TLSProfile, &out.TLSProfile
+		*out = new(TLSProfileStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(ProfileStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RBACAudit != nil {
+		in, out := &in.RBACAudit, &out.RBACAudit
+		*out = new(RBACAuditStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restricted != nil {
+		in, out := &in.Restricted, &out.Restricted
+		*out = new(RestrictedModeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CRDLifecycle != nil {
+		in, out := &in.CRDLifecycle, &out.CRDLifecycle
+		*out = new(CRDLifecycleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceBackends != nil {
+		in, out := &in.ServiceBackends, &out.ServiceBackends
+		*out = make([]ServiceBackendStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiClusterHubStatus.
+func (in *MultiClusterHubStatus) DeepCopy() *MultiClusterHubStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiClusterHubStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPoliciesConfig) DeepCopyInto(out *NetworkPoliciesConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPoliciesConfig.
+func (in *NetworkPoliciesConfig) DeepCopy() *NetworkPoliciesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPoliciesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OLMMigrationStep) DeepCopyInto(out *OLMMigrationStep) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OLMMigrationStep.
+func (in *OLMMigrationStep) DeepCopy() *OLMMigrationStep {
+	if in == nil {
+		return nil
+	}
+	out := new(OLMMigrationStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverrideRevision) DeepCopyInto(out *OverrideRevision) {
+	*out = *in
+	in.AppliedTime.DeepCopyInto(&out.AppliedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverrideRevision.
+func (in *OverrideRevision) DeepCopy() *OverrideRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(OverrideRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverrideRevisionsStatus) DeepCopyInto(out *OverrideRevisionsStatus) {
+	*out = *in
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = new(OverrideRevision)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TemplateOverrides != nil {
+		in, out := &in.TemplateOverrides, &out.TemplateOverrides
+		*out = new(OverrideRevision)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverrideRevisionsStatus.
+func (in *OverrideRevisionsStatus) DeepCopy() *OverrideRevisionsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OverrideRevisionsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Overrides) DeepCopyInto(out *Overrides) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreservedFields != nil {
+		in, out := &in.PreservedFields, &out.PreservedFields
+		*out = make([]PreservedFields, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Overrides.
+func (in *Overrides) DeepCopy() *Overrides {
+	if in == nil {
+		return nil
+	}
+	out := new(Overrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreservedFields) DeepCopyInto(out *PreservedFields) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreservedFields.
+func (in *PreservedFields) DeepCopy() *PreservedFields {
+	if in == nil {
+		return nil
+	}
+	out := new(PreservedFields)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeSettings) DeepCopyInto(out *ProbeSettings) {
+	*out = *in
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(ProbeTuning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ProbeTuning)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSettings.
+func (in *ProbeSettings) DeepCopy() *ProbeSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeTuning) DeepCopyInto(out *ProbeTuning) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTuning.
+func (in *ProbeTuning) DeepCopy() *ProbeTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbesConfig) DeepCopyInto(out *ProbesConfig) {
+	*out = *in
+	if in.Global != nil {
+		in, out := &in.Global, &out.Global
+		*out = new(ProbeSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentProbeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesConfig.
+func (in *ProbesConfig) DeepCopy() *ProbesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileStatus) DeepCopyInto(out *ProfileStatus) {
+	*out = *in
+	if in.EnabledComponents != nil {
+		in, out := &in.EnabledComponents, &out.EnabledComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisabledComponents != nil {
+		in, out := &in.DisabledComponents, &out.DisabledComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OverriddenComponents != nil {
+		in, out := &in.OverriddenComponents, &out.OverriddenComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingComponents != nil {
+		in, out := &in.PendingComponents, &out.PendingComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileStatus.
+func (in *ProfileStatus) DeepCopy() *ProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyStatus) DeepCopyInto(out *ProxyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyStatus.
+func (in *ProxyStatus) DeepCopy() *ProxyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACAuditStatus) DeepCopyInto(out *RBACAuditStatus) {
+	*out = *in
+	in.LastAuditTime.DeepCopyInto(&out.LastAuditTime)
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentRBACAudit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACAuditStatus.
+func (in *RBACAuditStatus) DeepCopy() *RBACAuditStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACAuditStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RejectedTemplateOverride) DeepCopyInto(out *RejectedTemplateOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RejectedTemplateOverride.
+func (in *RejectedTemplateOverride) DeepCopy() *RejectedTemplateOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(RejectedTemplateOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceGVK) DeepCopyInto(out *ResourceGVK) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceGVK.
+func (in *ResourceGVK) DeepCopy() *ResourceGVK {
 	if in == nil {
 		return nil
 	}
@@ -437,6 +1370,105 @@ func (in *ResourceGVK) DeepCopy() *ResourceGVK {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestrictedModeBundle) DeepCopyInto(out *RestrictedModeBundle) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Missing != nil {
+		in, out := &in.Missing, &out.Missing
+		*out = make([]FailingObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestrictedModeBundle.
+func (in *RestrictedModeBundle) DeepCopy() *RestrictedModeBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(RestrictedModeBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestrictedModeStatus) DeepCopyInto(out *RestrictedModeStatus) {
+	*out = *in
+	if in.Bundles != nil {
+		in, out := &in.Bundles, &out.Bundles
+		*out = make([]RestrictedModeBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestrictedModeStatus.
+func (in *RestrictedModeStatus) DeepCopy() *RestrictedModeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestrictedModeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetainedCRD) DeepCopyInto(out *RetainedCRD) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetainedCRD.
+func (in *RetainedCRD) DeepCopy() *RetainedCRD {
+	if in == nil {
+		return nil
+	}
+	out := new(RetainedCRD)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutDeployment) DeepCopyInto(out *RolloutDeployment) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutDeployment.
+func (in *RolloutDeployment) DeepCopy() *RolloutDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceBackendStatus) DeepCopyInto(out *ServiceBackendStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceBackendStatus.
+func (in *ServiceBackendStatus) DeepCopy() *ServiceBackendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceBackendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusCondition) DeepCopyInto(out *StatusCondition) {
 	*out = *in
@@ -453,3 +1485,85 @@ func (in *StatusCondition) DeepCopy() *StatusCondition {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusWorkloadReference) DeepCopyInto(out *StatusWorkloadReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusWorkloadReference.
+func (in *StatusWorkloadReference) DeepCopy() *StatusWorkloadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusWorkloadReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSProfileStatus) DeepCopyInto(out *TLSProfileStatus) {
+	*out = *in
+	if in.Ciphers != nil {
+		in, out := &in.Ciphers, &out.Ciphers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSProfileStatus.
+func (in *TLSProfileStatus) DeepCopy() *TLSProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustBundleStatus) DeepCopyInto(out *TrustBundleStatus) {
+	*out = *in
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxyStatus)
+		**out = **in
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftedNamespaces != nil {
+		in, out := &in.DriftedNamespaces, &out.DriftedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustBundleStatus.
+func (in *TrustBundleStatus) DeepCopy() *TrustBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedCAConfig) DeepCopyInto(out *TrustedCAConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCAConfig.
+func (in *TrustedCAConfig) DeepCopy() *TrustedCAConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedCAConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,394 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v2
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	v1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+const (
+	annotationIgnoreOCPVersion         = "installer.open-cluster-management.io/ignore-ocp-version"
+	annotationImageOverridesCM         = "installer.open-cluster-management.io/image-overrides-configmap"
+	annotationImageRepo                = "installer.open-cluster-management.io/image-repository"
+	annotationKubeconfig               = "installer.open-cluster-management.io/kubeconfig"
+	annotationMCHPause                 = "installer.open-cluster-management.io/pause"
+	annotationMCESubscriptionSpec      = "installer.open-cluster-management.io/mce-subscription-spec"
+	annotationMCEClusterExtensionSpec  = "installer.open-cluster-management.io/mce-clusterextension-spec"
+	annotationMCEOLMVersion            = "installer.open-cluster-management.io/mce-olm-version"
+	annotationOADPSubscriptionSpec     = "installer.open-cluster-management.io/oadp-subscription-spec"
+	annotationOADPClusterExtensionSpec = "installer.open-cluster-management.io/oadp-clusterextension-spec"
+	annotationOADPOLMVersion           = "installer.open-cluster-management.io/oadp-olm-version"
+	annotationTemplateOverridesCM      = "installer.open-cluster-management.io/template-override-configmap"
+	annotationResourceAdoptionPolicy   = "installer.open-cluster-management.io/resource-adoption-policy"
+	annotationRemoveDisabledCRDs       = "installer.open-cluster-management.io/remove-disabled-crds"
+
+	/*
+		AnnotationV1Values records the original v1 annotation values whose typed v2 representation would
+		serialize differently (e.g. "True" for the pause annotation, or reordered JSON). Converting back to
+		v1 restores the original value as long as the typed field was not changed.
+	*/
+	AnnotationV1Values = "operator.open-cluster-management.io/v1-annotation-values"
+)
+
+// annotationField maps a v1 annotation onto a typed v2 spec field
+type annotationField struct {
+	key string
+	// decode sets the spec field from the annotation value, returning false if the value cannot be
+	// represented by the typed field. The spec is left untouched in that case.
+	decode func(value string, spec *MultiClusterHubSpec) bool
+	// encode returns the annotation value for the spec field and whether the annotation should be set
+	encode func(spec *MultiClusterHubSpec) (string, bool)
+}
+
+var annotationFields = []annotationField{
+	{
+		key: annotationMCHPause,
+		decode: func(value string, spec *MultiClusterHubSpec) bool {
+			spec.Paused = strings.EqualFold(value, "true")
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) { return "true", spec.Paused },
+	},
+	{
+		key: annotationIgnoreOCPVersion,
+		decode: func(_ string, spec *MultiClusterHubSpec) bool {
+			// the annotation is honored whenever it is present, regardless of value
+			spec.IgnoreOCPVersion = true
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) { return "true", spec.IgnoreOCPVersion },
+	},
+	stringField(annotationImageRepo, func(spec *MultiClusterHubSpec) *string { return &spec.ImageRepository }),
+	stringField(annotationImageOverridesCM, func(spec *MultiClusterHubSpec) *string {
+		return &spec.ImageOverridesConfigMap
+	}),
+	stringField(annotationTemplateOverridesCM, func(spec *MultiClusterHubSpec) *string {
+		return &spec.TemplateOverridesConfigMap
+	}),
+	stringField(annotationKubeconfig, func(spec *MultiClusterHubSpec) *string { return &spec.KubeconfigSecret }),
+	{
+		key: annotationResourceAdoptionPolicy,
+		decode: func(value string, spec *MultiClusterHubSpec) bool {
+			policy := ResourceAdoptionPolicy(value)
+			if policy != AdoptionStrict && policy != AdoptionAdopt {
+				return false
+			}
+			spec.ResourceAdoptionPolicy = policy
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) {
+			return string(spec.ResourceAdoptionPolicy), spec.ResourceAdoptionPolicy != ""
+		},
+	},
+	{
+		key: annotationRemoveDisabledCRDs,
+		decode: func(value string, spec *MultiClusterHubSpec) bool {
+			spec.RemoveDisabledCRDs = nil
+			for _, component := range strings.Split(value, ",") {
+				if component = strings.TrimSpace(component); component != "" {
+					spec.RemoveDisabledCRDs = append(spec.RemoveDisabledCRDs, component)
+				}
+			}
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) {
+			return strings.Join(spec.RemoveDisabledCRDs, ","), len(spec.RemoveDisabledCRDs) > 0
+		},
+	},
+	{
+		key: annotationMCEOLMVersion,
+		decode: func(value string, spec *MultiClusterHubSpec) bool {
+			if value != "v0" && value != "v1" {
+				return false
+			}
+			mceConfig(spec).OLMVersion = value
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) {
+			if spec.MultiClusterEngine == nil {
+				return "", false
+			}
+			return spec.MultiClusterEngine.OLMVersion, spec.MultiClusterEngine.OLMVersion != ""
+		},
+	},
+	{
+		key: annotationOADPOLMVersion,
+		decode: func(value string, spec *MultiClusterHubSpec) bool {
+			if value != "v0" && value != "v1" {
+				return false
+			}
+			oadpConfig(spec).OLMVersion = value
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) {
+			if spec.OADP == nil {
+				return "", false
+			}
+			return spec.OADP.OLMVersion, spec.OADP.OLMVersion != ""
+		},
+	},
+	subscriptionField(annotationMCESubscriptionSpec, ensureMCEInstall, mceInstall),
+	clusterExtensionField(annotationMCEClusterExtensionSpec, ensureMCEInstall, mceInstall),
+	subscriptionField(annotationOADPSubscriptionSpec, ensureOADPInstall, oadpInstall),
+	clusterExtensionField(annotationOADPClusterExtensionSpec, ensureOADPInstall, oadpInstall),
+}
+
+func stringField(key string, field func(spec *MultiClusterHubSpec) *string) annotationField {
+	return annotationField{
+		key: key,
+		decode: func(value string, spec *MultiClusterHubSpec) bool {
+			*field(spec) = value
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) {
+			value := *field(spec)
+			return value, value != ""
+		},
+	}
+}
+
+// installConfigFunc returns an operator install config from the spec
+type installConfigFunc func(spec *MultiClusterHubSpec) *OperatorInstallConfig
+
+// subscriptionField maps a Subscription spec annotation. ensure returns the install config to decode into,
+// creating it if needed; get returns it if it exists.
+func subscriptionField(key string, ensure, get installConfigFunc) annotationField {
+	return annotationField{
+		key: key,
+		decode: func(value string, spec *MultiClusterHubSpec) bool {
+			sub := &SubscriptionOverrides{}
+			if !decodeStrict(value, sub) {
+				return false
+			}
+			if sub.InstallPlanApproval != "" && sub.InstallPlanApproval != "Automatic" &&
+				sub.InstallPlanApproval != "Manual" {
+				return false
+			}
+			ensure(spec).Subscription = sub
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) {
+			if c := get(spec); c != nil && c.Subscription != nil {
+				return encodeJSON(c.Subscription)
+			}
+			return "", false
+		},
+	}
+}
+
+// clusterExtensionField maps a ClusterExtension spec annotation, see subscriptionField
+func clusterExtensionField(key string, ensure, get installConfigFunc) annotationField {
+	return annotationField{
+		key: key,
+		decode: func(value string, spec *MultiClusterHubSpec) bool {
+			ce := &ClusterExtensionOverrides{}
+			if !decodeStrict(value, ce) {
+				return false
+			}
+			if ce.CRDUpgradeSafetyEnforcement != "" && ce.CRDUpgradeSafetyEnforcement != "None" &&
+				ce.CRDUpgradeSafetyEnforcement != "Strict" {
+				return false
+			}
+			ensure(spec).ClusterExtension = ce
+			return true
+		},
+		encode: func(spec *MultiClusterHubSpec) (string, bool) {
+			if c := get(spec); c != nil && c.ClusterExtension != nil {
+				return encodeJSON(c.ClusterExtension)
+			}
+			return "", false
+		},
+	}
+}
+
+func mceConfig(spec *MultiClusterHubSpec) *MultiClusterEngineConfig {
+	if spec.MultiClusterEngine == nil {
+		spec.MultiClusterEngine = &MultiClusterEngineConfig{}
+	}
+	return spec.MultiClusterEngine
+}
+
+func ensureMCEInstall(spec *MultiClusterHubSpec) *OperatorInstallConfig {
+	return &mceConfig(spec).OperatorInstallConfig
+}
+
+func mceInstall(spec *MultiClusterHubSpec) *OperatorInstallConfig {
+	if spec.MultiClusterEngine == nil {
+		return nil
+	}
+	return &spec.MultiClusterEngine.OperatorInstallConfig
+}
+
+func oadpConfig(spec *MultiClusterHubSpec) *OADPConfig {
+	if spec.OADP == nil {
+		spec.OADP = &OADPConfig{}
+	}
+	return spec.OADP
+}
+
+func ensureOADPInstall(spec *MultiClusterHubSpec) *OperatorInstallConfig {
+	return &oadpConfig(spec).OperatorInstallConfig
+}
+
+func oadpInstall(spec *MultiClusterHubSpec) *OperatorInstallConfig {
+	if spec.OADP == nil {
+		return nil
+	}
+	return &spec.OADP.OperatorInstallConfig
+}
+
+// decodeStrict unmarshals a single JSON value, rejecting unknown fields so that settings the typed v2 fields
+// cannot hold stay in their annotation
+func decodeStrict(value string, into interface{}) bool {
+	d := json.NewDecoder(strings.NewReader(value))
+	d.DisallowUnknownFields()
+	if err := d.Decode(into); err != nil {
+		return false
+	}
+	var extra json.RawMessage
+	return d.Decode(&extra) == io.EOF
+}
+
+// encodeJSON marshals v without HTML escaping, so version constraints such as ">=1.4.0" stay readable
+func encodeJSON(v interface{}) (string, bool) {
+	var b strings.Builder
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(b.String(), "\n"), true
+}
+
+var _ conversion.Convertible = &MultiClusterHub{}
+
+// ConvertTo converts this MultiClusterHub to the v1 storage version. Typed fields are written back to their
+// annotations.
+func (src *MultiClusterHub) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1.MultiClusterHub)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+	spec := src.Spec.DeepCopy()
+	dst.Spec = v1.MultiClusterHubSpec{
+		ImagePullSecret:               spec.ImagePullSecret,
+		AvailabilityConfig:            spec.AvailabilityConfig,
+		NodeSelector:                  spec.NodeSelector,
+		Tolerations:                   spec.Tolerations,
+		Overrides:                     spec.Overrides,
+		DisableHubSelfManagement:      spec.DisableHubSelfManagement,
+		DisableUpdateClusterImageSets: spec.DisableUpdateClusterImageSets,
+		LocalClusterName:              spec.LocalClusterName,
+		NetworkPolicies:               spec.NetworkPolicies,
+		Probes:                        spec.Probes,
+		TrustedCA:                     spec.TrustedCA,
+		Adoption:                      spec.Adoption,
+		LocalCluster:                  spec.LocalCluster,
+		Profile:                       spec.Profile,
+		NewComponentPolicy:            spec.NewComponentPolicy,
+	}
+
+	annotations := dst.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	original := map[string]string{}
+	if raw, ok := annotations[AnnotationV1Values]; ok {
+		// an unreadable record only costs the original formatting, so it is dropped rather than failing
+		_ = json.Unmarshal([]byte(raw), &original)
+		delete(annotations, AnnotationV1Values)
+	}
+
+	for _, f := range annotationFields {
+		value, set := f.encode(&src.Spec)
+		if prev, ok := original[f.key]; ok && sameTypedValue(f, prev, value, set) {
+			annotations[f.key] = prev
+			continue
+		}
+		if set {
+			annotations[f.key] = value
+		}
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	dst.SetAnnotations(annotations)
+	return nil
+}
+
+// sameTypedValue reports whether an original annotation value decodes to the given encoded typed value
+func sameTypedValue(f annotationField, original, value string, set bool) bool {
+	scratch := &MultiClusterHubSpec{}
+	if !f.decode(original, scratch) {
+		return false
+	}
+	v, s := f.encode(scratch)
+	return v == value && s == set
+}
+
+// ConvertFrom converts from the v1 storage version. Annotations that map onto typed fields are removed; ones
+// whose value the typed field cannot hold are left in place.
+func (dst *MultiClusterHub) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1.MultiClusterHub)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+	spec := src.Spec.DeepCopy()
+	dst.Spec = MultiClusterHubSpec{
+		ImagePullSecret:               spec.ImagePullSecret,
+		AvailabilityConfig:            spec.AvailabilityConfig,
+		NodeSelector:                  spec.NodeSelector,
+		Tolerations:                   spec.Tolerations,
+		Overrides:                     spec.Overrides,
+		DisableHubSelfManagement:      spec.DisableHubSelfManagement,
+		DisableUpdateClusterImageSets: spec.DisableUpdateClusterImageSets,
+		LocalClusterName:              spec.LocalClusterName,
+		NetworkPolicies:               spec.NetworkPolicies,
+		Probes:                        spec.Probes,
+		TrustedCA:                     spec.TrustedCA,
+		Adoption:                      spec.Adoption,
+		LocalCluster:                  spec.LocalCluster,
+		Profile:                       spec.Profile,
+		NewComponentPolicy:            spec.NewComponentPolicy,
+	}
+
+	annotations := dst.GetAnnotations()
+	delete(annotations, AnnotationV1Values)
+	original := map[string]string{}
+	for _, f := range annotationFields {
+		value, ok := annotations[f.key]
+		if !ok || !f.decode(value, &dst.Spec) {
+			continue
+		}
+		if encoded, set := f.encode(&dst.Spec); !set || encoded != value {
+			original[f.key] = value
+		}
+		delete(annotations, f.key)
+	}
+
+	if len(original) > 0 {
+		raw, err := json.Marshal(original)
+		if err != nil {
+			return err
+		}
+		annotations[AnnotationV1Values] = string(raw)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	dst.SetAnnotations(annotations)
+	return nil
+}
+
+// SetupWebhookWithManager registers the conversion webhook for the v2 API
+func (r *MultiClusterHub) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return builder.WebhookManagedBy(mgr, r).Complete()
+}
@@ -0,0 +1,247 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package v2
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConvertFrom(t *testing.T) {
+	hub := &v1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "multiclusterhub",
+			Namespace: "open-cluster-management",
+			Annotations: map[string]string{
+				annotationMCHPause:               "true",
+				annotationImageRepo:              "quay.io/example",
+				annotationResourceAdoptionPolicy: "Adopt",
+				annotationRemoveDisabledCRDs:     "submariner-addon,siteconfig",
+				annotationMCEOLMVersion:          "v1",
+				annotationMCESubscriptionSpec:    `{"channel":"stable-2.9","source":"custom-catalog"}`,
+				annotationOADPOLMVersion:         "v1",
+				annotationOADPClusterExtensionSpec: `{"channels":["stable"],"version":">=1.4.0",` +
+					`"source":"redhat-operators"}`,
+				"unrelated": "value",
+			},
+		},
+		Spec: v1.MultiClusterHubSpec{
+			AvailabilityConfig: v1.HABasic,
+			LocalClusterName:   "local-cluster",
+			TrustedCA:          &v1.TrustedCAConfig{SecretName: "custom-ca"},
+			Adoption:           &v1.AdoptionConfig{AdoptNow: "migration-1"},
+			LocalCluster:       &v1.LocalClusterConfig{ClusterSet: "hub"},
+			Profile:            &v1.HubProfile{Name: v1.ProfileGovernance, Pinned: true},
+			NewComponentPolicy: v1.NewComponentAcknowledge,
+		},
+	}
+
+	got := &MultiClusterHub{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+
+	want := MultiClusterHubSpec{
+		AvailabilityConfig:     v1.HABasic,
+		LocalClusterName:       "local-cluster",
+		TrustedCA:              &v1.TrustedCAConfig{SecretName: "custom-ca"},
+		Adoption:               &v1.AdoptionConfig{AdoptNow: "migration-1"},
+		LocalCluster:           &v1.LocalClusterConfig{ClusterSet: "hub"},
+		Profile:                &v1.HubProfile{Name: v1.ProfileGovernance, Pinned: true},
+		NewComponentPolicy:     v1.NewComponentAcknowledge,
+		Paused:                 true,
+		ImageRepository:        "quay.io/example",
+		ResourceAdoptionPolicy: AdoptionAdopt,
+		RemoveDisabledCRDs:     []string{"submariner-addon", "siteconfig"},
+		MultiClusterEngine: &MultiClusterEngineConfig{
+			OLMVersion: "v1",
+			OperatorInstallConfig: OperatorInstallConfig{
+				Subscription: &SubscriptionOverrides{Channel: "stable-2.9", Source: "custom-catalog"},
+			},
+		},
+		OADP: &OADPConfig{
+			OLMVersion: "v1",
+			OperatorInstallConfig: OperatorInstallConfig{
+				ClusterExtension: &ClusterExtensionOverrides{
+					Channels: []string{"stable"},
+					Version:  ">=1.4.0",
+					Source:   "redhat-operators",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got.Spec, want) {
+		t.Errorf("ConvertFrom() spec = %+v, want %+v", got.Spec, want)
+	}
+	if !reflect.DeepEqual(got.GetAnnotations(), map[string]string{"unrelated": "value"}) {
+		t.Errorf("expected only unrelated annotations to remain, got %v", got.GetAnnotations())
+	}
+}
+
+func TestConvertFrom_UnrepresentableAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		// unknown fields cannot be held by the typed overrides
+		annotationMCESubscriptionSpec: `{"channel":"stable-2.9","config":{"env":[{"name":"A","value":"b"}]}}`,
+		// not a supported policy
+		annotationResourceAdoptionPolicy: "adopt",
+		annotationMCEOLMVersion:          "v2",
+	}
+	hub := &v1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Annotations: annotations},
+	}
+
+	got := &MultiClusterHub{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if got.Spec.MultiClusterEngine != nil || got.Spec.ResourceAdoptionPolicy != "" {
+		t.Errorf("expected no typed fields to be set, got %+v", got.Spec)
+	}
+	if !reflect.DeepEqual(got.GetAnnotations(), annotations) {
+		t.Errorf("expected annotations to be kept, got %v", got.GetAnnotations())
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{
+			name: "canonical values",
+			annotations: map[string]string{
+				annotationMCHPause:                "true",
+				annotationIgnoreOCPVersion:        "true",
+				annotationImageOverridesCM:        "image-overrides",
+				annotationTemplateOverridesCM:     "template-overrides",
+				annotationKubeconfig:              "hub-kubeconfig",
+				annotationMCEClusterExtensionSpec: `{"channels":["stable-2.9"],"crdUpgradeSafetyEnforcement":"None"}`,
+				annotationOADPSubscriptionSpec:    `{"channel":"stable-1.4","installPlanApproval":"Manual"}`,
+			},
+		},
+		{
+			name: "non-canonical values",
+			annotations: map[string]string{
+				annotationMCHPause:            "True",
+				annotationIgnoreOCPVersion:    "",
+				annotationImageRepo:           "",
+				annotationRemoveDisabledCRDs:  " submariner-addon, siteconfig,",
+				annotationMCESubscriptionSpec: `{ "source": "custom-catalog", "channel": "stable-2.9" }`,
+				annotationMCEClusterExtensionSpec: `{"config":{"inline":{"b": 1, "a": [true]}},` +
+					`"version":"2.9.0"}`,
+			},
+		},
+		{
+			name: "paused false",
+			annotations: map[string]string{
+				annotationMCHPause: "false",
+			},
+		},
+		{
+			name: "unrepresentable values",
+			annotations: map[string]string{
+				annotationOADPSubscriptionSpec:   `{"channel":"stable","unknown":true}`,
+				annotationResourceAdoptionPolicy: "Relaxed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1.MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Annotations: tt.annotations},
+				Spec:       v1.MultiClusterHubSpec{LocalClusterName: "local-cluster"},
+			}
+
+			spoke := &MultiClusterHub{}
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			got := &v1.MultiClusterHub{}
+			if err := spoke.ConvertTo(got); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, hub) {
+				t.Errorf("round trip = %+v, want %+v", got, hub)
+			}
+		})
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	spoke := &MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub"},
+		Spec: MultiClusterHubSpec{
+			Paused:                     true,
+			TemplateOverridesConfigMap: "template-overrides",
+			ResourceAdoptionPolicy:     AdoptionStrict,
+			RemoveDisabledCRDs:         []string{"submariner-addon", "siteconfig"},
+			MultiClusterEngine: &MultiClusterEngineConfig{
+				OperatorInstallConfig: OperatorInstallConfig{
+					ClusterExtension: &ClusterExtensionOverrides{
+						Config: &ClusterExtensionConfig{
+							Inline: &apiextensionsv1.JSON{Raw: []byte(`{"watchNamespace": "mce"}`)},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := &v1.MultiClusterHub{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	want := map[string]string{
+		annotationMCHPause:                "true",
+		annotationTemplateOverridesCM:     "template-overrides",
+		annotationResourceAdoptionPolicy:  "Strict",
+		annotationRemoveDisabledCRDs:      "submariner-addon,siteconfig",
+		annotationMCEClusterExtensionSpec: `{"config":{"inline":{"watchNamespace":"mce"}}}`,
+	}
+	if !reflect.DeepEqual(got.GetAnnotations(), want) {
+		t.Errorf("ConvertTo() annotations = %v, want %v", got.GetAnnotations(), want)
+	}
+
+	back := &MultiClusterHub{}
+	if err := back.ConvertFrom(got); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if back.GetAnnotations() != nil {
+		t.Errorf("expected no annotations after converting back, got %v", back.GetAnnotations())
+	}
+	if !back.Spec.Paused || back.Spec.MultiClusterEngine == nil ||
+		string(back.Spec.MultiClusterEngine.ClusterExtension.Config.Inline.Raw) != `{"watchNamespace":"mce"}` {
+		t.Errorf("ConvertFrom() spec = %+v, want the original typed fields", back.Spec)
+	}
+}
+
+func TestConvertTo_ChangedTypedValue(t *testing.T) {
+	hub := &v1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "multiclusterhub",
+			Annotations: map[string]string{annotationMCHPause: "True"},
+		},
+	}
+	spoke := &MultiClusterHub{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if spoke.GetAnnotations()[AnnotationV1Values] == "" {
+		t.Fatalf("expected the original annotation value to be recorded, got %v", spoke.GetAnnotations())
+	}
+
+	spoke.Spec.Paused = false
+	got := &v1.MultiClusterHub{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if got.GetAnnotations() != nil {
+		t.Errorf("expected the pause annotation to be removed once unpaused, got %v", got.GetAnnotations())
+	}
+}
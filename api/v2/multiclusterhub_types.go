@@ -0,0 +1,279 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	v1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceAdoptionPolicy controls how the operator handles existing resources without installer labels
+// +kubebuilder:validation:Enum=Strict;Adopt
+type ResourceAdoptionPolicy string
+
+const (
+	// AdoptionStrict only manages resources that carry the installer labels
+	AdoptionStrict ResourceAdoptionPolicy = "Strict"
+	// AdoptionAdopt adopts existing unlabeled resources
+	AdoptionAdopt ResourceAdoptionPolicy = "Adopt"
+)
+
+// MultiClusterHubSpec defines the desired state of MultiClusterHub
+type MultiClusterHubSpec struct {
+
+	// Override pull secret for accessing MultiClusterHub operand and endpoint images
+	ImagePullSecret string `json:"imagePullSecret,omitempty"`
+
+	// Specifies deployment replication for improved availability. Options are: Basic and High (default)
+	AvailabilityConfig v1.AvailabilityType `json:"availabilityConfig,omitempty"`
+
+	// Set the nodeselectors
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations causes all components to tolerate any taints.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Developer Overrides
+	Overrides *v1.Overrides `json:"overrides,omitempty"`
+
+	// Disable automatic import of the hub cluster as a managed cluster
+	DisableHubSelfManagement bool `json:"disableHubSelfManagement,omitempty"`
+
+	// Disable automatic update of ClusterImageSets
+	DisableUpdateClusterImageSets bool `json:"disableUpdateClusterImageSets,omitempty"`
+
+	// The name of the local-cluster resource
+	//+kubebuilder:default="local-cluster"
+	LocalClusterName string `json:"localClusterName,omitempty"`
+
+	// NetworkPolicies configures NetworkPolicy deployment for ACM components
+	// +optional
+	NetworkPolicies *v1.NetworkPoliciesConfig `json:"networkPolicies,omitempty"`
+
+	// Probes tunes the liveness and readiness probes of component deployments
+	// +optional
+	Probes *v1.ProbesConfig `json:"probes,omitempty"`
+
+	// TrustedCA adds CA certificates to the trust bundle propagated to the hub components
+	// +optional
+	TrustedCA *v1.TrustedCAConfig `json:"trustedCA,omitempty"`
+
+	// Adoption configures how existing resources that are not labeled by the installer are adopted
+	// +optional
+	Adoption *v1.AdoptionConfig `json:"adoption,omitempty"`
+
+	// LocalCluster configures the add-ons and the ManagedCluster of the self-managed local-cluster
+	// +optional
+	LocalCluster *v1.LocalClusterConfig `json:"localCluster,omitempty"`
+
+	// Profile expands into the components and settings of a common hub shape. Components configured in
+	// spec.overrides.components take precedence over the profile.
+	// +optional
+	Profile *v1.HubProfile `json:"profile,omitempty"`
+
+	// NewComponentPolicy decides whether components an upgrade enables by default are installed: Enable (default)
+	// installs them, Disable leaves them disabled and Acknowledge leaves them disabled and lists them in
+	// status.profile.pendingComponents until they are configured in spec.overrides.components.
+	// +optional
+	NewComponentPolicy v1.NewComponentPolicy `json:"newComponentPolicy,omitempty"`
+
+	// Paused stops the operator from reconciling the hub. Replaces the
+	// installer.open-cluster-management.io/pause annotation.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// IgnoreOCPVersion skips the minimum OpenShift version check. Replaces the
+	// installer.open-cluster-management.io/ignore-ocp-version annotation.
+	// +optional
+	IgnoreOCPVersion bool `json:"ignoreOCPVersion,omitempty"`
+
+	// ImageRepository replaces the registry and organization of every component image. Replaces the
+	// installer.open-cluster-management.io/image-repository annotation.
+	// +optional
+	ImageRepository string `json:"imageRepository,omitempty"`
+
+	// ImageOverridesConfigMap names a ConfigMap in the hub namespace with image overrides. Replaces the
+	// installer.open-cluster-management.io/image-overrides-configmap annotation.
+	// +optional
+	ImageOverridesConfigMap string `json:"imageOverridesConfigMap,omitempty"`
+
+	// TemplateOverridesConfigMap names a ConfigMap in the hub namespace with template overrides. Replaces the
+	// installer.open-cluster-management.io/template-override-configmap annotation.
+	// +optional
+	TemplateOverridesConfigMap string `json:"templateOverridesConfigMap,omitempty"`
+
+	// KubeconfigSecret names a Secret holding a kubeconfig for the hub. Replaces the
+	// installer.open-cluster-management.io/kubeconfig annotation.
+	// +optional
+	KubeconfigSecret string `json:"kubeconfigSecret,omitempty"`
+
+	// ResourceAdoptionPolicy controls whether existing resources without installer labels are adopted.
+	// Defaults to Strict. Replaces the installer.open-cluster-management.io/resource-adoption-policy annotation.
+	// +optional
+	ResourceAdoptionPolicy ResourceAdoptionPolicy `json:"resourceAdoptionPolicy,omitempty"`
+
+	// RemoveDisabledCRDs lists the components whose CRDs are removed while the component is disabled, once
+	// no custom resources of the CRD remain. Replaces the
+	// installer.open-cluster-management.io/remove-disabled-crds annotation.
+	// +optional
+	RemoveDisabledCRDs []string `json:"removeDisabledCRDs,omitempty"`
+
+	// MultiClusterEngine customizes how the MultiClusterEngine operator is installed
+	// +optional
+	MultiClusterEngine *MultiClusterEngineConfig `json:"multiClusterEngine,omitempty"`
+
+	// OADP customizes how the OADP operator is installed by cluster-backup
+	// +optional
+	OADP *OADPConfig `json:"oadp,omitempty"`
+}
+
+// OperatorInstallConfig overrides the OLM resources used to install an operator. Only the section matching the
+// OLM version in use is honored.
+type OperatorInstallConfig struct {
+	// Subscription overrides the OLM v0 Subscription
+	// +optional
+	Subscription *SubscriptionOverrides `json:"subscription,omitempty"`
+
+	// ClusterExtension overrides the OLM v1 ClusterExtension
+	// +optional
+	ClusterExtension *ClusterExtensionOverrides `json:"clusterExtension,omitempty"`
+}
+
+// MultiClusterEngineConfig customizes the MultiClusterEngine installation
+type MultiClusterEngineConfig struct {
+	OperatorInstallConfig `json:",inline"`
+
+	// OLMVersion selects the OLM API used to install MultiClusterEngine, overriding the detected version.
+	// Changing it migrates an existing installation. Replaces the
+	// installer.open-cluster-management.io/mce-olm-version annotation.
+	// +kubebuilder:validation:Enum=v0;v1
+	// +optional
+	OLMVersion string `json:"olmVersion,omitempty"`
+}
+
+// OADPConfig customizes the OADP installation
+type OADPConfig struct {
+	OperatorInstallConfig `json:",inline"`
+
+	// OLMVersion selects the OLM API used to install OADP. OADP is installed with an OLM v0 Subscription unless
+	// it is set to v1 on a cluster serving OLM v1. Replaces the
+	// installer.open-cluster-management.io/oadp-olm-version annotation.
+	// +kubebuilder:validation:Enum=v0;v1
+	// +optional
+	OLMVersion string `json:"olmVersion,omitempty"`
+}
+
+// SubscriptionOverrides holds the OLM v0 Subscription fields that may be overridden. Replaces the
+// mce-subscription-spec and oadp-subscription-spec annotations.
+type SubscriptionOverrides struct {
+	// Channel is the subscription channel
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Package is the name of the operator package
+	// +optional
+	Package string `json:"name,omitempty"`
+
+	// Source is the name of the CatalogSource
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// SourceNamespace is the namespace of the CatalogSource
+	// +optional
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
+
+	// StartingCSV is the ClusterServiceVersion to start the subscription from
+	// +optional
+	StartingCSV string `json:"startingCSV,omitempty"`
+
+	// InstallPlanApproval is the install plan approval mode
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +optional
+	InstallPlanApproval string `json:"installPlanApproval,omitempty"`
+}
+
+// ClusterExtensionOverrides holds the OLM v1 ClusterExtension fields that may be overridden. Replaces the
+// mce-clusterextension-spec and oadp-clusterextension-spec annotations.
+type ClusterExtensionOverrides struct {
+	// Channels constrains upgrades to the listed channels
+	// +optional
+	Channels []string `json:"channels,omitempty"`
+
+	// Version is a semver constraint for version selection
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Source is the name of the catalog to install from. Only honored for OADP.
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// CRDUpgradeSafetyEnforcement controls CRD upgrade safety checks
+	// +kubebuilder:validation:Enum=None;Strict
+	// +optional
+	CRDUpgradeSafetyEnforcement string `json:"crdUpgradeSafetyEnforcement,omitempty"`
+
+	// Config passes configuration to the installed bundle
+	// +optional
+	Config *ClusterExtensionConfig `json:"config,omitempty"`
+}
+
+// ClusterExtensionConfig holds inline bundle configuration
+type ClusterExtensionConfig struct {
+	// Inline contains JSON or YAML configuration values
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Inline *apiextensionsv1.JSON `json:"inline,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=multiclusterhubs,scope=Namespaced,shortName=mch
+//+kubebuilder:unservedversion
+
+// MultiClusterHub is the v2 representation of the hub configuration. Settings that v1 carries as annotations
+// are typed spec fields here. v1 remains the storage version and objects are converted by the operator's
+// conversion webhook. The CRD ships with v2 unserved, the operator serves it once the conversion webhook is
+// configured so the API server never drops the fields v1 does not define.
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="The overall status of the MultiClusterHub"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="CurrentVersion",type="string",JSONPath=".status.currentVersion",description="The current version of the MultiClusterHub"
+// +kubebuilder:printcolumn:name="DesiredVersion",type="string",JSONPath=".status.desiredVersion",description="The desired version of the MultiClusterHub"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[-1:].message",description="Message from the most recent condition"
+type MultiClusterHub struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MultiClusterHubSpec      `json:"spec,omitempty"`
+	Status v1.MultiClusterHubStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MultiClusterHubList contains a list of MultiClusterHub
+type MultiClusterHubList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MultiClusterHub `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MultiClusterHub{}, &MultiClusterHubList{})
+}
@@ -0,0 +1,149 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// adoptionReport collects the existing resources the hub does not own during a pass over the hub components
+type adoptionReport struct {
+	candidates map[string]operatorv1.AdoptionCandidate
+	adoptNow   string
+	adopted    int
+}
+
+/*
+beginAdoptionReport starts collecting the resources rendered by the hub that exist without being owned by it. An
+adopt-now request that has not been acted on yet applies for the duration of the pass.
+*/
+func (r *MultiClusterHubReconciler) beginAdoptionReport(m *operatorv1.MultiClusterHub) {
+	r.adoption = &adoptionReport{
+		candidates: map[string]operatorv1.AdoptionCandidate{},
+		adoptNow:   pendingAdoptNow(m),
+	}
+}
+
+/*
+completeAdoptionReport publishes the resources collected since beginAdoptionReport in the hub status. It is only
+called once every component has been reconciled, so the report and the adopt-now record are never partial.
+*/
+func (r *MultiClusterHubReconciler) completeAdoptionReport(m *operatorv1.MultiClusterHub) {
+	report := r.adoption
+	r.adoption = nil
+	if report == nil {
+		return
+	}
+
+	status := &operatorv1.AdoptionStatus{}
+	if m.Status.Adoption != nil {
+		status.LastAdoptNow = m.Status.Adoption.LastAdoptNow
+	}
+	for _, c := range report.candidates {
+		status.Candidates = append(status.Candidates, c)
+	}
+	sort.Slice(status.Candidates, func(i, j int) bool {
+		a, b := status.Candidates[i], status.Candidates[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	if report.adoptNow != "" {
+		status.LastAdoptNow = &operatorv1.AdoptNowStatus{
+			Token:   report.adoptNow,
+			Time:    metav1.Now(),
+			Adopted: report.adopted,
+		}
+		r.Log.Info("Completed adopt-now request", "Token", report.adoptNow, "Adopted", report.adopted)
+	}
+
+	if len(status.Candidates) == 0 && status.LastAdoptNow == nil {
+		status = nil
+	}
+	m.Status.Adoption = status
+}
+
+// pendingAdoptNow returns the adopt-now request of the hub that has not been acted on yet
+func pendingAdoptNow(m *operatorv1.MultiClusterHub) string {
+	if m.Spec.Adoption == nil || m.Spec.Adoption.AdoptNow == "" {
+		return ""
+	}
+	if last := m.Status.Adoption; last != nil && last.LastAdoptNow != nil &&
+		last.LastAdoptNow.Token == m.Spec.Adoption.AdoptNow {
+		return ""
+	}
+	return m.Spec.Adoption.AdoptNow
+}
+
+// adoptingNow returns true while an adopt-now request is being acted on
+func (r *MultiClusterHubReconciler) adoptingNow() bool {
+	return r.adoption != nil && r.adoption.adoptNow != ""
+}
+
+/*
+resourceAdoptionPolicy returns the adoption policy of an existing resource and whether it was set by a rule of
+spec.adoption. The first rule matching the kind and namespace of the resource applies; other resources follow the
+resource adoption policy annotation.
+*/
+func (r *MultiClusterHubReconciler) resourceAdoptionPolicy(existing *unstructured.Unstructured,
+	m *operatorv1.MultiClusterHub) (operatorv1.ResourceAdoptionPolicy, bool) {
+	if m.Spec.Adoption != nil {
+		for _, rule := range m.Spec.Adoption.Rules {
+			if (rule.Kind == "" || rule.Kind == existing.GetKind()) &&
+				(rule.Namespace == "" || rule.Namespace == existing.GetNamespace()) {
+				return rule.Policy, true
+			}
+		}
+	}
+	return operatorv1.ResourceAdoptionPolicy(r.getAdoptionPolicy(m)), false
+}
+
+// recordAdoptionCandidate adds an existing resource the hub does not own to the adoption report
+func (r *MultiClusterHubReconciler) recordAdoptionCandidate(existing *unstructured.Unstructured,
+	state operatorv1.AdoptionState, owner string, action operatorv1.AdoptionAction) {
+	if r.adoption == nil {
+		return
+	}
+
+	candidate := operatorv1.AdoptionCandidate{
+		APIVersion: existing.GetAPIVersion(),
+		Kind:       existing.GetKind(),
+		Namespace:  existing.GetNamespace(),
+		Name:       existing.GetName(),
+		State:      state,
+		Owner:      owner,
+		Action:     action,
+	}
+	key := candidate.APIVersion + "/" + candidate.Kind + "/" + candidate.Namespace + "/" + candidate.Name
+	if previous, ok := r.adoption.candidates[key]; action == operatorv1.AdoptionActionAdopted &&
+		(!ok || previous.Action != operatorv1.AdoptionActionAdopted) {
+		r.adoption.adopted++
+	}
+	r.adoption.candidates[key] = candidate
+}
@@ -0,0 +1,186 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func adoptionHub(adoption *operatorv1.AdoptionConfig) *operatorv1.MultiClusterHub {
+	return &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "multiclusterhub",
+			Namespace:   "open-cluster-management",
+			Annotations: map[string]string{utils.AnnotationResourceAdoptionPolicy: "Strict"},
+		},
+		Spec: operatorv1.MultiClusterHubSpec{Adoption: adoption},
+	}
+}
+
+func Test_ensureResourceOwnership_rules(t *testing.T) {
+	hub := adoptionHub(&operatorv1.AdoptionConfig{
+		Rules: []operatorv1.AdoptionRule{
+			{Kind: "PersistentVolumeClaim", Policy: operatorv1.AdoptionStrict},
+			{Kind: "ConfigMap", Policy: operatorv1.AdoptionAdopt},
+			{Namespace: "open-cluster-management", Policy: operatorv1.AdoptionAdopt},
+		},
+	})
+	r := &MultiClusterHubReconciler{Log: clog.Log.WithName("test")}
+
+	tests := []struct {
+		name       string
+		obj        *unstructured.Unstructured
+		wantManage bool
+	}{
+		{
+			name:       "kind rule adopts",
+			obj:        testObject("v1", "ConfigMap", "open-cluster-management-backup", "config"),
+			wantManage: true,
+		},
+		{
+			name:       "first matching rule applies",
+			obj:        testObject("v1", "PersistentVolumeClaim", "open-cluster-management", "data"),
+			wantManage: false,
+		},
+		{
+			name:       "namespace rule adopts",
+			obj:        testObject("v1", "Service", "open-cluster-management", "api"),
+			wantManage: true,
+		},
+		{
+			name:       "no rule follows the annotation",
+			obj:        testObject("v1", "Service", "open-cluster-management-backup", "api"),
+			wantManage: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := tt.obj.DeepCopy()
+			if got := r.ensureResourceOwnership(tt.obj, template, hub); got != tt.wantManage {
+				t.Errorf("ensureResourceOwnership() = %v, want %v", got, tt.wantManage)
+			}
+			if _, labeled := template.GetLabels()["installer.name"]; labeled != tt.wantManage {
+				t.Errorf("expected installer labels on the template only when adopting, got %v", template.GetLabels())
+			}
+		})
+	}
+}
+
+func Test_adoptionReport(t *testing.T) {
+	hub := adoptionHub(&operatorv1.AdoptionConfig{
+		Rules:    []operatorv1.AdoptionRule{{Kind: "PersistentVolumeClaim", Policy: operatorv1.AdoptionStrict}},
+		AdoptNow: "migration-1",
+	})
+	r := &MultiClusterHubReconciler{Log: clog.Log.WithName("test")}
+
+	objects := []*unstructured.Unstructured{
+		testObject("v1", "ConfigMap", hub.Namespace, "unowned"),
+		withLabels(testObject("v1", "ConfigMap", hub.Namespace, "partial"),
+			map[string]string{"installer.name": hub.Name}),
+		testObject("v1", "PersistentVolumeClaim", hub.Namespace, "excluded"),
+		withLabels(testObject("v1", "ConfigMap", hub.Namespace, "other"), map[string]string{
+			"installer.name": "other-hub", "installer.namespace": "other-namespace"}),
+		withLabels(testObject("v1", "ConfigMap", hub.Namespace, "owned"), map[string]string{
+			"installer.name": hub.Name, "installer.namespace": hub.Namespace}),
+	}
+
+	r.beginAdoptionReport(hub)
+	for _, obj := range objects {
+		r.ensureResourceOwnership(obj, obj.DeepCopy(), hub)
+	}
+	r.completeAdoptionReport(hub)
+
+	status := hub.Status.Adoption
+	if status == nil || status.LastAdoptNow == nil {
+		t.Fatalf("expected an adoption report with the adopt-now result, got %+v", status)
+	}
+	if status.LastAdoptNow.Token != "migration-1" || status.LastAdoptNow.Adopted != 2 {
+		t.Errorf("LastAdoptNow = %+v, want token migration-1 with 2 adopted resources", status.LastAdoptNow)
+	}
+
+	want := []struct {
+		name   string
+		state  operatorv1.AdoptionState
+		action operatorv1.AdoptionAction
+	}{
+		{"other", operatorv1.AdoptionStateOwnedByOtherHub, operatorv1.AdoptionActionManaged},
+		{"partial", operatorv1.AdoptionStatePartiallyLabeled, operatorv1.AdoptionActionAdopted},
+		{"unowned", operatorv1.AdoptionStateUnowned, operatorv1.AdoptionActionAdopted},
+		{"excluded", operatorv1.AdoptionStateUnowned, operatorv1.AdoptionActionSkipped},
+	}
+	if len(status.Candidates) != len(want) {
+		t.Fatalf("Candidates = %+v, want %d entries", status.Candidates, len(want))
+	}
+	for i, w := range want {
+		got := status.Candidates[i]
+		if got.Name != w.name || got.State != w.state || got.Action != w.action {
+			t.Errorf("Candidates[%d] = %+v, want %s %s %s", i, got, w.name, w.state, w.action)
+		}
+	}
+	if status.Candidates[0].Owner != "other-namespace/other-hub" {
+		t.Errorf("Owner = %q, want other-namespace/other-hub", status.Candidates[0].Owner)
+	}
+
+	// The adopt-now request is only acted on once
+	if pendingAdoptNow(hub) != "" {
+		t.Error("expected the adopt-now request to be recorded as handled")
+	}
+	r.beginAdoptionReport(hub)
+	partial := withLabels(testObject("v1", "ConfigMap", hub.Namespace, "partial"),
+		map[string]string{"installer.name": hub.Name})
+	if r.ensureResourceOwnership(partial, partial.DeepCopy(), hub) {
+		t.Error("expected partially labeled resources to be skipped without an adopt-now request")
+	}
+	r.completeAdoptionReport(hub)
+	if got := hub.Status.Adoption.LastAdoptNow; got == nil || got.Adopted != 2 {
+		t.Errorf("expected the last adopt-now result to be kept, got %+v", got)
+	}
+}
+
+func Test_deleteTemplate_doesNotAdopt(t *testing.T) {
+	hub := adoptionHub(&operatorv1.AdoptionConfig{
+		Rules:    []operatorv1.AdoptionRule{{Kind: "ConfigMap", Policy: operatorv1.AdoptionAdopt}},
+		AdoptNow: "migration-1",
+	})
+	unowned := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unowned", Namespace: hub.Namespace}}
+	owned := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: hub.Namespace,
+		Labels: map[string]string{"installer.name": hub.Name, "installer.namespace": hub.Namespace}}}
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(unowned, owned).Build(),
+		Log:    clog.Log.WithName("test"),
+	}
+
+	// Unowned resources of a disabled component are neither adopted nor deleted, even when a rule adopts them
+	r.beginAdoptionReport(hub)
+	for _, name := range []string{"unowned", "owned"} {
+		if _, err := r.deleteTemplate(context.TODO(), hub,
+			testObject("v1", "ConfigMap", hub.Namespace, name)); err != nil {
+			t.Fatalf("deleteTemplate(%s) error = %v", name, err)
+		}
+	}
+
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "unowned", Namespace: hub.Namespace},
+		&corev1.ConfigMap{}); err != nil {
+		t.Errorf("expected the unowned ConfigMap to be kept, got %v", err)
+	}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "owned", Namespace: hub.Namespace},
+		&corev1.ConfigMap{}); err == nil {
+		t.Error("expected the ConfigMap labeled by this hub to be deleted")
+	}
+	if len(r.adoption.candidates) != 0 {
+		t.Errorf("expected no adoption candidates from the delete path, got %+v", r.adoption.candidates)
+	}
+}
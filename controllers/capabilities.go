@@ -0,0 +1,253 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	mcev1 "github.com/stolostron/backplane-operator/api/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/capabilities"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlpredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// hasCapability reports whether an optional cluster API is available. Without capability discovery (e.g. in unit
+// tests) every capability is assumed present, matching the behaviour before discovery existed.
+func (r *MultiClusterHubReconciler) hasCapability(c capabilities.Capability) bool {
+	return r.Capabilities == nil || r.Capabilities.Has(c)
+}
+
+// refreshOLMVersion picks up OLM being installed or removed since the last reconcile
+func (r *MultiClusterHubReconciler) refreshOLMVersion() {
+	if r.Capabilities == nil {
+		return
+	}
+	if v := r.Capabilities.OLMVersion(); v != r.OLMVersion {
+		r.Log.Info("OLM version changed", "from", r.OLMVersion, "to", v)
+		r.OLMVersion = v
+	}
+}
+
+// capabilitiesStatus returns the currently detected capabilities for the MCH status
+func (r *MultiClusterHubReconciler) capabilitiesStatus() *operatorv1.ClusterCapabilitiesStatus {
+	if r.Capabilities == nil {
+		return nil
+	}
+	return &operatorv1.ClusterCapabilitiesStatus{
+		OLMVersion: r.OLMVersion,
+		Available:  r.Capabilities.Available(),
+	}
+}
+
+// capabilityPredicate only passes the CRDs or APIServices that signal a tracked capability
+func capabilityPredicate(kind capabilities.SourceKind) ctrlpredicate.Predicate {
+	return ctrlpredicate.NewPredicateFuncs(func(o client.Object) bool {
+		_, ok := capabilities.Lookup(kind, o.GetName())
+		return ok
+	})
+}
+
+// capabilityEventHandler records CRD and APIService changes in the capability discovery and requeues the hub
+// whenever a capability appears or disappears.
+func (r *MultiClusterHubReconciler) capabilityEventHandler(kind capabilities.SourceKind) handler.Funcs {
+	observe := func(ctx context.Context, obj client.Object, available bool,
+		q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		c, changed := r.Capabilities.Observe(kind, obj, available)
+		if !changed {
+			return
+		}
+		log.Info("Cluster capability changed", "capability", c, "available", available)
+		for _, req := range r.firstHubRequest(ctx) {
+			q.Add(req)
+		}
+	}
+
+	observeAvailable := func(ctx context.Context, obj client.Object,
+		q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		available, err := r.capabilityAvailable(ctx, kind, obj)
+		if err != nil {
+			log.Info("Unable to check a cluster capability", "kind", kind, "name", obj.GetName(), "error", err.Error())
+			return
+		}
+		observe(ctx, obj, available, q)
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.TypedCreateEvent[client.Object],
+			q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			observeAvailable(ctx, e.Object, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.TypedUpdateEvent[client.Object],
+			q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			observeAvailable(ctx, e.ObjectNew, q)
+		},
+		DeleteFunc: func(ctx context.Context, e event.TypedDeleteEvent[client.Object],
+			q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			observe(ctx, e.Object, false, q)
+		},
+	}
+}
+
+/*
+capabilityAvailable reports whether a watched CRD or APIService is ready to serve requests. CRDs are watched by their
+metadata only so the cache does not hold the schemas of every CRD on the cluster, the conditions of the tracked CRDs
+are read from the API server instead.
+*/
+func (r *MultiClusterHubReconciler) capabilityAvailable(ctx context.Context, kind capabilities.SourceKind,
+	obj client.Object) (bool, error) {
+	if _, ok := obj.(*metav1.PartialObjectMetadata); !ok {
+		return capabilities.ObjectAvailable(obj), nil
+	}
+	if obj.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+	var reader client.Reader = r.Client
+	if r.UncachedClient != nil {
+		reader = r.UncachedClient
+	}
+	return capabilities.SourceAvailable(ctx, reader, capabilities.Source{Kind: kind, Name: obj.GetName()})
+}
+
+// firstHubRequest returns a reconcile request for the MultiClusterHub, if one exists
+func (r *MultiClusterHubReconciler) firstHubRequest(ctx context.Context) []reconcile.Request {
+	multiClusterHubList := &operatorv1.MultiClusterHubList{}
+	if err := r.Client.List(ctx, multiClusterHubList); err == nil && len(multiClusterHubList.Items) > 0 {
+		mch := multiClusterHubList.Items[0]
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Name:      mch.GetName(),
+					Namespace: mch.GetNamespace(),
+				},
+			},
+		}
+	}
+	return []reconcile.Request{}
+}
+
+/*
+watchMultiClusterEngineWhenAvailable adds the MultiClusterEngine watch as soon as the MultiClusterEngine API is
+served. The API is usually installed by the hub itself, so it is often missing when the operator starts.
+*/
+func (r *MultiClusterHubReconciler) watchMultiClusterEngineWhenAvailable(c controller.Controller, ca cache.Cache) {
+	var mu sync.Mutex
+	added := false
+	addWatch := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if added {
+			return
+		}
+		if err := c.Watch(source.Kind(ca, &mcev1.MultiClusterEngine{}, multiClusterEngineEventHandler())); err != nil {
+			log.Error(err, "failed to add MultiClusterEngine watch")
+			return
+		}
+		added = true
+		log.Info("mce watch added")
+	}
+
+	r.Capabilities.OnChange(func(capability capabilities.Capability, available bool) {
+		if capability == capabilities.MultiClusterEngine && available {
+			addWatch()
+		}
+	})
+	if r.Capabilities.Has(capabilities.MultiClusterEngine) {
+		addWatch()
+	}
+}
+
+/*
+watchInternalHubComponentsWhenAvailable adds the InternalHubComponent watch as soon as the hub has installed the
+InternalHubComponent API, so status reported by the operands is picked up without waiting for the next resync.
+*/
+func (r *MultiClusterHubReconciler) watchInternalHubComponentsWhenAvailable(c controller.Controller, ca cache.Cache) {
+	var mu sync.Mutex
+	added := false
+	addWatch := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if added {
+			return
+		}
+		if err := c.Watch(source.Kind(ca, &operatorv1.InternalHubComponent{},
+			handler.TypedEnqueueRequestsFromMapFunc(
+				func(ctx context.Context, _ *operatorv1.InternalHubComponent) []reconcile.Request {
+					return r.firstHubRequest(ctx)
+				},
+			))); err != nil {
+			log.Error(err, "failed to add InternalHubComponent watch")
+			return
+		}
+		added = true
+		log.Info("internalhubcomponent watch added")
+	}
+
+	r.Capabilities.OnChange(func(capability capabilities.Capability, available bool) {
+		if capability == capabilities.InternalHubComponent && available {
+			addWatch()
+		}
+	})
+	if r.Capabilities.Has(capabilities.InternalHubComponent) {
+		addWatch()
+	}
+}
+
+// multiClusterEngineEventHandler requeues the hub that installed an MCE whenever that MCE changes
+func multiClusterEngineEventHandler() handler.TypedEventHandler[*mcev1.MultiClusterEngine, reconcile.Request] {
+	return handler.TypedFuncs[*mcev1.MultiClusterEngine, reconcile.Request]{
+		UpdateFunc: func(ctx context.Context, e event.TypedUpdateEvent[*mcev1.MultiClusterEngine],
+			q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			labels := e.ObjectNew.GetLabels()
+			name := labels["installer.name"]
+			if name == "" {
+				name = labels["multiclusterhub.name"]
+			}
+			namespace := labels["installer.namespace"]
+			if namespace == "" {
+				namespace = labels["multiclusterhub.namespace"]
+			}
+			if name == "" || namespace == "" {
+				log.WithName("mce").Info(fmt.Sprintf(
+					"MCE updated but missing installer.name or installer.namespace labels. Current labels: %v", labels))
+				return
+			}
+			q.Add(
+				reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      name,
+						Namespace: namespace,
+					},
+				},
+			)
+		},
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/capabilities"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_capabilityEventHandler(t *testing.T) {
+	registerScheme()
+
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+	discovery := capabilities.New()
+	discovery.Set(capabilities.MultiClusterEngine, false)
+
+	r := &MultiClusterHubReconciler{
+		Client:       fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(hub).Build(),
+		Log:          clog.Log.WithName("test"),
+		Capabilities: discovery,
+	}
+	h := r.capabilityEventHandler(capabilities.CRDSource)
+	ctx := context.Background()
+
+	crd := &apixv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterengines.multicluster.openshift.io"},
+		Status: apixv1.CustomResourceDefinitionStatus{
+			Conditions: []apixv1.CustomResourceDefinitionCondition{
+				{Type: apixv1.Established, Status: apixv1.ConditionTrue},
+			},
+		},
+	}
+
+	newQueue := func() workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		return workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	}
+
+	q := newQueue()
+	h.Create(ctx, event.TypedCreateEvent[client.Object]{Object: crd}, q)
+	if q.Len() != 1 {
+		t.Fatalf("expected the hub to be requeued when the MCE API appears, queue length = %d", q.Len())
+	}
+	if !discovery.Has(capabilities.MultiClusterEngine) {
+		t.Error("expected MultiClusterEngine capability to be available")
+	}
+
+	q = newQueue()
+	h.Update(ctx, event.TypedUpdateEvent[client.Object]{ObjectOld: crd, ObjectNew: crd}, q)
+	if q.Len() != 0 {
+		t.Errorf("expected no requeue when the capability did not change, queue length = %d", q.Len())
+	}
+
+	q = newQueue()
+	h.Delete(ctx, event.TypedDeleteEvent[client.Object]{Object: crd}, q)
+	if q.Len() != 1 {
+		t.Errorf("expected the hub to be requeued when the MCE API is removed, queue length = %d", q.Len())
+	}
+	if discovery.Has(capabilities.MultiClusterEngine) {
+		t.Error("expected MultiClusterEngine capability to be unavailable")
+	}
+}
+
+func Test_capabilityEventHandler_Metadata(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apixv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := operatorv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+	crd := &apixv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "servicemonitors.monitoring.coreos.com"},
+	}
+	discovery := capabilities.New()
+	discovery.Set(capabilities.ServiceMonitor, false)
+
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(hub, crd).WithStatusSubresource(crd).Build()
+	r := &MultiClusterHubReconciler{Client: cl, Log: clog.Log.WithName("test"), Capabilities: discovery}
+	h := r.capabilityEventHandler(capabilities.CRDSource)
+	ctx := context.Background()
+
+	// The watch only carries the metadata of the CRD, its conditions are read from the API server
+	metadata := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: crd.Name}}
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	h.Create(ctx, event.TypedCreateEvent[client.Object]{Object: metadata}, q)
+	if discovery.Has(capabilities.ServiceMonitor) {
+		t.Fatal("expected ServiceMonitor to be unavailable while its CRD is not established")
+	}
+
+	crd.Status.Conditions = []apixv1.CustomResourceDefinitionCondition{
+		{Type: apixv1.Established, Status: apixv1.ConditionTrue},
+	}
+	if err := cl.Status().Update(ctx, crd); err != nil {
+		t.Fatal(err)
+	}
+	h.Update(ctx, event.TypedUpdateEvent[client.Object]{ObjectOld: metadata, ObjectNew: metadata}, q)
+	if !discovery.Has(capabilities.ServiceMonitor) {
+		t.Error("expected ServiceMonitor to be available once its CRD is established")
+	}
+	if q.Len() != 1 {
+		t.Errorf("expected the hub to be requeued when the capability appears, queue length = %d", q.Len())
+	}
+}
+
+func Test_refreshOLMVersion(t *testing.T) {
+	t.Setenv("OPERATOR_CONDITION_NAME", "")
+
+	discovery := capabilities.New()
+	r := &MultiClusterHubReconciler{
+		Log:          clog.Log.WithName("test"),
+		Capabilities: discovery,
+	}
+
+	r.refreshOLMVersion()
+	if r.OLMVersion != "" {
+		t.Errorf("OLMVersion = %q, want empty without OLM", r.OLMVersion)
+	}
+
+	discovery.Set(capabilities.OLMv1, true)
+	r.refreshOLMVersion()
+	if r.OLMVersion != "v1" {
+		t.Errorf("OLMVersion = %q, want v1 once the ClusterExtension API is served", r.OLMVersion)
+	}
+
+	status := r.capabilitiesStatus()
+	if status.OLMVersion != "v1" || len(status.Available) != 1 || status.Available[0] != string(capabilities.OLMv1) {
+		t.Errorf("capabilitiesStatus() = %+v, want OLM v1 with only the OLMv1 capability", status)
+	}
+}
+
+func Test_ensureMCEComplianceBanner_WithoutConsoleNotification(t *testing.T) {
+	registerScheme()
+
+	r := &MultiClusterHubReconciler{
+		Client:       fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		Log:          clog.Log.WithName("test"),
+		Capabilities: capabilities.New(),
+	}
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+	compliance := &operatorv1.MCEVersionComplianceStatus{
+		RequiredChannel: "stable-5.1",
+		CurrentVersion:  "5.2.0",
+		IsCompliant:     false,
+	}
+
+	ctx := context.Background()
+	if err := r.ensureMCEComplianceBanner(ctx, hub, compliance); err != nil {
+		t.Fatalf("ensureMCEComplianceBanner() error = %v", err)
+	}
+
+	notifications := &consolev1.ConsoleNotificationList{}
+	if err := r.Client.List(ctx, notifications); err != nil {
+		t.Fatalf("failed to list ConsoleNotifications: %v", err)
+	}
+	if len(notifications.Items) != 0 {
+		t.Errorf("expected no banner while the ConsoleNotification API is unavailable, found %d", len(notifications.Items))
+	}
+}
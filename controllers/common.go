@@ -35,9 +35,11 @@ import (
 	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
 
 	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/capabilities"
 	"github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine"
 	v0 "github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine/olm/v0"
 	v1 "github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine/olm/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/overrideschema"
 	"github.com/stolostron/multiclusterhub-operator/pkg/version"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -50,13 +52,15 @@ import (
 
 // CacheSpec ...
 type CacheSpec struct {
-	IngressDomain       string
-	ImageOverrides      map[string]string
-	ImageOverridesCM    string
-	ImageRepository     string
-	ManifestVersion     string
-	TemplateOverrides   map[string]string
-	TemplateOverridesCM string
+	IngressDomain           string
+	ImageOverrides          map[string]string
+	ImageOverridesCM        string
+	ImageRepository         string
+	ManifestVersion         string
+	TemplateOverrides       map[string]string
+	TemplateOverridesCM     string
+	TemplateOverrideSchemas overrideschema.Schemas
+	PreservedFields         []operatorv1.PreservedFields
 }
 
 type MigratedComponentInfo struct {
@@ -310,7 +314,7 @@ func (r *MultiClusterHubReconciler) ensureMultiClusterEngineCR(ctx context.Conte
 		// Determine target namespace from OLM resource if it exists
 		targetNS := multiclusterengine.OperandNamespace() // default
 
-		switch r.OLMVersion {
+		switch r.mceOLMVersion(m) {
 		case "v1":
 			// Check if ClusterExtension exists to get its namespace
 			ce, err := v1.GetManagedMCEClusterExtension(ctx, r.Client)
@@ -503,12 +507,14 @@ func (r *MultiClusterHubReconciler) listDeployments(namespaces []string) ([]*app
 }
 
 // listCustomResources gets custom resources the installer observes
-func (r *MultiClusterHubReconciler) listCustomResources() (map[string]*unstructured.Unstructured, error) {
+func (r *MultiClusterHubReconciler) listCustomResources(m *operatorv1.MultiClusterHub) (
+	map[string]*unstructured.Unstructured, error) {
 	ret := make(map[string]*unstructured.Unstructured)
 
-	// List OLM resources based on detected version
+	// List OLM resources based on the OLM version managing MCE
 	// Use different keys for v0 vs v1 to enable proper status mapping
-	if r.OLMVersion == "v1" {
+	olmVersion := r.mceOLMVersion(m)
+	if olmVersion == "v1" {
 		// OLM v1 path - get ClusterExtension
 		gotCE, err := v1.GetManagedMCEClusterExtension(context.Background(), r.Client)
 		if err != nil {
@@ -522,7 +528,7 @@ func (r *MultiClusterHubReconciler) listCustomResources() (map[string]*unstructu
 			}
 		}
 
-	} else if r.OLMVersion == "v0" {
+	} else if olmVersion == "v0" {
 		// OLM v0 path - get Subscription and CSV
 		gotSub, subErr := v0.GetManagedMCESubscription(context.Background(), r.Client)
 		if subErr != nil {
@@ -559,6 +565,12 @@ func (r *MultiClusterHubReconciler) listCustomResources() (map[string]*unstructu
 	}
 
 	ret["mce"] = mce
+
+	if m.Enabled(operatorv1.ClusterBackup) {
+		r.listOADPResources(context.Background(), m, ret)
+	}
+
+	r.listInternalHubComponents(context.Background(), m, ret)
 	return ret, nil
 }
 
@@ -584,13 +596,14 @@ func addInstallerLabelSecret(d *corev1.Secret, name string, ns string) bool {
 func (r *MultiClusterHubReconciler) ensureMCEInstallation(ctx context.Context, multiClusterHub *operatorv1.MultiClusterHub) (ctrl.Result, error) {
 	// If no OLM detected, skip subscription management
 	// MCE is expected to be pre-installed or managed externally
-	if r.OLMVersion == "" {
+	olmVersion := r.mceOLMVersion(multiClusterHub)
+	if olmVersion == "" {
 		r.Log.Info("No OLM detected - skipping MCE subscription management")
 		return ctrl.Result{}, nil
 	}
 
 	// Handle OLM v1 (ClusterExtension-based)
-	if r.OLMVersion == "v1" {
+	if olmVersion == "v1" {
 		return r.ensureMCEClusterExtension(ctx, multiClusterHub)
 	}
 
@@ -602,46 +615,11 @@ func (r *MultiClusterHubReconciler) ensureMCEInstallation(ctx context.Context, m
 	}
 
 	// Get sub config, catalogsource, and annotation overrides
-	subConfig, err := r.GetSubConfig()
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-	overrides, err := v0.GetAnnotationOverrides(multiClusterHub)
+	subConfig, overrides, ctlSrc, err := r.mceSubscriptionSettings(ctx, multiClusterHub)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Warn if annotation overrides conflict with desired state
-	checkSubscriptionAnnotationConflicts(r.Log, overrides)
-
-	// Get InstallPlan approval from MCH operator subscription
-	var installPlanApproval subv1alpha1.Approval = subv1alpha1.ApprovalAutomatic
-	mchOperatorSub, err := r.FindMultiClusterHubOperatorSubscription(ctx)
-	if err != nil {
-		r.Log.Info("Unable to find MultiClusterHub operator subscription, defaulting to automatic InstallPlan approval", "error", err)
-	} else {
-		installPlanApproval = r.GetInstallPlanApprovalFromSubscription(mchOperatorSub)
-		r.Log.Info("Using InstallPlan approval from MCH operator subscription", "approval", installPlanApproval)
-	}
-
-	// Apply InstallPlan approval to overrides if not already set
-	if overrides == nil {
-		overrides = &subv1alpha1.SubscriptionSpec{}
-	}
-	if overrides.InstallPlanApproval == "" {
-		overrides.InstallPlanApproval = installPlanApproval
-	}
-	ctlSrc := types.NamespacedName{}
-	// Search for catalogsource if not defined in overrides
-	if overrides == nil || overrides.CatalogSource == "" {
-		desiredChannel := multiclusterengine.DesiredChannel()
-		ctlSrc, err = v0.GetCatalogSource(r.Client, desiredChannel, desiredPackage)
-		if err != nil {
-			r.Log.Info("Failed to find a suitable catalogsource.", "error", err)
-			return ctrl.Result{}, err
-		}
-	}
-
 	createSub := false
 	namespace := multiclusterengine.Namespace()
 	operandNs := multiclusterengine.OperandNamespace()
@@ -686,6 +664,55 @@ func (r *MultiClusterHubReconciler) ensureMCEInstallation(ctx context.Context, m
 	return ctrl.Result{}, nil
 }
 
+/*
+mceSubscriptionSettings returns the config, the annotation overrides and the catalog source of the MCE subscription.
+The overrides default the InstallPlan approval to the one of the MCH operator subscription, and the catalog source is
+only searched when the overrides do not set one.
+*/
+func (r *MultiClusterHubReconciler) mceSubscriptionSettings(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	*subv1alpha1.SubscriptionConfig, *subv1alpha1.SubscriptionSpec, types.NamespacedName, error) {
+	ctlSrc := types.NamespacedName{}
+	subConfig, err := r.GetSubConfig()
+	if err != nil {
+		return nil, nil, ctlSrc, err
+	}
+	overrides, err := v0.GetAnnotationOverrides(m)
+	if err != nil {
+		return nil, nil, ctlSrc, err
+	}
+
+	// Warn if annotation overrides conflict with desired state
+	checkSubscriptionAnnotationConflicts(r.Log, overrides)
+
+	// Get InstallPlan approval from MCH operator subscription
+	var installPlanApproval subv1alpha1.Approval = subv1alpha1.ApprovalAutomatic
+	mchOperatorSub, err := r.FindMultiClusterHubOperatorSubscription(ctx)
+	if err != nil {
+		r.Log.Info("Unable to find MultiClusterHub operator subscription, defaulting to automatic InstallPlan approval", "error", err)
+	} else {
+		installPlanApproval = r.GetInstallPlanApprovalFromSubscription(mchOperatorSub)
+		r.Log.Info("Using InstallPlan approval from MCH operator subscription", "approval", installPlanApproval)
+	}
+
+	// Apply InstallPlan approval to overrides if not already set
+	if overrides == nil {
+		overrides = &subv1alpha1.SubscriptionSpec{}
+	}
+	if overrides.InstallPlanApproval == "" {
+		overrides.InstallPlanApproval = installPlanApproval
+	}
+	// Search for catalogsource if not defined in overrides
+	if overrides.CatalogSource == "" {
+		desiredChannel := multiclusterengine.DesiredChannel()
+		ctlSrc, err = v0.GetCatalogSource(r.Client, desiredChannel, multiclusterengine.DesiredPackage())
+		if err != nil {
+			r.Log.Info("Failed to find a suitable catalogsource.", "error", err)
+			return nil, nil, ctlSrc, err
+		}
+	}
+	return subConfig, overrides, ctlSrc, nil
+}
+
 // ensureMCEClusterExtension verifies resources needed for MCE are created (OLM v1 path)
 func (r *MultiClusterHubReconciler) ensureMCEClusterExtension(ctx context.Context, multiClusterHub *operatorv1.MultiClusterHub) (ctrl.Result, error) {
 	desiredPackage := multiclusterengine.DesiredPackage()
@@ -773,8 +800,14 @@ func (r *MultiClusterHubReconciler) ensureMCEClusterExtension(ctx context.Contex
 }
 
 func (r *MultiClusterHubReconciler) ensureMultiClusterEngine(ctx context.Context, multiClusterHub *operatorv1.MultiClusterHub) (ctrl.Result, error) {
+	// move MCE between OLM versions first so installation management only ever sees one owner
+	result, err := r.ensureMCEOLMMigration(ctx, multiClusterHub)
+	if result != (ctrl.Result{}) || err != nil {
+		return result, err
+	}
+
 	// confirm subscription and reqs exist and are configured correctly
-	result, err := r.ensureMCEInstallation(ctx, multiClusterHub)
+	result, err = r.ensureMCEInstallation(ctx, multiClusterHub)
 	if result != (ctrl.Result{}) || err != nil {
 		return result, err
 	}
@@ -1000,7 +1033,7 @@ func (r *MultiClusterHubReconciler) ensureSearchCR(m *operatorv1.MultiClusterHub
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "search-v2-operator",
-			Namespace: m.Namespace,
+			Namespace: m.ComponentNamespace(operatorv1.Search),
 			Labels:    map[string]string{"cluster.open-cluster-management.io/backup": ""},
 			Annotations: map[string]string{
 				utils.AnnotationFineGrainedRbac: strconv.FormatBool(
@@ -1080,11 +1113,17 @@ func (r *MultiClusterHubReconciler) ensureNoClusterManagementAddOn(m *operatorv1
 	return ctrl.Result{}, errors.NewBadRequest("ClusterManagementAddOn CR has not been deleted")
 }
 
-func (r *MultiClusterHubReconciler) ensureNoSearchCR(m *operatorv1.MultiClusterHub) (ctrl.Result, error) {
+// ensureNoSearchCR deletes the Search CR from the given namespace
+func (r *MultiClusterHubReconciler) ensureNoSearchCR(m *operatorv1.MultiClusterHub, namespace string) (ctrl.Result,
+	error) {
 	ctx := context.Background()
 
 	searchList := &searchv2v1alpha1.SearchList{}
-	err := r.Client.List(ctx, searchList, client.InNamespace(m.GetNamespace()))
+	err := r.Client.List(ctx, searchList, client.InNamespace(namespace))
+	if apimeta.IsNoMatchError(err) {
+		// The Search CRD was removed with the component
+		return ctrl.Result{}, nil
+	}
 	if err != nil {
 		r.Log.Info(fmt.Sprintf("error locating Search CR. Error: %s", err.Error()))
 		return ctrl.Result{}, err
@@ -1098,7 +1137,7 @@ func (r *MultiClusterHubReconciler) ensureNoSearchCR(m *operatorv1.MultiClusterH
 		}
 
 	}
-	err = r.Client.List(ctx, searchList, client.InNamespace(m.GetNamespace()))
+	err = r.Client.List(ctx, searchList, client.InNamespace(namespace))
 	if err != nil {
 		r.Log.Info(fmt.Sprintf("error locating Search CR. Error: %s", err.Error()))
 		return ctrl.Result{}, err
@@ -1110,9 +1149,12 @@ func (r *MultiClusterHubReconciler) ensureNoSearchCR(m *operatorv1.MultiClusterH
 	return ctrl.Result{}, nil
 }
 
-// Checks if OCP Console is enabled and return true if so. If <OCP v4.12, always return true
-// Otherwise check in the EnabledCapabilities spec for OCP console
+// Checks if OCP Console is enabled and return true if so. Returns false while the console operator API is not served.
+// If <OCP v4.12, always return true. Otherwise check in the EnabledCapabilities spec for OCP console
 func (r *MultiClusterHubReconciler) CheckConsole(ctx context.Context) (bool, error) {
+	if !r.hasCapability(capabilities.Console) {
+		return false, nil
+	}
 	versionStatus := &configv1.ClusterVersion{}
 	err := r.Client.Get(ctx, types.NamespacedName{Name: "version"}, versionStatus)
 	if err != nil {
@@ -742,7 +742,7 @@ func TestListCustomResources(t *testing.T) {
 				OLMVersion: tt.olmVersion,
 			}
 
-			result, err := reconciler.listCustomResources()
+			result, err := reconciler.listCustomResources(&operatorsv1.MultiClusterHub{})
 			if err != nil {
 				t.Errorf("listCustomResources() unexpected error: %v", err)
 				return
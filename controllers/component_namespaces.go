@@ -0,0 +1,128 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	renderer "github.com/stolostron/multiclusterhub-operator/pkg/rendering"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ComponentMovingReason is added while a component is moved to a new namespace
+const ComponentMovingReason = "ComponentMoving"
+
+// ComponentNamespace returns the custom namespace a component is installed into
+func ComponentNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Namespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+/*
+installedComponentNamespace returns the namespace a component is installed into, as recorded in the placement of its
+InternalHubComponent. An InternalHubComponent without a placement was created before components could be placed in
+custom namespaces, so its component is installed in the hub namespace. It returns an empty string when the component is
+not installed or does not support custom namespaces.
+*/
+func (r *MultiClusterHubReconciler) installedComponentNamespace(ctx context.Context, m *operatorv1.MultiClusterHub,
+	component string) (string, error) {
+	if !slices.Contains(operatorv1.CustomNamespaceComponents, component) {
+		return "", nil
+	}
+
+	ihc := &operatorv1.InternalHubComponent{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: component, Namespace: m.GetNamespace()}, ihc); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get InternalHubComponent: %s/%s: %v", m.GetNamespace(), component, err)
+	}
+	if ihc.Spec.Placement == nil || ihc.Spec.Placement.Namespace == "" {
+		return m.GetNamespace(), nil
+	}
+	return ihc.Spec.Placement.Namespace, nil
+}
+
+/*
+ensureComponentMoved removes a component from the namespace it is installed into once its namespace is changed, so that
+it is installed into the new namespace afterwards. The namespaced resources of the component are deleted from the
+previous namespace and a requeue is returned until they are gone, so the component never runs in both namespaces.
+Cluster-scoped resources are updated in place when the component is installed again, and the previous namespace is
+left in place.
+*/
+func (r *MultiClusterHubReconciler) ensureComponentMoved(ctx context.Context, m *operatorv1.MultiClusterHub,
+	component string, cachespec CacheSpec, isSTSEnabled bool) (ctrl.Result, error) {
+	previous, err := r.installedComponentNamespace(ctx, m, component)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	namespace := m.ComponentNamespace(component)
+	if previous == "" || previous == namespace {
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Moving component to a new namespace", "Component", component, "From", previous, "To", namespace)
+	condition := NewHubCondition(operatorv1.Progressing, metav1.ConditionTrue, ComponentMovingReason,
+		fmt.Sprintf("Moving component %s from namespace %s to %s", component, previous, namespace))
+	SetHubCondition(&m.Status, *condition)
+
+	if component == operatorv1.Search {
+		if result, err := r.ensureNoSearchCR(m, previous); result != (ctrl.Result{}) || err != nil {
+			return result, err
+		}
+	}
+
+	templates, errs := renderer.RenderComponentChart(r.fetchChartLocation(component), m, previous,
+		cachespec.ImageOverrides, cachespec.TemplateOverrides, isSTSEnabled, r.OLMVersion)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Info(err.Error())
+		}
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	// Only the namespaced resources are deleted, including the NetworkPolicies that ensureNetworkPolicies creates in the
+	// new namespace
+	for _, template := range templates {
+		if template.GetNamespace() != previous {
+			continue
+		}
+		if result, err := r.deleteTemplate(ctx, m, template); result != (ctrl.Result{}) || err != nil {
+			return result, err
+		}
+	}
+
+	log.Info("Component removed from its previous namespace", "Component", component, "Namespace", previous)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,100 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func Test_ensureComponentMoved(t *testing.T) {
+	registerScheme()
+	_ = promv1.AddToScheme(scheme.Scheme)
+	setChartEnv(t)
+	ctx := context.TODO()
+
+	installerLabels := map[string]string{"installer.name": "mch", "installer.namespace": "ocm"}
+	mch := newTestMCH("mch", "ocm", nil, operatorv1.ComponentConfig{
+		Name: operatorv1.Insights, Enabled: true, Namespace: "insights",
+	})
+	ihc := &operatorv1.InternalHubComponent{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorv1.Insights, Namespace: "ocm"},
+		Spec: operatorv1.InternalHubComponentSpec{
+			Placement: &operatorv1.ComponentPlacement{Namespace: "ocm"},
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "insights-client", Namespace: "ocm", Labels: installerLabels},
+	}
+	// The pull secret role of insights is not in the component namespace, it is updated in place
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "open-cluster-management:insights:insights-client",
+			Namespace: "openshift-config", Labels: installerLabels},
+	}
+	r := newTestReconciler(ihc, deployment, role)
+
+	result, err := r.ensureComponentMoved(ctx, mch, operatorv1.Insights, r.CacheSpec, false)
+	if err != nil || result != (ctrl.Result{}) {
+		t.Fatalf("ensureComponentMoved() = %v, %v, want the component removed from its previous namespace", result,
+			err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: "ocm"},
+		&appsv1.Deployment{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the deployment to be deleted from the previous namespace, got %v", err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: role.Name, Namespace: role.Namespace},
+		&rbacv1.Role{}); err != nil {
+		t.Errorf("expected the role outside of the component namespace to be kept, got %v", err)
+	}
+	if c := GetHubCondition(mch.Status, operatorv1.Progressing); c == nil || c.Reason != ComponentMovingReason {
+		t.Errorf("Progressing condition = %v, want reason %s", c, ComponentMovingReason)
+	}
+
+	// Nothing is removed once the component is installed into its namespace
+	ihc.Spec.Placement.Namespace = "insights"
+	moved := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "insights-client", Namespace: "insights", Labels: installerLabels},
+	}
+	r = newTestReconciler(ihc, moved)
+	if _, err := r.ensureComponentMoved(ctx, mch, operatorv1.Insights, r.CacheSpec, false); err != nil {
+		t.Fatalf("ensureComponentMoved() error = %v", err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: moved.Name, Namespace: moved.Namespace},
+		&appsv1.Deployment{}); err != nil {
+		t.Errorf("expected the deployment in the component namespace to be kept, got %v", err)
+	}
+
+	// Components installed into their own namespace are never moved
+	backup := &operatorv1.InternalHubComponent{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorv1.ClusterBackup, Namespace: "ocm"},
+		Spec: operatorv1.InternalHubComponentSpec{
+			Placement: &operatorv1.ComponentPlacement{Namespace: "open-cluster-management-backup"},
+		},
+	}
+	r = newTestReconciler(backup)
+	if namespace, err := r.installedComponentNamespace(ctx, mch, operatorv1.ClusterBackup); err != nil ||
+		namespace != "" {
+		t.Errorf("installedComponentNamespace() = %q, %v, want no namespace", namespace, err)
+	}
+
+	// An InternalHubComponent without a placement was created when components were installed in the hub namespace
+	legacy := &operatorv1.InternalHubComponent{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorv1.Insights, Namespace: "ocm"},
+	}
+	r = newTestReconciler(legacy)
+	if namespace, err := r.installedComponentNamespace(ctx, mch, operatorv1.Insights); err != nil ||
+		namespace != "ocm" {
+		t.Errorf("installedComponentNamespace() = %q, %v, want the hub namespace", namespace, err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// FailedRenderingComponent is the ComponentFailure reason when only component renders failed
+	FailedRenderingComponent = "FailedRenderingComponent"
+)
+
+// setComponentRenderResult records the result of rendering the chart of a component in the hub status
+func setComponentRenderResult(m *operatorv1.MultiClusterHub, component string, errs []error) {
+	result := operatorv1.ComponentOperationResult{Succeeded: len(errs) == 0}
+	if !result.Succeeded {
+		result.Category = operatorv1.ComponentErrorRender
+		result.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	setComponentResult(m, component, false, result)
+}
+
+// setComponentApplyResult records the result of applying the rendered resources of a component in the hub status.
+// template is the resource that failed to apply, or nil if all resources were applied.
+func setComponentApplyResult(m *operatorv1.MultiClusterHub, component string, template *unstructured.Unstructured,
+	err error) {
+	result := operatorv1.ComponentOperationResult{Succeeded: err == nil}
+	if err != nil {
+		result.Category = componentErrorCategory(err)
+		result.Message = err.Error()
+		if template != nil {
+			result.FailingObjects = []operatorv1.FailingObjectReference{
+				{
+					APIVersion: template.GetAPIVersion(),
+					Kind:       template.GetKind(),
+					Namespace:  template.GetNamespace(),
+					Name:       template.GetName(),
+				},
+			}
+		}
+	}
+	setComponentResult(m, component, true, result)
+}
+
+func setComponentResult(m *operatorv1.MultiClusterHub, component string, apply bool,
+	result operatorv1.ComponentOperationResult) {
+	if m.Status.ComponentReconcile == nil {
+		m.Status.ComponentReconcile = map[string]operatorv1.ComponentReconcileStatus{}
+	}
+	status := m.Status.ComponentReconcile[component]
+
+	previous := status.LastRender
+	if apply {
+		previous = status.LastApply
+	}
+	if previous != nil && previous.Succeeded == result.Succeeded {
+		result.LastTransitionTime = previous.LastTransitionTime
+	} else {
+		result.LastTransitionTime = metav1.Now()
+	}
+
+	if apply {
+		status.LastApply = &result
+	} else {
+		status.LastRender = &result
+	}
+	m.Status.ComponentReconcile[component] = status
+}
+
+// componentErrorCategory classifies an error returned while applying a component resource
+func componentErrorCategory(err error) operatorv1.ComponentErrorCategory {
+	switch {
+	case meta.IsNoMatchError(err):
+		return operatorv1.ComponentErrorMissingCRD
+	case errors.IsInvalid(err), errors.IsBadRequest(err):
+		return operatorv1.ComponentErrorValidation
+	case errors.IsConflict(err), errors.IsAlreadyExists(err):
+		return operatorv1.ComponentErrorConflict
+	case errors.IsForbidden(err), errors.IsUnauthorized(err):
+		return operatorv1.ComponentErrorForbidden
+	}
+	return operatorv1.ComponentErrorUnknown
+}
+
+// enabledComponentReconcile returns the reconcile results of the components that are still enabled
+func enabledComponentReconcile(hub *operatorv1.MultiClusterHub) map[string]operatorv1.ComponentReconcileStatus {
+	if len(hub.Status.ComponentReconcile) == 0 {
+		return nil
+	}
+
+	results := map[string]operatorv1.ComponentReconcileStatus{}
+	for component, result := range hub.Status.ComponentReconcile {
+		if hub.Enabled(component) {
+			results[component] = *result.DeepCopy()
+		}
+	}
+	return results
+}
+
+/*
+componentFailureCondition derives the ComponentFailure hub condition from the component reconcile results. It returns
+nil when no component is failing.
+*/
+func componentFailureCondition(results map[string]operatorv1.ComponentReconcileStatus) *operatorv1.HubCondition {
+	var failures []string
+	reason := FailedRenderingComponent
+	for component, result := range results {
+		if !result.Failed() {
+			continue
+		}
+		if result.LastRender != nil && !result.LastRender.Succeeded {
+			failures = append(failures, fmt.Sprintf("%s (%s)", component, result.LastRender.Category))
+			continue
+		}
+		reason = FailedApplyingComponent
+		failures = append(failures, fmt.Sprintf("%s (%s)", component, result.LastApply.Category))
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	sort.Strings(failures)
+	return NewHubCondition(operatorv1.ComponentFailure, metav1.ConditionTrue, reason,
+		fmt.Sprintf("Components failed to reconcile: %s. See status.componentReconcile for details.",
+			strings.Join(failures, ", ")))
+}
@@ -0,0 +1,155 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_componentErrorCategory(t *testing.T) {
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		name string
+		err  error
+		want operatorv1.ComponentErrorCategory
+	}{
+		{
+			name: "missing CRD",
+			err: &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "example.io", Kind: "Widget"},
+				SearchedVersions: []string{"v1"}},
+			want: operatorv1.ComponentErrorMissingCRD,
+		},
+		{
+			name: "invalid",
+			err:  errors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "search-api", nil),
+			want: operatorv1.ComponentErrorValidation,
+		},
+		{
+			name: "conflict",
+			err:  errors.NewConflict(gr, "search-api", fmt.Errorf("object was modified")),
+			want: operatorv1.ComponentErrorConflict,
+		},
+		{
+			name: "wrapped forbidden",
+			err: pkgerrors.Wrapf(errors.NewForbidden(gr, "search-api", fmt.Errorf("denied")),
+				"failed to update resource"),
+			want: operatorv1.ComponentErrorForbidden,
+		},
+		{
+			name: "other",
+			err:  fmt.Errorf("connection refused"),
+			want: operatorv1.ComponentErrorUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := componentErrorCategory(tt.err); got != tt.want {
+				t.Errorf("componentErrorCategory() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setComponentResults(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{}
+	template := &unstructured.Unstructured{}
+	template.SetAPIVersion("apps/v1")
+	template.SetKind("Deployment")
+	template.SetNamespace("open-cluster-management")
+	template.SetName("search-api")
+
+	setComponentRenderResult(hub, operatorv1.Search, nil)
+	setComponentApplyResult(hub, operatorv1.Search, template,
+		errors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "search-api",
+			fmt.Errorf("denied")))
+
+	got := hub.Status.ComponentReconcile[operatorv1.Search]
+	if !got.Failed() || !got.LastRender.Succeeded {
+		t.Fatalf("expected a successful render and a failed apply, got %+v", got)
+	}
+	if got.LastApply.Category != operatorv1.ComponentErrorForbidden {
+		t.Errorf("LastApply.Category = %s, want %s", got.LastApply.Category, operatorv1.ComponentErrorForbidden)
+	}
+	want := []operatorv1.FailingObjectReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "open-cluster-management", Name: "search-api"},
+	}
+	if len(got.LastApply.FailingObjects) != 1 || got.LastApply.FailingObjects[0] != want[0] {
+		t.Errorf("LastApply.FailingObjects = %v, want %v", got.LastApply.FailingObjects, want)
+	}
+
+	// The transition time is kept while the result does not change
+	transition := metav1.NewTime(got.LastApply.LastTransitionTime.Add(-1))
+	got.LastApply.LastTransitionTime = transition
+	setComponentApplyResult(hub, operatorv1.Search, template, fmt.Errorf("still failing"))
+	if got := hub.Status.ComponentReconcile[operatorv1.Search]; !got.LastApply.LastTransitionTime.Equal(&transition) {
+		t.Errorf("expected the transition time to be kept, got %v", got.LastApply.LastTransitionTime)
+	}
+
+	setComponentApplyResult(hub, operatorv1.Search, nil, nil)
+	got = hub.Status.ComponentReconcile[operatorv1.Search]
+	if got.Failed() || got.LastApply.Message != "" || len(got.LastApply.FailingObjects) != 0 {
+		t.Errorf("expected the component to no longer fail, got %+v", got.LastApply)
+	}
+}
+
+func Test_componentFailureCondition(t *testing.T) {
+	if got := componentFailureCondition(map[string]operatorv1.ComponentReconcileStatus{
+		operatorv1.Search: {LastRender: &operatorv1.ComponentOperationResult{Succeeded: true}},
+	}); got != nil {
+		t.Errorf("componentFailureCondition() = %+v, want nil", got)
+	}
+
+	got := componentFailureCondition(map[string]operatorv1.ComponentReconcileStatus{
+		operatorv1.Search: {
+			LastRender: &operatorv1.ComponentOperationResult{Succeeded: true},
+			LastApply: &operatorv1.ComponentOperationResult{
+				Category: operatorv1.ComponentErrorConflict,
+			},
+		},
+		operatorv1.Console: {
+			LastRender: &operatorv1.ComponentOperationResult{Category: operatorv1.ComponentErrorRender},
+		},
+	})
+	if got == nil || got.Type != operatorv1.ComponentFailure || got.Reason != FailedApplyingComponent {
+		t.Fatalf("componentFailureCondition() = %+v, want a ComponentFailure condition", got)
+	}
+	if !strings.Contains(got.Message, "console (Render), search (Conflict)") {
+		t.Errorf("componentFailureCondition() message = %q, want the failing components", got.Message)
+	}
+}
+
+func Test_enabledComponentReconcile(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{
+		Spec: operatorv1.MultiClusterHubSpec{
+			Overrides: &operatorv1.Overrides{
+				Components: []operatorv1.ComponentConfig{
+					{Name: operatorv1.Search, Enabled: true},
+					{Name: operatorv1.Console, Enabled: false},
+				},
+			},
+		},
+		Status: operatorv1.MultiClusterHubStatus{
+			ComponentReconcile: map[string]operatorv1.ComponentReconcileStatus{
+				operatorv1.Search:  {LastApply: &operatorv1.ComponentOperationResult{Succeeded: true}},
+				operatorv1.Console: {LastApply: &operatorv1.ComponentOperationResult{}},
+			},
+		},
+	}
+
+	got := enabledComponentReconcile(hub)
+	if _, ok := got[operatorv1.Console]; ok || len(got) != 1 {
+		t.Errorf("enabledComponentReconcile() = %v, want only the enabled components", got)
+	}
+}
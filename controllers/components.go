@@ -83,15 +83,34 @@ func (r *MultiClusterHubReconciler) fetchChartLocation(component string) string
 	}
 }
 
-// ensureComponentNamespaces creates namespaces for components that deploy to a separate namespace.
+// ensureComponentNamespaces creates namespaces for components that deploy to a separate namespace, including the
+// custom namespaces of enabled components.
 // Must run before ensureNetworkPolicies so policies can target these namespaces.
 func (r *MultiClusterHubReconciler) ensureComponentNamespaces(m *operatorv1.MultiClusterHub) (ctrl.Result, error) {
+	// In restricted mode an admin creates the namespaces
+	if r.RestrictedMode {
+		namespaces := []*corev1.Namespace{}
+		if m.Enabled(operatorv1.ClusterBackup) {
+			namespaces = append(namespaces, BackupNamespace())
+		}
+		for _, ns := range utils.ComponentNamespaces(m) {
+			namespaces = append(namespaces, ComponentNamespace(ns))
+		}
+		return r.ensureRestrictedNamespaces(context.TODO(), m, namespaces)
+	}
+
 	if m.Enabled(operatorv1.ClusterBackup) {
 		result, err := r.ensureNamespaceAndPullSecret(m, BackupNamespace())
 		if result != (ctrl.Result{}) || err != nil {
 			return result, err
 		}
 	}
+	for _, ns := range utils.ComponentNamespaces(m) {
+		result, err := r.ensureNamespaceAndPullSecret(m, ComponentNamespace(ns))
+		if result != (ctrl.Result{}) || err != nil {
+			return result, err
+		}
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -103,7 +122,7 @@ func (r *MultiClusterHubReconciler) ensureComponentOrNoComponent(ctx context.Con
 	if !m.Enabled(component) {
 		if component == operatorv1.ClusterBackup {
 			result, err = r.ensureNoComponent(ctx, m, component, cachespec, isSTSEnabled)
-			if result != (ctrl.Result{}) || err != nil {
+			if result != (ctrl.Result{}) || err != nil || r.RestrictedMode {
 				return result, err
 			}
 			return r.ensureNoNamespace(m, BackupNamespaceUnstructured())
@@ -150,17 +169,20 @@ func (r *MultiClusterHubReconciler) ensureComponent(ctx context.Context, m *oper
 		return ctrl.Result{}, nil
 	}
 
-	chartLocation := r.fetchChartLocation(component)
-
-	// Ensure that the InternalHubComponent CR instance is created for each component in MCH.
-	if result, err := r.ensureInternalHubComponent(ctx, m, component); err != nil {
+	// A component whose namespace changed is removed from its previous namespace before it is installed again
+	if result, err := r.ensureComponentMoved(ctx, m, component, cachespec, isSTSEnabled); result != (ctrl.Result{}) ||
+		err != nil {
 		return result, err
 	}
 
+	chartLocation := r.fetchChartLocation(component)
+
 	// Renders all templates from charts
-	templates, errs := renderer.RenderChart(chartLocation, m, cachespec.ImageOverrides, cachespec.TemplateOverrides,
-		isSTSEnabled, r.OLMVersion)
+	templates, errs := renderer.RenderComponentChart(chartLocation, m, m.ComponentNamespace(component),
+		cachespec.ImageOverrides, cachespec.TemplateOverrides, isSTSEnabled, r.OLMVersion)
 
+	setComponentRenderResult(m, component, errs)
+	setComponentTemplateOverrides(m, component, chartLocation, cachespec)
 	if len(errs) > 0 {
 		for _, err := range errs {
 			log.Info(err.Error())
@@ -168,8 +190,17 @@ func (r *MultiClusterHubReconciler) ensureComponent(ctx context.Context, m *oper
 		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
 	}
 
+	// The hub status tracks the workloads of the rendered templates
+	r.recordStatusWorkloads(m, component, templates)
+
+	// The RBAC audit compares the permissions of the rendered templates with the permissions of the operator
+	r.recordRBACRequirements(component, templates)
+
+	// The API server calls the services of the rendered APIServices, they are verified in the hub status
+	r.recordServiceBackends(component, []string{component}, templates)
+
 	// Apply overrides if available for the component
-	if componentConfig, found := r.getComponentConfig(m.Spec.Overrides.Components, component); found {
+	if componentConfig, found := r.getComponentConfig(configuredComponents(m), component); found {
 		for _, template := range templates {
 			if ok := template.GetKind() == "Deployment"; ok {
 				if deploymentConfig, found := r.getDeploymentConfig(componentConfig.ConfigOverrides.Deployments,
@@ -191,7 +222,64 @@ func (r *MultiClusterHubReconciler) ensureComponent(ctx context.Context, m *oper
 		log.V(2).Info("No component config found", "Component", component)
 	}
 
-	// Applies all templates
+	// Apply probe tuning from spec.probes
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		if err := applyProbeSettings(template, m.EffectiveProbeSettings(component, template.GetName())); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Mount the hub trust bundle and set the cluster proxy in the component deployments
+	for _, template := range templates {
+		if template.GetKind() != "Deployment" {
+			continue
+		}
+		if err := injectTrustConfig(template, m.Status.TrustBundle); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Adjust the OADP install to an existing OADP installation
+	if component == operatorv1.ClusterBackup {
+		var err error
+		if templates, err = r.reconcileOADPTemplates(ctx, m, templates); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In restricted mode an admin applies the cluster-scoped templates, the component waits until they are present
+	if r.RestrictedMode {
+		var external []*unstructured.Unstructured
+		templates, external = r.splitRestrictedTemplates(m, templates)
+		if len(external) == 0 {
+			if err := r.ensureNoRestrictedBundle(ctx, m, component); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else {
+			missing, err := r.ensureRestrictedBundle(ctx, m, component, external)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if len(missing) > 0 {
+				log.Info("Resources of the restricted mode bundle are not applied", "Component", component,
+					"Missing", len(missing))
+				return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+			}
+		}
+	}
+
+	// Ensure that the InternalHubComponent CR instance of the component describes the templates being applied.
+	if result, err := r.ensureInternalHubComponent(ctx, m, component, templates); err != nil {
+		return result, err
+	}
+
+	// Deployments of highly available hubs are rolled out to a new release one at a time, and the resources of a
+	// component are recorded before any of them is applied so the component can be rolled back
+	recorded := map[string]*rolloutManifest{}
+	var apply []*unstructured.Unstructured
 	for _, template := range templates {
 		// Skip NetworkPolicy resources - they are managed by ensureNetworkPolicies with create-once pattern
 		if template.GetKind() == "NetworkPolicy" {
@@ -204,14 +292,37 @@ func (r *MultiClusterHubReconciler) ensureComponent(ctx context.Context, m *oper
 		}
 		annotations[utils.AnnotationReleaseVersion] = version.Version
 		template.SetAnnotations(annotations)
+
+		hold, err := r.holdRolloutTemplate(ctx, m, component, template, recorded)
+		if err != nil {
+			setComponentApplyResult(m, component, template, err)
+			return ctrl.Result{}, err
+		}
+		if !hold {
+			apply = append(apply, template)
+		}
+	}
+	if err := r.recordRolloutManifests(ctx, m, recorded); err != nil {
+		setComponentApplyResult(m, component, nil, err)
+		return ctrl.Result{}, err
+	}
+
+	// Applies all templates
+	for _, template := range apply {
 		result, err := r.applyTemplate(ctx, m, template)
 		if err != nil {
+			setComponentApplyResult(m, component, template, err)
 			return result, err
 		}
 	}
+	setComponentApplyResult(m, component, nil, nil)
 
 	switch component {
 	case operatorv1.Console:
+		if r.RestrictedMode {
+			r.verifyConsolePlugin(ctx, m)
+			return ctrl.Result{}, nil
+		}
 		return r.addPluginToConsole(m)
 
 	case operatorv1.Search:
@@ -233,9 +344,21 @@ func (r *MultiClusterHubReconciler) ensureNoComponent(ctx context.Context, m *op
 		return ctrl.Result{}, nil
 	}
 
+	// The component is removed from the namespace it is installed into
+	namespace, err := r.installedComponentNamespace(ctx, m, component)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if namespace == "" {
+		namespace = m.ComponentNamespace(component)
+	}
+
 	if result, err := r.ensureNoInternalHubComponent(ctx, m, component); result != (ctrl.Result{}) || err != nil {
 		return result, err
 	}
+	r.statusWorkloads.remove(component)
+	r.rbacRequirements.remove(component)
+	r.serviceBackends.remove(component)
 
 	chartLocation := r.fetchChartLocation(component)
 
@@ -251,14 +374,17 @@ func (r *MultiClusterHubReconciler) ensureNoComponent(ctx context.Context, m *op
 			return ctrl.Result{}, nil
 		}
 
-		result, err := r.removePluginFromConsole()
-		if result != (ctrl.Result{}) {
-			return result, err
+		// In restricted mode an admin removes the plugin from the console
+		if !r.RestrictedMode {
+			result, err := r.removePluginFromConsole()
+			if result != (ctrl.Result{}) {
+				return result, err
+			}
 		}
 
 	// SearchV2
 	case operatorv1.Search:
-		result, err := r.ensureNoSearchCR(m)
+		result, err := r.ensureNoSearchCR(m, namespace)
 		if err != nil {
 			return result, err
 		}
@@ -268,9 +394,11 @@ func (r *MultiClusterHubReconciler) ensureNoComponent(ctx context.Context, m *op
 	   removing the submariner-addon component.
 	*/
 	case operatorv1.SubmarinerAddon:
-		result, err := r.ensureNoClusterManagementAddOn(m, component)
-		if err != nil {
-			return result, err
+		if !r.RestrictedMode {
+			result, err := r.ensureNoClusterManagementAddOn(m, component)
+			if err != nil {
+				return result, err
+			}
 		}
 	}
 
@@ -288,8 +416,8 @@ func (r *MultiClusterHubReconciler) ensureNoComponent(ctx context.Context, m *op
 	}
 
 	// Renders all templates from charts
-	templates, errs := renderer.RenderChart(chartLocation, m, imageOverrides, cachespec.TemplateOverrides,
-		isSTSEnabled, r.OLMVersion)
+	templates, errs := renderer.RenderComponentChart(chartLocation, m, namespace, imageOverrides,
+		cachespec.TemplateOverrides, isSTSEnabled, r.OLMVersion)
 
 	if len(errs) > 0 {
 		for _, err := range errs {
@@ -298,6 +426,11 @@ func (r *MultiClusterHubReconciler) ensureNoComponent(ctx context.Context, m *op
 		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
 	}
 
+	// In restricted mode an admin removes the cluster-scoped templates with the bundle
+	if r.RestrictedMode {
+		templates = r.namespacedTemplates(templates)
+	}
+
 	// Deletes all templates
 	for _, template := range templates {
 		// Skip NetworkPolicy resources - they are managed by ensureNetworkPolicies with create-once pattern
@@ -313,6 +446,11 @@ func (r *MultiClusterHubReconciler) ensureNoComponent(ctx context.Context, m *op
 			return result, err
 		}
 	}
+	if r.RestrictedMode {
+		if err := r.ensureNoRestrictedBundle(ctx, m, component); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 	return ctrl.Result{}, nil
 }
 
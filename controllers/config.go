@@ -99,14 +99,12 @@ func (r *MultiClusterHubReconciler) setDefaults(m *operatorv1.MultiClusterHub, o
 
 	updateNecessary := false
 
-	defaultUpdate, err := utils.SetDefaultComponents(m)
+	// Components that are not configured follow the profile, they are resolved without writing them to the spec
+	profileStatus, err := resolveProfile(m)
 	if err != nil {
-		log.Error(err, "OPERATOR_CATALOG is an illegal value")
+		log.Error(err, "Failed to resolve the hub profile")
 		return ctrl.Result{}, err
 	}
-	if defaultUpdate {
-		updateNecessary = true
-	}
 
 	// Add finalizer for this CR
 	if controllerutil.AddFinalizer(m, hubFinalizer) {
@@ -139,7 +137,7 @@ func (r *MultiClusterHubReconciler) setDefaults(m *operatorv1.MultiClusterHub, o
 		}
 	}
 
-	for _, c := range m.Spec.Overrides.Components {
+	for _, c := range configuredComponents(m) {
 		if !operatorv1.ValidComponent(c, operatorv1.MCHComponents) {
 			if m.Prune(c.Name) {
 				log.Info(fmt.Sprintf("Removing invalid component: %v from existing MultiClusterHub", c.Name))
@@ -148,7 +146,8 @@ func (r *MultiClusterHubReconciler) setDefaults(m *operatorv1.MultiClusterHub, o
 		}
 	}
 
-	if !operatorv1.AvailabilityConfigIsValid(m.Spec.AvailabilityConfig) {
+	// A hub with a profile and no availability runs with the availability of the profile
+	if !utils.MchIsValid(m) {
 		m.Spec.AvailabilityConfig = operatorv1.HAHigh
 		updateNecessary = true
 	}
@@ -158,6 +157,11 @@ func (r *MultiClusterHubReconciler) setDefaults(m *operatorv1.MultiClusterHub, o
 		updateNecessary = true
 	}
 
+	// The profile resolution is recorded once the spec is up to date, updating the hub resets its status
+	if !updateNecessary {
+		m.Status.Profile = profileStatus
+	}
+
 	if utils.MchIsValid(m) && os.Getenv("ACM_HUB_OCP_VERSION") != "" && !updateNecessary {
 		return ctrl.Result{}, nil
 	}
@@ -199,6 +203,7 @@ func (r *MultiClusterHubReconciler) setDefaults(m *operatorv1.MultiClusterHub, o
 			return ctrl.Result{}, err
 		}
 		r.Log.Info("MultiClusterHub successfully updated")
+		m.Status.Profile = profileStatus
 		return ctrl.Result{Requeue: true}, nil
 
 	}
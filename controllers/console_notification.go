@@ -10,6 +10,7 @@ import (
 	"github.com/go-logr/logr"
 	consolev1 "github.com/openshift/api/console/v1"
 	operatorsv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/capabilities"
 	"github.com/stolostron/multiclusterhub-operator/pkg/version"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -47,6 +48,10 @@ func (r *MultiClusterHubReconciler) ensureMCEComplianceBanner(ctx context.Contex
 	hub *operatorsv1.MultiClusterHub,
 	compliance *operatorsv1.MCEVersionComplianceStatus) error {
 
+	if !r.hasCapability(capabilities.ConsoleNotification) {
+		return nil
+	}
+
 	if compliance == nil || compliance.IsCompliant {
 		return r.removeMCEComplianceBanner(ctx)
 	}
@@ -0,0 +1,357 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CRDStorageMigrationBlockedReason is added when the custom resources of a CRD cannot be migrated to its storage
+	// version before versions are removed from it
+	CRDStorageMigrationBlockedReason = "CRDStorageMigrationBlocked"
+
+	// CRDStorageMigrationPendingReason is added while the API server does not store the custom resources of a CRD in
+	// its new storage version yet
+	CRDStorageMigrationPendingReason = "CRDStorageMigrationPending"
+
+	// crdMigrationPageSize is the number of custom resources listed at once during a storage migration
+	crdMigrationPageSize = 500
+)
+
+/*
+crdComponents maps the subdirectories of the CRD directory to the components the CRDs are installed for. The CRDs are
+needed while any of the components is enabled. The CRDs of the other subdirectories are always installed.
+*/
+var crdComponents = map[string][]string{
+	"cluster-backup":                      {operatorv1.ClusterBackup},
+	"cluster-lifecycle":                   {operatorv1.ClusterLifecycle},
+	"console":                             {operatorv1.Console},
+	"fine-grained-rbac":                   {operatorv1.FineGrainedRbac, operatorv1.FineGrainedRbacPreview},
+	"grc":                                 {operatorv1.GRC},
+	"insights":                            {operatorv1.Insights},
+	"multicloud-operators-subscription":   {operatorv1.Appsub},
+	"multicluster-observability-operator": {operatorv1.MultiClusterObservability},
+	"search-v2-operator":                  {operatorv1.Search},
+	"siteconfig-operator":                 {operatorv1.SiteConfig},
+	"submariner-addon":                    {operatorv1.SubmarinerAddon},
+}
+
+/*
+removableCRDComponent returns the component the CRDs of a subdirectory of the CRD directory are removed for: a
+component listed in the remove disabled CRDs annotation, when none of the components of the CRDs is enabled.
+*/
+func removableCRDComponent(m *operatorv1.MultiClusterHub, dir string) (string, bool) {
+	components, ok := crdComponents[dir]
+	if !ok {
+		return "", false
+	}
+	removable := ""
+	listed := utils.GetRemoveDisabledCRDs(m)
+	for _, component := range components {
+		if m.Enabled(component) {
+			return "", false
+		}
+		if removable == "" && utils.Contains(listed, component) {
+			removable = component
+		}
+	}
+	return removable, removable != ""
+}
+
+/*
+migrateCRDStorage migrates the custom resources of an installed CRD when the rendered CRD removes versions listed in
+its status.storedVersions, which the API server refuses. The installed CRD is first switched to the storage version of
+the rendered CRD. On a later reconcile, once the CRD is established again and its status lists the storage version in
+status.storedVersions, every custom resource is rewritten twice: the second pass stores again the custom resources an
+API server still handling the previous storage version wrote during the first one. The removed versions are then
+dropped from status.storedVersions. It returns false while the API server does not store the storage version yet.
+*/
+func (r *MultiClusterHubReconciler) migrateCRDStorage(ctx context.Context, m *operatorv1.MultiClusterHub,
+	rendered *unstructured.Unstructured) (bool, error) {
+	desired := &apixv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rendered.Object, desired); err != nil {
+		return false, fmt.Errorf("failed to read CRD %s: %w", rendered.GetName(), err)
+	}
+
+	existing := &apixv1.CustomResourceDefinition{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: desired.Name}, existing); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	dropped := droppedStoredVersions(existing, desired)
+	if len(dropped) == 0 {
+		return true, nil
+	}
+	storage := storageVersion(desired)
+	if storage == "" {
+		return false, fmt.Errorf("CRD %s has no storage version", desired.Name)
+	}
+
+	stored := append([]string{}, existing.Status.StoredVersions...)
+	r.Log.Info("Migrating custom resources before versions are removed from their CRD", "CRD", desired.Name,
+		"StoredVersions", stored, "StorageVersion", storage)
+	setCRDMigration(m, desired.Name, stored, storage, metav1.ConditionUnknown,
+		fmt.Sprintf("migrating the custom resources stored in %s", strings.Join(dropped, ", ")))
+
+	fail := func(err error) error {
+		setCRDMigration(m, desired.Name, stored, storage, metav1.ConditionFalse, err.Error())
+		return fmt.Errorf("storage migration of CRD %s to %s failed: %w", desired.Name, storage, err)
+	}
+
+	waiting := func() {
+		r.Log.Info("Waiting for the API server to store custom resources in the storage version of their CRD",
+			"CRD", desired.Name, "StorageVersion", storage)
+		setCRDMigration(m, desired.Name, stored, storage, metav1.ConditionUnknown,
+			fmt.Sprintf("waiting for the API server to store custom resources in %s", storage))
+	}
+
+	// Custom resources rewritten right after the storage version is switched could still be stored in a removed
+	// version by an API server that did not pick up the change, the rewrite waits for a later reconcile
+	switched, err := r.switchCRDStorageVersion(ctx, existing, desired, storage)
+	if err != nil {
+		return false, fail(err)
+	}
+	if switched {
+		waiting()
+		return false, nil
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: desired.Name}, existing); err != nil {
+		return false, fail(err)
+	}
+	if !crdEstablished(existing) || !slices.Contains(existing.Status.StoredVersions, storage) {
+		waiting()
+		return false, nil
+	}
+
+	migrated, err := r.rewriteCustomResources(ctx, existing.Spec.Group, storage, existing.Spec.Names.ListKind)
+	if err != nil {
+		return false, fail(err)
+	}
+	if _, err := r.rewriteCustomResources(ctx, existing.Spec.Group, storage, existing.Spec.Names.ListKind); err != nil {
+		return false, fail(err)
+	}
+
+	// Every custom resource is stored in the storage version, the other versions can be removed
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: desired.Name}, existing); err != nil {
+		return false, fail(err)
+	}
+	existing.Status.StoredVersions = []string{storage}
+	if err := r.Client.Status().Update(ctx, existing); err != nil {
+		return false, fail(err)
+	}
+
+	r.Log.Info("Migrated custom resources to the storage version of their CRD", "CRD", desired.Name,
+		"StorageVersion", storage, "Migrated", migrated)
+	setCRDMigration(m, desired.Name, stored, storage, metav1.ConditionTrue,
+		fmt.Sprintf("migrated %d custom resources", migrated))
+	return true, nil
+}
+
+/*
+switchCRDStorageVersion makes the installed CRD serve and store the storage version of the rendered CRD. It returns
+true when the installed CRD was updated.
+*/
+func (r *MultiClusterHubReconciler) switchCRDStorageVersion(ctx context.Context,
+	existing, desired *apixv1.CustomResourceDefinition, storage string) (bool, error) {
+	if storageVersion(existing) == storage {
+		return false, nil
+	}
+
+	found := false
+	for i := range existing.Spec.Versions {
+		version := &existing.Spec.Versions[i]
+		version.Storage = version.Name == storage
+		if version.Storage {
+			version.Served = true
+			found = true
+		}
+	}
+	if !found {
+		for _, version := range desired.Spec.Versions {
+			if version.Name == storage {
+				existing.Spec.Versions = append(existing.Spec.Versions, version)
+			}
+		}
+	}
+	if err := r.Client.Update(ctx, existing); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// crdEstablished reports whether the API server serves the current versions of a CRD
+func crdEstablished(crd *apixv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apixv1.Established {
+			return c.Status == apixv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+/*
+rewriteCustomResources updates every custom resource of a kind without changes, which makes the API server store it
+in the storage version of its CRD. It returns the number of custom resources rewritten.
+*/
+func (r *MultiClusterHubReconciler) rewriteCustomResources(ctx context.Context, group, version, listKind string) (
+	int, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{Group: group, Version: version, Kind: listKind})
+
+	count := 0
+	opts := []client.ListOption{client.Limit(crdMigrationPageSize)}
+	for {
+		if err := r.Client.List(ctx, list, opts...); err != nil {
+			return count, err
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			// A resource that was removed or changed meanwhile is stored in the storage version already
+			if err := r.Client.Update(ctx, item); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+				return count, fmt.Errorf("failed to migrate %s %s: %w", item.GetKind(),
+					client.ObjectKeyFromObject(item), err)
+			}
+			count++
+		}
+		if list.GetContinue() == "" {
+			return count, nil
+		}
+		opts = []client.ListOption{client.Limit(crdMigrationPageSize), client.Continue(list.GetContinue())}
+	}
+}
+
+/*
+ensureNoComponentCRD removes a CRD the operator installed for a disabled component once no custom resources remain. It
+returns false while the CRD is kept.
+*/
+func (r *MultiClusterHubReconciler) ensureNoComponentCRD(ctx context.Context, m *operatorv1.MultiClusterHub,
+	rendered *unstructured.Unstructured) (bool, error) {
+	existing := &apixv1.CustomResourceDefinition{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: rendered.GetName()}, existing); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if existing.Labels["installer.name"] != m.GetName() || existing.Labels["installer.namespace"] != m.GetNamespace() {
+		return true, nil
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group: existing.Spec.Group, Version: storageVersion(existing), Kind: existing.Spec.Names.ListKind,
+	})
+	if err := r.Client.List(ctx, list, client.Limit(1)); err != nil {
+		return false, err
+	}
+	if len(list.Items) > 0 {
+		return false, nil
+	}
+
+	r.Log.Info("Removing the CRD of a disabled component", "CRD", existing.Name)
+	if err := r.Client.Delete(ctx, existing); err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// droppedStoredVersions returns the stored versions of the installed CRD that the rendered CRD removes
+func droppedStoredVersions(existing, desired *apixv1.CustomResourceDefinition) []string {
+	versions := map[string]bool{}
+	for _, version := range desired.Spec.Versions {
+		versions[version.Name] = true
+	}
+	dropped := []string{}
+	for _, stored := range existing.Status.StoredVersions {
+		if !versions[stored] {
+			dropped = append(dropped, stored)
+		}
+	}
+	return dropped
+}
+
+// storageVersion returns the version a CRD stores its custom resources in
+func storageVersion(crd *apixv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+// setCRDMigration records the state of the storage migration of a CRD in the hub status
+func setCRDMigration(m *operatorv1.MultiClusterHub, name string, stored []string, storage string,
+	status metav1.ConditionStatus, message string) {
+	if m.Status.CRDLifecycle == nil {
+		m.Status.CRDLifecycle = &operatorv1.CRDLifecycleStatus{}
+	}
+	migrations := m.Status.CRDLifecycle.Migrations
+	for i := range migrations {
+		if migrations[i].Name != name {
+			continue
+		}
+		if migrations[i].Status != status || migrations[i].StorageVersion != storage {
+			migrations[i].LastTransitionTime = metav1.Now()
+		}
+		migrations[i].StoredVersions = stored
+		migrations[i].StorageVersion = storage
+		migrations[i].Status = status
+		migrations[i].Message = message
+		return
+	}
+	m.Status.CRDLifecycle.Migrations = append(migrations, operatorv1.CRDStorageMigration{
+		Name:               name,
+		StoredVersions:     stored,
+		StorageVersion:     storage,
+		Status:             status,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+// setRetainedCRDs records the CRDs of disabled components that are kept in the hub status
+func setRetainedCRDs(m *operatorv1.MultiClusterHub, retained []operatorv1.RetainedCRD) {
+	if m.Status.CRDLifecycle == nil {
+		if len(retained) == 0 {
+			return
+		}
+		m.Status.CRDLifecycle = &operatorv1.CRDLifecycleStatus{}
+	}
+	m.Status.CRDLifecycle.RetainedCRDs = retained
+}
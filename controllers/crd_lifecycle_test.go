@@ -0,0 +1,274 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+func widgetCRD(storedVersions []string, versions ...string) *apixv1.CustomResourceDefinition {
+	crd := &apixv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apixv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apixv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget", ListKind: "WidgetList"},
+			Scope: apixv1.NamespaceScoped,
+		},
+		Status: apixv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+	// The last version is the storage version
+	for i, version := range versions {
+		crd.Spec.Versions = append(crd.Spec.Versions, apixv1.CustomResourceDefinitionVersion{
+			Name: version, Served: true, Storage: i == len(versions)-1,
+		})
+	}
+	return crd
+}
+
+func widget(name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(widgetGVK)
+	u.SetNamespace("default")
+	u.SetName(name)
+	return u
+}
+
+func crdLifecycleClient(t *testing.T, funcs interceptor.Funcs, objs ...client.Object) client.Client {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(apixv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"), meta.RESTScopeRoot)
+	mapper.Add(widgetGVK, meta.RESTScopeNamespace)
+	return testClientBuilder(t, apixv1.AddToScheme).WithRESTMapper(mapper).WithObjects(objs...).
+		WithStatusSubresource(&apixv1.CustomResourceDefinition{}).WithInterceptorFuncs(funcs).Build()
+}
+
+func toUnstructured(t *testing.T, obj runtime.Object) *unstructured.Unstructured {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("failed to convert %v: %v", obj, err)
+	}
+	return &unstructured.Unstructured{Object: u}
+}
+
+func Test_migrateCRDStorage(t *testing.T) {
+	ctx := context.TODO()
+	hub := &operatorv1.MultiClusterHub{}
+
+	// v1alpha1 is removed while custom resources are stored in it
+	installed := widgetCRD([]string{"v1alpha1"}, "v1", "v1alpha1")
+	rendered := toUnstructured(t, widgetCRD(nil, "v1"))
+
+	rewrites := 0
+	funcs := interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if _, ok := obj.(*unstructured.Unstructured); ok {
+				rewrites++
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}
+	r := &MultiClusterHubReconciler{
+		Client: crdLifecycleClient(t, funcs, installed, widget("a"), widget("b")),
+		Log:    clog.Log.WithName("test"),
+	}
+	migrated, err := r.migrateCRDStorage(ctx, hub, rendered)
+	if err != nil || migrated {
+		t.Fatalf("migrateCRDStorage() = %v, %v, want to wait for the storage version", migrated, err)
+	}
+
+	// The custom resources are not rewritten in the reconcile that switches the storage version
+	crd := &apixv1.CustomResourceDefinition{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: installed.Name}, crd); err != nil {
+		t.Fatalf("failed to get the CRD: %v", err)
+	}
+	if got := storageVersion(crd); got != "v1" {
+		t.Errorf("storage version = %s, want v1", got)
+	}
+	if migration := hub.Status.CRDLifecycle.Migrations[0]; migration.Status != metav1.ConditionUnknown ||
+		!strings.Contains(migration.Message, "waiting") {
+		t.Errorf("unexpected migration status %v", migration)
+	}
+	if rewrites != 0 {
+		t.Errorf("expected no custom resources to be rewritten, got %d updates", rewrites)
+	}
+
+	// Nor before the CRD is established with the storage version
+	crd.Status.StoredVersions = append(crd.Status.StoredVersions, "v1")
+	if err := r.Client.Status().Update(ctx, crd); err != nil {
+		t.Fatalf("failed to update the CRD status: %v", err)
+	}
+	if migrated, err := r.migrateCRDStorage(ctx, hub, rendered); err != nil || migrated || rewrites != 0 {
+		t.Fatalf("migrateCRDStorage() = %v, %v after %d updates, want to wait for the CRD to be established",
+			migrated, err, rewrites)
+	}
+
+	// The API server stores new writes in the storage version
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: installed.Name}, crd); err != nil {
+		t.Fatalf("failed to get the CRD: %v", err)
+	}
+	crd.Status.Conditions = []apixv1.CustomResourceDefinitionCondition{
+		{Type: apixv1.Established, Status: apixv1.ConditionTrue},
+	}
+	if err := r.Client.Status().Update(ctx, crd); err != nil {
+		t.Fatalf("failed to update the CRD status: %v", err)
+	}
+	if migrated, err := r.migrateCRDStorage(ctx, hub, rendered); err != nil || !migrated {
+		t.Fatalf("migrateCRDStorage() = %v, %v, want the custom resources migrated", migrated, err)
+	}
+	// Every custom resource is rewritten twice
+	if rewrites != 4 {
+		t.Errorf("expected 4 custom resource updates, got %d", rewrites)
+	}
+
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: installed.Name}, crd); err != nil {
+		t.Fatalf("failed to get the CRD: %v", err)
+	}
+	if !reflect.DeepEqual(crd.Status.StoredVersions, []string{"v1"}) {
+		t.Errorf("storedVersions = %v, want [v1]", crd.Status.StoredVersions)
+	}
+	migration := hub.Status.CRDLifecycle.Migrations[0]
+	if migration.Status != metav1.ConditionTrue || migration.Message != "migrated 2 custom resources" ||
+		!reflect.DeepEqual(migration.StoredVersions, []string{"v1alpha1", "v1"}) {
+		t.Errorf("unexpected migration status %v", migration)
+	}
+
+	// Nothing is migrated once the removed versions are no longer stored
+	hub.Status.CRDLifecycle = nil
+	if migrated, err := r.migrateCRDStorage(ctx, hub, rendered); err != nil || !migrated ||
+		hub.Status.CRDLifecycle != nil {
+		t.Errorf("expected no migration, got %v and %v", err, hub.Status.CRDLifecycle)
+	}
+}
+
+func Test_migrateCRDStorageFailure(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{}
+	funcs := interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if _, ok := obj.(*unstructured.Unstructured); ok {
+				return fmt.Errorf("webhook denied the request")
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}
+	installed := widgetCRD([]string{"v1alpha1", "v1"}, "v1alpha1", "v1")
+	installed.Status.Conditions = []apixv1.CustomResourceDefinitionCondition{
+		{Type: apixv1.Established, Status: apixv1.ConditionTrue},
+	}
+	r := &MultiClusterHubReconciler{
+		Client: crdLifecycleClient(t, funcs, installed, widget("a")),
+		Log:    clog.Log.WithName("test"),
+	}
+
+	_, err := r.migrateCRDStorage(context.TODO(), hub, toUnstructured(t, widgetCRD(nil, "v1")))
+	if err == nil || !strings.Contains(err.Error(), "webhook denied the request") {
+		t.Fatalf("migrateCRDStorage() error = %v, want the failure of the custom resource update", err)
+	}
+	migration := hub.Status.CRDLifecycle.Migrations[0]
+	if migration.Status != metav1.ConditionFalse || !strings.Contains(migration.Message, "default/a") {
+		t.Errorf("unexpected migration status %v", migration)
+	}
+
+	crd := &apixv1.CustomResourceDefinition{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "widgets.example.com"}, crd); err != nil {
+		t.Fatalf("failed to get the CRD: %v", err)
+	}
+	if len(crd.Status.StoredVersions) != 2 {
+		t.Errorf("expected the stored versions to be kept, got %v", crd.Status.StoredVersions)
+	}
+}
+
+func Test_ensureNoComponentCRD(t *testing.T) {
+	ctx := context.TODO()
+	hub := &operatorv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "ocm"}}
+	installed := widgetCRD([]string{"v1"}, "v1")
+	installed.Labels = map[string]string{"installer.name": "multiclusterhub", "installer.namespace": "ocm"}
+	remaining := widget("a")
+
+	r := &MultiClusterHubReconciler{
+		Client: crdLifecycleClient(t, interceptor.Funcs{}, installed, remaining),
+		Log:    clog.Log.WithName("test"),
+	}
+	rendered := toUnstructured(t, widgetCRD(nil, "v1"))
+
+	if removed, err := r.ensureNoComponentCRD(ctx, hub, rendered); err != nil || removed {
+		t.Fatalf("ensureNoComponentCRD() = %v, %v, want the CRD to be kept while a Widget remains", removed, err)
+	}
+	if err := r.Client.Delete(ctx, remaining); err != nil {
+		t.Fatalf("failed to delete the Widget: %v", err)
+	}
+	if removed, err := r.ensureNoComponentCRD(ctx, hub, rendered); err != nil || !removed {
+		t.Fatalf("ensureNoComponentCRD() = %v, %v, want the CRD to be removed", removed, err)
+	}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: installed.Name}, &apixv1.CustomResourceDefinition{})
+	if err == nil {
+		t.Errorf("expected the CRD to be deleted")
+	}
+
+	// CRDs the operator did not install are left alone
+	other := widgetCRD([]string{"v1"}, "v1")
+	r.Client = crdLifecycleClient(t, interceptor.Funcs{}, other)
+	if removed, err := r.ensureNoComponentCRD(ctx, hub, rendered); err != nil || !removed {
+		t.Fatalf("ensureNoComponentCRD() = %v, %v", removed, err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: other.Name}, &apixv1.CustomResourceDefinition{}); err != nil {
+		t.Errorf("expected the CRD that was not installed by the operator to be kept: %v", err)
+	}
+}
+
+func Test_removableCRDComponent(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			utils.AnnotationRemoveDisabledCRDs: "submariner-addon,grc,fine-grained-rbac",
+		}},
+		Spec: operatorv1.MultiClusterHubSpec{Overrides: &operatorv1.Overrides{Components: []operatorv1.ComponentConfig{
+			{Name: operatorv1.SubmarinerAddon, Enabled: false},
+			{Name: operatorv1.SiteConfig, Enabled: false},
+			{Name: operatorv1.GRC, Enabled: true},
+			{Name: operatorv1.FineGrainedRbac, Enabled: false},
+			{Name: operatorv1.FineGrainedRbacPreview, Enabled: true},
+		}}},
+	}
+
+	tests := []struct {
+		dir       string
+		component string
+	}{
+		{dir: "submariner-addon", component: operatorv1.SubmarinerAddon},
+		// Not listed in the annotation
+		{dir: "siteconfig-operator"},
+		// Enabled
+		{dir: "grc"},
+		// The preview component still needs the CRDs
+		{dir: "fine-grained-rbac"},
+		// Always installed
+		{dir: "internal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			component, ok := removableCRDComponent(hub, tt.dir)
+			if component != tt.component || ok != (tt.component != "") {
+				t.Errorf("removableCRDComponent() = %s, %v, want %s", component, ok, tt.component)
+			}
+		})
+	}
+}
@@ -94,7 +94,8 @@ func (r *MultiClusterHubReconciler) cleanupMultiClusterEngine(log logr.Logger, m
 
 	// Clean up OLM resources based on detected OLM version
 	operandNs := multiclusterengine.OperandNamespace()
-	if r.OLMVersion == "v1" {
+	olmVersion := r.mceOLMVersion(m)
+	if olmVersion == "v1" {
 		// OLM v1 cleanup path (ClusterExtension + ServiceAccount)
 		mceCE, err := v1.GetManagedMCEClusterExtension(ctx, r.Client)
 		if err != nil {
@@ -126,7 +127,7 @@ func (r *MultiClusterHubReconciler) cleanupMultiClusterEngine(log logr.Logger, m
 			return err
 		}
 
-	} else if r.OLMVersion == "v0" {
+	} else if olmVersion == "v0" {
 		// OLM v0 cleanup path (Subscription + CSV + OperatorGroup)
 		mceSub, err := v0.GetManagedMCESubscription(ctx, r.Client)
 		if err != nil {
@@ -0,0 +1,140 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/health"
+)
+
+// healthHistoryName is the ConfigMap in the hub namespace that keeps the availability history of the hub components, so
+// the SLO windows are not reset when the operator restarts
+const healthHistoryName = "multiclusterhub-health-history"
+
+// criticalHubComponents are the components the hub cannot run without, the other components are optional add-ons
+var criticalHubComponents = []string{
+	operatorv1.MultiClusterEngine,
+	operatorv1.ClusterLifecycle,
+	operatorv1.Appsub,
+	operatorv1.GRC,
+}
+
+// criticalStatusComponents returns the names of the status components that belong to a critical hub component
+func criticalStatusComponents(hub *operatorv1.MultiClusterHub, workloads *registry[[]statusWorkload], ocpConsole,
+	isSTSEnabled bool) map[string]bool {
+	critical := map[string]bool{}
+	for _, component := range criticalHubComponents {
+		critical[component] = true
+		for _, w := range componentStatusWorkloads(workloads, hub, component, ocpConsole, isSTSEnabled) {
+			critical[w.Name] = true
+		}
+	}
+	return critical
+}
+
+/*
+observeHealth records the availability of the status components in the health tracker and returns the health status of
+the hub. The previous health status is kept when no tracker is configured or no component is reported, for example
+while the hub is paused. The tracker is seeded from the health history ConfigMap on the first observation, and the
+ConfigMap is updated whenever the history changes.
+*/
+func (r *MultiClusterHubReconciler) observeHealth(ctx context.Context, hub *operatorv1.MultiClusterHub,
+	components map[string]operatorv1.StatusCondition, ocpConsole, isSTSEnabled bool) *operatorv1.HubHealthStatus {
+	if r.Health == nil || len(components) == 0 {
+		return hub.Status.Health
+	}
+	if !r.healthRestored {
+		r.restoreHealthHistory(ctx, hub)
+	}
+
+	critical := criticalStatusComponents(hub, r.statusWorkloads, ocpConsole, isSTSEnabled)
+	samples := []health.Sample{}
+	for name, c := range components {
+		samples = append(samples, health.Sample{
+			Name:      name,
+			Critical:  critical[name] || strings.HasPrefix(name, operatorv1.MultiClusterEngine),
+			Available: c.Available,
+		})
+	}
+	report := r.Health.Observe(hub.GetNamespace()+"/"+hub.GetName(), samples)
+	r.persistHealthHistory(ctx, hub)
+	return hubHealthStatus(report)
+}
+
+// restoreHealthHistory seeds the health tracker with the history persisted before the operator restarted
+func (r *MultiClusterHubReconciler) restoreHealthHistory(ctx context.Context, hub *operatorv1.MultiClusterHub) {
+	snapshot := health.Snapshot{}
+	cm, err := r.readHistoryConfigMap(ctx, hub, healthHistoryName, &snapshot)
+	if err != nil {
+		log.Error(err, "Failed to read the hub health history, it is restored on the next observation")
+		return
+	}
+	r.healthRestored = true
+	r.healthPersisted = cm.Data[historyConfigMapKey]
+	if r.Health.Restore(snapshot) {
+		log.Info("Restored the hub health history", "ConfigMap", healthHistoryName)
+	}
+}
+
+// persistHealthHistory writes the history of the health tracker to the health history ConfigMap when it changed
+func (r *MultiClusterHubReconciler) persistHealthHistory(ctx context.Context, hub *operatorv1.MultiClusterHub) {
+	snapshot := r.Health.Snapshot()
+	data, err := json.Marshal(snapshot)
+	if err != nil || string(data) == r.healthPersisted {
+		return
+	}
+
+	cm, err := r.readHistoryConfigMap(ctx, hub, healthHistoryName, &health.Snapshot{})
+	if err == nil {
+		err = r.writeHistoryConfigMap(ctx, hub, cm, snapshot)
+	}
+	if err != nil {
+		log.Error(err, "Failed to persist the hub health history")
+		return
+	}
+	r.healthPersisted = string(data)
+}
+
+func hubHealthStatus(report health.Report) *operatorv1.HubHealthStatus {
+	status := &operatorv1.HubHealthStatus{
+		Score:     int32(report.Score),
+		State:     operatorv1.HubHealthState(report.State),
+		Objective: percent(report.Objective),
+	}
+	if unavailable := report.UnavailableComponents(); len(unavailable) > 0 {
+		status.UnavailableComponents = unavailable
+	}
+	for _, w := range report.SLO {
+		status.SLO = append(status.SLO, operatorv1.HubSLOWindow{
+			Window:       w.Window,
+			Availability: percent(w.Availability),
+			BurnRate:     fmt.Sprintf("%.2f", w.BurnRate),
+		})
+	}
+	return status
+}
+
+func percent(ratio float64) string {
+	return fmt.Sprintf("%.2f", ratio*100)
+}
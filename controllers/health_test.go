@@ -0,0 +1,108 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/health"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_observeHealth(t *testing.T) {
+	registerScheme()
+	ctx := context.TODO()
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+	components := func(search, grc, mce bool) map[string]operatorv1.StatusCondition {
+		return map[string]operatorv1.StatusCondition{
+			"search-api":            {Available: search},
+			"grc-policy-propagator": {Available: grc},
+			"multicluster-engine":   {Available: mce},
+		}
+	}
+
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(hub).Build(),
+		Scheme: scheme.Scheme,
+	}
+	if got := r.observeHealth(ctx, hub, components(true, true, true), true, false); got != nil {
+		t.Errorf("observeHealth() = %+v without a tracker, want the previous status", got)
+	}
+
+	r.Health = health.NewTracker(health.DefaultObjective)
+	tests := []struct {
+		name            string
+		components      map[string]operatorv1.StatusCondition
+		wantState       operatorv1.HubHealthState
+		wantUnavailable []string
+	}{
+		{
+			name:       "All components available",
+			components: components(true, true, true),
+			wantState:  operatorv1.HubHealthy,
+		},
+		{
+			name:            "Optional add-on unavailable",
+			components:      components(false, true, true),
+			wantState:       operatorv1.HubDegraded,
+			wantUnavailable: []string{"search-api"},
+		},
+		{
+			name:            "Policy propagator unavailable",
+			components:      components(true, false, true),
+			wantState:       operatorv1.HubUnavailable,
+			wantUnavailable: []string{"grc-policy-propagator"},
+		},
+		{
+			name:            "MCE unavailable",
+			components:      components(true, true, false),
+			wantState:       operatorv1.HubUnavailable,
+			wantUnavailable: []string{"multicluster-engine"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.observeHealth(ctx, hub, tt.components, true, false)
+			if got.State != tt.wantState {
+				t.Errorf("State = %s, want %s", got.State, tt.wantState)
+			}
+			if !reflect.DeepEqual(got.UnavailableComponents, tt.wantUnavailable) {
+				t.Errorf("UnavailableComponents = %v, want %v", got.UnavailableComponents, tt.wantUnavailable)
+			}
+			if got.Objective != "99.00" || len(got.SLO) != len(health.Windows) {
+				t.Errorf("status = %+v, want the objective and every SLO window", got)
+			}
+		})
+	}
+
+	// The history is persisted, so a restarted operator keeps the outages observed before
+	cm := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: healthHistoryName, Namespace: hub.Namespace},
+		cm); err != nil {
+		t.Fatalf("expected the health history configmap, got %v", err)
+	}
+	restarted := &MultiClusterHubReconciler{
+		Client: r.Client,
+		Scheme: scheme.Scheme,
+		Health: health.NewTracker(health.DefaultObjective),
+	}
+	restarted.observeHealth(ctx, hub, components(true, true, true), true, false)
+	snapshot := restarted.Health.Snapshot()
+	if search := snapshot.Components["search-api"]; search == nil || len(search.Outages) != 1 {
+		t.Errorf("search-api history = %+v, want the outage observed before the restart", search)
+	}
+	if snapshot.Hub == nil || len(snapshot.Hub.Outages) != 1 {
+		t.Errorf("hub history = %+v, want the outage that ended after the restart", snapshot.Hub)
+	}
+}
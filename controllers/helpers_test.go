@@ -0,0 +1,51 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// testObject returns an unstructured object, as rendered from a chart or read from the cluster
+func testObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+// withLabels sets the labels of a test object
+func withLabels(u *unstructured.Unstructured, labels map[string]string) *unstructured.Unstructured {
+	u.SetLabels(labels)
+	return u
+}
+
+// withAnnotations sets the annotations of a test object
+func withAnnotations(u *unstructured.Unstructured, annotations map[string]string) *unstructured.Unstructured {
+	u.SetAnnotations(annotations)
+	return u
+}
+
+// testScheme returns a scheme holding the types of the add functions
+func testScheme(t *testing.T, adds ...func(*runtime.Scheme) error) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	for _, add := range adds {
+		if err := add(s); err != nil {
+			t.Fatalf("failed to build the scheme: %v", err)
+		}
+	}
+	return s
+}
+
+// testClientBuilder returns a fake client builder with a scheme holding the types of the add functions
+func testClientBuilder(t *testing.T, adds ...func(*runtime.Scheme) error) *fake.ClientBuilder {
+	t.Helper()
+	return fake.NewClientBuilder().WithScheme(testScheme(t, adds...))
+}
@@ -24,12 +24,14 @@ import (
 	"os"
 
 	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/servingcert"
 	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
 
 	configv1 "github.com/openshift/api/config/v1"
 	pkgerrors "github.com/pkg/errors"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -75,11 +77,8 @@ func (r *MultiClusterHubReconciler) createTrustBundleConfigmap(ctx context.Conte
 	ctrl.Result, error,
 ) {
 	// Get Trusted Bundle configmap name
-	trustBundleName := defaultTrustBundleName
+	trustBundleName := trustBundleConfigMapName()
 	trustBundleNamespace := mch.Namespace
-	if name, ok := os.LookupEnv(trustBundleNameEnvVar); ok && name != "" {
-		trustBundleName = name
-	}
 	namespacedName := types.NamespacedName{
 		Name:      trustBundleName,
 		Namespace: trustBundleNamespace,
@@ -133,6 +132,9 @@ This service exposes the operator's metrics endpoint (port 8383) so that Prometh
 operator-specific metrics for monitoring and observability. The service is owned by the MCH CR
 and will be automatically cleaned up when the MCH is deleted.
 
+When the metrics are served over HTTPS, the service asks the OpenShift service CA for a serving
+certificate and the certificate is loaded into the metrics server once it is issued.
+
 This is required for:
   - Monitoring operator health and performance
   - Alerting on operator issues
@@ -152,7 +154,8 @@ func (r *MultiClusterHubReconciler) createMetricsService(ctx context.Context, m
 	}
 
 	// Check if service exists
-	if err := r.Client.Get(ctx, namespacedName, &corev1.Service{}); err != nil {
+	existing := &corev1.Service{}
+	if err := r.Client.Get(ctx, namespacedName, existing); err != nil {
 		if !errors.IsNotFound(err) {
 			// Unknown error. Requeue
 			log.Error(err, fmt.Sprintf("error while getting multiclusterhub metrics service: %s/%s", sNamespace, sName))
@@ -167,6 +170,7 @@ func (r *MultiClusterHubReconciler) createMetricsService(ctx context.Context, m
 				Labels: map[string]string{
 					"name": operatorv1.MCH,
 				},
+				Annotations: r.metricsServiceAnnotations(),
 			},
 			Spec: corev1.ServiceSpec{
 				Ports: []corev1.ServicePort{
@@ -196,11 +200,35 @@ func (r *MultiClusterHubReconciler) createMetricsService(ctx context.Context, m
 		}
 
 		log.Info(fmt.Sprintf("Created multiclusterhub metrics service: %s", sName))
+	} else if existing.Annotations[servingcert.ServingCertSecretAnnotation] !=
+		r.metricsServiceAnnotations()[servingcert.ServingCertSecretAnnotation] {
+		// Services created before the metrics were served over HTTPS do not request a certificate
+		if r.MetricsCert != nil {
+			if existing.Annotations == nil {
+				existing.Annotations = map[string]string{}
+			}
+			existing.Annotations[servingcert.ServingCertSecretAnnotation] = utils.MCHOperatorMetricsServingCertSecretName
+		} else {
+			delete(existing.Annotations, servingcert.ServingCertSecretAnnotation)
+		}
+		if err := r.Client.Update(ctx, existing); err != nil {
+			log.Error(err, fmt.Sprintf("error updating multiclusterhub metrics service: %s", sName))
+			return ctrl.Result{}, err
+		}
+		log.Info(fmt.Sprintf("Updated the serving certificate request of multiclusterhub metrics service: %s", sName))
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// metricsServiceAnnotations returns the annotations of the metrics service
+func (r *MultiClusterHubReconciler) metricsServiceAnnotations() map[string]string {
+	if r.MetricsCert == nil {
+		return nil
+	}
+	return map[string]string{servingcert.ServingCertSecretAnnotation: utils.MCHOperatorMetricsServingCertSecretName}
+}
+
 /*
 createMetricsServiceMonitor ensures the MCH operator's ServiceMonitor exists in the MCH namespace.
 
@@ -226,8 +254,11 @@ func (r *MultiClusterHubReconciler) createMetricsServiceMonitor(ctx context.Cont
 		Namespace: smNamespace,
 	}
 
+	endpoints := []promv1.Endpoint{r.metricsServiceMonitorEndpoint(m)}
+
 	// Check if service exists
-	if err := r.Client.Get(ctx, namespacedName, &promv1.ServiceMonitor{}); err != nil {
+	existing := &promv1.ServiceMonitor{}
+	if err := r.Client.Get(ctx, namespacedName, existing); err != nil {
 		if !errors.IsNotFound(err) {
 			// Unknown error. Requeue
 			log.Error(err, fmt.Sprintf("error while getting multiclusterhub metrics service: %s/%s", smNamespace, smName))
@@ -244,15 +275,7 @@ func (r *MultiClusterHubReconciler) createMetricsServiceMonitor(ctx context.Cont
 				},
 			},
 			Spec: promv1.ServiceMonitorSpec{
-				Endpoints: []promv1.Endpoint{
-					{
-						BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
-						BearerTokenSecret: &corev1.SecretKeySelector{
-							Key: "",
-						},
-						Port: "metrics",
-					},
-				},
+				Endpoints: endpoints,
 				NamespaceSelector: promv1.NamespaceSelector{
 					MatchNames: []string{
 						m.GetNamespace(),
@@ -278,11 +301,50 @@ func (r *MultiClusterHubReconciler) createMetricsServiceMonitor(ctx context.Cont
 		}
 
 		logf.Log.Info(fmt.Sprintf("Created multiclusterhub metrics servicemonitor: %s", smName))
+	} else if !equality.Semantic.DeepEqual(existing.Spec.Endpoints, endpoints) {
+		// Switch the scrape configuration when the metrics move between HTTPS and plain HTTP
+		existing.Spec.Endpoints = endpoints
+		if err := r.Client.Update(ctx, existing); err != nil {
+			log.Error(err, fmt.Sprintf("error updating metrics servicemonitor: %s", smName))
+			return ctrl.Result{}, err
+		}
+
+		logf.Log.Info(fmt.Sprintf("Updated multiclusterhub metrics servicemonitor: %s", smName))
 	}
 
 	return ctrl.Result{}, nil
 }
 
+/*
+metricsServiceMonitorEndpoint returns how Prometheus scrapes the metrics service. Over HTTPS, Prometheus verifies the
+serving certificate with the service CA bundle and authenticates with its service account token, which must be allowed
+to get the /metrics URL.
+*/
+func (r *MultiClusterHubReconciler) metricsServiceMonitorEndpoint(m *operatorv1.MultiClusterHub) promv1.Endpoint {
+	if r.MetricsCert == nil {
+		return promv1.Endpoint{
+			BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			BearerTokenSecret: &corev1.SecretKeySelector{
+				Key: "",
+			},
+			Port: "metrics",
+		}
+	}
+
+	serverName := fmt.Sprintf("%s.%s.svc", utils.MCHOperatorMetricsServiceName, m.GetNamespace())
+	return promv1.Endpoint{
+		BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		Port:            "metrics",
+		Scheme:          "https",
+		TLSConfig: &promv1.TLSConfig{
+			SafeTLSConfig: promv1.SafeTLSConfig{
+				ServerName: &serverName,
+			},
+			CAFile: servingcert.ServiceCAFile,
+		},
+	}
+}
+
 // ingressDomain is discovered from Openshift cluster configuration resources
 func (r *MultiClusterHubReconciler) ingressDomain(ctx context.Context) (ctrl.Result, error) {
 	ingress := &configv1.Ingress{}
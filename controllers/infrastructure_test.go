@@ -0,0 +1,96 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/servingcert"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_secureMetrics(t *testing.T) {
+	registerScheme()
+	_ = promv1.AddToScheme(scheme.Scheme)
+	ctx := context.TODO()
+
+	m := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+	metricsCert, err := servingcert.NewProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The service monitor was created while the metrics were served over plain HTTP
+	plain := (&MultiClusterHubReconciler{}).metricsServiceMonitorEndpoint(m)
+	existing := &promv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.MCHOperatorMetricsServiceMonitorName, Namespace: m.Namespace},
+		Spec:       promv1.ServiceMonitorSpec{Endpoints: []promv1.Endpoint{plain}},
+	}
+	r := &MultiClusterHubReconciler{
+		Client:      fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(m, existing).Build(),
+		Scheme:      scheme.Scheme,
+		Log:         clog.Log.WithName("test"),
+		MetricsCert: metricsCert,
+	}
+
+	// The service requests a serving certificate, which is not issued yet
+	if _, err := r.createMetricsService(ctx, m); err != nil {
+		t.Fatalf("createMetricsService() error = %v", err)
+	}
+	service := &corev1.Service{}
+	key := types.NamespacedName{Name: utils.MCHOperatorMetricsServiceName, Namespace: m.Namespace}
+	if err := r.Client.Get(ctx, key, service); err != nil {
+		t.Fatal(err)
+	}
+	if got := service.Annotations[servingcert.ServingCertSecretAnnotation]; got !=
+		utils.MCHOperatorMetricsServingCertSecretName {
+		t.Errorf("serving certificate annotation = %q, want %s", got,
+			utils.MCHOperatorMetricsServingCertSecretName)
+	}
+
+	// Prometheus is switched to HTTPS with the service CA and its token
+	if _, err := r.createMetricsServiceMonitor(ctx, m); err != nil {
+		t.Fatalf("createMetricsServiceMonitor() error = %v", err)
+	}
+	sm := &promv1.ServiceMonitor{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: existing.Name, Namespace: m.Namespace}, sm); err != nil {
+		t.Fatal(err)
+	}
+	endpoint := sm.Spec.Endpoints[0]
+	if endpoint.Scheme != "https" || endpoint.TLSConfig == nil || endpoint.TLSConfig.CAFile != servingcert.ServiceCAFile ||
+		*endpoint.TLSConfig.ServerName != key.Name+"."+key.Namespace+".svc" || endpoint.BearerTokenFile == "" {
+		t.Errorf("endpoint = %+v, want HTTPS with the service CA and a bearer token", endpoint)
+	}
+
+	// Plain HTTP is only served on request
+	r.MetricsCert = nil
+	if _, err := r.createMetricsService(ctx, m); err != nil {
+		t.Fatalf("createMetricsService() error = %v", err)
+	}
+	if err := r.Client.Get(ctx, key, service); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := service.Annotations[servingcert.ServingCertSecretAnnotation]; ok {
+		t.Error("expected the serving certificate annotation to be removed")
+	}
+	if _, err := r.createMetricsServiceMonitor(ctx, m); err != nil {
+		t.Fatalf("createMetricsServiceMonitor() error = %v", err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: existing.Name, Namespace: m.Namespace}, sm); err != nil {
+		t.Fatal(err)
+	}
+	if sm.Spec.Endpoints[0].Scheme != "" || sm.Spec.Endpoints[0].TLSConfig != nil {
+		t.Errorf("endpoint = %+v, want plain HTTP", sm.Spec.Endpoints[0])
+	}
+}
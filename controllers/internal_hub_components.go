@@ -20,19 +20,39 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
 
 	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+	"github.com/stolostron/multiclusterhub-operator/pkg/version"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+/*
+ensureInternalHubComponent ensures the InternalHubComponent of a component exists and that its spec carries the
+desired version, configuration hash and placement of the rendered component templates.
+*/
 func (r *MultiClusterHubReconciler) ensureInternalHubComponent(ctx context.Context, m *operatorv1.MultiClusterHub,
-	component string) (ctrl.Result, error) {
+	component string, templates []*unstructured.Unstructured) (ctrl.Result, error) {
+
+	spec, err := internalHubComponentSpec(m, templates)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to build InternalHubComponent spec for %s: %v", component, err)
+	}
 
 	ihc := &operatorv1.InternalHubComponent{
 		TypeMeta: metav1.TypeMeta{
@@ -43,25 +63,87 @@ func (r *MultiClusterHubReconciler) ensureInternalHubComponent(ctx context.Conte
 			Name:      component,
 			Namespace: m.GetNamespace(),
 		},
+		Spec: spec,
 	}
 
+	existing := &operatorv1.InternalHubComponent{}
 	if err := r.Client.Get(
-		ctx, types.NamespacedName{Name: ihc.GetName(), Namespace: ihc.GetNamespace()}, ihc); err != nil {
+		ctx, types.NamespacedName{Name: ihc.GetName(), Namespace: ihc.GetNamespace()}, existing); err != nil {
 
 		if errors.IsNotFound(err) {
 			if err := r.Client.Create(ctx, ihc); err != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to create InternalHubComponent CR: %s/%s: %v",
 					ihc.GetNamespace(), ihc.GetName(), err)
 			}
-		} else {
-			return ctrl.Result{}, fmt.Errorf("failed to get InternalHubComponent CR: %s/%s: %v",
-				ihc.GetNamespace(), ihc.GetName(), err)
+			return ctrl.Result{}, nil
 		}
+		return ctrl.Result{}, fmt.Errorf("failed to get InternalHubComponent CR: %s/%s: %v",
+			ihc.GetNamespace(), ihc.GetName(), err)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Spec, spec) {
+		return ctrl.Result{}, nil
 	}
 
+	patch := client.MergeFrom(existing.DeepCopy())
+	existing.Spec = spec
+	if err := r.Client.Patch(ctx, existing, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update InternalHubComponent CR: %s/%s: %v",
+			ihc.GetNamespace(), ihc.GetName(), err)
+	}
 	return ctrl.Result{}, nil
 }
 
+// internalHubComponentSpec returns the InternalHubComponent spec for the rendered templates of a component
+func internalHubComponentSpec(m *operatorv1.MultiClusterHub, templates []*unstructured.Unstructured) (
+	operatorv1.InternalHubComponentSpec, error) {
+	configHash, err := templatesHash(templates)
+	if err != nil {
+		return operatorv1.InternalHubComponentSpec{}, err
+	}
+
+	placement := &operatorv1.ComponentPlacement{
+		Namespace:    m.GetNamespace(),
+		NodeSelector: m.Spec.NodeSelector,
+		Tolerations:  utils.GetTolerations(m),
+	}
+	for _, template := range templates {
+		if template.GetKind() == "Deployment" && template.GetNamespace() != "" {
+			placement.Namespace = template.GetNamespace()
+			break
+		}
+	}
+
+	return operatorv1.InternalHubComponentSpec{
+		DesiredVersion: version.Version,
+		ConfigHash:     configHash,
+		Placement:      placement,
+	}, nil
+}
+
+// templatesHash returns a hash of the rendered templates that does not depend on the order they were rendered in
+func templatesHash(templates []*unstructured.Unstructured) (string, error) {
+	if len(templates) == 0 {
+		return "", nil
+	}
+
+	encoded := make([]string, 0, len(templates))
+	for _, template := range templates {
+		b, err := json.Marshal(template.Object)
+		if err != nil {
+			return "", err
+		}
+		encoded = append(encoded, string(b))
+	}
+	sort.Strings(encoded)
+
+	h := sha256.New()
+	for _, e := range encoded {
+		h.Write([]byte(e))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func (r *MultiClusterHubReconciler) ensureNoInternalHubComponent(ctx context.Context, m *operatorv1.MultiClusterHub,
 	component string) (ctrl.Result, error) {
 
@@ -77,8 +159,14 @@ func (r *MultiClusterHubReconciler) ensureNoInternalHubComponent(ctx context.Con
 
 	// Check if it has a deletion timestamp (indicating it's in the process of being deleted)
 	if ihc.GetDeletionTimestamp() != nil {
-		log.Info("InternalHubComponent deletion in progress", "Name", ihc.GetName(), "Namespace", ihc.GetNamespace(),
-			"DeletionTimestamp", ihc.GetDeletionTimestamp())
+		keysAndValues := []interface{}{"Name", ihc.GetName(), "Namespace", ihc.GetNamespace(),
+			"DeletionTimestamp", ihc.GetDeletionTimestamp()}
+		// The operand reports the progress of its cleanup while it holds a finalizer on the InternalHubComponent
+		if cleanup := ihc.Status.Cleanup; cleanup != nil {
+			keysAndValues = append(keysAndValues, "Phase", cleanup.Phase, "RemainingResources",
+				cleanup.RemainingResources, "Message", cleanup.Message)
+		}
+		log.Info("InternalHubComponent deletion in progress", keysAndValues...)
 
 		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
 	}
@@ -106,3 +194,82 @@ func (r *MultiClusterHubReconciler) ensureNoInternalHubComponent(ctx context.Con
 	// Requeue to check again after a short delay
 	return ctrl.Result{RequeueAfter: resyncPeriod}, nil
 }
+
+// internalHubComponentKeyPrefix prefixes the InternalHubComponents in the custom resources the installer observes
+const internalHubComponentKeyPrefix = "ihc/"
+
+/*
+listInternalHubComponents adds the InternalHubComponents of enabled components to the custom resources the installer
+observes. Only InternalHubComponents whose operand reports status are added; the status of other components is still
+derived from their deployments.
+*/
+func (r *MultiClusterHubReconciler) listInternalHubComponents(ctx context.Context, m *operatorv1.MultiClusterHub,
+	ret map[string]*unstructured.Unstructured) {
+	ihcList := &operatorv1.InternalHubComponentList{}
+	if err := r.Client.List(ctx, ihcList, client.InNamespace(m.GetNamespace())); err != nil {
+		r.Log.V(2).Info("Failed to list InternalHubComponents", "error", err)
+		return
+	}
+
+	for i := range ihcList.Items {
+		ihc := &ihcList.Items[i]
+		if !m.Enabled(ihc.GetName()) || !reportsStatus(ihc) {
+			continue
+		}
+
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ihc)
+		if err != nil {
+			r.Log.Error(err, "Failed to unmarshal InternalHubComponent", "Name", ihc.GetName())
+			continue
+		}
+		ret[internalHubComponentKeyPrefix+ihc.GetName()] = &unstructured.Unstructured{Object: u}
+	}
+}
+
+// reportsStatus returns true if the operand of the InternalHubComponent has started reporting its status
+func reportsStatus(ihc *operatorv1.InternalHubComponent) bool {
+	return ihc.Status.ObservedVersion != "" || len(ihc.Status.Conditions) > 0
+}
+
+/*
+mapInternalHubComponent maps the status reported by the operand of a component to a component status. The component is
+available once the operand reports it is ready for the latest spec at the desired version.
+*/
+func mapInternalHubComponent(cr *unstructured.Unstructured) operatorv1.StatusCondition {
+	ihc := &operatorv1.InternalHubComponent{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(cr.Object, ihc); err != nil {
+		return unknownStatus(cr.GetName(), "InternalHubComponent")
+	}
+
+	ready := meta.FindStatusCondition(ihc.Status.Conditions, operatorv1.ComponentReady)
+	if ready == nil {
+		return unknownStatus(ihc.GetName(), "InternalHubComponent")
+	}
+
+	ret := operatorv1.StatusCondition{
+		Name:               ihc.GetName(),
+		Kind:               "InternalHubComponent",
+		Type:               ready.Type,
+		Status:             ready.Status,
+		LastUpdateTime:     ready.LastTransitionTime,
+		LastTransitionTime: ready.LastTransitionTime,
+		Reason:             ready.Reason,
+		Message:            ready.Message,
+	}
+
+	switch {
+	case ihc.Status.ObservedGeneration < ihc.GetGeneration():
+		ret.Status = metav1.ConditionFalse
+		ret.Reason = "SpecNotObserved"
+		ret.Message = fmt.Sprintf("The component has not observed generation %d of its spec", ihc.GetGeneration())
+	case ihc.Spec.DesiredVersion != "" && ihc.Status.ObservedVersion != ihc.Spec.DesiredVersion:
+		ret.Status = metav1.ConditionFalse
+		ret.Reason = "VersionMismatch"
+		ret.Message = fmt.Sprintf("The component is running version %s, expected %s", ihc.Status.ObservedVersion,
+			ihc.Spec.DesiredVersion)
+	case ready.Status == metav1.ConditionTrue:
+		ret.Available = true
+		ret.Message = ""
+	}
+	return ret
+}
@@ -0,0 +1,168 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/version"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_internalHubComponentSpec(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+		Spec: operatorv1.MultiClusterHubSpec{
+			NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		},
+	}
+	templates := []*unstructured.Unstructured{
+		testObject("v1", "ServiceAccount", "open-cluster-management-backup", "cluster-backup"),
+		testObject("apps/v1", "Deployment", "open-cluster-management-backup", "cluster-backup-chart-clusterbackup"),
+	}
+
+	spec, err := internalHubComponentSpec(hub, templates)
+	if err != nil {
+		t.Fatalf("internalHubComponentSpec() error = %v", err)
+	}
+	if spec.DesiredVersion != version.Version {
+		t.Errorf("DesiredVersion = %q, want %q", spec.DesiredVersion, version.Version)
+	}
+	if spec.ConfigHash == "" {
+		t.Error("expected a ConfigHash for the rendered templates")
+	}
+	if spec.Placement.Namespace != "open-cluster-management-backup" {
+		t.Errorf("Placement.Namespace = %q, want the namespace of the component deployment", spec.Placement.Namespace)
+	}
+	if spec.Placement.NodeSelector["node-role.kubernetes.io/infra"] != "" || len(spec.Placement.NodeSelector) != 1 {
+		t.Errorf("Placement.NodeSelector = %v, want the hub node selector", spec.Placement.NodeSelector)
+	}
+	if len(spec.Placement.Tolerations) == 0 {
+		t.Error("expected the default hub tolerations in the placement")
+	}
+
+	// The hash does not depend on the rendering order but changes with the configuration
+	reordered, _ := internalHubComponentSpec(hub, []*unstructured.Unstructured{templates[1], templates[0]})
+	if reordered.ConfigHash != spec.ConfigHash {
+		t.Errorf("ConfigHash changed with the template order: %s != %s", reordered.ConfigHash, spec.ConfigHash)
+	}
+	templates[1].SetLabels(map[string]string{"changed": "true"})
+	changed, _ := internalHubComponentSpec(hub, templates)
+	if changed.ConfigHash == spec.ConfigHash {
+		t.Error("expected ConfigHash to change with the rendered templates")
+	}
+}
+
+func Test_ensureInternalHubComponent_updatesSpec(t *testing.T) {
+	registerScheme()
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+	existing := &operatorv1.InternalHubComponent{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorv1.Search, Namespace: hub.Namespace},
+		Spec:       operatorv1.InternalHubComponentSpec{DesiredVersion: "0.0.1", ConfigHash: "stale"},
+	}
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(existing).Build(),
+		Log:    clog.Log.WithName("test"),
+	}
+
+	templates := []*unstructured.Unstructured{testObject("apps/v1", "Deployment", hub.Namespace, "search-api")}
+	if _, err := r.ensureInternalHubComponent(context.Background(), hub, operatorv1.Search, templates); err != nil {
+		t.Fatalf("ensureInternalHubComponent() error = %v", err)
+	}
+
+	got := &operatorv1.InternalHubComponent{}
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: operatorv1.Search,
+		Namespace: hub.Namespace}, got); err != nil {
+		t.Fatalf("failed to get InternalHubComponent: %v", err)
+	}
+	if got.Spec.DesiredVersion != version.Version || got.Spec.ConfigHash == "stale" {
+		t.Errorf("expected the InternalHubComponent spec to be updated, got %+v", got.Spec)
+	}
+}
+
+func Test_getComponentStatuses_InternalHubComponent(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+		Spec: operatorv1.MultiClusterHubSpec{
+			Overrides: &operatorv1.Overrides{
+				Components: []operatorv1.ComponentConfig{
+					{Name: operatorv1.Search, Enabled: true},
+				},
+			},
+		},
+	}
+
+	ihc := func(observedVersion string, ready metav1.ConditionStatus) *unstructured.Unstructured {
+		obj := &operatorv1.InternalHubComponent{
+			ObjectMeta: metav1.ObjectMeta{Name: operatorv1.Search, Namespace: hub.Namespace, Generation: 2},
+			Spec:       operatorv1.InternalHubComponentSpec{DesiredVersion: version.Version},
+			Status: operatorv1.InternalHubComponentStatus{
+				ObservedGeneration: 2,
+				ObservedVersion:    observedVersion,
+				Conditions: []metav1.Condition{
+					{Type: operatorv1.ComponentReady, Status: ready, Reason: "Reconciled"},
+				},
+			},
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &unstructured.Unstructured{Object: u}
+	}
+
+	tests := []struct {
+		name          string
+		cr            *unstructured.Unstructured
+		wantAvailable bool
+		wantReason    string
+	}{
+		{
+			name:          "ready at the desired version",
+			cr:            ihc(version.Version, metav1.ConditionTrue),
+			wantAvailable: true,
+			wantReason:    "Reconciled",
+		},
+		{
+			name:       "not ready",
+			cr:         ihc(version.Version, metav1.ConditionFalse),
+			wantReason: "Reconciled",
+		},
+		{
+			name:       "previous version still running",
+			cr:         ihc("0.0.1", metav1.ConditionTrue),
+			wantReason: "VersionMismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses := getComponentStatuses(hub, nil, nil,
+				map[string]*unstructured.Unstructured{internalHubComponentKeyPrefix + operatorv1.Search: tt.cr},
+				true, false, "v0")
+
+			if _, ok := statuses["search-api"]; ok {
+				t.Error("expected the search deployments to no longer be tracked")
+			}
+			got, ok := statuses[operatorv1.Search]
+			if !ok {
+				t.Fatalf("expected a status for %s, got %v", operatorv1.Search, statuses)
+			}
+			if got.Available != tt.wantAvailable || got.Reason != tt.wantReason ||
+				got.Kind != "InternalHubComponent" {
+				t.Errorf("status = %+v, want available %v with reason %s", got, tt.wantAvailable, tt.wantReason)
+			}
+		})
+	}
+}
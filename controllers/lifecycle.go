@@ -24,6 +24,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 
 	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
 	"github.com/stolostron/multiclusterhub-operator/pkg/deploying"
@@ -87,7 +88,7 @@ func (r *MultiClusterHubReconciler) installCRDs(reqLogger logr.Logger, m *operat
 		return CRDRenderReason, err
 	}
 
-	crds, errs := renderer.RenderCRDs(crdDir, m)
+	crdsByDir, errs := renderer.RenderComponentCRDs(crdDir, m)
 	if len(errs) > 0 {
 		message := mergeErrors(errs)
 		err := fmt.Errorf("failed to render CRD templates: %s", message)
@@ -95,19 +96,87 @@ func (r *MultiClusterHubReconciler) installCRDs(reqLogger logr.Logger, m *operat
 		return CRDRenderReason, err
 	}
 
-	for _, crd := range crds {
-		utils.AddInstallerLabel(crd, m.GetName(), m.GetNamespace())
-		err, ok := deploying.Deploy(r.Client, crd)
+	dirs := make([]string, 0, len(crdsByDir))
+	for dir := range crdsByDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	crds := []*unstructured.Unstructured{}
+	for _, dir := range dirs {
+		for _, crd := range crdsByDir[dir] {
+			utils.AddInstallerLabel(crd, m.GetName(), m.GetNamespace())
+			crds = append(crds, crd)
+		}
+		// The services of conversion webhooks are verified while a component the CRDs belong to is enabled
+		r.recordServiceBackends(crdServiceBackends+"/"+dir, crdComponents[dir], crdsByDir[dir])
+	}
+	r.recordHubServiceBackend()
+
+	// In restricted mode an admin applies the CRDs, the operator waits until they are present
+	if r.RestrictedMode {
+		missing, err := r.ensureRestrictedBundle(context.TODO(), m, restrictedCRDsBundle, crds)
 		if err != nil {
-			reqLogger.Error(err, "failed to deploy", "Kind", crd.GetKind(), "Name", crd.GetName())
+			reqLogger.Error(err, "failed to publish the CRDs")
 			return DeployFailedReason, err
 		}
-		if ok {
-			message := fmt.Sprintf("created new resource: %s %s", crd.GetKind(), crd.GetName())
-			condition := NewHubCondition(operatorv1.Progressing, metav1.ConditionTrue, NewComponentReason, message)
-			SetHubCondition(&m.Status, *condition)
+		if len(missing) > 0 {
+			return RestrictedPrerequisitesMissingReason, fmt.Errorf(
+				"%d CRDs of the %s%s ConfigMaps are not applied", len(missing), restrictedBundlePrefix,
+				restrictedCRDsBundle)
+		}
+		return "", nil
+	}
+
+	var retained []operatorv1.RetainedCRD
+	for _, dir := range dirs {
+		// The CRDs of components disabled for good are removed once no custom resources remain
+		if component, ok := removableCRDComponent(m, dir); ok {
+			for _, crd := range crdsByDir[dir] {
+				removed, err := r.ensureNoComponentCRD(context.TODO(), m, crd)
+				if err != nil {
+					reqLogger.Error(err, "failed to remove the CRD of a disabled component", "Name", crd.GetName())
+					return DeployFailedReason, err
+				}
+				if !removed {
+					retained = append(retained, operatorv1.RetainedCRD{Name: crd.GetName(), Component: component})
+				}
+			}
+			continue
+		}
+
+		for _, crd := range crdsByDir[dir] {
+			// Custom resources stored in versions the CRD removes are migrated before the CRD is updated
+			migrated, err := r.migrateCRDStorage(context.TODO(), m, crd)
+			if err != nil {
+				reqLogger.Error(err, "failed to migrate custom resources", "Name", crd.GetName())
+				condition := NewHubCondition(operatorv1.Blocked, metav1.ConditionTrue, CRDStorageMigrationBlockedReason,
+					err.Error())
+				SetHubCondition(&m.Status, *condition)
+				return CRDStorageMigrationBlockedReason, err
+			}
+			if !migrated {
+				return CRDStorageMigrationPendingReason, fmt.Errorf(
+					"waiting for the API server to store the custom resources of CRD %s in its storage version",
+					crd.GetName())
+			}
+
+			err, ok := deploying.Deploy(r.Client, crd)
+			if err != nil {
+				reqLogger.Error(err, "failed to deploy", "Kind", crd.GetKind(), "Name", crd.GetName())
+				return DeployFailedReason, err
+			}
+			if ok {
+				message := fmt.Sprintf("created new resource: %s %s", crd.GetKind(), crd.GetName())
+				condition := NewHubCondition(operatorv1.Progressing, metav1.ConditionTrue, NewComponentReason, message)
+				SetHubCondition(&m.Status, *condition)
+			}
 		}
 	}
+
+	setRetainedCRDs(m, retained)
+	if c := GetHubCondition(m.Status, operatorv1.Blocked); c != nil && c.Reason == CRDStorageMigrationBlockedReason {
+		RemoveHubCondition(&m.Status, operatorv1.Blocked)
+	}
 	return "", nil
 }
 
@@ -158,6 +227,33 @@ func (r *MultiClusterHubReconciler) deployResources(reqLogger logr.Logger, m *op
 		return CRDRenderReason, err
 	}
 
+	// In restricted mode an admin applies the base resources and labels the hub namespace for cluster monitoring
+	if r.RestrictedMode {
+		hubNamespace := &unstructured.Unstructured{}
+		hubNamespace.SetAPIVersion("v1")
+		hubNamespace.SetKind("Namespace")
+		hubNamespace.SetName(m.GetNamespace())
+		hubNamespace.SetLabels(map[string]string{utils.OpenShiftClusterMonitoringLabel: "true"})
+
+		missing, err := r.ensureRestrictedBundle(context.TODO(), m, restrictedHubBundle,
+			append(resources, hubNamespace))
+		if err != nil {
+			reqLogger.Error(err, "failed to publish the hub resources")
+			return DeployFailedReason, err
+		}
+		if len(missing) > 0 {
+			reqLogger.Info("Resources of the restricted mode bundle are not applied", "Bundle", restrictedHubBundle,
+				"Missing", len(missing))
+		}
+		if webhooksEnabled() {
+			if err := r.ensureRestrictedWebhooks(context.TODO(), m); err != nil {
+				reqLogger.Error(err, "failed to publish the webhook resources")
+				return DeployFailedReason, err
+			}
+		}
+		return "", nil
+	}
+
 	for _, res := range resources {
 		if res.GetNamespace() == m.Namespace {
 			err := controllerutil.SetControllerReference(m, res, r.Scheme)
@@ -6,11 +6,15 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	operatorsv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
 	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -20,19 +24,90 @@ import (
 const (
 	// AnnotationNodeSelector key name of nodeSelector annotation synced from mch
 	AnnotationNodeSelector = "open-cluster-management/nodeSelector"
+
+	// AnnotationLocalClusterLabels records the local-cluster ManagedCluster labels set from spec.localCluster, so
+	// labels removed from the spec are removed from the ManagedCluster as well
+	AnnotationLocalClusterLabels = "installer.open-cluster-management.io/local-cluster-labels"
+
+	// clusterSetLabel assigns a ManagedCluster to a ManagedClusterSet
+	clusterSetLabel = "cluster.open-cluster-management.io/clusterset"
+
+	// defaultAddonProxyPolicy is the proxy policy of add-ons without one in spec.localCluster
+	defaultAddonProxyPolicy = "Disabled"
+)
+
+var (
+	// localClusterAddons lists the add-on settings of the KlusterletAddonConfig in the order they are reconciled
+	localClusterAddons = []string{"applicationManager", "certPolicyController", "policyController", "searchCollector"}
+
+	// localClusterAddonNames maps the add-on settings of the KlusterletAddonConfig to the ManagedClusterAddOns they
+	// install
+	localClusterAddonNames = map[string][]string{
+		"applicationManager":   {"application-manager"},
+		"certPolicyController": {"cert-policy-controller"},
+		"policyController":     {"config-policy-controller", "governance-policy-framework"},
+		"searchCollector":      {"search-collector"},
+	}
 )
 
+// localClusterAddonConfig returns the spec.localCluster configuration of a KlusterletAddonConfig add-on
+func localClusterAddonConfig(m *operatorsv1.MultiClusterHub, addon string) *operatorsv1.LocalClusterAddonConfig {
+	lc := m.Spec.LocalCluster
+	if lc == nil {
+		return nil
+	}
+	switch addon {
+	case "applicationManager":
+		return lc.ApplicationManager
+	case "certPolicyController":
+		return lc.CertPolicyController
+	case "policyController":
+		return lc.PolicyController
+	case "searchCollector":
+		return lc.SearchCollector
+	}
+	return nil
+}
+
 func getKlusterletAddonConfig(m *operatorsv1.MultiClusterHub) *unstructured.Unstructured {
-	grcEnabled := true
+	// GRC is assumed enabled until the profile is resolved
+	grcEnabled := m.Enabled(operatorsv1.GRC) || (!m.ComponentPresent(operatorsv1.GRC) && m.Status.Profile == nil)
 
-	if m.Spec.Overrides != nil {
-		for _, component := range m.Spec.Overrides.Components {
-			if component.Name == operatorsv1.GRC {
-				grcEnabled = component.Enabled
+	defaults := map[string]bool{
+		"applicationManager":   true,
+		"certPolicyController": grcEnabled,
+		"policyController":     grcEnabled,
+		"searchCollector":      false,
+	}
+	spec := map[string]interface{}{}
+	for _, addon := range localClusterAddons {
+		enabled, proxyPolicy := defaults[addon], defaultAddonProxyPolicy
+		if cfg := localClusterAddonConfig(m, addon); cfg != nil {
+			if cfg.Enabled != nil {
+				enabled = *cfg.Enabled
+			}
+			if cfg.ProxyPolicy != "" {
+				proxyPolicy = cfg.ProxyPolicy
+			}
+		}
+		spec[addon] = map[string]interface{}{
+			"enabled":     enabled,
+			"proxyPolicy": proxyPolicy,
+		}
+	}
 
-				break
+	if lc := m.Spec.LocalCluster; lc != nil && lc.ProxyConfig != nil {
+		proxyConfig := map[string]interface{}{}
+		for key, value := range map[string]string{
+			"httpProxy":  lc.ProxyConfig.HTTPProxy,
+			"httpsProxy": lc.ProxyConfig.HTTPSProxy,
+			"noProxy":    lc.ProxyConfig.NoProxy,
+		} {
+			if value != "" {
+				proxyConfig[key] = value
 			}
 		}
+		spec["proxyConfig"] = proxyConfig
 	}
 
 	klusterletaddonconfig := &unstructured.Unstructured{
@@ -43,20 +118,7 @@ func getKlusterletAddonConfig(m *operatorsv1.MultiClusterHub) *unstructured.Unst
 				"name":      m.Spec.LocalClusterName,
 				"namespace": m.Spec.LocalClusterName,
 			},
-			"spec": map[string]interface{}{
-				"applicationManager": map[string]interface{}{
-					"enabled": true,
-				},
-				"certPolicyController": map[string]interface{}{
-					"enabled": grcEnabled,
-				},
-				"policyController": map[string]interface{}{
-					"enabled": grcEnabled,
-				},
-				"searchCollector": map[string]interface{}{
-					"enabled": false,
-				},
-			},
+			"spec": spec,
 		},
 	}
 	return klusterletaddonconfig
@@ -77,11 +139,13 @@ func (r *MultiClusterHubReconciler) ensureKlusterletAddonConfig(m *operatorsv1.M
 	}
 
 	klusterletaddonconfig := getKlusterletAddonConfig(m)
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(klusterletaddonconfig.GroupVersionKind())
 	nsn := types.NamespacedName{
 		Name:      m.Spec.LocalClusterName,
 		Namespace: m.Spec.LocalClusterName,
 	}
-	err = r.Client.Get(ctx, nsn, klusterletaddonconfig)
+	err = r.Client.Get(ctx, nsn, existing)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Creating new klusterletAddonConfig
@@ -101,15 +165,208 @@ func (r *MultiClusterHubReconciler) ensureKlusterletAddonConfig(m *operatorsv1.M
 		return ctrl.Result{}, err
 	}
 
-	utils.AddInstallerLabel(klusterletaddonconfig, m.GetName(), m.GetNamespace())
-
-	err = r.Client.Update(ctx, klusterletaddonconfig)
+	// Only the add-on and proxy settings are reconciled, other fields are left to the klusterlet addon controller
+	desiredSpec, _, _ := unstructured.NestedMap(klusterletaddonconfig.Object, "spec")
+	spec, _, err := unstructured.NestedMap(existing.Object, "spec")
 	if err != nil {
-		r.Log.Error(err, "Failed to update klusterletaddonconfig resource")
+		r.Log.Error(err, "Failed to read klusterletaddonconfig spec")
 		return ctrl.Result{}, err
 	}
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+
+	changed := false
+	for _, key := range append(append([]string{}, localClusterAddons...), "proxyConfig") {
+		want, desired := desiredSpec[key]
+		current, found := spec[key]
+		inSync := containsFields(current, want)
+		if key == "proxyConfig" {
+			// None of the proxy settings are defaulted, so the proxy configuration is reconciled as a whole
+			inSync = equality.Semantic.DeepEqual(current, want)
+		}
+		switch {
+		case !desired && found:
+			delete(spec, key)
+			changed = true
+		case desired && !inSync:
+			spec[key] = want
+			changed = true
+		}
+	}
+
+	labels := existing.GetLabels()
+	if changed || labels["installer.name"] != m.GetName() || labels["installer.namespace"] != m.GetNamespace() {
+		if err := unstructured.SetNestedMap(existing.Object, spec, "spec"); err != nil {
+			return ctrl.Result{}, err
+		}
+		utils.AddInstallerLabel(existing, m.GetName(), m.GetNamespace())
+
+		err = r.Client.Update(ctx, existing)
+		if err != nil {
+			r.Log.Error(err, "Failed to update klusterletaddonconfig resource")
+			return ctrl.Result{}, err
+		}
+
+		r.Log.Info("Updated the KlusterletAddonConfig", "SpecChanged", changed)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// containsFields returns true if the existing value holds every field of the desired value. Fields the server
+// defaults are ignored unless the operator sets them.
+func containsFields(existing, desired interface{}) bool {
+	desiredMap, ok := desired.(map[string]interface{})
+	if !ok {
+		return equality.Semantic.DeepEqual(existing, desired)
+	}
+	existingMap, ok := existing.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key, value := range desiredMap {
+		if !containsFields(existingMap[key], value) {
+			return false
+		}
+	}
+	return true
+}
 
-	r.Log.Info("Updated the KlusterletAddonConfig")
+/*
+ensureLocalClusterConfig applies the labels, cluster set and add-on install namespaces of spec.localCluster to the
+local-cluster ManagedCluster and its ManagedClusterAddOns. Resources that do not exist yet are configured once they
+are created by the multicluster engine and the klusterlet addon controller.
+*/
+func (r *MultiClusterHubReconciler) ensureLocalClusterConfig(ctx context.Context,
+	m *operatorsv1.MultiClusterHub) (ctrl.Result, error) {
+	if err := r.ensureLocalClusterLabels(ctx, m); err != nil {
+		return ctrl.Result{}, err
+	}
 
+	for _, addon := range localClusterAddons {
+		cfg := localClusterAddonConfig(m, addon)
+		if cfg == nil || cfg.InstallNamespace == "" {
+			continue
+		}
+		for _, name := range localClusterAddonNames[addon] {
+			if err := r.ensureLocalClusterAddonNamespace(ctx, m, name, cfg.InstallNamespace); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
 	return ctrl.Result{}, nil
 }
+
+// localClusterLabels returns the labels spec.localCluster sets on the local-cluster ManagedCluster
+func localClusterLabels(m *operatorsv1.MultiClusterHub) map[string]string {
+	labels := map[string]string{}
+	if lc := m.Spec.LocalCluster; lc != nil {
+		for key, value := range lc.Labels {
+			labels[key] = value
+		}
+		if lc.ClusterSet != "" {
+			labels[clusterSetLabel] = lc.ClusterSet
+		}
+	}
+	return labels
+}
+
+/*
+ensureLocalClusterLabels sets the labels of spec.localCluster on the local-cluster ManagedCluster. The keys it set
+are recorded in an annotation, so labels removed from the spec are removed while labels set by others are kept.
+*/
+func (r *MultiClusterHubReconciler) ensureLocalClusterLabels(ctx context.Context, m *operatorsv1.MultiClusterHub) error {
+	mc := &unstructured.Unstructured{}
+	mc.SetAPIVersion("cluster.open-cluster-management.io/v1")
+	mc.SetKind("ManagedCluster")
+	err := r.Client.Get(ctx, types.NamespacedName{Name: m.Spec.LocalClusterName}, mc)
+	if errors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+		r.Log.Info("Local cluster ManagedCluster does not exist yet", "Name", m.Spec.LocalClusterName)
+		return nil
+	} else if err != nil {
+		r.Log.Error(err, "Failed to get local cluster ManagedCluster", "Name", m.Spec.LocalClusterName)
+		return err
+	}
+
+	desired := localClusterLabels(m)
+	labels := mc.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	annotations := mc.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	changed := false
+	for _, key := range strings.Split(annotations[AnnotationLocalClusterLabels], ",") {
+		if _, keep := desired[key]; key == "" || keep {
+			continue
+		}
+		if _, found := labels[key]; found {
+			delete(labels, key)
+			changed = true
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		keys = append(keys, key)
+		if current, found := labels[key]; !found || current != value {
+			labels[key] = value
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+	if recorded := strings.Join(keys, ","); annotations[AnnotationLocalClusterLabels] != recorded {
+		if recorded == "" {
+			delete(annotations, AnnotationLocalClusterLabels)
+		} else {
+			annotations[AnnotationLocalClusterLabels] = recorded
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	mc.SetLabels(labels)
+	mc.SetAnnotations(annotations)
+	if err := r.Client.Update(ctx, mc); err != nil {
+		r.Log.Error(err, "Failed to update local cluster ManagedCluster labels", "Name", m.Spec.LocalClusterName)
+		return err
+	}
+	r.Log.Info("Updated local cluster ManagedCluster labels", "Name", m.Spec.LocalClusterName, "Labels", keys)
+	return nil
+}
+
+// ensureLocalClusterAddonNamespace sets the install namespace of a ManagedClusterAddOn of the local-cluster
+func (r *MultiClusterHubReconciler) ensureLocalClusterAddonNamespace(ctx context.Context,
+	m *operatorsv1.MultiClusterHub, name, installNamespace string) error {
+	addon := &unstructured.Unstructured{}
+	addon.SetAPIVersion("addon.open-cluster-management.io/v1alpha1")
+	addon.SetKind("ManagedClusterAddOn")
+	err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: m.Spec.LocalClusterName}, addon)
+	if errors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+		return nil
+	} else if err != nil {
+		r.Log.Error(err, "Failed to get local cluster ManagedClusterAddOn", "Name", name)
+		return err
+	}
+
+	current, _, _ := unstructured.NestedString(addon.Object, "spec", "installNamespace")
+	if current == installNamespace {
+		return nil
+	}
+	if err := unstructured.SetNestedField(addon.Object, installNamespace, "spec", "installNamespace"); err != nil {
+		return err
+	}
+	if err := r.Client.Update(ctx, addon); err != nil {
+		r.Log.Error(err, "Failed to update local cluster ManagedClusterAddOn", "Name", name)
+		return err
+	}
+	r.Log.Info("Updated local cluster ManagedClusterAddOn install namespace", "Name", name,
+		"InstallNamespace", installNamespace)
+	return nil
+}
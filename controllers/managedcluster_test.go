@@ -1,8 +1,18 @@
 package controllers
 
 import (
+	"context"
+	"testing"
+
+	operatorsv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 func LocalClusterNamespace(name string) *corev1.Namespace {
@@ -16,3 +26,146 @@ func LocalClusterNamespace(name string) *corev1.Namespace {
 		},
 	}
 }
+
+func localClusterHub(localCluster *operatorsv1.LocalClusterConfig) *operatorsv1.MultiClusterHub {
+	return &operatorsv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+		Spec: operatorsv1.MultiClusterHubSpec{
+			LocalClusterName: "local-cluster",
+			LocalCluster:     localCluster,
+			Overrides: &operatorsv1.Overrides{
+				Components: []operatorsv1.ComponentConfig{{Name: operatorsv1.GRC, Enabled: false}},
+			},
+		},
+	}
+}
+
+func Test_getKlusterletAddonConfig(t *testing.T) {
+	enabled := true
+	hub := localClusterHub(&operatorsv1.LocalClusterConfig{
+		SearchCollector: &operatorsv1.LocalClusterAddonConfig{Enabled: &enabled, ProxyPolicy: "CustomProxy"},
+		ProxyConfig:     &operatorsv1.LocalClusterProxyConfig{HTTPSProxy: "http://proxy:3128"},
+	})
+
+	spec := getKlusterletAddonConfig(hub).Object["spec"].(map[string]interface{})
+	want := map[string]map[string]interface{}{
+		"applicationManager":   {"enabled": true, "proxyPolicy": defaultAddonProxyPolicy},
+		"certPolicyController": {"enabled": false, "proxyPolicy": defaultAddonProxyPolicy},
+		"policyController":     {"enabled": false, "proxyPolicy": defaultAddonProxyPolicy},
+		"searchCollector":      {"enabled": true, "proxyPolicy": "CustomProxy"},
+	}
+	for addon, fields := range want {
+		if !equality.Semantic.DeepEqual(spec[addon], map[string]interface{}(fields)) {
+			t.Errorf("spec.%s = %v, want %v", addon, spec[addon], fields)
+		}
+	}
+	if proxy := spec["proxyConfig"]; !equality.Semantic.DeepEqual(proxy,
+		map[string]interface{}{"httpsProxy": "http://proxy:3128"}) {
+		t.Errorf("spec.proxyConfig = %v, want the custom proxy", proxy)
+	}
+}
+
+func Test_ensureKlusterletAddonConfig_reconcilesDrift(t *testing.T) {
+	hub := localClusterHub(nil)
+	existing := testObject("agent.open-cluster-management.io/v1", "KlusterletAddonConfig", "local-cluster",
+		"local-cluster")
+	existing.Object["spec"] = map[string]interface{}{
+		"clusterName":        "local-cluster",
+		"applicationManager": map[string]interface{}{"enabled": true, "proxyPolicy": "Disabled"},
+		"searchCollector":    map[string]interface{}{"enabled": true},
+		"proxyConfig":        map[string]interface{}{"httpProxy": "http://stale:3128"},
+	}
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(LocalClusterNamespace("local-cluster"), existing).Build(),
+		Log: clog.Log.WithName("test"),
+	}
+
+	if _, err := r.ensureKlusterletAddonConfig(hub); err != nil {
+		t.Fatalf("ensureKlusterletAddonConfig() error = %v", err)
+	}
+
+	got := testObject("agent.open-cluster-management.io/v1", "KlusterletAddonConfig", "", "")
+	key := types.NamespacedName{Name: "local-cluster", Namespace: "local-cluster"}
+	if err := r.Client.Get(context.Background(), key, got); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, _, _ := unstructured.NestedBool(got.Object, "spec", "searchCollector", "enabled"); enabled {
+		t.Error("expected the search collector to be disabled again")
+	}
+	if _, found, _ := unstructured.NestedMap(got.Object, "spec", "proxyConfig"); found {
+		t.Error("expected the proxy configuration to be removed")
+	}
+	if name, _, _ := unstructured.NestedString(got.Object, "spec", "clusterName"); name != "local-cluster" {
+		t.Errorf("expected fields not managed by the operator to be kept, got clusterName %q", name)
+	}
+	if got.GetLabels()["installer.name"] != hub.Name {
+		t.Errorf("expected the installer labels, got %v", got.GetLabels())
+	}
+
+	// A configuration in sync is not updated again
+	resourceVersion := got.GetResourceVersion()
+	if _, err := r.ensureKlusterletAddonConfig(hub); err != nil {
+		t.Fatalf("ensureKlusterletAddonConfig() error = %v", err)
+	}
+	if err := r.Client.Get(context.Background(), key, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.GetResourceVersion() != resourceVersion {
+		t.Error("expected no update of a KlusterletAddonConfig in sync")
+	}
+}
+
+func Test_ensureLocalClusterConfig(t *testing.T) {
+	hub := localClusterHub(&operatorsv1.LocalClusterConfig{
+		Labels:           map[string]string{"environment": "prod"},
+		ClusterSet:       "hub",
+		PolicyController: &operatorsv1.LocalClusterAddonConfig{InstallNamespace: "policy-agent"},
+	})
+	mc := testObject("cluster.open-cluster-management.io/v1", "ManagedCluster", "", "local-cluster")
+	mc.SetLabels(map[string]string{"vendor": "OpenShift"})
+	addon := testObject("addon.open-cluster-management.io/v1alpha1", "ManagedClusterAddOn", "local-cluster",
+		"config-policy-controller")
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(mc, addon).Build(),
+		Log:    clog.Log.WithName("test"),
+	}
+
+	if _, err := r.ensureLocalClusterConfig(context.Background(), hub); err != nil {
+		t.Fatalf("ensureLocalClusterConfig() error = %v", err)
+	}
+
+	got := testObject("cluster.open-cluster-management.io/v1", "ManagedCluster", "", "")
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "local-cluster"}, got); err != nil {
+		t.Fatal(err)
+	}
+	labels := got.GetLabels()
+	if labels["environment"] != "prod" || labels[clusterSetLabel] != "hub" || labels["vendor"] != "OpenShift" {
+		t.Errorf("ManagedCluster labels = %v, want the spec.localCluster labels and cluster set", labels)
+	}
+
+	gotAddon := testObject("addon.open-cluster-management.io/v1alpha1", "ManagedClusterAddOn", "", "")
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "config-policy-controller",
+		Namespace: "local-cluster"}, gotAddon); err != nil {
+		t.Fatal(err)
+	}
+	if ns, _, _ := unstructured.NestedString(gotAddon.Object, "spec", "installNamespace"); ns != "policy-agent" {
+		t.Errorf("installNamespace = %q, want policy-agent", ns)
+	}
+
+	// Labels removed from the spec are removed, labels set by others are kept
+	hub.Spec.LocalCluster.Labels = nil
+	if _, err := r.ensureLocalClusterConfig(context.Background(), hub); err != nil {
+		t.Fatalf("ensureLocalClusterConfig() error = %v", err)
+	}
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "local-cluster"}, got); err != nil {
+		t.Fatal(err)
+	}
+	labels = got.GetLabels()
+	if _, found := labels["environment"]; found || labels["vendor"] != "OpenShift" || labels[clusterSetLabel] != "hub" {
+		t.Errorf("ManagedCluster labels = %v, want the environment label removed", labels)
+	}
+	if got.GetAnnotations()[AnnotationLocalClusterLabels] != clusterSetLabel {
+		t.Errorf("recorded labels = %q, want %s", got.GetAnnotations()[AnnotationLocalClusterLabels], clusterSetLabel)
+	}
+}
@@ -33,6 +33,11 @@ package controllers
 import (
 	"time"
 
+	"github.com/stolostron/multiclusterhub-operator/pkg/capabilities"
+	"github.com/stolostron/multiclusterhub-operator/pkg/health"
+	"github.com/stolostron/multiclusterhub-operator/pkg/rbacaudit"
+	"github.com/stolostron/multiclusterhub-operator/pkg/servingcert"
+	"github.com/stolostron/multiclusterhub-operator/pkg/tlsprofile"
 	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
 
 	"github.com/go-logr/logr"
@@ -45,11 +50,42 @@ import (
 type MultiClusterHubReconciler struct {
 	Client          client.Client
 	UncachedClient  client.Client
+	APIReader       client.Reader
 	CacheSpec       CacheSpec
 	Scheme          *runtime.Scheme
 	Log             logr.Logger
 	UpgradeableCond utils.Condition
 	OLMVersion      string // "v0", "v1", or "" (no OLM)
+	Capabilities    *capabilities.Discovery
+	Health          *health.Tracker
+	TLSProfile      *tlsprofile.Profile
+	MetricsCert     *servingcert.Provider
+
+	// RestrictedMode leaves the cluster-scoped resources and the resources of untracked namespaces to an admin
+	RestrictedMode bool
+
+	// adoption collects the adoption report of the current pass over the hub components
+	adoption *adoptionReport
+
+	// rolloutHeld is set when a component deployment is held back by the rollout during the current pass
+	rolloutHeld bool
+
+	// oadpDetected holds the OADP installation detected during the current reconcile
+	oadpDetected *oadpDetection
+
+	// healthRestored is set once the health tracker was seeded from the health history ConfigMap, and
+	// healthPersisted holds the history last read from or written to it
+	healthRestored  bool
+	healthPersisted string
+
+	// statusWorkloads holds the workloads tracked in the status of each component, as last rendered from its chart
+	statusWorkloads *registry[[]statusWorkload]
+
+	// rbacRequirements holds the permissions each component needs, as last rendered from its chart
+	rbacRequirements *registry[rbacaudit.Requirements]
+
+	// serviceBackends holds the services of the conversion webhooks and APIServices, as last rendered
+	serviceBackends *registry[[]serviceBackend]
 }
 
 const (
@@ -84,6 +120,7 @@ var (
 //+kubebuilder:rbac:groups=console.openshift.io;search.open-cluster-management.io,resources=consoleplugins;consolelinks;consolenotifications;searches,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=operator.openshift.io,resources=cloudcredentials;consoles,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=config.openshift.io,resources=apiservers;authentications;infrastructures,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
 //+kubebuilder:rbac:groups="";"apps",resources=deployments;services;serviceaccounts,verbs=patch;delete;get;deletecollection
 //+kubebuilder:rbac:groups=packages.operators.coreos.com,resources=packagemanifests,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules;servicemonitors,verbs=create;delete;get;list;watch;update;patch;deletecollection
@@ -96,6 +133,9 @@ var (
 // InternalHubComponent
 // +kubebuilder:rbac:groups="operator.open-cluster-management.io",resources="internalhubcomponents",verbs=create;get;delete;patch;list;watch
 
+// Status of the component workloads
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+
 // Note: The Reconcile function has been moved to reconcile.go
 // Note: STS-related functions have been moved to sts.go
 // Note: Component management functions have been moved to components.go
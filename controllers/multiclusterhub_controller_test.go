@@ -1463,6 +1463,25 @@ func Test_ensureComponentNamespaces(t *testing.T) {
 			wantResult: ctrl.Result{},
 			wantErr:    false,
 		},
+		{
+			name: "should create the custom namespaces of enabled components",
+			mch: &operatorv1.MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{Name: "mch", Namespace: "ocm"},
+				Spec: operatorv1.MultiClusterHubSpec{
+					Overrides: &operatorv1.Overrides{
+						Components: []operatorv1.ComponentConfig{
+							{
+								Enabled:   true,
+								Name:      operatorv1.Search,
+								Namespace: "search-tenant",
+							},
+						},
+					},
+				},
+			},
+			wantResult: ctrl.Result{},
+			wantErr:    false,
+		},
 	}
 
 	registerScheme()
@@ -1471,6 +1490,9 @@ func Test_ensureComponentNamespaces(t *testing.T) {
 	backupNS := BackupNamespace()
 	backupNS.Status.Phase = corev1.NamespaceActive
 	_ = recon.Client.Create(context.TODO(), backupNS)
+	searchNS := ComponentNamespace("search-tenant")
+	searchNS.Status.Phase = corev1.NamespaceActive
+	_ = recon.Client.Create(context.TODO(), searchNS)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1488,6 +1510,11 @@ func Test_ensureComponentNamespaces(t *testing.T) {
 					t.Errorf("expected backup namespace to be created, got error: %v", err)
 				}
 			}
+			for _, name := range utils.ComponentNamespaces(tt.mch) {
+				if err := recon.Client.Get(context.TODO(), types.NamespacedName{Name: name}, &corev1.Namespace{}); err != nil {
+					t.Errorf("expected component namespace %s to be created, got error: %v", name, err)
+				}
+			}
 		})
 	}
 }
@@ -1541,7 +1568,7 @@ func Test_ensureInternalHubComponent(t *testing.T) {
 			}
 
 			for _, c := range tt.mch.Spec.Overrides.Components {
-				if _, err := recon.ensureInternalHubComponent(context.TODO(), tt.mch, c.Name); err != nil {
+				if _, err := recon.ensureInternalHubComponent(context.TODO(), tt.mch, c.Name, nil); err != nil {
 					t.Errorf("ensureInternalHubComponent(context.TODO(), tt.mch, c.Name) = %v", err)
 				}
 
@@ -1607,7 +1634,7 @@ func Test_ensureNoInternalHubComponent(t *testing.T) {
 				}
 
 				// Create instances of the InternalHubComponent
-				if _, err := recon.ensureInternalHubComponent(context.TODO(), tt.mch, c.Name); err != nil {
+				if _, err := recon.ensureInternalHubComponent(context.TODO(), tt.mch, c.Name, nil); err != nil {
 					t.Errorf("ensureInternalHubComponent(context.TODO(), tt.mch, c.Name) = %v", err)
 				}
 
@@ -1895,23 +1922,16 @@ func Test_applyEnvConfig(t *testing.T) {
 	}
 }
 
-func Test_logAndSetCondition(t *testing.T) {
+func Test_logApplyError(t *testing.T) {
 	tests := []struct {
 		name     string
 		err      error
-		mch      *operatorv1.MultiClusterHub
 		message  string
 		template *unstructured.Unstructured
 	}{
 		{
-			name: "should log and set condition of MCH",
-			err:  fmt.Errorf("Test error"),
-			mch: &operatorv1.MultiClusterHub{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "multiclusterhub",
-					Namespace: "test-ns",
-				},
-			},
+			name:    "should log and wrap the apply error",
+			err:     fmt.Errorf("Test error"),
 			message: "This is a test error",
 			template: &unstructured.Unstructured{
 				Object: map[string]interface{}{
@@ -1930,8 +1950,9 @@ func Test_logAndSetCondition(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			if _, err := recon.logAndSetCondition(tt.err, tt.message, tt.template, tt.mch); err == nil {
-				t.Errorf("logAndSetCondition() = %v, expected an error", err)
+			_, err := recon.logApplyError(tt.err, tt.message, tt.template)
+			if err == nil || !strings.Contains(err.Error(), tt.err.Error()) {
+				t.Errorf("logApplyError() = %v, expected the wrapped error", err)
 			}
 		})
 	}
@@ -30,7 +30,7 @@ import (
 // These are sufficient to identify MCH-created NetworkPolicies for deletion when disabled.
 
 // networkPolicyNamespaceOverrides maps components whose NetworkPolicies must be deployed outside
-// the MCH namespace (chart rendering otherwise always targets mch.Namespace). Observability's
+// the component namespace (chart rendering otherwise targets mch.ComponentNamespace). Observability's
 // operand NetworkPolicies live in open-cluster-management-observability, created by the
 // multicluster-observability-operator rather than MCH.
 var networkPolicyNamespaceOverrides = map[string]string{
@@ -116,8 +116,8 @@ func (r *MultiClusterHubReconciler) ensureNetworkPolicies(ctx context.Context, m
 		if !componentEnabled {
 			// Component disabled - delete its NetworkPolicy if MCH-created
 			chartLocation := r.fetchChartLocation(component)
-			templates, errs := renderer.RenderChart(chartLocation, mch, cacheSpec.ImageOverrides, cacheSpec.TemplateOverrides,
-				isSTSEnabled, r.OLMVersion)
+			templates, errs := renderer.RenderComponentChart(chartLocation, mch, mch.ComponentNamespace(component),
+				cacheSpec.ImageOverrides, cacheSpec.TemplateOverrides, isSTSEnabled, r.OLMVersion)
 
 			if len(errs) > 0 {
 				// Skip deletion if chart rendering fails - component may have been removed
@@ -169,8 +169,8 @@ func (r *MultiClusterHubReconciler) ensureNetworkPolicies(ctx context.Context, m
 
 		// Render NetworkPolicy from Helm template
 		chartLocation := r.fetchChartLocation(component)
-		templates, errs := renderer.RenderChart(chartLocation, mch, cacheSpec.ImageOverrides, cacheSpec.TemplateOverrides,
-			isSTSEnabled, r.OLMVersion)
+		templates, errs := renderer.RenderComponentChart(chartLocation, mch, mch.ComponentNamespace(component),
+			cacheSpec.ImageOverrides, cacheSpec.TemplateOverrides, isSTSEnabled, r.OLMVersion)
 
 		if len(errs) > 0 {
 			// Rendering errors indicate real chart failures - log and requeue
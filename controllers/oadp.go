@@ -0,0 +1,168 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/oadp"
+	renderer "github.com/stolostron/multiclusterhub-operator/pkg/rendering"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// oadpDetection is the OADP installation detected during the current reconcile
+type oadpDetection struct {
+	installation *oadp.Installation
+	err          error
+}
+
+/*
+detectOADP returns the OADP installation cluster-backup depends on, or nil if OADP is not installed. Detecting OADP
+lists Subscriptions and ClusterExtensions across the cluster, so it is done once per reconcile and the result is shared
+by the template adjustments and the status.
+*/
+func (r *MultiClusterHubReconciler) detectOADP(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	*oadp.Installation, error) {
+	if r.oadpDetected == nil {
+		packageName, _, _, _, _, _ := renderer.GetOADPConfig(m)
+		inst, err := oadp.Detect(ctx, r.Client, packageName)
+		r.oadpDetected = &oadpDetection{installation: inst, err: err}
+	}
+	return r.oadpDetected.installation, r.oadpDetected.err
+}
+
+/*
+reconcileOADPTemplates adjusts the rendered cluster-backup templates to the OADP installation already on the cluster.
+OADP installed outside of cluster-backup, or with a different OLM version than the one rendered, is kept as is and the
+OADP install templates are dropped so a second copy is not installed. A managed Subscription is never moved to a
+channel older than the installed version.
+*/
+func (r *MultiClusterHubReconciler) reconcileOADPTemplates(ctx context.Context, m *operatorv1.MultiClusterHub,
+	templates []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	inst, err := r.detectOADP(ctx, m)
+	if err != nil || inst == nil {
+		return templates, err
+	}
+
+	renderedOLMVersion := "v0"
+	for _, template := range templates {
+		if template.GetKind() == "ClusterExtension" {
+			renderedOLMVersion = "v1"
+		}
+	}
+
+	if !inst.Managed || inst.OLMVersion != renderedOLMVersion {
+		r.Log.Info("Using existing OADP installation", "Managed", inst.Managed, "OLMVersion", inst.OLMVersion,
+			"Version", inst.Version())
+
+		kept := templates[:0]
+		for _, template := range templates {
+			if !isOADPInstallTemplate(template) {
+				kept = append(kept, template)
+			}
+		}
+		return kept, nil
+	}
+
+	installed := inst.Version()
+	for _, template := range templates {
+		if template.GetKind() != "Subscription" || template.GetName() != oadp.SubscriptionName {
+			continue
+		}
+
+		channel, _, _ := unstructured.NestedString(template.Object, "spec", "channel")
+		if inst.Channel() == "" || !oadp.IsDowngrade(installed, channel) {
+			continue
+		}
+
+		r.Log.Info("Refusing to downgrade OADP, keeping the installed channel", "Installed", installed,
+			"Channel", inst.Channel(), "Requested", channel)
+		if err := unstructured.SetNestedField(template.Object, inst.Channel(), "spec", "channel"); err != nil {
+			return templates, err
+		}
+	}
+	return templates, nil
+}
+
+// isOADPInstallTemplate returns true for the cluster-backup templates that install the OADP operator
+func isOADPInstallTemplate(template *unstructured.Unstructured) bool {
+	switch template.GetKind() {
+	case "Subscription", "OperatorGroup", "ClusterExtension":
+		return true
+	case "ServiceAccount":
+		return template.GetName() == "oadp-installer"
+	case "ClusterRoleBinding":
+		return template.GetName() == "oadp-installer-admin"
+	}
+	return false
+}
+
+// listOADPResources adds the OLM resources of the OADP installation to the custom resources the installer observes
+func (r *MultiClusterHubReconciler) listOADPResources(ctx context.Context, m *operatorv1.MultiClusterHub,
+	ret map[string]*unstructured.Unstructured) {
+	inst, err := r.detectOADP(ctx, m)
+	if err != nil {
+		r.Log.V(2).Info("Failed to detect OADP installation", "error", err)
+		return
+	}
+	if inst == nil {
+		return
+	}
+
+	objs := map[string]runtime.Object{}
+	if inst.Subscription != nil {
+		objs["oadp-sub"] = inst.Subscription
+	}
+	if inst.CSV != nil {
+		objs["oadp-csv"] = inst.CSV
+	}
+	if inst.ClusterExtension != nil {
+		objs["oadp-clusterextension"] = inst.ClusterExtension
+	}
+
+	for key, obj := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			r.Log.Error(err, "Failed to unmarshal OADP resource", "Key", key)
+			continue
+		}
+		ret[key] = &unstructured.Unstructured{Object: u}
+	}
+}
+
+// mapOADPVersion marks an OADP component unavailable if the installed OADP version is not supported by
+// cluster-backup
+func mapOADPVersion(status operatorv1.StatusCondition, version string) operatorv1.StatusCondition {
+	if version == "" {
+		return status
+	}
+	if err := oadp.CheckCompatible(version); err != nil {
+		status.Type = "Available"
+		status.Status = metav1.ConditionFalse
+		status.Reason = "IncompatibleVersion"
+		status.Message = fmt.Sprintf("%s. Upgrade OADP to use cluster-backup", err.Error())
+		status.Available = false
+	}
+	return status
+}
@@ -0,0 +1,211 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/api/pkg/lib/version"
+	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/oadp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func oadpInstallTemplates() []*unstructured.Unstructured {
+	sub := &unstructured.Unstructured{}
+	sub.SetAPIVersion("operators.coreos.com/v1alpha1")
+	sub.SetKind("Subscription")
+	sub.SetName(oadp.SubscriptionName)
+	sub.SetNamespace(oadp.Namespace)
+	_ = unstructured.SetNestedField(sub.Object, "stable-1.4", "spec", "channel")
+
+	og := &unstructured.Unstructured{}
+	og.SetAPIVersion("operators.coreos.com/v1")
+	og.SetKind("OperatorGroup")
+	og.SetName("redhat-oadp-operator-group")
+
+	deploy := &unstructured.Unstructured{}
+	deploy.SetAPIVersion("apps/v1")
+	deploy.SetKind("Deployment")
+	deploy.SetName("cluster-backup-chart-clusterbackup")
+
+	return []*unstructured.Unstructured{sub, og, deploy}
+}
+
+func oadpSubscription(namespace, name, channel, installedCSV string) *subv1alpha1.Subscription {
+	return &subv1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       &subv1alpha1.SubscriptionSpec{Package: "redhat-oadp-operator", Channel: channel},
+		Status:     subv1alpha1.SubscriptionStatus{InstalledCSV: installedCSV},
+	}
+}
+
+func oadpCSV(v string) *subv1alpha1.ClusterServiceVersion {
+	return &subv1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "oadp-operator.v" + v, Namespace: oadp.Namespace},
+		Spec: subv1alpha1.ClusterServiceVersionSpec{
+			Version: version.OperatorVersion{Version: semver.MustParse(v)},
+		},
+	}
+}
+
+func Test_reconcileOADPTemplates(t *testing.T) {
+	registerScheme()
+
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		wantKinds   []string
+		wantChannel string
+	}{
+		{
+			name:        "not installed",
+			wantKinds:   []string{"Subscription", "OperatorGroup", "Deployment"},
+			wantChannel: "stable-1.4",
+		},
+		{
+			name: "managed installation upgraded in place",
+			objs: []client.Object{
+				oadpSubscription(oadp.Namespace, oadp.SubscriptionName, "stable-1.4", "oadp-operator.v1.4.2"),
+				oadpCSV("1.4.2"),
+			},
+			wantKinds:   []string{"Subscription", "OperatorGroup", "Deployment"},
+			wantChannel: "stable-1.4",
+		},
+		{
+			name: "managed installation is not downgraded",
+			objs: []client.Object{
+				oadpSubscription(oadp.Namespace, oadp.SubscriptionName, "stable-1.5", "oadp-operator.v1.5.1"),
+				oadpCSV("1.5.1"),
+			},
+			wantKinds:   []string{"Subscription", "OperatorGroup", "Deployment"},
+			wantChannel: "stable-1.5",
+		},
+		{
+			name: "external installation is kept",
+			objs: []client.Object{
+				oadpSubscription("openshift-adp", "redhat-oadp-operator", "stable", ""),
+			},
+			wantKinds: []string{"Deployment"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MultiClusterHubReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tt.objs...).Build(),
+				Log:    clog.Log.WithName("test"),
+			}
+			hub := &operatorv1.MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+			}
+
+			got, err := r.reconcileOADPTemplates(context.Background(), hub, oadpInstallTemplates())
+			if err != nil {
+				t.Fatalf("reconcileOADPTemplates() error = %v", err)
+			}
+
+			var kinds []string
+			for _, template := range got {
+				kinds = append(kinds, template.GetKind())
+				if template.GetKind() == "Subscription" {
+					channel, _, _ := unstructured.NestedString(template.Object, "spec", "channel")
+					if channel != tt.wantChannel {
+						t.Errorf("Subscription channel = %q, want %q", channel, tt.wantChannel)
+					}
+				}
+			}
+			if len(kinds) != len(tt.wantKinds) {
+				t.Fatalf("reconcileOADPTemplates() kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+			for i := range kinds {
+				if kinds[i] != tt.wantKinds[i] {
+					t.Errorf("reconcileOADPTemplates() kinds = %v, want %v", kinds, tt.wantKinds)
+				}
+			}
+		})
+	}
+}
+
+func Test_detectOADP_OncePerReconcile(t *testing.T) {
+	registerScheme()
+
+	lists := 0
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			oadpSubscription(oadp.Namespace, oadp.SubscriptionName, "stable-1.5", "oadp-operator.v1.5.1"),
+			oadpCSV("1.5.1"),
+		).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				lists++
+				return c.List(ctx, list, opts...)
+			},
+		}).Build(),
+		Log: clog.Log.WithName("test"),
+	}
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+
+	if _, err := r.reconcileOADPTemplates(context.Background(), hub, oadpInstallTemplates()); err != nil {
+		t.Fatalf("reconcileOADPTemplates() error = %v", err)
+	}
+	detected := lists
+
+	resources := map[string]*unstructured.Unstructured{}
+	r.listOADPResources(context.Background(), hub, resources)
+	if lists != detected {
+		t.Errorf("expected OADP to be detected once per reconcile, got %d lists after %d", lists, detected)
+	}
+	if resources["oadp-sub"] == nil {
+		t.Errorf("expected the detected Subscription to be listed, got %v", resources)
+	}
+
+	// The next reconcile detects OADP again
+	r.oadpDetected = nil
+	r.listOADPResources(context.Background(), hub, resources)
+	if lists != 2*detected {
+		t.Errorf("expected OADP to be detected again on the next reconcile, got %d lists", lists)
+	}
+}
+
+func Test_getComponentStatuses_OADP(t *testing.T) {
+	csv := func(v string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"version": v},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"phase": "Succeeded", "reason": "InstallSucceeded"},
+				},
+			},
+		}}
+		u.SetName("oadp-operator.v" + v)
+		return u
+	}
+
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+
+	statuses := getComponentStatuses(hub, nil, nil, map[string]*unstructured.Unstructured{"oadp-csv": csv("1.5.0")},
+		true, false, "v0")
+	if got := statuses["redhat-oadp-operator-csv"]; !got.Available {
+		t.Errorf("expected a supported OADP version to be available, got %+v", got)
+	}
+
+	statuses = getComponentStatuses(hub, nil, nil, map[string]*unstructured.Unstructured{"oadp-csv": csv("1.3.2")},
+		true, false, "v0")
+	if got := statuses["redhat-oadp-operator-csv"]; got.Available || got.Reason != "IncompatibleVersion" {
+		t.Errorf("expected an unsupported OADP version to be unavailable, got %+v", got)
+	}
+}
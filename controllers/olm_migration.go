@@ -0,0 +1,605 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	e "errors"
+	"fmt"
+	"time"
+
+	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine"
+	v0 "github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine/olm/v0"
+	v1 "github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine/olm/v1"
+	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// migrationPollInterval is how often an in-flight MCE OLM migration step is re-checked
+	migrationPollInterval = 10 * time.Second
+
+	// Steps moving an OLM v0 installation to OLM v1
+	migrationStepPreflight          = "Preflight"
+	migrationStepInstallerRBAC      = "InstallerServiceAccount"
+	migrationStepRemoveSubscription = "RemoveSubscription"
+	migrationStepCreateCE           = "CreateClusterExtension"
+	migrationStepRemoveCSV          = "RemoveClusterServiceVersion"
+	migrationStepRemoveOG           = "RemoveOperatorGroup"
+	migrationStepReleaseVersionPin  = "ReleaseVersionPin"
+
+	// Steps rolling an unfinished migration back to OLM v0
+	migrationStepRemoveCE            = "RemoveClusterExtension"
+	migrationStepRestoreSubscription = "RestoreSubscription"
+	migrationStepRemoveInstallerRBAC = "RemoveInstallerServiceAccount"
+)
+
+// migrationBlockedError is returned by a migration step that cannot make progress without user intervention
+type migrationBlockedError struct {
+	msg string
+}
+
+func (b *migrationBlockedError) Error() string {
+	return b.msg
+}
+
+func migrationBlocked(format string, args ...interface{}) error {
+	return &migrationBlockedError{msg: fmt.Sprintf(format, args...)}
+}
+
+// migrationStep is a single idempotent step of an MCE OLM migration. run returns done=false with a message while
+// the step is waiting on the cluster.
+type migrationStep struct {
+	name string
+	run  func(ctx context.Context, m *operatorv1.MultiClusterHub,
+		migration *operatorv1.MCEOLMMigrationStatus) (done bool, message string, err error)
+}
+
+// mceOLMVersion returns the OLM version used to manage the MCE installation. The mce-olm-version annotation takes
+// precedence over the OLM version detected on the cluster so an existing installation can be migrated or rolled back.
+// A pending migration keeps the installation on its current OLM version.
+func (r *MultiClusterHubReconciler) mceOLMVersion(m *operatorv1.MultiClusterHub) string {
+	if v := requestedMCEOLMVersion(m); v != "" {
+		return v
+	}
+	if migration := m.Status.MCEOLMMigration; migration != nil && migration.Phase == operatorv1.OLMMigrationPending {
+		return migration.SourceOLMVersion
+	}
+	return r.OLMVersion
+}
+
+// requestedMCEOLMVersion returns the OLM version explicitly requested for the MCE installation, or "" when none is
+func requestedMCEOLMVersion(m *operatorv1.MultiClusterHub) string {
+	if v := utils.GetMCEOLMVersion(m); v == "v0" || v == "v1" {
+		return v
+	}
+	return ""
+}
+
+// installedMCEOLMVersion reports which OLM API currently holds the MCE installation created by this hub.
+// Returns "" when MCH did not install MCE through OLM.
+func (r *MultiClusterHubReconciler) installedMCEOLMVersion(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	string, error) {
+	sub, err := v0.GetManagedMCESubscription(ctx, r.Client)
+	if err != nil && !apimeta.IsNoMatchError(err) {
+		return "", err
+	}
+	if sub != nil && v0.CreatedByMCH(sub, m) {
+		return "v0", nil
+	}
+
+	ce, err := v1.GetManagedMCEClusterExtension(ctx, r.Client)
+	if err != nil && !apimeta.IsNoMatchError(err) {
+		return "", err
+	}
+	if ce != nil && v1.CreatedByMCH(ce, m) {
+		return "v1", nil
+	}
+	return "", nil
+}
+
+// mceOLMMigrationActive returns true while a migration owns the MCE OLM resources
+func mceOLMMigrationActive(m *operatorv1.MultiClusterHub) bool {
+	migration := m.Status.MCEOLMMigration
+	return migration != nil &&
+		(migration.Phase == operatorv1.OLMMigrationInProgress || migration.Phase == operatorv1.OLMMigrationBlocked)
+}
+
+/*
+ensureMCEOLMMigration moves the MCE installation between OLM v0 and OLM v1 when the installed OLM version differs from
+the requested one. Each step is recorded in status.mceOLMMigration and is safe to repeat, so the migration resumes
+where it left off after an operator restart. Requesting the source OLM version while a migration is unfinished rolls
+it back. As the migration deletes the MCE Subscription and CSV, a change of the detected OLM version is only reported
+as a pending migration until the OLM version is requested.
+*/
+func (r *MultiClusterHubReconciler) ensureMCEOLMMigration(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	ctrl.Result, error) {
+	desired := requestedMCEOLMVersion(m)
+	migration := m.Status.MCEOLMMigration
+
+	if !mceOLMMigrationActive(m) {
+		installed, err := r.installedMCEOLMVersion(ctx, m)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		pending := migration != nil && migration.Phase == operatorv1.OLMMigrationPending
+		if desired == "" {
+			// The detected OLM version only proposes a migration
+			detected := r.OLMVersion
+			if installed == "" || detected == "" || installed == detected {
+				if pending {
+					m.Status.MCEOLMMigration = nil
+				}
+				return ctrl.Result{}, nil
+			}
+			if !pending || migration.TargetOLMVersion != detected {
+				r.Log.Info("MCE OLM migration is available, set the OLM version of MCE to start it",
+					"from", installed, "to", detected)
+				m.Status.MCEOLMMigration = &operatorv1.MCEOLMMigrationStatus{
+					SourceOLMVersion: installed,
+					TargetOLMVersion: detected,
+					Phase:            operatorv1.OLMMigrationPending,
+				}
+			}
+			return ctrl.Result{}, nil
+		}
+		if installed == "" || installed == desired {
+			if pending {
+				m.Status.MCEOLMMigration = nil
+			}
+			return ctrl.Result{}, nil
+		}
+
+		r.Log.Info("Starting MCE OLM migration", "from", installed, "to", desired)
+		migration = &operatorv1.MCEOLMMigrationStatus{
+			SourceOLMVersion: installed,
+			TargetOLMVersion: desired,
+			Phase:            operatorv1.OLMMigrationInProgress,
+		}
+		m.Status.MCEOLMMigration = migration
+
+	} else if desired != "" && desired != migration.TargetOLMVersion {
+		r.Log.Info("Rolling back MCE OLM migration", "from", migration.TargetOLMVersion, "to", desired)
+		migration.TargetOLMVersion = desired
+		migration.Phase = operatorv1.OLMMigrationInProgress
+	}
+
+	for _, step := range r.mceOLMMigrationSteps(migration) {
+		if s := getMigrationStep(migration, step.name); s != nil && s.Status == metav1.ConditionTrue {
+			continue
+		}
+
+		done, message, err := step.run(ctx, m, migration)
+		var blocked *migrationBlockedError
+		switch {
+		case e.As(err, &blocked):
+			r.Log.Info("MCE OLM migration blocked", "step", step.name, "reason", blocked.Error())
+			setMigrationStep(migration, step.name, metav1.ConditionFalse, blocked.Error())
+			migration.Phase = operatorv1.OLMMigrationBlocked
+			condition := NewHubCondition(operatorv1.Blocked, metav1.ConditionTrue, MCEOLMMigrationBlockedReason,
+				fmt.Sprintf("MCE OLM migration blocked at step %s: %s", step.name, blocked.Error()))
+			SetHubCondition(&m.Status, *condition)
+			return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+
+		case err != nil:
+			setMigrationStep(migration, step.name, metav1.ConditionFalse, err.Error())
+			return ctrl.Result{}, fmt.Errorf("MCE OLM migration step %s failed: %w", step.name, err)
+
+		case !done:
+			r.Log.Info("Waiting on MCE OLM migration step", "step", step.name, "message", message)
+			setMigrationStep(migration, step.name, metav1.ConditionUnknown, message)
+			return ctrl.Result{RequeueAfter: migrationPollInterval}, nil
+		}
+
+		r.Log.Info("Completed MCE OLM migration step", "step", step.name)
+		setMigrationStep(migration, step.name, metav1.ConditionTrue, message)
+	}
+
+	if migration.TargetOLMVersion == migration.SourceOLMVersion {
+		migration.Phase = operatorv1.OLMMigrationRolledBack
+	} else {
+		migration.Phase = operatorv1.OLMMigrationCompleted
+	}
+	if c := GetHubCondition(m.Status, operatorv1.Blocked); c != nil && c.Reason == MCEOLMMigrationBlockedReason {
+		RemoveHubCondition(&m.Status, operatorv1.Blocked)
+	}
+	r.Log.Info("MCE OLM migration finished", "phase", migration.Phase)
+	return ctrl.Result{}, nil
+}
+
+// mceOLMMigrationSteps returns the ordered steps needed to reach the migration's target OLM version
+func (r *MultiClusterHubReconciler) mceOLMMigrationSteps(migration *operatorv1.MCEOLMMigrationStatus) []migrationStep {
+	switch {
+	case migration.SourceOLMVersion == "v0" && migration.TargetOLMVersion == "v1":
+		return []migrationStep{
+			{name: migrationStepPreflight, run: r.migrationPreflight},
+			{name: migrationStepInstallerRBAC, run: r.migrationEnsureInstallerRBAC},
+			{name: migrationStepRemoveSubscription, run: r.migrationRemoveSubscription},
+			{name: migrationStepCreateCE, run: r.migrationCreateClusterExtension},
+			{name: migrationStepRemoveCSV, run: r.migrationRemoveCSV},
+			{name: migrationStepRemoveOG, run: r.migrationRemoveOperatorGroup},
+			{name: migrationStepReleaseVersionPin, run: r.migrationReleaseVersionPin},
+		}
+
+	case migration.SourceOLMVersion == "v0" && migration.TargetOLMVersion == "v0":
+		return []migrationStep{
+			{name: migrationStepRemoveCE, run: r.migrationRemoveClusterExtension},
+			{name: migrationStepRestoreSubscription, run: r.migrationRestoreSubscription},
+			{name: migrationStepRemoveInstallerRBAC, run: r.migrationRemoveInstallerRBAC},
+		}
+
+	case migration.SourceOLMVersion == "v1" && migration.TargetOLMVersion == "v0":
+		return []migrationStep{
+			{name: migrationStepPreflight, run: func(context.Context, *operatorv1.MultiClusterHub,
+				*operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+				return false, "", migrationBlocked("moving an MCE ClusterExtension back to an OLM v0 Subscription " +
+					"is not supported because deleting the ClusterExtension uninstalls the MCE CRDs and every " +
+					"MultiClusterEngine resource")
+			}},
+		}
+	}
+
+	// Nothing was changed on the cluster, so there is nothing to undo
+	return nil
+}
+
+// migrationPreflight confirms OLM v1 is available and records the MCE version installed through OLM v0
+func (r *MultiClusterHubReconciler) migrationPreflight(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	if err := r.Client.List(ctx, &ocv1.ClusterExtensionList{}, client.Limit(1)); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			return false, "", migrationBlocked("the ClusterExtension API is not available; OLM v1 must be " +
+				"installed before MCE can be migrated")
+		}
+		return false, "", err
+	}
+
+	sub, err := v0.GetManagedMCESubscription(ctx, r.Client)
+	if err != nil {
+		return false, "", err
+	}
+	if sub == nil {
+		return false, "", migrationBlocked("no MCE Subscription was found to migrate")
+	}
+	if !v0.CreatedByMCH(sub, m) {
+		return false, "", migrationBlocked("MCE Subscription %s/%s was not created by this MultiClusterHub and "+
+			"must be migrated manually", sub.GetNamespace(), sub.GetName())
+	}
+
+	csv, err := r.GetCSVFromSubscription(sub)
+	if err != nil {
+		return false, fmt.Sprintf("Waiting for Subscription %s/%s to report its CSV: %s",
+			sub.GetNamespace(), sub.GetName(), err.Error()), nil
+	}
+	phase, _, _ := unstructured.NestedString(csv.Object, "status", "phase")
+	if phase != string(subv1alpha1.CSVPhaseSucceeded) {
+		return false, fmt.Sprintf("Waiting for CSV %s to succeed before migrating (phase: %s)",
+			csv.GetName(), phase), nil
+	}
+
+	csvVersion, _, _ := unstructured.NestedString(csv.Object, "spec", "version")
+	migration.PreviousCSV = csv.GetName()
+	migration.PinnedVersion = csvVersion
+
+	return true, fmt.Sprintf("MCE %s installed by Subscription %s/%s is ready to migrate",
+		csvVersion, sub.GetNamespace(), sub.GetName()), nil
+}
+
+// migrationEnsureInstallerRBAC creates the ServiceAccount and ClusterRoleBinding OLM v1 installs MCE with
+func (r *MultiClusterHubReconciler) migrationEnsureInstallerRBAC(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	operandNs := multiclusterengine.OperandNamespace()
+
+	if result, err := r.ensureServiceAccount(m, v1.ServiceAccount(operandNs)); result != (ctrl.Result{}) || err != nil {
+		return false, fmt.Sprintf("Waiting for ServiceAccount %s/%s", operandNs, v1.MCEInstallerServiceAccountName), err
+	}
+	if result, err := r.ensureClusterRoleBinding(m, v1.ClusterRoleBinding(operandNs)); result != (ctrl.Result{}) ||
+		err != nil {
+		return false, fmt.Sprintf("Waiting for ClusterRoleBinding %s", v1.MCEInstallerClusterRoleBindingName), err
+	}
+
+	return true, fmt.Sprintf("ServiceAccount %s/%s is bound to %s", operandNs, v1.MCEInstallerServiceAccountName,
+		v1.MCEInstallerClusterRoleBindingName), nil
+}
+
+// migrationRemoveSubscription deletes the MCE Subscription so OLM v0 stops resolving upgrades. The CSV keeps running.
+func (r *MultiClusterHubReconciler) migrationRemoveSubscription(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	sub, err := v0.GetManagedMCESubscription(ctx, r.Client)
+	if err != nil {
+		return false, "", err
+	}
+	if sub == nil {
+		return true, "MCE Subscription removed", nil
+	}
+
+	if err := r.Client.Delete(ctx, sub); err != nil && !errors.IsNotFound(err) {
+		return false, "", err
+	}
+	return false, fmt.Sprintf("Waiting for Subscription %s/%s to be removed", sub.GetNamespace(), sub.GetName()), nil
+}
+
+/*
+migrationRemoveCSV deletes the MCE ClusterServiceVersion once the ClusterExtension has installed MCE. OLM v0 removes
+the previous MCE operator deployment with it but leaves the MCE CRDs and custom resources in place, which are owned by
+the ClusterExtension at this point.
+*/
+func (r *MultiClusterHubReconciler) migrationRemoveCSV(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	if migration.PreviousCSV == "" {
+		return true, "No MCE CSV recorded", nil
+	}
+
+	csv := &subv1alpha1.ClusterServiceVersion{}
+	err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      migration.PreviousCSV,
+		Namespace: multiclusterengine.OperandNamespace(),
+	}, csv)
+	if errors.IsNotFound(err) {
+		return true, fmt.Sprintf("Removed CSV %s; MCE CRDs and MultiClusterEngine resources were retained",
+			migration.PreviousCSV), nil
+	} else if err != nil {
+		return false, "", err
+	}
+
+	if err := r.Client.Delete(ctx, csv); err != nil && !errors.IsNotFound(err) {
+		return false, "", err
+	}
+	return false, fmt.Sprintf("Waiting for CSV %s to be removed", migration.PreviousCSV), nil
+}
+
+/*
+migrationCreateClusterExtension creates the MCE ClusterExtension pinned to the version that OLM v0 had installed and
+waits for OLM v1 to report the bundle installed. The MCE CSV keeps running until then, so a ClusterExtension that OLM
+v1 refuses to install, for example because it cannot take ownership of the existing MCE CRDs, blocks the migration
+without taking MCE down and can still be rolled back.
+*/
+func (r *MultiClusterHubReconciler) migrationCreateClusterExtension(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	ce, err := v1.FindAndManageMCEClusterExtension(ctx, r.Client, multiclusterengine.DesiredPackage())
+	if err != nil {
+		return false, "", err
+	}
+
+	if ce == nil {
+		overrides, err := v1.GetAnnotationOverrides(m)
+		if err != nil {
+			return false, "", err
+		}
+
+		ce = v1.NewClusterExtension(m)
+		v1.ApplyAnnotationOverrides(ce, overrides)
+		if migration.PinnedVersion != "" && ce.Spec.Source.Catalog != nil && ce.Spec.Source.Catalog.Version == "" {
+			ce.Spec.Source.Catalog.Version = migration.PinnedVersion
+		}
+
+		r.Log.Info("Creating MCE ClusterExtension for OLM migration", "name", ce.GetName(),
+			"version", migration.PinnedVersion)
+		if err := r.Client.Create(ctx, ce); err != nil {
+			return false, "", fmt.Errorf("error creating ClusterExtension %s: %w", ce.GetName(), err)
+		}
+		return false, fmt.Sprintf("Created ClusterExtension %s", ce.GetName()), nil
+	}
+
+	if !apimeta.IsStatusConditionTrue(ce.Status.Conditions, ocv1.TypeInstalled) {
+		c := apimeta.FindStatusCondition(ce.Status.Conditions, ocv1.TypeProgressing)
+		if c != nil && c.Reason == ocv1.ReasonBlocked {
+			return false, "", migrationBlocked("ClusterExtension %s cannot install MCE: %s; the MCE CSV %s is kept "+
+				"running, request OLM version v0 to roll the migration back", ce.GetName(), c.Message,
+				migration.PreviousCSV)
+		}
+		message := fmt.Sprintf("Waiting for ClusterExtension %s to install MCE", ce.GetName())
+		if c != nil && c.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, c.Message)
+		}
+		return false, message, nil
+	}
+
+	return true, fmt.Sprintf("ClusterExtension %s owns the MCE installation", ce.GetName()), nil
+}
+
+// migrationRemoveOperatorGroup deletes the OLM v0 OperatorGroup unless other Subscriptions in the namespace use it
+func (r *MultiClusterHubReconciler) migrationRemoveOperatorGroup(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	operandNs := multiclusterengine.OperandNamespace()
+
+	subList := &subv1alpha1.SubscriptionList{}
+	if err := r.Client.List(ctx, subList, client.InNamespace(operandNs)); err != nil {
+		return false, "", err
+	}
+	if len(subList.Items) > 0 {
+		return true, fmt.Sprintf("OperatorGroup in %s retained because other Subscriptions use it", operandNs), nil
+	}
+
+	if err := r.Client.Delete(ctx, v0.OperatorGroup(operandNs)); err != nil && !errors.IsNotFound(err) {
+		return false, "", err
+	}
+	return true, fmt.Sprintf("OperatorGroup in %s removed", operandNs), nil
+}
+
+// migrationReleaseVersionPin lets the ClusterExtension follow its channel again once the handover is complete
+func (r *MultiClusterHubReconciler) migrationReleaseVersionPin(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	ce, err := v1.GetManagedMCEClusterExtension(ctx, r.Client)
+	if err != nil {
+		return false, "", err
+	}
+	if ce == nil {
+		return false, "", fmt.Errorf("MCE ClusterExtension not found")
+	}
+
+	overrides, err := v1.GetAnnotationOverrides(m)
+	if err != nil {
+		return false, "", err
+	}
+	if overrides != nil && overrides.Version != "" {
+		return true, fmt.Sprintf("Version constraint %s from annotation %s retained", overrides.Version,
+			utils.AnnotationMCEClusterExtensionSpec), nil
+	}
+
+	if ce.Spec.Source.Catalog != nil && ce.Spec.Source.Catalog.Version != "" &&
+		ce.Spec.Source.Catalog.Version == migration.PinnedVersion {
+		ce.Spec.Source.Catalog.Version = ""
+		if err := r.Client.Update(ctx, ce); err != nil {
+			return false, "", err
+		}
+	}
+	return true, fmt.Sprintf("ClusterExtension %s follows channel %s", ce.GetName(),
+		multiclusterengine.DesiredChannel()), nil
+}
+
+// migrationRemoveClusterExtension deletes a ClusterExtension created by the migration, provided OLM v1 has not yet
+// taken ownership of the MCE CRDs
+func (r *MultiClusterHubReconciler) migrationRemoveClusterExtension(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	ce, err := v1.GetManagedMCEClusterExtension(ctx, r.Client)
+	if err != nil && !apimeta.IsNoMatchError(err) {
+		return false, "", err
+	}
+	if ce == nil {
+		return true, "No MCE ClusterExtension to remove", nil
+	}
+	if !v1.CreatedByMCH(ce, m) {
+		return false, "", migrationBlocked("ClusterExtension %s was not created by this MultiClusterHub", ce.GetName())
+	}
+	if ce.GetDeletionTimestamp() == nil && apimeta.IsStatusConditionTrue(ce.Status.Conditions, ocv1.TypeInstalled) {
+		return false, "", migrationBlocked("ClusterExtension %s already owns the MCE installation; removing it "+
+			"would uninstall the MCE CRDs and every MultiClusterEngine resource", ce.GetName())
+	}
+
+	if err := r.Client.Delete(ctx, ce); err != nil && !errors.IsNotFound(err) {
+		return false, "", err
+	}
+	return false, fmt.Sprintf("Waiting for ClusterExtension %s to be removed", ce.GetName()), nil
+}
+
+// migrationRestoreSubscription recreates the MCE Subscription, pinned to the CSV that was installed before the
+// migration started, and waits for the CSV to succeed
+func (r *MultiClusterHubReconciler) migrationRestoreSubscription(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	operandNs := multiclusterengine.OperandNamespace()
+	if result, err := r.ensureOperatorGroup(m, v0.OperatorGroup(operandNs)); result != (ctrl.Result{}) || err != nil {
+		return false, fmt.Sprintf("Waiting for OperatorGroup in %s", operandNs), err
+	}
+
+	sub, err := v0.GetManagedMCESubscription(ctx, r.Client)
+	if err != nil {
+		return false, "", err
+	}
+
+	if sub == nil {
+		subConfig, err := r.GetSubConfig()
+		if err != nil {
+			return false, "", err
+		}
+		overrides, err := v0.GetAnnotationOverrides(m)
+		if err != nil {
+			return false, "", err
+		}
+		if overrides == nil {
+			overrides = &subv1alpha1.SubscriptionSpec{}
+		}
+		if overrides.StartingCSV == "" {
+			overrides.StartingCSV = migration.PreviousCSV
+		}
+
+		ctlSrc := types.NamespacedName{}
+		if overrides.CatalogSource == "" {
+			ctlSrc, err = v0.GetCatalogSource(r.Client, multiclusterengine.DesiredChannel(),
+				multiclusterengine.DesiredPackage())
+			if err != nil {
+				return false, "", err
+			}
+		}
+
+		sub = v0.RenderSubscription(v0.NewSubscription(m, subConfig, overrides), subConfig, overrides, ctlSrc)
+		if err := r.Client.Create(ctx, sub); err != nil {
+			return false, "", fmt.Errorf("error creating subscription %s: %w", sub.GetName(), err)
+		}
+		return false, fmt.Sprintf("Created Subscription %s/%s", sub.GetNamespace(), sub.GetName()), nil
+	}
+
+	csv, err := r.GetCSVFromSubscription(sub)
+	if err != nil {
+		return false, fmt.Sprintf("Waiting for Subscription %s/%s to install a CSV", sub.GetNamespace(),
+			sub.GetName()), nil
+	}
+	phase, _, _ := unstructured.NestedString(csv.Object, "status", "phase")
+	if phase != string(subv1alpha1.CSVPhaseSucceeded) {
+		return false, fmt.Sprintf("Waiting for CSV %s to succeed (phase: %s)", csv.GetName(), phase), nil
+	}
+
+	return true, fmt.Sprintf("Subscription %s/%s restored with CSV %s", sub.GetNamespace(), sub.GetName(),
+		csv.GetName()), nil
+}
+
+// migrationRemoveInstallerRBAC deletes the OLM v1 installer ServiceAccount and ClusterRoleBinding
+func (r *MultiClusterHubReconciler) migrationRemoveInstallerRBAC(ctx context.Context, m *operatorv1.MultiClusterHub,
+	migration *operatorv1.MCEOLMMigrationStatus) (bool, string, error) {
+	operandNs := multiclusterengine.OperandNamespace()
+
+	if err := r.Client.Delete(ctx, v1.ClusterRoleBinding(operandNs)); err != nil && !errors.IsNotFound(err) {
+		return false, "", err
+	}
+	if err := r.Client.Delete(ctx, v1.ServiceAccount(operandNs)); err != nil && !errors.IsNotFound(err) {
+		return false, "", err
+	}
+	return true, "OLM v1 installer ServiceAccount and ClusterRoleBinding removed", nil
+}
+
+// getMigrationStep returns the named step from the migration status, or nil
+func getMigrationStep(migration *operatorv1.MCEOLMMigrationStatus, name string) *operatorv1.OLMMigrationStep {
+	for i := range migration.Steps {
+		if migration.Steps[i].Name == name {
+			return &migration.Steps[i]
+		}
+	}
+	return nil
+}
+
+// setMigrationStep records the outcome of a step, keeping its transition time when the status is unchanged
+func setMigrationStep(migration *operatorv1.MCEOLMMigrationStatus, name string, status metav1.ConditionStatus,
+	message string) {
+	if s := getMigrationStep(migration, name); s != nil {
+		if s.Status != status {
+			s.LastTransitionTime = metav1.Now()
+		}
+		s.Status = status
+		s.Message = message
+		return
+	}
+
+	migration.Steps = append(migration.Steps, operatorv1.OLMMigrationStep{
+		Name:               name,
+		Status:             status,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
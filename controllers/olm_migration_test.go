@@ -0,0 +1,404 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
+	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	operatorsv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	v1 "github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine/olm/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengineutils"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const migrationTestCSV = "multicluster-engine.v2.10.0"
+
+func migrationTestHub(annotations map[string]string) *operatorsv1.MultiClusterHub {
+	return &operatorsv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "multiclusterhub",
+			Namespace:   "open-cluster-management",
+			Annotations: annotations,
+		},
+	}
+}
+
+func migrationTestLabels() map[string]string {
+	return map[string]string{
+		"installer.name":                          "multiclusterhub",
+		"installer.namespace":                     "open-cluster-management",
+		multiclusterengineutils.MCEManagedByLabel: "true",
+	}
+}
+
+func migrationTestSubscription() *subv1alpha1.Subscription {
+	return &subv1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.MCESubscriptionName,
+			Namespace: "multicluster-engine",
+			Labels:    migrationTestLabels(),
+		},
+		Spec: &subv1alpha1.SubscriptionSpec{
+			Channel: "stable-2.10",
+			Package: "multicluster-engine",
+		},
+		Status: subv1alpha1.SubscriptionStatus{CurrentCSV: migrationTestCSV},
+	}
+}
+
+func migrationTestCSVObject() *subv1alpha1.ClusterServiceVersion {
+	csv := &subv1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      migrationTestCSV,
+			Namespace: "multicluster-engine",
+		},
+		Status: subv1alpha1.ClusterServiceVersionStatus{Phase: subv1alpha1.CSVPhaseSucceeded},
+	}
+	_ = csv.Spec.Version.Set("2.10.0")
+	return csv
+}
+
+func migrationTestReconciler(t *testing.T, olmVersion string, objs ...client.Object) *MultiClusterHubReconciler {
+	t.Helper()
+	s := scheme.Scheme
+	_ = operatorsv1.AddToScheme(s)
+	_ = subv1alpha1.AddToScheme(s)
+	_ = olmv1.AddToScheme(s)
+	_ = ocv1.AddToScheme(s)
+
+	return &MultiClusterHubReconciler{
+		Client:     fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+		Scheme:     s,
+		Log:        clog.Log.WithName("test"),
+		OLMVersion: olmVersion,
+	}
+}
+
+// runMigration reconciles the migration until it stops making progress on its own
+func runMigration(t *testing.T, r *MultiClusterHubReconciler, m *operatorsv1.MultiClusterHub) (ctrl.Result, error) {
+	t.Helper()
+	last := operatorsv1.OLMMigrationStep{}
+	for i := 0; i < 20; i++ {
+		result, err := r.ensureMCEOLMMigration(context.TODO(), m)
+		if err != nil || result.RequeueAfter != migrationPollInterval {
+			return result, err
+		}
+		steps := m.Status.MCEOLMMigration.Steps
+		current := steps[len(steps)-1]
+		if current.Name == last.Name && current.Message == last.Message {
+			return result, err
+		}
+		last = current
+	}
+	t.Fatalf("migration did not settle")
+	return ctrl.Result{}, nil
+}
+
+func setClusterExtensionInstalled(t *testing.T, r *MultiClusterHubReconciler) {
+	t.Helper()
+	ce, err := v1.GetManagedMCEClusterExtension(context.TODO(), r.Client)
+	if err != nil || ce == nil {
+		t.Fatalf("expected managed ClusterExtension, got %v (err %v)", ce, err)
+	}
+	apimeta.SetStatusCondition(&ce.Status.Conditions, metav1.Condition{
+		Type:   ocv1.TypeInstalled,
+		Status: metav1.ConditionTrue,
+		Reason: "Succeeded",
+	})
+	if err := r.Client.Update(context.TODO(), ce); err != nil {
+		t.Fatalf("failed to update ClusterExtension status: %v", err)
+	}
+}
+
+func Test_mceOLMVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		olmVersion  string
+		annotations map[string]string
+		want        string
+	}{
+		{name: "detected version", olmVersion: "v0", want: "v0"},
+		{name: "annotation wins", olmVersion: "v0",
+			annotations: map[string]string{utils.AnnotationMCEOLMVersion: "v1"}, want: "v1"},
+		{name: "invalid annotation ignored", olmVersion: "v1",
+			annotations: map[string]string{utils.AnnotationMCEOLMVersion: "v2"}, want: "v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MultiClusterHubReconciler{OLMVersion: tt.olmVersion}
+			if got := r.mceOLMVersion(migrationTestHub(tt.annotations)); got != tt.want {
+				t.Errorf("mceOLMVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ensureMCEOLMMigration_NoChange(t *testing.T) {
+	r := migrationTestReconciler(t, "v0", migrationTestSubscription(), migrationTestCSVObject())
+	m := migrationTestHub(nil)
+
+	result, err := r.ensureMCEOLMMigration(context.TODO(), m)
+	if err != nil || result != (ctrl.Result{}) {
+		t.Fatalf("ensureMCEOLMMigration() = %v, %v; want empty result", result, err)
+	}
+	if m.Status.MCEOLMMigration != nil {
+		t.Errorf("expected no migration status, got %+v", m.Status.MCEOLMMigration)
+	}
+}
+
+func Test_ensureMCEOLMMigration_PendingWithoutOptIn(t *testing.T) {
+	r := migrationTestReconciler(t, "v1", migrationTestSubscription(), migrationTestCSVObject())
+	m := migrationTestHub(nil)
+
+	result, err := r.ensureMCEOLMMigration(context.TODO(), m)
+	if err != nil || result != (ctrl.Result{}) {
+		t.Fatalf("ensureMCEOLMMigration() = %v, %v; want empty result", result, err)
+	}
+	migration := m.Status.MCEOLMMigration
+	if migration == nil || migration.Phase != operatorsv1.OLMMigrationPending || migration.SourceOLMVersion != "v0" ||
+		migration.TargetOLMVersion != "v1" {
+		t.Fatalf("expected a pending migration from v0 to v1, got %+v", migration)
+	}
+	if r.mceOLMVersion(m) != "v0" {
+		t.Errorf("expected MCE to be managed through OLM v0 while the migration is pending")
+	}
+
+	// Nothing is removed until the OLM version is requested
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: utils.MCESubscriptionName,
+		Namespace: "multicluster-engine"}, &subv1alpha1.Subscription{})
+	if err != nil {
+		t.Errorf("expected Subscription to be kept, got %v", err)
+	}
+	if ce, _ := v1.GetManagedMCEClusterExtension(context.TODO(), r.Client); ce != nil {
+		t.Errorf("expected no ClusterExtension, got %s", ce.GetName())
+	}
+
+	m.Annotations = map[string]string{utils.AnnotationMCEOLMVersion: "v0"}
+	if _, err := r.ensureMCEOLMMigration(context.TODO(), m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Status.MCEOLMMigration != nil {
+		t.Errorf("expected the pending migration to be cleared, got %+v", m.Status.MCEOLMMigration)
+	}
+}
+
+func Test_ensureMCEOLMMigration_V0ToV1(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "open-cluster-management")
+	r := migrationTestReconciler(t, "v0", migrationTestSubscription(), migrationTestCSVObject(),
+		v0OperatorGroupForTest())
+	m := migrationTestHub(map[string]string{utils.AnnotationMCEOLMVersion: "v1"})
+
+	if _, err := runMigration(t, r, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	migration := m.Status.MCEOLMMigration
+	if migration == nil || migration.Phase != operatorsv1.OLMMigrationInProgress {
+		t.Fatalf("expected migration in progress, got %+v", migration)
+	}
+	if migration.PreviousCSV != migrationTestCSV || migration.PinnedVersion != "2.10.0" {
+		t.Errorf("expected CSV %s at 2.10.0 recorded, got %s at %s", migrationTestCSV, migration.PreviousCSV,
+			migration.PinnedVersion)
+	}
+
+	// The Subscription is gone, the CSV keeps running, and the ClusterExtension is pinned to the installed version
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: utils.MCESubscriptionName,
+		Namespace: "multicluster-engine"}, &subv1alpha1.Subscription{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected Subscription to be removed, got %v", err)
+	}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: migrationTestCSV,
+		Namespace: "multicluster-engine"}, &subv1alpha1.ClusterServiceVersion{})
+	if err != nil {
+		t.Errorf("expected CSV to be kept until the ClusterExtension is installed, got %v", err)
+	}
+	ce, _ := v1.GetManagedMCEClusterExtension(context.TODO(), r.Client)
+	if ce == nil || ce.Spec.Source.Catalog.Version != "2.10.0" {
+		t.Fatalf("expected ClusterExtension pinned to 2.10.0, got %+v", ce)
+	}
+
+	setClusterExtensionInstalled(t, r)
+	if _, err := runMigration(t, r, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if migration.Phase != operatorsv1.OLMMigrationCompleted {
+		t.Fatalf("expected migration completed, got %s: %+v", migration.Phase, migration.Steps)
+	}
+	for _, step := range migration.Steps {
+		if step.Status != metav1.ConditionTrue {
+			t.Errorf("expected step %s to be complete, got %s", step.Name, step.Status)
+		}
+	}
+
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: migrationTestCSV,
+		Namespace: "multicluster-engine"}, &subv1alpha1.ClusterServiceVersion{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected CSV to be removed, got %v", err)
+	}
+	ce, _ = v1.GetManagedMCEClusterExtension(context.TODO(), r.Client)
+	if ce.Spec.Source.Catalog.Version != "" {
+		t.Errorf("expected version pin to be released, got %s", ce.Spec.Source.Catalog.Version)
+	}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: "default", Namespace: "multicluster-engine"},
+		&olmv1.OperatorGroup{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected OperatorGroup to be removed, got %v", err)
+	}
+	if r.mceOLMVersion(m) != "v1" {
+		t.Errorf("expected MCE to be managed through OLM v1 after migration")
+	}
+}
+
+func Test_ensureMCEOLMMigration_ClusterExtensionBlocked(t *testing.T) {
+	r := migrationTestReconciler(t, "v0", migrationTestSubscription(), migrationTestCSVObject())
+	m := migrationTestHub(map[string]string{utils.AnnotationMCEOLMVersion: "v1"})
+
+	if _, err := runMigration(t, r, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ce, err := v1.GetManagedMCEClusterExtension(context.TODO(), r.Client)
+	if err != nil || ce == nil {
+		t.Fatalf("expected managed ClusterExtension, got %v (err %v)", ce, err)
+	}
+	apimeta.SetStatusCondition(&ce.Status.Conditions, metav1.Condition{
+		Type:    ocv1.TypeProgressing,
+		Status:  metav1.ConditionFalse,
+		Reason:  ocv1.ReasonBlocked,
+		Message: "CustomResourceDefinition multiclusterengines.multicluster.openshift.io exists and is not owned",
+	})
+	if err := r.Client.Update(context.TODO(), ce); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := r.ensureMCEOLMMigration(context.TODO(), m)
+	if err != nil || result.RequeueAfter != resyncPeriod {
+		t.Fatalf("ensureMCEOLMMigration() = %v, %v; want requeue after %s", result, err, resyncPeriod)
+	}
+	if m.Status.MCEOLMMigration.Phase != operatorsv1.OLMMigrationBlocked {
+		t.Errorf("expected migration blocked, got %s", m.Status.MCEOLMMigration.Phase)
+	}
+
+	// MCE keeps running on its CSV
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: migrationTestCSV,
+		Namespace: "multicluster-engine"}, &subv1alpha1.ClusterServiceVersion{})
+	if err != nil {
+		t.Errorf("expected CSV to be kept, got %v", err)
+	}
+}
+
+func Test_ensureMCEOLMMigration_Rollback(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "open-cluster-management")
+	operatorDeploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.MCHOperatorName, Namespace: "open-cluster-management"},
+	}
+	r := migrationTestReconciler(t, "v0", migrationTestSubscription(), migrationTestCSVObject(),
+		v0OperatorGroupForTest(), operatorDeploy)
+	m := migrationTestHub(map[string]string{utils.AnnotationMCEOLMVersion: "v1"})
+
+	if _, err := runMigration(t, r, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// ClusterExtension has not installed yet, so the migration can still be rolled back
+	m.Annotations[utils.AnnotationMCEOLMVersion] = "v0"
+	m.Annotations[utils.AnnotationMCESubscriptionSpec] = `{"source":"redhat-operators","sourceNamespace":"openshift-marketplace"}`
+	if _, err := runMigration(t, r, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ce, _ := v1.GetManagedMCEClusterExtension(context.TODO(), r.Client)
+	if ce != nil {
+		t.Errorf("expected ClusterExtension to be removed, got %s", ce.GetName())
+	}
+	sub := &subv1alpha1.Subscription{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: utils.MCESubscriptionName,
+		Namespace: "multicluster-engine"}, sub); err != nil {
+		t.Fatalf("expected Subscription to be restored: %v", err)
+	}
+	if sub.Spec.StartingCSV != migrationTestCSV {
+		t.Errorf("expected restored Subscription to start at %s, got %s", migrationTestCSV, sub.Spec.StartingCSV)
+	}
+
+	// OLM reinstalls the CSV
+	sub.Status.CurrentCSV = migrationTestCSV
+	if err := r.Client.Update(context.TODO(), sub); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Create(context.TODO(), migrationTestCSVObject()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runMigration(t, r, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Status.MCEOLMMigration.Phase != operatorsv1.OLMMigrationRolledBack {
+		t.Fatalf("expected migration rolled back, got %s: %+v", m.Status.MCEOLMMigration.Phase,
+			m.Status.MCEOLMMigration.Steps)
+	}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: v1.MCEInstallerServiceAccountName,
+		Namespace: "multicluster-engine"}, &corev1.ServiceAccount{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected installer ServiceAccount to be removed, got %v", err)
+	}
+}
+
+func Test_ensureMCEOLMMigration_RollbackBlockedOnceInstalled(t *testing.T) {
+	r := migrationTestReconciler(t, "v0", migrationTestSubscription(), migrationTestCSVObject())
+	m := migrationTestHub(map[string]string{utils.AnnotationMCEOLMVersion: "v1"})
+
+	if _, err := runMigration(t, r, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	setClusterExtensionInstalled(t, r)
+
+	m.Annotations[utils.AnnotationMCEOLMVersion] = "v0"
+	result, err := r.ensureMCEOLMMigration(context.TODO(), m)
+	if err != nil || result.RequeueAfter != resyncPeriod {
+		t.Fatalf("ensureMCEOLMMigration() = %v, %v; want requeue after %s", result, err, resyncPeriod)
+	}
+	if m.Status.MCEOLMMigration.Phase != operatorsv1.OLMMigrationBlocked {
+		t.Errorf("expected migration blocked, got %s", m.Status.MCEOLMMigration.Phase)
+	}
+	if c := GetHubCondition(m.Status, operatorsv1.Blocked); c == nil || c.Reason != MCEOLMMigrationBlockedReason {
+		t.Errorf("expected Blocked condition with reason %s, got %+v", MCEOLMMigrationBlockedReason, c)
+	}
+	if ce, _ := v1.GetManagedMCEClusterExtension(context.TODO(), r.Client); ce == nil {
+		t.Errorf("expected installed ClusterExtension to be kept")
+	}
+}
+
+func Test_ensureMCEOLMMigration_AdoptedSubscription(t *testing.T) {
+	sub := migrationTestSubscription()
+	sub.Labels = map[string]string{multiclusterengineutils.MCEManagedByLabel: "true"}
+	r := migrationTestReconciler(t, "v0", sub, migrationTestCSVObject())
+	m := migrationTestHub(map[string]string{utils.AnnotationMCEOLMVersion: "v1"})
+
+	// Subscription was not created by this hub, so no migration is started
+	result, err := r.ensureMCEOLMMigration(context.TODO(), m)
+	if err != nil || result != (ctrl.Result{}) {
+		t.Fatalf("ensureMCEOLMMigration() = %v, %v; want empty result", result, err)
+	}
+	if m.Status.MCEOLMMigration != nil {
+		t.Errorf("expected no migration for an adopted Subscription, got %+v", m.Status.MCEOLMMigration)
+	}
+}
+
+func v0OperatorGroupForTest() *olmv1.OperatorGroup {
+	return &olmv1.OperatorGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "multicluster-engine"},
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/overrides"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// overridesHistoryName is the ConfigMap in the hub namespace that keeps the content of the override ConfigMaps
+	// in use and the content they replaced
+	overridesHistoryName = "multiclusterhub-overrides-history"
+
+	// historyConfigMapKey is the key holding the content of the history ConfigMaps
+	historyConfigMapKey = "history.json"
+
+	imageOverridesSource    = "image-overrides"
+	templateOverridesSource = "template-overrides"
+)
+
+// overridesContent is a revision of the content of an override ConfigMap
+type overridesContent struct {
+	Revision string `json:"revision"`
+	Key      string `json:"key"`
+	Data     string `json:"data"`
+}
+
+// overridesSourceHistory is the content in use and the previous content of an override ConfigMap
+type overridesSourceHistory struct {
+	ConfigMap string            `json:"configMap"`
+	Live      *overridesContent `json:"live,omitempty"`
+	Previous  *overridesContent `json:"previous,omitempty"`
+}
+
+// overridesHistory is stored in the overrides history ConfigMap
+type overridesHistory struct {
+	LastRollback string                             `json:"lastRollback,omitempty"`
+	Sources      map[string]*overridesSourceHistory `json:"sources,omitempty"`
+}
+
+// overridesConfigMapName returns the name of the override ConfigMap of a source, or an empty string if none is set
+func overridesConfigMapName(m *operatorv1.MultiClusterHub, source string) string {
+	if source == imageOverridesSource {
+		return utils.GetImageOverridesConfigmapName(m)
+	}
+	return utils.GetTemplateOverridesConfigmapName(m)
+}
+
+// overrideRevisionsStatus returns the override revisions status of the hub, creating it if needed
+func overrideRevisionsStatus(m *operatorv1.MultiClusterHub) *operatorv1.OverrideRevisionsStatus {
+	if m.Status.OverrideRevisions == nil {
+		m.Status.OverrideRevisions = &operatorv1.OverrideRevisionsStatus{}
+	}
+	return m.Status.OverrideRevisions
+}
+
+// overrideRevisionStatus returns the status of an override source, creating it if needed
+func overrideRevisionStatus(m *operatorv1.MultiClusterHub, source string) *operatorv1.OverrideRevision {
+	revisions := overrideRevisionsStatus(m)
+	status := &revisions.TemplateOverrides
+	if source == imageOverridesSource {
+		status = &revisions.ImageOverrides
+	}
+	if *status == nil {
+		*status = &operatorv1.OverrideRevision{}
+	}
+	return *status
+}
+
+// overridesRevision identifies the content of an override ConfigMap
+func overridesRevision(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// readOverridesHistory returns the overrides history ConfigMap of the hub and its content
+func (r *MultiClusterHubReconciler) readOverridesHistory(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	*corev1.ConfigMap, *overridesHistory, error) {
+	history := &overridesHistory{}
+	cm, err := r.readHistoryConfigMap(ctx, m, overridesHistoryName, history)
+	if err != nil {
+		return nil, nil, err
+	}
+	if history.Sources == nil {
+		history.Sources = map[string]*overridesSourceHistory{}
+	}
+	return cm, history, nil
+}
+
+// readHistoryConfigMap returns a history ConfigMap of the hub, or a new one if it does not exist, and parses its
+// content into history
+func (r *MultiClusterHubReconciler) readHistoryConfigMap(ctx context.Context, m *operatorv1.MultiClusterHub,
+	name string, history interface{}) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: m.GetNamespace()}, cm)
+	if errors.IsNotFound(err) {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: m.GetNamespace(),
+				Labels:    map[string]string{"installer.name": m.GetName(), "installer.namespace": m.GetNamespace()},
+			},
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if data := cm.Data[historyConfigMapKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), history); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	}
+	return cm, nil
+}
+
+// writeHistoryConfigMap creates or updates a history ConfigMap of the hub
+func (r *MultiClusterHubReconciler) writeHistoryConfigMap(ctx context.Context, m *operatorv1.MultiClusterHub,
+	cm *corev1.ConfigMap, history interface{}) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	cm.Data = map[string]string{historyConfigMapKey: string(data)}
+
+	if cm.GetResourceVersion() != "" {
+		return r.Client.Update(ctx, cm)
+	}
+	if err := ctrl.SetControllerReference(m, cm, r.Scheme); err != nil {
+		return err
+	}
+	return r.Client.Create(ctx, cm)
+}
+
+/*
+loadOverridesConfigMap parses the image or template override ConfigMap of the hub into the overrides. A ConfigMap whose
+content cannot be parsed, for example because of an invalid image reference, is not applied: the content applied last
+is used instead and the error is reported in the hub status. The error is only returned when no content was applied
+before. New content is recorded in the overrides history so it can be rolled back.
+*/
+func (r *MultiClusterHubReconciler) loadOverridesConfigMap(ctx context.Context, m *operatorv1.MultiClusterHub,
+	source, name string, base map[string]string) (map[string]string, error) {
+	isTemplate := source == templateOverridesSource
+	log.Info(fmt.Sprintf("Overriding %s from configmap: %s/%s", source, m.GetNamespace(), name))
+
+	configmap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: m.GetNamespace()}, configmap); err != nil {
+		return base, err
+	}
+
+	historyCM, history, err := r.readOverridesHistory(ctx, m)
+	if err != nil {
+		return base, err
+	}
+	sourceHistory := history.Sources[source]
+	if sourceHistory == nil || sourceHistory.ConfigMap != name {
+		sourceHistory = &overridesSourceHistory{ConfigMap: name}
+		history.Sources[source] = sourceHistory
+	}
+
+	status := overrideRevisionStatus(m, source)
+	if status.ConfigMap != name {
+		*status = operatorv1.OverrideRevision{ConfigMap: name}
+	}
+	status.ResourceVersion = configmap.GetResourceVersion()
+
+	key, data, err := overrides.GetOverridesData(configmap)
+	var parsed map[string]string
+	if err == nil {
+		parsed, err = overrides.ParseOverrides(copyOverrides(base), data, isTemplate)
+	}
+	if err != nil {
+		status.Message = fmt.Sprintf("ConfigMap content is not applied: %s", err)
+		if sourceHistory.Live == nil {
+			return base, err
+		}
+		log.Error(err, "Invalid override configmap, keeping the revision in use", "ConfigMap", name,
+			"Revision", sourceHistory.Live.Revision)
+		return overrides.ParseOverrides(copyOverrides(base), sourceHistory.Live.Data, isTemplate)
+	}
+
+	status.Message = ""
+	revision := overridesRevision(data)
+	if sourceHistory.Live == nil || sourceHistory.Live.Revision != revision {
+		log.Info("Applying new override configmap revision", "ConfigMap", name, "Revision", revision)
+		sourceHistory.Previous = sourceHistory.Live
+		sourceHistory.Live = &overridesContent{Revision: revision, Key: key, Data: data}
+		if err := r.writeHistoryConfigMap(ctx, m, historyCM, history); err != nil {
+			return base, err
+		}
+		status.AppliedTime = metav1.Now()
+	}
+	status.Revision = revision
+	status.PreviousRevision = ""
+	if sourceHistory.Previous != nil {
+		status.PreviousRevision = sourceHistory.Previous.Revision
+	}
+	return parsed, nil
+}
+
+/*
+rollbackOverrides restores the previous content of the override ConfigMaps when the overrides-rollback annotation has
+a value that was not acted on yet. A second rollback restores the content that was rolled back. The rollback rewrites
+the user's ConfigMaps: their data is replaced with the previous content, so keys other than the overrides key are
+dropped. It also clears the status of override ConfigMaps the hub no longer references.
+*/
+func (r *MultiClusterHubReconciler) rollbackOverrides(ctx context.Context, m *operatorv1.MultiClusterHub) error {
+	if s := m.Status.OverrideRevisions; s != nil {
+		if utils.GetImageOverridesConfigmapName(m) == "" {
+			s.ImageOverrides = nil
+		}
+		if utils.GetTemplateOverridesConfigmapName(m) == "" {
+			s.TemplateOverrides = nil
+		}
+	}
+
+	token := utils.GetOverridesRollback(m)
+	if token == "" {
+		return nil
+	}
+
+	historyCM, history, err := r.readOverridesHistory(ctx, m)
+	if err != nil {
+		return err
+	}
+	if history.LastRollback == token {
+		overrideRevisionsStatus(m).LastRollback = token
+		return nil
+	}
+
+	for _, source := range []string{imageOverridesSource, templateOverridesSource} {
+		name := overridesConfigMapName(m, source)
+		sourceHistory := history.Sources[source]
+		if name == "" || sourceHistory == nil || sourceHistory.ConfigMap != name || sourceHistory.Previous == nil {
+			continue
+		}
+
+		configmap := &corev1.ConfigMap{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: m.GetNamespace()},
+			configmap); err != nil {
+			return err
+		}
+		configmap.Data = map[string]string{sourceHistory.Previous.Key: sourceHistory.Previous.Data}
+		if err := r.Client.Update(ctx, configmap); err != nil {
+			return err
+		}
+		log.Info("Rolled back override configmap", "ConfigMap", name, "Revision", sourceHistory.Previous.Revision)
+	}
+
+	history.LastRollback = token
+	if err := r.writeHistoryConfigMap(ctx, m, historyCM, history); err != nil {
+		return err
+	}
+	overrideRevisionsStatus(m).LastRollback = token
+	return nil
+}
+
+// overridesConfigMapRequests maps an override ConfigMap to the hubs referencing it by name
+func (r *MultiClusterHubReconciler) overridesConfigMapRequests(ctx context.Context,
+	obj client.Object) []reconcile.Request {
+	hubs := &operatorv1.MultiClusterHubList{}
+	if err := r.Client.List(ctx, hubs, client.InNamespace(obj.GetNamespace())); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for i := range hubs.Items {
+		hub := &hubs.Items[i]
+		if overridesConfigMapName(hub, imageOverridesSource) == obj.GetName() ||
+			overridesConfigMapName(hub, templateOverridesSource) == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: hub.GetName(), Namespace: hub.GetNamespace()},
+			})
+		}
+	}
+	return requests
+}
+
+func copyOverrides(overrides map[string]string) map[string]string {
+	copied := make(map[string]string, len(overrides))
+	for k, v := range overrides {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,171 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	consoleImageV1  = `[{"image-key": "console", "image-remote": "quay.io/acm", "image-name": "console", "image-tag": "1"}]`
+	consoleImageV2  = `[{"image-key": "console", "image-remote": "quay.io/acm", "image-name": "console", "image-tag": "2"}]`
+	consoleImageBad = `[{"image-key": "console", "image-remote": "quay.io/ACM", "image-name": "console", "image-tag": "3"}]`
+)
+
+func overridesHub(annotations map[string]string) *operatorv1.MultiClusterHub {
+	return &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "multiclusterhub",
+			Namespace:   "open-cluster-management",
+			Annotations: annotations,
+		},
+	}
+}
+
+func setOverridesData(t *testing.T, r *MultiClusterHubReconciler, data string) {
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: "image-overrides", Namespace: "open-cluster-management"}
+	if err := r.Client.Get(context.Background(), key, cm); err != nil {
+		t.Fatal(err)
+	}
+	cm.Data = map[string]string{"overrides.json": data}
+	if err := r.Client.Update(context.Background(), cm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_loadOverridesConfigMap(t *testing.T) {
+	registerScheme()
+	hub := overridesHub(map[string]string{utils.AnnotationImageOverridesCM: "image-overrides"})
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(hub, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "image-overrides", Namespace: hub.Namespace},
+			Data:       map[string]string{"overrides.json": consoleImageV1},
+		}).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+	base := map[string]string{"console": "quay.io/stolostron/console:latest"}
+
+	got, err := r.loadOverridesConfigMap(context.Background(), hub, imageOverridesSource, "image-overrides", base)
+	if err != nil || got["console"] != "quay.io/acm/console:1" {
+		t.Fatalf("loadOverridesConfigMap() = %v, %v, want the configmap image", got, err)
+	}
+	if base["console"] != "quay.io/stolostron/console:latest" {
+		t.Error("expected the base overrides to be left unchanged")
+	}
+	first := *hub.Status.OverrideRevisions.ImageOverrides
+	if first.Revision == "" || first.PreviousRevision != "" || first.Message != "" || first.ConfigMap != "image-overrides" {
+		t.Errorf("ImageOverrides = %+v, want the first revision in use", first)
+	}
+
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: "image-overrides", Namespace: hub.Namespace}
+	if err := r.Client.Get(context.Background(), key, cm); err != nil {
+		t.Fatal(err)
+	}
+	if len(cm.GetLabels()) != 0 {
+		t.Errorf("expected the override configmap to be left unlabeled, got %v", cm.GetLabels())
+	}
+	if requests := r.overridesConfigMapRequests(context.Background(), cm); len(requests) != 1 ||
+		requests[0].Name != hub.Name {
+		t.Errorf("overridesConfigMapRequests() = %v, want the hub", requests)
+	}
+
+	// A new revision replaces the one in use
+	setOverridesData(t, r, consoleImageV2)
+	got, err = r.loadOverridesConfigMap(context.Background(), hub, imageOverridesSource, "image-overrides", base)
+	if err != nil || got["console"] != "quay.io/acm/console:2" {
+		t.Fatalf("loadOverridesConfigMap() = %v, %v, want the new image", got, err)
+	}
+	second := *hub.Status.OverrideRevisions.ImageOverrides
+	if second.Revision == first.Revision || second.PreviousRevision != first.Revision {
+		t.Errorf("ImageOverrides = %+v, want a new revision replacing %s", second, first.Revision)
+	}
+
+	// An invalid image reference is not applied
+	setOverridesData(t, r, consoleImageBad)
+	got, err = r.loadOverridesConfigMap(context.Background(), hub, imageOverridesSource, "image-overrides", base)
+	if err != nil || got["console"] != "quay.io/acm/console:2" {
+		t.Fatalf("loadOverridesConfigMap() = %v, %v, want the revision in use", got, err)
+	}
+	if status := hub.Status.OverrideRevisions.ImageOverrides; status.Revision != second.Revision ||
+		status.Message == "" {
+		t.Errorf("ImageOverrides = %+v, want revision %s with a message", status, second.Revision)
+	}
+
+	// Without a revision in use the error is returned
+	fresh := overridesHub(hub.Annotations)
+	r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(fresh, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "image-overrides", Namespace: hub.Namespace},
+		Data:       map[string]string{"overrides.json": consoleImageBad},
+	}).Build()
+	if _, err := r.loadOverridesConfigMap(context.Background(), fresh, imageOverridesSource, "image-overrides",
+		base); err == nil {
+		t.Error("expected an error for an invalid configmap without a revision in use")
+	}
+}
+
+func Test_rollbackOverrides(t *testing.T) {
+	registerScheme()
+	hub := overridesHub(map[string]string{utils.AnnotationImageOverridesCM: "image-overrides"})
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(hub, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "image-overrides", Namespace: hub.Namespace},
+			Data:       map[string]string{"overrides.json": consoleImageV1},
+		}).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+	load := func() string {
+		got, err := r.loadOverridesConfigMap(context.Background(), hub, imageOverridesSource, "image-overrides",
+			map[string]string{})
+		if err != nil {
+			t.Fatalf("loadOverridesConfigMap() error = %v", err)
+		}
+		return got["console"]
+	}
+
+	load()
+	setOverridesData(t, r, consoleImageV2)
+	load()
+
+	hub.Annotations[utils.AnnotationOverridesRollback] = "undo-1"
+	if err := r.rollbackOverrides(context.Background(), hub); err != nil {
+		t.Fatalf("rollbackOverrides() error = %v", err)
+	}
+	if got := load(); got != "quay.io/acm/console:1" {
+		t.Errorf("image after rollback = %s, want the previous revision", got)
+	}
+	if hub.Status.OverrideRevisions.LastRollback != "undo-1" {
+		t.Errorf("LastRollback = %q, want undo-1", hub.Status.OverrideRevisions.LastRollback)
+	}
+
+	// The same token is only acted on once
+	if err := r.rollbackOverrides(context.Background(), hub); err != nil {
+		t.Fatalf("rollbackOverrides() error = %v", err)
+	}
+	if got := load(); got != "quay.io/acm/console:1" {
+		t.Errorf("image after repeated rollback = %s, want no change", got)
+	}
+
+	// Removing the annotation clears the status of the configmap
+	delete(hub.Annotations, utils.AnnotationImageOverridesCM)
+	if err := r.rollbackOverrides(context.Background(), hub); err != nil {
+		t.Fatalf("rollbackOverrides() error = %v", err)
+	}
+	if hub.Status.OverrideRevisions.ImageOverrides != nil {
+		t.Errorf("ImageOverrides = %+v, want it cleared", hub.Status.OverrideRevisions.ImageOverrides)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+/*
+preservedFieldPaths returns the paths of the fields of a rendered resource that keep their value from the cluster. The
+paths are listed in spec.overrides.preservedFields and in the preservedFields section of the template override
+ConfigMap.
+*/
+func preservedFieldPaths(m *operatorv1.MultiClusterHub, configured []operatorv1.PreservedFields,
+	template *unstructured.Unstructured) []string {
+	var rules []operatorv1.PreservedFields
+	if m.Spec.Overrides != nil {
+		rules = append(rules, m.Spec.Overrides.PreservedFields...)
+	}
+	rules = append(rules, configured...)
+
+	var paths []string
+	for _, rule := range rules {
+		if rule.Kind != template.GetKind() || rule.Name != template.GetName() {
+			continue
+		}
+		if rule.Namespace != "" && rule.Namespace != template.GetNamespace() {
+			continue
+		}
+		if rule.APIVersion != "" && rule.APIVersion != template.GetAPIVersion() {
+			continue
+		}
+		paths = append(paths, rule.Paths...)
+	}
+	return paths
+}
+
+/*
+preserveFields copies the fields at the given paths from the existing resource into the template, so applying the
+template keeps their value. Fields missing from the existing resource are removed from the template.
+*/
+func preserveFields(existing, template *unstructured.Unstructured, paths []string) error {
+	for _, path := range paths {
+		fields, err := operatorv1.ParseFieldPath(path)
+		if err != nil {
+			return err
+		}
+
+		value, found, err := unstructured.NestedFieldCopy(existing.Object, fields...)
+		if err != nil {
+			return fmt.Errorf("failed to read preserved field %s: %w", path, err)
+		}
+		if !found {
+			unstructured.RemoveNestedField(template.Object, fields...)
+			continue
+		}
+		if err := unstructured.SetNestedField(template.Object, value, fields...); err != nil {
+			return fmt.Errorf("failed to preserve field %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_preserveFields(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{
+		Spec: operatorv1.MultiClusterHubSpec{
+			Overrides: &operatorv1.Overrides{
+				PreservedFields: []operatorv1.PreservedFields{
+					{Kind: "ConfigMap", Name: "search-config", Paths: []string{`data["tuning.yaml"]`, "data.removed"}},
+					{Kind: "ConfigMap", Name: "search-config", Namespace: "other", Paths: []string{"data.ignored"}},
+				},
+			},
+		},
+	}
+	configured := []operatorv1.PreservedFields{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "search-config", Paths: []string{"metadata.labels.tier"}},
+	}
+
+	template := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "search-config", "namespace": "open-cluster-management"},
+		"data": map[string]interface{}{
+			"tuning.yaml": "default",
+			"removed":     "default",
+			"managed":     "rendered",
+		},
+	}}
+	existing := template.DeepCopy()
+	existing.Object["data"] = map[string]interface{}{"tuning.yaml": "custom", "managed": "edited"}
+	existing.SetLabels(map[string]string{"tier": "gold"})
+
+	paths := preservedFieldPaths(hub, configured, template)
+	if want := []string{`data["tuning.yaml"]`, "data.removed", "metadata.labels.tier"}; !reflect.DeepEqual(paths,
+		want) {
+		t.Fatalf("preservedFieldPaths() = %v, want %v", paths, want)
+	}
+
+	if err := preserveFields(existing, template, paths); err != nil {
+		t.Fatalf("preserveFields() error = %v", err)
+	}
+	want := map[string]interface{}{"tuning.yaml": "custom", "managed": "rendered"}
+	if !reflect.DeepEqual(template.Object["data"], want) {
+		t.Errorf("data = %v, want %v", template.Object["data"], want)
+	}
+	if template.GetLabels()["tier"] != "gold" {
+		t.Errorf("labels = %v, want the preserved tier label", template.GetLabels())
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+/*
+applyProbeSettings tunes the liveness and readiness probes of every container in a rendered Deployment with the
+effective spec.probes settings. Probes the chart does not define are left undefined, and fields that are not set
+keep the chart's value.
+*/
+func applyProbeSettings(template *unstructured.Unstructured, settings operatorv1.ProbeSettings) error {
+	if settings.Liveness == nil && settings.Readiness == nil {
+		return nil
+	}
+
+	containers, found, err := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found {
+		log.Error(err, "Failed to get containers from template", "Kind", template.GetKind(), "Name", template.GetName())
+		return err
+	}
+
+	for i, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tuneProbe(container, "livenessProbe", settings.Liveness)
+		tuneProbe(container, "readinessProbe", settings.Readiness)
+		containers[i] = container
+	}
+
+	if err := unstructured.SetNestedSlice(template.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+		log.Error(err, "Failed to set containers in template", "Template", template.GetName())
+		return err
+	}
+	return nil
+}
+
+func tuneProbe(container map[string]interface{}, probeField string, tuning *operatorv1.ProbeTuning) {
+	probe, ok := container[probeField].(map[string]interface{})
+	if !ok || tuning == nil {
+		return
+	}
+
+	fields := []struct {
+		name  string
+		value *int32
+	}{
+		{"initialDelaySeconds", tuning.InitialDelaySeconds},
+		{"periodSeconds", tuning.PeriodSeconds},
+		{"timeoutSeconds", tuning.TimeoutSeconds},
+		{"failureThreshold", tuning.FailureThreshold},
+		{"successThreshold", tuning.SuccessThreshold},
+	}
+	for _, f := range fields {
+		if f.value != nil {
+			probe[f.name] = int64(*f.value)
+		}
+	}
+	container[probeField] = probe
+}
@@ -0,0 +1,71 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_applyProbeSettings(t *testing.T) {
+	timeout := int32(10)
+	success := int32(2)
+
+	template := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "grc-policy-propagator"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name": "with-probes",
+							"livenessProbe": map[string]interface{}{
+								"periodSeconds": int64(15),
+							},
+							"readinessProbe": map[string]interface{}{
+								"periodSeconds": int64(15),
+							},
+						},
+						map[string]interface{}{
+							"name": "without-probes",
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	settings := operatorv1.ProbeSettings{
+		Liveness:  &operatorv1.ProbeTuning{TimeoutSeconds: &timeout},
+		Readiness: &operatorv1.ProbeTuning{SuccessThreshold: &success},
+	}
+	if err := applyProbeSettings(template, settings); err != nil {
+		t.Fatalf("applyProbeSettings() error = %v", err)
+	}
+
+	containers, _, _ := unstructured.NestedSlice(template.Object, "spec", "template", "spec", "containers")
+	withProbes := containers[0].(map[string]interface{})
+
+	liveness := withProbes["livenessProbe"].(map[string]interface{})
+	if liveness["timeoutSeconds"] != int64(10) || liveness["periodSeconds"] != int64(15) {
+		t.Errorf("livenessProbe = %v, want timeoutSeconds 10 and the chart's periodSeconds kept", liveness)
+	}
+	if _, ok := liveness["successThreshold"]; ok {
+		t.Error("readiness settings must not be applied to the liveness probe")
+	}
+
+	readiness := withProbes["readinessProbe"].(map[string]interface{})
+	if readiness["successThreshold"] != int64(2) {
+		t.Errorf("readinessProbe = %v, want successThreshold 2", readiness)
+	}
+
+	withoutProbes := containers[1].(map[string]interface{})
+	if _, ok := withoutProbes["livenessProbe"]; ok {
+		t.Error("expected no liveness probe to be added to a container that does not define one")
+	}
+}
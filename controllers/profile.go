@@ -0,0 +1,111 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"slices"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+)
+
+// profileComponents returns the components a hub profile decides: the default enabled and default disabled components
+func profileComponents() ([]string, error) {
+	enabled, err := operatorv1.GetDefaultEnabledComponents()
+	if err != nil {
+		return nil, err
+	}
+	disabled, err := operatorv1.GetDefaultDisabledComponents()
+	if err != nil {
+		return nil, err
+	}
+	return append(enabled, disabled...), nil
+}
+
+// configuredComponents returns the components configured in spec.overrides.components
+func configuredComponents(m *operatorv1.MultiClusterHub) []operatorv1.ComponentConfig {
+	if m.Spec.Overrides == nil {
+		return nil
+	}
+	return m.Spec.Overrides.Components
+}
+
+// newComponentPolicy returns the policy for the components an upgrade enables by default, a pinned profile acknowledges
+func newComponentPolicy(m *operatorv1.MultiClusterHub) operatorv1.NewComponentPolicy {
+	if m.Spec.Profile != nil && m.Spec.Profile.Pinned {
+		return operatorv1.NewComponentAcknowledge
+	}
+	if m.Spec.NewComponentPolicy == "" {
+		return operatorv1.NewComponentEnable
+	}
+	return m.Spec.NewComponentPolicy
+}
+
+/*
+resolveProfile resolves the components the hub profile enables, the full profile when the hub sets none, without
+writing them to spec.overrides.components. The resolution is recorded in the status, where the components that are not
+configured look up whether they are enabled.
+
+Components an upgrade enables by default are the ones the last resolution of the same profile did not enable. They are
+enabled or left disabled following the new component policy, and those waiting for an acknowledgement are listed as
+pending until they are configured. The availability of the profile is recorded in the status as well, the hub runs
+with it when the spec sets no availability.
+*/
+func resolveProfile(m *operatorv1.MultiClusterHub) (*operatorv1.ProfileStatus, error) {
+	profile := operatorv1.HubProfile{Name: operatorv1.ProfileFull}
+	if m.Spec.Profile != nil {
+		profile = *m.Spec.Profile
+	}
+	enabled, err := operatorv1.GetProfileEnabledComponents(profile.Name)
+	if err != nil {
+		return nil, err
+	}
+	components, err := profileComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	// New components are only decided on once the profile has been resolved, a profile change is a decision itself
+	last := m.Status.Profile
+	policy := newComponentPolicy(m)
+	decide := last != nil && last.Name == profile.Name && policy != operatorv1.NewComponentEnable
+
+	status := &operatorv1.ProfileStatus{
+		Name:               profile.Name,
+		AvailabilityConfig: operatorv1.GetProfileAvailabilityConfig(profile.Name),
+	}
+	for _, c := range components {
+		want := slices.Contains(enabled, c)
+		if want && decide && !slices.Contains(last.EnabledComponents, c) {
+			want = false
+			if policy == operatorv1.NewComponentAcknowledge && !m.ComponentPresent(c) {
+				status.PendingComponents = append(status.PendingComponents, c)
+			}
+		}
+
+		if want {
+			status.EnabledComponents = append(status.EnabledComponents, c)
+		} else {
+			status.DisabledComponents = append(status.DisabledComponents, c)
+		}
+		if m.ComponentPresent(c) && m.Enabled(c) != want {
+			status.OverriddenComponents = append(status.OverriddenComponents, c)
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,148 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_resolveProfile(t *testing.T) {
+	newHub := func(profile *operatorv1.HubProfile, components ...operatorv1.ComponentConfig) *operatorv1.MultiClusterHub {
+		m := &operatorv1.MultiClusterHub{
+			ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+			Spec:       operatorv1.MultiClusterHubSpec{Profile: profile},
+		}
+		if len(components) > 0 {
+			m.Spec.Overrides = &operatorv1.Overrides{Components: components}
+		}
+		return m
+	}
+	// resolve resolves the profile and records the resolution like setDefaults does
+	resolve := func(t *testing.T, m *operatorv1.MultiClusterHub) {
+		status, err := resolveProfile(m)
+		if err != nil {
+			t.Fatalf("resolveProfile() error = %v", err)
+		}
+		m.Status.Profile = status
+	}
+	// upgrade drops a component from the last resolution, as if the previous release did not enable it by default
+	upgrade := func(m *operatorv1.MultiClusterHub, component string) {
+		m.Status.Profile.EnabledComponents = slices.DeleteFunc(m.Status.Profile.EnabledComponents,
+			func(c string) bool { return c == component })
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		m := newHub(nil)
+		resolve(t, m)
+		if m.Spec.AvailabilityConfig != "" {
+			t.Errorf("expected the availability not to be written to the spec, got %s", m.Spec.AvailabilityConfig)
+		}
+		if got := m.EffectiveAvailabilityConfig(); got != operatorv1.HAHigh {
+			t.Errorf("EffectiveAvailabilityConfig() = %s, want %s", got, operatorv1.HAHigh)
+		}
+		if m.Spec.Overrides != nil {
+			t.Errorf("expected the components not to be written to the spec, got %+v", m.Spec.Overrides)
+		}
+		if m.Status.Profile.Name != operatorv1.ProfileFull || !m.Enabled(operatorv1.Search) ||
+			m.Enabled(operatorv1.ClusterBackup) {
+			t.Errorf("status = %+v, want the default components", m.Status.Profile)
+		}
+		last := m.Status.Profile
+		resolve(t, m)
+		if !reflect.DeepEqual(m.Status.Profile, last) {
+			t.Errorf("status = %+v, want the resolution to be stable %+v", m.Status.Profile, last)
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		m := newHub(&operatorv1.HubProfile{Name: operatorv1.ProfileEdgeRAN},
+			operatorv1.ComponentConfig{Name: operatorv1.ClusterBackup, Enabled: true},
+			operatorv1.ComponentConfig{Name: operatorv1.SiteConfig, Enabled: true})
+		resolve(t, m)
+		if !m.Enabled(operatorv1.SiteConfig) || !m.Enabled(operatorv1.ClusterBackup) || m.Enabled(operatorv1.Search) {
+			t.Error("expected the edge-ran components and the cluster-backup override to be enabled")
+		}
+		if got := m.EffectiveAvailabilityConfig(); got != operatorv1.HABasic || m.Spec.AvailabilityConfig != "" {
+			t.Errorf("EffectiveAvailabilityConfig() = %s, want %s from the status only", got, operatorv1.HABasic)
+		}
+		if got := m.Status.Profile.OverriddenComponents; !reflect.DeepEqual(got, []string{operatorv1.ClusterBackup}) {
+			t.Errorf("OverriddenComponents = %v, want [%s]", got, operatorv1.ClusterBackup)
+		}
+
+		// A profile change switches the components that are not configured and the availability it set
+		m.Spec.Profile.Name = operatorv1.ProfileGovernance
+		resolve(t, m)
+		if !m.Enabled(operatorv1.SiteConfig) || m.Enabled(operatorv1.Search) || !m.Enabled(operatorv1.GRC) {
+			t.Error("expected siteconfig to stay configured and the governance components to be enabled")
+		}
+		if got := m.EffectiveAvailabilityConfig(); got != operatorv1.HAHigh {
+			t.Errorf("EffectiveAvailabilityConfig() = %s, want %s", got, operatorv1.HAHigh)
+		}
+
+		// The availability of the spec takes precedence over the one of the profile
+		m.Spec.AvailabilityConfig = operatorv1.HABasic
+		resolve(t, m)
+		if got := m.EffectiveAvailabilityConfig(); got != operatorv1.HABasic {
+			t.Errorf("EffectiveAvailabilityConfig() = %s, want %s", got, operatorv1.HABasic)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		policy      operatorv1.NewComponentPolicy
+		pinned      bool
+		wantEnabled bool
+		wantPending []string
+	}{
+		{name: "enable", wantEnabled: true},
+		{name: "disable", policy: operatorv1.NewComponentDisable},
+		{name: "acknowledge", policy: operatorv1.NewComponentAcknowledge, wantPending: []string{operatorv1.Volsync}},
+		{name: "pinned", pinned: true, wantPending: []string{operatorv1.Volsync}},
+	}
+	for _, tt := range tests {
+		t.Run("new component "+tt.name, func(t *testing.T) {
+			m := newHub(&operatorv1.HubProfile{Name: operatorv1.ProfileFull, Pinned: tt.pinned})
+			m.Spec.NewComponentPolicy = tt.policy
+			resolve(t, m)
+			if !m.Enabled(operatorv1.Volsync) {
+				t.Fatal("expected the first resolution to enable the default components")
+			}
+
+			upgrade(m, operatorv1.Volsync)
+			for i := 0; i < 2; i++ {
+				resolve(t, m)
+				if m.Enabled(operatorv1.Volsync) != tt.wantEnabled {
+					t.Fatalf("Enabled(%s) = %v, want %v", operatorv1.Volsync, !tt.wantEnabled, tt.wantEnabled)
+				}
+				if got := m.Status.Profile.PendingComponents; !reflect.DeepEqual(got, tt.wantPending) {
+					t.Fatalf("PendingComponents = %v, want %v", got, tt.wantPending)
+				}
+			}
+
+			// Configuring the component decides on it
+			m.Enable(operatorv1.Volsync)
+			resolve(t, m)
+			if !m.Enabled(operatorv1.Volsync) || len(m.Status.Profile.PendingComponents) != 0 {
+				t.Error("expected the configured component to be enabled and not pending")
+			}
+		})
+	}
+
+	t.Run("new component after a profile change", func(t *testing.T) {
+		m := newHub(&operatorv1.HubProfile{Name: operatorv1.ProfileGovernance})
+		m.Spec.NewComponentPolicy = operatorv1.NewComponentAcknowledge
+		resolve(t, m)
+
+		m.Spec.Profile.Name = operatorv1.ProfileObservability
+		resolve(t, m)
+		if !m.Enabled(operatorv1.Search) || len(m.Status.Profile.PendingComponents) != 0 {
+			t.Error("expected the components of the new profile to be enabled")
+		}
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/rbacaudit"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// rbacAuditInterval is how often the granted permissions are compared with the rendered charts when no chart changes
+const rbacAuditInterval = 10 * time.Minute
+
+// recordRBACRequirements records the permissions the rendered templates of a component need
+func (r *MultiClusterHubReconciler) recordRBACRequirements(component string, templates []*unstructured.Unstructured) {
+	req, err := rbacaudit.Required(templates)
+	if err != nil {
+		r.Log.Error(err, "Unable to compute the permissions of the rendered templates", "Component", component)
+		return
+	}
+	if r.rbacRequirements == nil {
+		r.rbacRequirements = newRegistry[rbacaudit.Requirements]()
+	}
+	r.rbacRequirements.set(component, req)
+}
+
+/*
+auditRBAC compares the permissions the rendered components need with the rules of the ClusterRoles bound to the
+operator service account, and reports the missing permissions and the wildcard rules of the component ClusterRoles in
+the hub status. The audit runs when a chart renders different permissions and at least every rbacAuditInterval.
+Failures are logged, they do not fail the reconcile.
+*/
+func (r *MultiClusterHubReconciler) auditRBAC(ctx context.Context, m *operatorv1.MultiClusterHub) {
+	if r.rbacRequirements == nil {
+		return
+	}
+	if last := m.Status.RBACAudit; last != nil && !r.rbacRequirements.hasChanged() &&
+		time.Since(last.LastAuditTime.Time) < rbacAuditInterval {
+		return
+	}
+
+	granted, err := r.operatorClusterRules(ctx)
+	if err != nil {
+		r.Log.Error(err, "Unable to read the permissions granted to the operator")
+		return
+	}
+
+	m.Status.RBACAudit = rbacAuditStatus(r.rbacRequirements.snapshot(), granted)
+}
+
+// rbacAuditStatus returns the findings of the components whose requirements are not covered by the granted rules or
+// whose ClusterRoles use wildcards
+func rbacAuditStatus(components map[string]rbacaudit.Requirements,
+	granted []rbacv1.PolicyRule) *operatorv1.RBACAuditStatus {
+	status := &operatorv1.RBACAuditStatus{LastAuditTime: metav1.Now()}
+	for component, req := range components {
+		missing := req.Missing(granted)
+		if len(missing) == 0 && len(req.WildcardRules) == 0 {
+			continue
+		}
+		audit := operatorv1.ComponentRBACAudit{Name: component, WildcardRules: req.WildcardRules}
+		if len(missing) > 0 {
+			audit.MissingPermissions = rbacaudit.Strings(missing)
+		}
+		status.Components = append(status.Components, audit)
+	}
+	sort.Slice(status.Components, func(i, j int) bool {
+		return status.Components[i].Name < status.Components[j].Name
+	})
+	return status
+}
+
+// operatorClusterRules returns the rules of the ClusterRoles bound to the operator service account
+func (r *MultiClusterHubReconciler) operatorClusterRules(ctx context.Context) ([]rbacv1.PolicyRule, error) {
+	namespace, err := utils.OperatorNamespace()
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := &rbacv1.ClusterRoleBindingList{}
+	if err := r.Client.List(ctx, bindings); err != nil {
+		return nil, err
+	}
+
+	rules := []rbacv1.PolicyRule{}
+	for _, binding := range bindings.Items {
+		if binding.RoleRef.Kind != "ClusterRole" || !bindsOperator(binding.Subjects, namespace) {
+			continue
+		}
+		role := &rbacv1.ClusterRole{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: binding.RoleRef.Name}, role); err != nil {
+			return nil, err
+		}
+		rules = append(rules, role.Rules...)
+	}
+	return rules, nil
+}
+
+// bindsOperator returns true when the subjects include the operator service account
+func bindsOperator(subjects []rbacv1.Subject, namespace string) bool {
+	for _, s := range subjects {
+		if s.Kind == rbacv1.ServiceAccountKind && s.Name == utils.MCHOperatorName && s.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_auditRBAC(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "open-cluster-management")
+
+	operatorRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub-role"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"*"}},
+			{
+				APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"},
+				Verbs: []string{"get", "create", "update", "patch", "delete"},
+			},
+		},
+	}
+	otherRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+	}
+	binding := func(name, role, namespace string) *rbacv1.ClusterRoleBinding {
+		return &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role},
+			Subjects: []rbacv1.Subject{
+				{Kind: rbacv1.ServiceAccountKind, Name: utils.MCHOperatorName, Namespace: namespace},
+			},
+		}
+	}
+	objs := []client.Object{
+		operatorRole, otherRole,
+		binding("multiclusterhub-rolebinding", operatorRole.Name, "open-cluster-management"),
+		// The operator service account of another namespace is not the operator
+		binding("other", otherRole.Name, "other-namespace"),
+	}
+
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+	hub := &operatorv1.MultiClusterHub{}
+
+	// Nothing is audited before a chart is rendered
+	r.auditRBAC(context.TODO(), hub)
+	if hub.Status.RBACAudit != nil {
+		t.Fatalf("expected no audit before a chart is rendered, got %v", hub.Status.RBACAudit)
+	}
+
+	role := testObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "search")
+	role.Object["rules"] = []interface{}{
+		map[string]interface{}{
+			"apiGroups": []interface{}{"*"}, "resources": []interface{}{"*"}, "verbs": []interface{}{"list"},
+		},
+	}
+	r.recordRBACRequirements(operatorv1.Console, []*unstructured.Unstructured{
+		testObject("apps/v1", "Deployment", "", "console"),
+	})
+	r.recordRBACRequirements(operatorv1.Search, []*unstructured.Unstructured{
+		testObject("apps/v1", "Deployment", "", "search"),
+		role,
+		testObject("v1", "ServiceAccount", "", "search"),
+	})
+	r.auditRBAC(context.TODO(), hub)
+
+	want := []operatorv1.ComponentRBACAudit{
+		{
+			Name: operatorv1.Search,
+			MissingPermissions: []string{
+				"create serviceaccounts", "delete serviceaccounts", "get serviceaccounts", "patch serviceaccounts",
+				"update serviceaccounts", "list *.*",
+			},
+			WildcardRules: []string{"ClusterRole search: apiGroups=[*] resources=[*] verbs=[list]"},
+		},
+	}
+	if hub.Status.RBACAudit == nil || !reflect.DeepEqual(hub.Status.RBACAudit.Components, want) {
+		t.Fatalf("RBACAudit = %v, want components %v", hub.Status.RBACAudit, want)
+	}
+
+	// The audit is not repeated until a chart changes or the interval passes
+	last := hub.Status.RBACAudit.LastAuditTime
+	hub.Status.RBACAudit.LastAuditTime = metav1.NewTime(last.Add(-time.Second))
+	r.auditRBAC(context.TODO(), hub)
+	if !hub.Status.RBACAudit.LastAuditTime.Equal(&metav1.Time{Time: last.Add(-time.Second)}) {
+		t.Errorf("expected the audit to be skipped while the charts are unchanged")
+	}
+
+	r.rbacRequirements.remove(operatorv1.Search)
+	r.auditRBAC(context.TODO(), hub)
+	if len(hub.Status.RBACAudit.Components) != 0 {
+		t.Errorf("expected no findings once search is removed, got %v", hub.Status.RBACAudit.Components)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"os"
 
 	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/capabilities"
 	"github.com/stolostron/multiclusterhub-operator/pkg/overrides"
 	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
 	"github.com/stolostron/multiclusterhub-operator/pkg/version"
@@ -48,6 +49,9 @@ import (
 func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Request) (retQueue ctrl.Result, retError error) {
 	r.Log = log
 	r.Log.Info("Reconciling MultiClusterHub")
+	r.refreshOLMVersion()
+	r.refreshTLSProfile(ctx)
+	r.oadpDetected = nil
 
 	// Fetch the MultiClusterHub instance
 	multiClusterHub := &operatorv1.MultiClusterHub{}
@@ -75,9 +79,6 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	multiClusterHub.Status.HubConditions = filterOutConditionWithSubstring(multiClusterHub.Status.HubConditions,
-		string(operatorv1.ComponentFailure))
-
 	// Check to see if upgradeable (OLM v0 only)
 	var upgrade bool
 	if r.OLMVersion == "v0" {
@@ -96,7 +97,7 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	allCRs, err := r.listCustomResources()
+	allCRs, err := r.listCustomResources(multiClusterHub)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -149,10 +150,16 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		imageOverrides = utils.OverrideImageRepository(imageOverrides, imageRepo)
 	}
 
+	// Restore the previous override configmaps if a rollback is requested
+	if err := r.rollbackOverrides(ctx, multiClusterHub); err != nil {
+		r.Log.Error(err, "Failed to roll back the override configmaps")
+		return ctrl.Result{}, err
+	}
+
 	// Check for developer overrides in configmap.
 	if ioConfigmapName := utils.GetImageOverridesConfigmapName(multiClusterHub); ioConfigmapName != "" {
-		imageOverrides, err = overrides.GetOverridesFromConfigmap(r.Client, imageOverrides,
-			multiClusterHub.GetNamespace(), ioConfigmapName, false)
+		imageOverrides, err = r.loadOverridesConfigMap(ctx, multiClusterHub, imageOverridesSource, ioConfigmapName,
+			imageOverrides)
 		if err != nil {
 			r.Log.Error(err, fmt.Sprintf("Failed to find image override configmap: %s/%s",
 				multiClusterHub.GetNamespace(), ioConfigmapName))
@@ -172,8 +179,8 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	// Check for developer overrides in configmap
 	if toConfigmapName := utils.GetTemplateOverridesConfigmapName(multiClusterHub); toConfigmapName != "" {
-		templateOverrides, err = overrides.GetOverridesFromConfigmap(r.Client, templateOverrides,
-			multiClusterHub.GetNamespace(), toConfigmapName, true)
+		templateOverrides, err = r.loadOverridesConfigMap(ctx, multiClusterHub, templateOverridesSource,
+			toConfigmapName, templateOverrides)
 		if err != nil {
 			r.Log.Error(err, fmt.Sprintf("Failed to find template override configmap: %s/%s",
 				multiClusterHub.GetNamespace(), toConfigmapName))
@@ -182,8 +189,26 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 	}
 
+	// Drop template overrides that no component chart supports
+	templateOverrides, templateOverrideSchemas := r.validateTemplateOverrides(multiClusterHub, templateOverrides)
+
+	// Fields preserved from the cluster can be listed next to the template overrides
+	var preservedFields []operatorv1.PreservedFields
+	if toConfigmapName := utils.GetTemplateOverridesConfigmapName(multiClusterHub); toConfigmapName != "" {
+		preservedFields, err = overrides.GetPreservedFieldsFromConfigmap(r.Client, multiClusterHub.GetNamespace(),
+			toConfigmapName)
+		if err != nil {
+			r.Log.Error(err, fmt.Sprintf("Failed to read preserved fields from template override configmap: %s/%s",
+				multiClusterHub.GetNamespace(), toConfigmapName))
+
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Update cache with template overrides and related information.
 	r.CacheSpec.TemplateOverrides = templateOverrides
+	r.CacheSpec.TemplateOverrideSchemas = templateOverrideSchemas
+	r.CacheSpec.PreservedFields = preservedFields
 	r.CacheSpec.TemplateOverridesCM = utils.GetTemplateOverridesConfigmapName(multiClusterHub)
 
 	var result ctrl.Result
@@ -238,11 +263,13 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	   MultiClusterHub to avoid conflicts with the openshift-* namespace when deploying PrometheusRules and
 	   ServiceMonitors in ACM.
 	*/
-	_, err = r.ensureOpenShiftNamespaceLabel(ctx, multiClusterHub)
-	if err != nil {
-		r.Log.Error(err, "Failed to add to %s label to namespace: %s", utils.OpenShiftClusterMonitoringLabel,
-			multiClusterHub.GetNamespace())
-		return ctrl.Result{}, err
+	if !r.RestrictedMode {
+		_, err = r.ensureOpenShiftNamespaceLabel(ctx, multiClusterHub)
+		if err != nil {
+			r.Log.Error(err, "Failed to add to %s label to namespace: %s", utils.OpenShiftClusterMonitoringLabel,
+				multiClusterHub.GetNamespace())
+			return ctrl.Result{}, err
+		}
 	}
 
 	err = r.maintainImageManifestConfigmap(multiClusterHub)
@@ -286,6 +313,29 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
+	// Roll the component deployments out one at a time when a highly available hub is upgraded
+	if err := r.progressRollout(ctx, multiClusterHub); err != nil {
+		r.Log.Error(err, "Failed to progress the component rollout")
+		return ctrl.Result{}, err
+	}
+
+	// The trust bundle is synced into the component namespaces before any component is rendered, so every component
+	// is configured with the current bundle
+	result, err = r.createTrustBundleConfigmap(ctx, multiClusterHub)
+	if err != nil {
+		return result, err
+	}
+
+	result, err = r.ensureComponentNamespaces(multiClusterHub)
+	if result != (ctrl.Result{}) || err != nil {
+		return result, err
+	}
+
+	result, err = r.ensureTrustBundle(ctx, multiClusterHub)
+	if result != (ctrl.Result{}) || err != nil {
+		return result, err
+	}
+
 	// Deploy appsub operator component
 	if multiClusterHub.Enabled(operatorv1.Appsub) {
 		result, err = r.ensureComponent(ctx, multiClusterHub, operatorv1.Appsub, r.CacheSpec, stsEnabled)
@@ -323,7 +373,11 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		deployment process where MCE must be deployed before any other components to ensure the necessary CRDs are
 		present for the other components to deploy successfully.
 	*/
-	result, err = r.ensureMultiClusterEngine(ctx, multiClusterHub)
+	if r.RestrictedMode {
+		result, err = r.ensureRestrictedMultiClusterEngine(ctx, multiClusterHub)
+	} else {
+		result, err = r.ensureMultiClusterEngine(ctx, multiClusterHub)
+	}
 	if result != (ctrl.Result{}) || err != nil {
 		return result, err
 	}
@@ -338,16 +392,6 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return result, err
 	}
 
-	result, err = r.createTrustBundleConfigmap(ctx, multiClusterHub)
-	if err != nil {
-		return result, err
-	}
-
-	result, err = r.ensureComponentNamespaces(multiClusterHub)
-	if result != (ctrl.Result{}) || err != nil {
-		return result, err
-	}
-
 	/*
 		Ensure NetworkPolicies for ACM components. This implements a create-once pattern where
 		MCH creates initial NetworkPolicy resources with delegation annotations. Operand teams
@@ -371,9 +415,11 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return result, err
 	}
 
-	result, err = r.createMetricsServiceMonitor(ctx, multiClusterHub)
-	if err != nil {
-		return result, err
+	if r.hasCapability(capabilities.ServiceMonitor) {
+		result, err = r.createMetricsServiceMonitor(ctx, multiClusterHub)
+		if err != nil {
+			return result, err
+		}
 	}
 
 	// Install CRDs
@@ -403,14 +449,25 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return result, err
 	}
 
-	if !multiClusterHub.Spec.DisableHubSelfManagement {
+	if r.RestrictedMode {
+		if err := r.ensureRestrictedLocalCluster(ctx, multiClusterHub); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if !multiClusterHub.Spec.DisableHubSelfManagement {
 		result, err = r.ensureKlusterletAddonConfig(multiClusterHub)
 		if result != (ctrl.Result{}) || err != nil {
 			return result, err
 		}
+
+		result, err = r.ensureLocalClusterConfig(ctx, multiClusterHub)
+		if result != (ctrl.Result{}) || err != nil {
+			return result, err
+		}
 	}
 
 	// Install the rest of the subscriptions in no particular order
+	r.beginAdoptionReport(multiClusterHub)
+	defer func() { r.adoption = nil }()
 	for _, c := range operatorv1.MCHComponents {
 		// Skip components that have been migrated to MCE and pruned from MCH spec.
 		// These components must remain in MCHComponents for webhook validation but
@@ -425,6 +482,15 @@ func (r *MultiClusterHubReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return result, err
 		}
 	}
+	r.completeAdoptionReport(multiClusterHub)
+	r.completeRollout(multiClusterHub)
+	r.auditRBAC(ctx, multiClusterHub)
+	r.verifyServiceBackends(ctx, multiClusterHub)
+
+	// Check the readiness of the deployment being rolled out
+	if rollout := multiClusterHub.Status.ComponentRollout; rollout != nil && rollout.Current != nil {
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
 
 	if upgrade {
 		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
@@ -0,0 +1,110 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"sync"
+)
+
+/*
+registry holds what the reconciler last rendered for each key, a component or the hub CRDs, so that it is available
+outside of the reconcile of the key. It is safe for concurrent use, and a nil registry holds no key.
+*/
+type registry[T any] struct {
+	mu      sync.RWMutex
+	entries map[string]T
+	// changed is set when an entry changed since the last snapshot
+	changed bool
+}
+
+func newRegistry[T any]() *registry[T] {
+	return &registry[T]{entries: map[string]T{}}
+}
+
+func (r *registry[T]) set(key string, value T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if last, ok := r.entries[key]; !ok || !reflect.DeepEqual(last, value) {
+		r.entries[key] = value
+		r.changed = true
+	}
+}
+
+func (r *registry[T]) remove(key string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[key]; ok {
+		delete(r.entries, key)
+		r.changed = true
+	}
+}
+
+// get returns the entry of a key and whether it has been recorded
+func (r *registry[T]) get(key string) (T, bool) {
+	if r == nil {
+		var zero T
+		return zero, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	value, ok := r.entries[key]
+	return value, ok
+}
+
+// list returns a copy of the entries
+func (r *registry[T]) list() map[string]T {
+	if r == nil {
+		return map[string]T{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entries := make(map[string]T, len(r.entries))
+	for key, value := range r.entries {
+		entries[key] = value
+	}
+	return entries
+}
+
+// snapshot returns a copy of the entries and clears the changed flag
+func (r *registry[T]) snapshot() map[string]T {
+	if r == nil {
+		return map[string]T{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := make(map[string]T, len(r.entries))
+	for key, value := range r.entries {
+		entries[key] = value
+	}
+	r.changed = false
+	return entries
+}
+
+func (r *registry[T]) hasChanged() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.changed
+}
@@ -0,0 +1,42 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_registry(t *testing.T) {
+	var missing *registry[[]string]
+	if _, ok := missing.get("a"); ok || len(missing.list()) != 0 || missing.hasChanged() {
+		t.Errorf("expected a nil registry to hold nothing")
+	}
+	missing.remove("a")
+
+	r := newRegistry[[]string]()
+	r.set("a", []string{"x"})
+	if got := r.snapshot(); !reflect.DeepEqual(got, map[string][]string{"a": {"x"}}) {
+		t.Errorf("snapshot() = %v", got)
+	}
+	if r.hasChanged() {
+		t.Errorf("expected the snapshot to clear the changed flag")
+	}
+
+	// Recording the same entry again is not a change
+	r.set("a", []string{"x"})
+	if r.hasChanged() {
+		t.Errorf("expected no change")
+	}
+	r.set("a", []string{"y"})
+	if got, ok := r.get("a"); !ok || !r.hasChanged() || !reflect.DeepEqual(got, []string{"y"}) {
+		t.Errorf("get() = %v, %v, want the updated entry", got, ok)
+	}
+
+	// list leaves the changed flag alone
+	r.snapshot()
+	r.remove("a")
+	if len(r.list()) != 0 || !r.hasChanged() {
+		t.Errorf("expected the entry to be removed")
+	}
+}
@@ -0,0 +1,738 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	mcev1 "github.com/stolostron/backplane-operator/api/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	operatorv2 "github.com/stolostron/multiclusterhub-operator/api/v2"
+	"github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine"
+	v0 "github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine/olm/v0"
+	v1 "github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengine/olm/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/multiclusterengineutils"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	consolev1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// restrictedBundleLabel is set on the ConfigMaps of a restricted mode bundle, with the name of the bundle
+	restrictedBundleLabel = "installer.open-cluster-management.io/restricted-bundle"
+
+	// restrictedBundlePrefix is the prefix of the names of the ConfigMaps of the restricted mode bundles
+	restrictedBundlePrefix = "multiclusterhub-restricted-"
+
+	// restrictedBundleSize is the size of the resources held by a ConfigMap, below the size limit of ConfigMaps
+	restrictedBundleSize = 900 * 1024
+
+	// The bundles of the hub itself
+	restrictedCRDsBundle         = "crds"
+	restrictedNamespacesBundle   = "namespaces"
+	restrictedHubBundle          = "hub"
+	restrictedWebhooksBundle     = "webhooks"
+	restrictedMCEBundle          = "multiclusterengine"
+	restrictedLocalClusterBundle = "local-cluster"
+
+	// mchCRDName is the name of the MultiClusterHub CRD
+	mchCRDName = "multiclusterhubs.operator.open-cluster-management.io"
+
+	// injectCABundleAnnotation has the service CA operator inject the CA bundle of the webhook service
+	injectCABundleAnnotation = "service.beta.openshift.io/inject-cabundle"
+)
+
+// bundleKeyChars matches the characters that are not allowed in ConfigMap keys
+var bundleKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+/*
+splitRestrictedTemplates returns the templates the operator applies in restricted mode, the namespaced templates of
+the tracked namespaces, and the templates an admin applies.
+*/
+func (r *MultiClusterHubReconciler) splitRestrictedTemplates(m *operatorv1.MultiClusterHub,
+	templates []*unstructured.Unstructured) (applied, external []*unstructured.Unstructured) {
+	for _, template := range templates {
+		if r.appliedInRestrictedMode(m, template) {
+			applied = append(applied, template)
+		} else {
+			external = append(external, template)
+		}
+	}
+	return applied, external
+}
+
+// appliedInRestrictedMode returns true when the object is namespaced and belongs to a tracked namespace
+func (r *MultiClusterHubReconciler) appliedInRestrictedMode(m *operatorv1.MultiClusterHub,
+	obj *unstructured.Unstructured) bool {
+	return r.isNamespaced(obj) && utils.Contains(utils.TrackedNamespaces(m), obj.GetNamespace())
+}
+
+// namespacedTemplates returns the namespaced templates, the templates the operator removes in restricted mode
+func (r *MultiClusterHubReconciler) namespacedTemplates(
+	templates []*unstructured.Unstructured) []*unstructured.Unstructured {
+	namespaced := []*unstructured.Unstructured{}
+	for _, template := range templates {
+		if r.isNamespaced(template) {
+			namespaced = append(namespaced, template)
+		}
+	}
+	return namespaced
+}
+
+// isNamespaced returns true when the kind of the object is namespaced
+func (r *MultiClusterHubReconciler) isNamespaced(obj *unstructured.Unstructured) bool {
+	namespaced, err := r.Client.IsObjectNamespaced(obj)
+	if err != nil {
+		// The kind is not served yet, its resources are namespaced when the chart sets a namespace
+		return obj.GetNamespace() != ""
+	}
+	return namespaced
+}
+
+/*
+ensureRestrictedBundle publishes the resources of a bundle in ConfigMaps of the hub namespace for an admin to apply,
+and reports the bundle in the hub status with the resources that are not present yet. It returns the missing
+resources.
+*/
+func (r *MultiClusterHubReconciler) ensureRestrictedBundle(ctx context.Context, m *operatorv1.MultiClusterHub,
+	name string, objs []*unstructured.Unstructured) ([]operatorv1.FailingObjectReference, error) {
+	configMaps, err := restrictedBundleConfigMaps(m, name, objs)
+	if err != nil {
+		return nil, err
+	}
+
+	force := true
+	published := map[string]bool{}
+	for _, cm := range configMaps {
+		if err := controllerutil.SetControllerReference(m, cm, r.Scheme); err != nil {
+			return nil, err
+		}
+		if err := r.Client.Patch(ctx, cm, client.Apply,
+			&client.PatchOptions{Force: &force, FieldManager: "multiclusterhub-operator"}); err != nil {
+			return nil, fmt.Errorf("failed to publish %s: %w", cm.Name, err)
+		}
+		published[cm.Name] = true
+	}
+	if err := r.deleteRestrictedBundleConfigMaps(ctx, m, name, published); err != nil {
+		return nil, err
+	}
+
+	bundle := operatorv1.RestrictedModeBundle{Name: name, Missing: r.missingResources(ctx, objs)}
+	for _, cm := range configMaps {
+		bundle.ConfigMaps = append(bundle.ConfigMaps, cm.Name)
+	}
+	setRestrictedBundle(m, bundle)
+	return bundle.Missing, nil
+}
+
+// ensureNoRestrictedBundle removes the ConfigMaps of a bundle and the bundle from the hub status
+func (r *MultiClusterHubReconciler) ensureNoRestrictedBundle(ctx context.Context, m *operatorv1.MultiClusterHub,
+	name string) error {
+	if restrictedBundle(m, name) == nil {
+		return nil
+	}
+	if err := r.deleteRestrictedBundleConfigMaps(ctx, m, name, nil); err != nil {
+		return err
+	}
+	removeRestrictedBundle(m, name)
+	return nil
+}
+
+// deleteRestrictedBundleConfigMaps deletes the ConfigMaps of a bundle that are not kept
+func (r *MultiClusterHubReconciler) deleteRestrictedBundleConfigMaps(ctx context.Context,
+	m *operatorv1.MultiClusterHub, name string, keep map[string]bool) error {
+	existing := &corev1.ConfigMapList{}
+	if err := r.Client.List(ctx, existing, client.InNamespace(m.GetNamespace()),
+		client.MatchingLabels{restrictedBundleLabel: name}); err != nil {
+		return err
+	}
+	for i := range existing.Items {
+		if keep[existing.Items[i].Name] {
+			continue
+		}
+		if err := r.Client.Delete(ctx, &existing.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+restrictedBundleConfigMaps returns the ConfigMaps holding the resources of a bundle, one YAML document per key. The
+resources are split over several ConfigMaps when they do not fit in one.
+*/
+func restrictedBundleConfigMaps(m *operatorv1.MultiClusterHub, name string, objs []*unstructured.Unstructured) (
+	[]*corev1.ConfigMap, error) {
+	docs := map[string]string{}
+	for _, obj := range objs {
+		doc, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		docs[restrictedBundleKey(obj)] = "---\n" + string(doc)
+	}
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	configMaps := []*corev1.ConfigMap{}
+	var current *corev1.ConfigMap
+	size := 0
+	for _, key := range keys {
+		if current == nil || (size > 0 && size+len(docs[key]) > restrictedBundleSize) {
+			cmName := restrictedBundlePrefix + name
+			if len(configMaps) > 0 {
+				cmName = fmt.Sprintf("%s-%d", cmName, len(configMaps))
+			}
+			current = &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      cmName,
+					Namespace: m.GetNamespace(),
+					Labels:    map[string]string{restrictedBundleLabel: name},
+				},
+				Data: map[string]string{},
+			}
+			configMaps = append(configMaps, current)
+			size = 0
+		}
+		current.Data[key] = docs[key]
+		size += len(docs[key])
+	}
+	return configMaps, nil
+}
+
+// restrictedBundleKey returns the ConfigMap key of a resource: its kind, namespace and name
+func restrictedBundleKey(obj *unstructured.Unstructured) string {
+	parts := []string{strings.ToLower(obj.GetKind())}
+	if obj.GetNamespace() != "" {
+		parts = append(parts, obj.GetNamespace())
+	}
+	parts = append(parts, obj.GetName())
+	return bundleKeyChars.ReplaceAllString(strings.Join(parts, "_"), "-") + ".yaml"
+}
+
+// apiReader returns the reader of the objects read from the API server, the client when none is set
+func (r *MultiClusterHubReconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+/*
+missingResources returns the resources that are not present in the cluster, that lack a label of the bundle or that
+the operator cannot read. The resources are read from the API server, so that checking a bundle only needs get
+permissions and starts no informer for its kinds.
+*/
+func (r *MultiClusterHubReconciler) missingResources(ctx context.Context,
+	objs []*unstructured.Unstructured) []operatorv1.FailingObjectReference {
+	missing := []operatorv1.FailingObjectReference{}
+	for _, obj := range objs {
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		err := r.apiReader().Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()},
+			existing)
+		if err == nil && hasLabels(existing.GetLabels(), obj.GetLabels()) {
+			continue
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			r.Log.Info("Unable to verify a resource of the restricted mode bundle", "Kind", obj.GetKind(),
+				"Name", obj.GetName(), "Error", err.Error())
+		}
+		missing = append(missing, operatorv1.FailingObjectReference{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		})
+	}
+	return missing
+}
+
+// hasLabels returns true when the labels include all the wanted labels
+func hasLabels(labels, wanted map[string]string) bool {
+	for k, v := range wanted {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// restrictedNamespaceObject returns a namespace as an unstructured object of a bundle
+func restrictedNamespaceObject(ns *corev1.Namespace) (*unstructured.Unstructured, error) {
+	ns = ns.DeepCopy()
+	ns.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ns)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "spec")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return u, nil
+}
+
+// restrictedObject returns a typed object as an unstructured object of a bundle, with its kind set from the scheme
+func (r *MultiClusterHubReconciler) restrictedObject(obj client.Object) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return u, nil
+}
+
+// restrictedObjectExists returns true when the object is present, read from the API server
+func (r *MultiClusterHubReconciler) restrictedObjectExists(ctx context.Context,
+	obj *unstructured.Unstructured) (bool, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := r.apiReader().Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, existing)
+	if errors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+/*
+ensureRestrictedMultiClusterEngine publishes the multicluster engine in the multiclusterengine bundle instead of
+installing it: the resources of its OLM installation and the MultiClusterEngine. It waits until they are present, then
+copies the image pull secret into the target namespace of the MultiClusterEngine. The OLM migration is left to the
+admin.
+*/
+func (r *MultiClusterHubReconciler) ensureRestrictedMultiClusterEngine(ctx context.Context,
+	m *operatorv1.MultiClusterHub) (ctrl.Result, error) {
+	objs, err := r.restrictedMCEInstallation(ctx, m)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	mce, err := r.restrictedMCE(ctx, m)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	mceObj, err := r.restrictedObject(mce)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	missing, err := r.ensureRestrictedBundle(ctx, m, restrictedMCEBundle, append(objs, mceObj))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(missing) > 0 {
+		r.Log.Info("Waiting for the multicluster engine resources of the restricted mode bundle",
+			"Missing", len(missing))
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
+	return r.ensurePullSecret(m, mce.Spec.TargetNamespace)
+}
+
+/*
+restrictedMCEInstallation returns the resources that install the multicluster engine with the OLM version in use: its
+namespace, and the OperatorGroup and Subscription, or the installer ServiceAccount, ClusterRoleBinding and
+ClusterExtension. None are returned when the MCE is not installed through OLM, or is installed by others.
+*/
+func (r *MultiClusterHubReconciler) restrictedMCEInstallation(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	[]*unstructured.Unstructured, error) {
+	operandNs := multiclusterengine.OperandNamespace()
+	installation := []client.Object{}
+	switch r.mceOLMVersion(m) {
+	case "v0":
+		sub, err := v0.GetManagedMCESubscription(ctx, r.Client)
+		if err != nil && !apimeta.IsNoMatchError(err) {
+			return nil, err
+		}
+		if sub != nil && !v0.CreatedByMCH(sub, m) {
+			return nil, nil
+		}
+		subConfig, overrides, ctlSrc, err := r.mceSubscriptionSettings(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		installation = append(installation, multiclusterengine.Namespace(), v0.OperatorGroup(operandNs),
+			v0.RenderSubscription(v0.NewSubscription(m, subConfig, overrides), subConfig, overrides, ctlSrc))
+	case "v1":
+		ce, err := v1.GetManagedMCEClusterExtension(ctx, r.Client)
+		if err != nil && !apimeta.IsNoMatchError(err) {
+			return nil, err
+		}
+		if ce != nil && !v1.CreatedByMCH(ce, m) {
+			return nil, nil
+		}
+		overrides, err := v1.GetAnnotationOverrides(m)
+		if err != nil {
+			return nil, err
+		}
+		calcCE := v1.RenderClusterExtension(v1.NewClusterExtension(m), m)
+		v1.ApplyAnnotationOverrides(calcCE, overrides)
+		installation = append(installation, multiclusterengine.Namespace(), v1.ServiceAccount(operandNs),
+			v1.ClusterRoleBinding(operandNs), calcCE)
+	}
+
+	objs := make([]*unstructured.Unstructured, 0, len(installation))
+	for _, obj := range installation {
+		u, err := r.restrictedObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+/*
+restrictedMCE returns the MultiClusterEngine configured from the hub: the one present, labeled as managed by the hub
+when it is not yet, or a new one targeting the operand namespace.
+*/
+func (r *MultiClusterHubReconciler) restrictedMCE(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	*mcev1.MultiClusterEngine, error) {
+	mces := &mcev1.MultiClusterEngineList{}
+	if err := r.Client.List(ctx, mces); err != nil && !apimeta.IsNoMatchError(err) {
+		return nil, err
+	}
+	if len(mces.Items) > 1 {
+		return nil, fmt.Errorf("multiple MCEs found. Only one MCE is supported")
+	}
+	if len(mces.Items) == 0 {
+		return multiclusterengine.NewMultiClusterEngine(m, multiclusterengine.OperandNamespace()), nil
+	}
+
+	mce := multiclusterengine.RenderMultiClusterEngine(&mces.Items[0], m)
+	labels := mce.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[multiclusterengineutils.MCEManagedByLabel] = "true"
+	mce.ObjectMeta = metav1.ObjectMeta{Name: mce.Name, Labels: labels, Annotations: mce.Annotations}
+	if mce.Spec.TargetNamespace == "" {
+		mce.Spec.TargetNamespace = multiclusterengine.OperandNamespace()
+	}
+	return mce, nil
+}
+
+/*
+ensureRestrictedLocalCluster publishes the configuration of the local-cluster in the local-cluster bundle: its
+KlusterletAddonConfig, and the labels of spec.localCluster and the install namespaces of its add-ons once the
+ManagedCluster and the ManagedClusterAddOns are created by the multicluster engine. The hub does not wait for the
+bundle, the resources that are not applied are reported in the hub status.
+*/
+func (r *MultiClusterHubReconciler) ensureRestrictedLocalCluster(ctx context.Context,
+	m *operatorv1.MultiClusterHub) error {
+	if m.Spec.DisableHubSelfManagement {
+		return r.ensureNoRestrictedBundle(ctx, m, restrictedLocalClusterBundle)
+	}
+
+	kac := getKlusterletAddonConfig(m)
+	utils.AddInstallerLabel(kac, m.GetName(), m.GetNamespace())
+	objs := []*unstructured.Unstructured{kac}
+
+	// Labels removed from the spec are removed by applying the bundle again
+	mc := &unstructured.Unstructured{}
+	mc.SetAPIVersion("cluster.open-cluster-management.io/v1")
+	mc.SetKind("ManagedCluster")
+	mc.SetName(m.Spec.LocalClusterName)
+	exists, err := r.restrictedObjectExists(ctx, mc)
+	if err != nil {
+		return err
+	}
+	if exists {
+		labels := localClusterLabels(m)
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			mc.SetLabels(labels)
+			mc.SetAnnotations(map[string]string{AnnotationLocalClusterLabels: strings.Join(keys, ",")})
+		}
+		objs = append(objs, mc)
+	}
+
+	for _, addon := range localClusterAddons {
+		cfg := localClusterAddonConfig(m, addon)
+		if cfg == nil || cfg.InstallNamespace == "" {
+			continue
+		}
+		for _, name := range localClusterAddonNames[addon] {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("addon.open-cluster-management.io/v1alpha1")
+			obj.SetKind("ManagedClusterAddOn")
+			obj.SetName(name)
+			obj.SetNamespace(m.Spec.LocalClusterName)
+			exists, err := r.restrictedObjectExists(ctx, obj)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				continue
+			}
+			if err := unstructured.SetNestedField(obj.Object, cfg.InstallNamespace, "spec",
+				"installNamespace"); err != nil {
+				return err
+			}
+			objs = append(objs, obj)
+		}
+	}
+
+	missing, err := r.ensureRestrictedBundle(ctx, m, restrictedLocalClusterBundle, objs)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		r.Log.Info("Resources of the restricted mode bundle are not applied", "Bundle", restrictedLocalClusterBundle,
+			"Missing", len(missing))
+	}
+	return nil
+}
+
+/*
+ensureRestrictedNamespaces publishes the component namespaces in the namespaces bundle and ensures the pull secret in
+the namespaces that are present. It waits until all namespaces are present.
+*/
+func (r *MultiClusterHubReconciler) ensureRestrictedNamespaces(ctx context.Context, m *operatorv1.MultiClusterHub,
+	namespaces []*corev1.Namespace) (ctrl.Result, error) {
+	objs := make([]*unstructured.Unstructured, 0, len(namespaces))
+	for _, ns := range namespaces {
+		obj, err := restrictedNamespaceObject(ns)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		objs = append(objs, obj)
+	}
+
+	missing, err := r.ensureRestrictedBundle(ctx, m, restrictedNamespacesBundle, objs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(missing) > 0 {
+		r.Log.Info("Waiting for the component namespaces of the restricted mode bundle", "Missing", len(missing))
+		return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+	}
+
+	for _, ns := range namespaces {
+		if result, err := r.ensurePullSecret(m, ns.Name); result != (ctrl.Result{}) || err != nil {
+			return result, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+/*
+verifyConsolePlugin reports the console operator configuration in the console bundle when the acm plugin is not
+enabled. In restricted mode an admin adds the plugin to spec.plugins of consoles.operator.openshift.io/cluster.
+*/
+func (r *MultiClusterHubReconciler) verifyConsolePlugin(ctx context.Context, m *operatorv1.MultiClusterHub) {
+	console := &consolev1.Console{}
+	err := r.apiReader().Get(ctx, types.NamespacedName{Name: "cluster"}, console)
+	if err == nil && utils.Contains(console.Spec.Plugins, "acm") {
+		return
+	}
+
+	bundle := restrictedBundle(m, operatorv1.Console)
+	if bundle == nil {
+		bundle = &operatorv1.RestrictedModeBundle{Name: operatorv1.Console}
+	}
+	bundle.Missing = append(bundle.Missing, operatorv1.FailingObjectReference{
+		APIVersion: consolev1.GroupVersion.String(),
+		Kind:       "Console",
+		Name:       "cluster",
+	})
+	setRestrictedBundle(m, *bundle)
+}
+
+/*
+ensureRestrictedWebhooks publishes the webhook resources the operator configures at startup outside restricted mode:
+the ValidatingWebhookConfiguration of the hub, and the MultiClusterHub CRD pointed at the conversion webhook with v2
+served. The CRD is reported as missing until its conversion is configured.
+*/
+func (r *MultiClusterHubReconciler) ensureRestrictedWebhooks(ctx context.Context, m *operatorv1.MultiClusterHub) error {
+	namespace, err := utils.OperatorNamespace()
+	if err != nil {
+		return err
+	}
+	crd := &apixv1.CustomResourceDefinition{}
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: mchCRDName}, crd); err != nil {
+		return fmt.Errorf("failed to get the %s CRD: %w", mchCRDName, err)
+	}
+
+	// The webhook configuration is removed with the CRD
+	webhook := operatorv1.ValidatingWebhook(namespace)
+	webhook.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "apiextensions.k8s.io/v1",
+		Kind:       "CustomResourceDefinition",
+		Name:       crd.Name,
+		UID:        crd.UID,
+	}})
+	webhookObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(webhook)
+	if err != nil {
+		return err
+	}
+	crdObj, err := restrictedCRDObject(crd, namespace)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.ensureRestrictedBundle(ctx, m, restrictedWebhooksBundle,
+		[]*unstructured.Unstructured{{Object: webhookObj}, crdObj}); err != nil {
+		return err
+	}
+	if crdConversionConfigured(crd, namespace) {
+		return nil
+	}
+	bundle := restrictedBundle(m, restrictedWebhooksBundle)
+	bundle.Missing = append(bundle.Missing, operatorv1.FailingObjectReference{
+		APIVersion: crdObj.GetAPIVersion(),
+		Kind:       crdObj.GetKind(),
+		Name:       crdObj.GetName(),
+	})
+	setRestrictedBundle(m, *bundle)
+	return nil
+}
+
+// crdConversionConfigured returns true when the CRD converts through the conversion webhook and serves v2
+func crdConversionConfigured(crd *apixv1.CustomResourceDefinition, namespace string) bool {
+	desired := operatorv1.CRDConversion(namespace)
+	current := crd.Spec.Conversion
+	if current == nil || current.Strategy != desired.Strategy || current.Webhook == nil ||
+		current.Webhook.ClientConfig == nil ||
+		!equality.Semantic.DeepEqual(current.Webhook.ClientConfig.Service, desired.Webhook.ClientConfig.Service) {
+		return false
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Name == operatorv2.GroupVersion.Version {
+			return version.Served
+		}
+	}
+	return false
+}
+
+/*
+restrictedCRDObject returns the MultiClusterHub CRD as an object of a bundle, with the conversion webhook configured
+and v2 served. The CA bundle is kept, the service CA operator injects it.
+*/
+func restrictedCRDObject(crd *apixv1.CustomResourceDefinition, namespace string) (*unstructured.Unstructured, error) {
+	crd = crd.DeepCopy()
+	crd.TypeMeta = metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"}
+	crd.ObjectMeta = metav1.ObjectMeta{
+		Name:        crd.Name,
+		Labels:      crd.Labels,
+		Annotations: crd.Annotations,
+	}
+	if crd.Annotations == nil {
+		crd.Annotations = map[string]string{}
+	}
+	crd.Annotations[injectCABundleAnnotation] = "true"
+
+	conversion := operatorv1.CRDConversion(namespace)
+	if current := crd.Spec.Conversion; current != nil && current.Webhook != nil && current.Webhook.ClientConfig != nil {
+		conversion.Webhook.ClientConfig.CABundle = current.Webhook.ClientConfig.CABundle
+	}
+	crd.Spec.Conversion = conversion
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == operatorv2.GroupVersion.Version {
+			crd.Spec.Versions[i].Served = true
+		}
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return u, nil
+}
+
+// restrictedBundle returns a copy of a bundle reported in the hub status, nil if it is not reported
+func restrictedBundle(m *operatorv1.MultiClusterHub, name string) *operatorv1.RestrictedModeBundle {
+	if m.Status.Restricted == nil {
+		return nil
+	}
+	for _, bundle := range m.Status.Restricted.Bundles {
+		if bundle.Name == name {
+			return bundle.DeepCopy()
+		}
+	}
+	return nil
+}
+
+// setRestrictedBundle reports a bundle in the hub status
+func setRestrictedBundle(m *operatorv1.MultiClusterHub, bundle operatorv1.RestrictedModeBundle) {
+	removeRestrictedBundle(m, bundle.Name)
+	if m.Status.Restricted == nil {
+		m.Status.Restricted = &operatorv1.RestrictedModeStatus{}
+	}
+	bundles := append(m.Status.Restricted.Bundles, bundle)
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].Name < bundles[j].Name })
+	m.Status.Restricted.Bundles = bundles
+}
+
+// removeRestrictedBundle removes a bundle from the hub status
+func removeRestrictedBundle(m *operatorv1.MultiClusterHub, name string) {
+	if m.Status.Restricted == nil {
+		return
+	}
+	bundles := []operatorv1.RestrictedModeBundle{}
+	for _, bundle := range m.Status.Restricted.Bundles {
+		if bundle.Name != name {
+			bundles = append(bundles, bundle)
+		}
+	}
+	m.Status.Restricted.Bundles = bundles
+}
+
+// restrictedStatus returns the restricted mode status of the hub, none when the operator is not restricted
+func (r *MultiClusterHubReconciler) restrictedStatus(m *operatorv1.MultiClusterHub) *operatorv1.RestrictedModeStatus {
+	if !r.RestrictedMode {
+		return nil
+	}
+	return m.Status.Restricted
+}
@@ -0,0 +1,339 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	mcev1 "github.com/stolostron/backplane-operator/api/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_splitRestrictedTemplates(t *testing.T) {
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		Scheme: scheme.Scheme,
+	}
+	hub := &operatorv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "open-cluster-management"}}
+
+	applied, external := r.splitRestrictedTemplates(hub, []*unstructured.Unstructured{
+		testObject("apps/v1", "Deployment", "open-cluster-management", "console"),
+		testObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "console"),
+		testObject("v1", "ServiceAccount", "other", "console"),
+		// Kinds that are not served are cluster-scoped without a namespace
+		testObject("console.openshift.io/v1", "ConsolePlugin", "", "acm"),
+	})
+	names := func(objs []*unstructured.Unstructured) []string {
+		s := []string{}
+		for _, obj := range objs {
+			s = append(s, obj.GetKind())
+		}
+		return s
+	}
+	if got := names(applied); !reflect.DeepEqual(got, []string{"Deployment"}) {
+		t.Errorf("applied = %v", got)
+	}
+	if got := names(external); !reflect.DeepEqual(got, []string{"ClusterRole", "ServiceAccount", "ConsolePlugin"}) {
+		t.Errorf("external = %v", got)
+	}
+}
+
+func Test_restrictedBundleConfigMaps(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "open-cluster-management"}}
+
+	large := testObject("v1", "ConfigMap", "other", "large")
+	large.Object["data"] = map[string]interface{}{"key": strings.Repeat("x", restrictedBundleSize-100)}
+	configMaps, err := restrictedBundleConfigMaps(hub, operatorv1.Console, []*unstructured.Unstructured{
+		testObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "open-cluster-management:console"),
+		large,
+	})
+	if err != nil {
+		t.Fatalf("restrictedBundleConfigMaps() error = %v", err)
+	}
+	if len(configMaps) != 2 {
+		t.Fatalf("expected the bundle to be split into 2 ConfigMaps, got %d", len(configMaps))
+	}
+	if configMaps[0].Name != "multiclusterhub-restricted-console" ||
+		configMaps[1].Name != "multiclusterhub-restricted-console-1" {
+		t.Errorf("unexpected ConfigMap names %s, %s", configMaps[0].Name, configMaps[1].Name)
+	}
+	doc, ok := configMaps[0].Data["clusterrole_open-cluster-management-console.yaml"]
+	if !ok || !strings.HasPrefix(doc, "---\n") || !strings.Contains(doc, "kind: ClusterRole") {
+		t.Errorf("unexpected ConfigMap data %v", configMaps[0].Data)
+	}
+	if _, ok := configMaps[1].Data["configmap_other_large.yaml"]; !ok {
+		t.Errorf("unexpected ConfigMap data keys in %s", configMaps[1].Name)
+	}
+	if configMaps[1].Labels[restrictedBundleLabel] != operatorv1.Console {
+		t.Errorf("expected the bundle label on %s", configMaps[1].Name)
+	}
+}
+
+func Test_ensureRestrictedBundle(t *testing.T) {
+	ctx := context.TODO()
+	hub := &operatorv1.MultiClusterHub{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "operator.open-cluster-management.io/v1", Kind: "MultiClusterHub"},
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management", UID: "uid"},
+	}
+	bundleLabels := map[string]string{"installer.name": "multiclusterhub"}
+	applied := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "applied", Labels: bundleLabels}}
+	unlabeled := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}}
+	stale := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name: "multiclusterhub-restricted-search-1", Namespace: hub.Namespace,
+		Labels: map[string]string{restrictedBundleLabel: operatorv1.Search},
+	}}
+
+	s := testScheme(t, scheme.AddToScheme, operatorv1.AddToScheme)
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(s).WithObjects(applied, unlabeled, stale).Build(),
+		Scheme: s,
+		Log:    clog.Log.WithName("test"),
+	}
+
+	missing, err := r.ensureRestrictedBundle(ctx, hub, operatorv1.Search, []*unstructured.Unstructured{
+		withLabels(testObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "applied"), bundleLabels),
+		withLabels(testObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "unlabeled"), bundleLabels),
+		withLabels(testObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "absent"), bundleLabels),
+	})
+	if err != nil {
+		t.Fatalf("ensureRestrictedBundle() error = %v", err)
+	}
+	want := []operatorv1.RestrictedModeBundle{{
+		Name:       operatorv1.Search,
+		ConfigMaps: []string{"multiclusterhub-restricted-search"},
+		Missing: []operatorv1.FailingObjectReference{
+			{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "unlabeled"},
+			{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "absent"},
+		},
+	}}
+	if len(missing) != 2 || hub.Status.Restricted == nil || !reflect.DeepEqual(hub.Status.Restricted.Bundles, want) {
+		t.Fatalf("Restricted = %v, want bundles %v", hub.Status.Restricted, want)
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.Client.List(ctx, configMaps, client.MatchingLabels{restrictedBundleLabel: operatorv1.Search}); err != nil {
+		t.Fatalf("failed to list the bundle ConfigMaps: %v", err)
+	}
+	if len(configMaps.Items) != 1 || configMaps.Items[0].Name != "multiclusterhub-restricted-search" ||
+		len(configMaps.Items[0].Data) != 3 {
+		t.Errorf("expected the stale ConfigMap to be replaced by the bundle, got %v", configMaps.Items)
+	}
+
+	if err := r.ensureNoRestrictedBundle(ctx, hub, operatorv1.Search); err != nil {
+		t.Fatalf("ensureNoRestrictedBundle() error = %v", err)
+	}
+	if err := r.Client.List(ctx, configMaps, client.MatchingLabels{restrictedBundleLabel: operatorv1.Search}); err != nil {
+		t.Fatalf("failed to list the bundle ConfigMaps: %v", err)
+	}
+	if len(configMaps.Items) != 0 || len(hub.Status.Restricted.Bundles) != 0 {
+		t.Errorf("expected the bundle to be removed, got %v and %v", configMaps.Items, hub.Status.Restricted)
+	}
+}
+
+func Test_ensureRestrictedWebhooks(t *testing.T) {
+	ctx := context.TODO()
+	t.Setenv("POD_NAMESPACE", "open-cluster-management")
+	hub := &operatorv1.MultiClusterHub{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "operator.open-cluster-management.io/v1", Kind: "MultiClusterHub"},
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management", UID: "uid"},
+	}
+	crd := &apixv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: mchCRDName, UID: "crd-uid"},
+		Spec: apixv1.CustomResourceDefinitionSpec{
+			Versions: []apixv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v2", Served: false},
+			},
+		},
+	}
+
+	s := testScheme(t, scheme.AddToScheme, apixv1.AddToScheme, operatorv1.AddToScheme)
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(s).WithObjects(crd).Build(),
+		Scheme: s,
+		Log:    clog.Log.WithName("test"),
+	}
+
+	if err := r.ensureRestrictedWebhooks(ctx, hub); err != nil {
+		t.Fatalf("ensureRestrictedWebhooks() error = %v", err)
+	}
+	bundle := restrictedBundle(hub, restrictedWebhooksBundle)
+	if bundle == nil || len(bundle.Missing) != 2 {
+		t.Fatalf("expected the webhook configuration and the CRD conversion to be missing, got %v", bundle)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: restrictedBundlePrefix + restrictedWebhooksBundle,
+		Namespace: hub.Namespace}, cm); err != nil {
+		t.Fatalf("failed to get the bundle ConfigMap: %v", err)
+	}
+	published := cm.Data["customresourcedefinition_"+mchCRDName+".yaml"]
+	if !strings.Contains(published, "strategy: Webhook") || strings.Contains(published, "resourceVersion") {
+		t.Errorf("expected the CRD to be published with its conversion webhook, got %s", published)
+	}
+
+	// Once an admin applies the bundle nothing is missing
+	crd.Spec.Conversion = operatorv1.CRDConversion(hub.Namespace)
+	crd.Spec.Versions[1].Served = true
+	crd.ResourceVersion = ""
+	webhook := operatorv1.ValidatingWebhook(hub.Namespace)
+	webhook.TypeMeta = metav1.TypeMeta{}
+	r.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(crd, webhook).Build()
+	if err := r.ensureRestrictedWebhooks(ctx, hub); err != nil {
+		t.Fatalf("ensureRestrictedWebhooks() error = %v", err)
+	}
+	if bundle := restrictedBundle(hub, restrictedWebhooksBundle); len(bundle.Missing) != 0 {
+		t.Errorf("expected no missing resources, got %v", bundle.Missing)
+	}
+}
+
+func Test_ensureRestrictedMultiClusterEngine(t *testing.T) {
+	ctx := context.TODO()
+	hub := &operatorv1.MultiClusterHub{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "operator.open-cluster-management.io/v1", Kind: "MultiClusterHub"},
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management", UID: "uid"},
+	}
+
+	s := testScheme(t, scheme.AddToScheme, operatorv1.AddToScheme, mcev1.AddToScheme, ocv1.AddToScheme)
+	r := &MultiClusterHubReconciler{
+		Client:     fake.NewClientBuilder().WithScheme(s).Build(),
+		Scheme:     s,
+		Log:        clog.Log.WithName("test"),
+		OLMVersion: "v1",
+	}
+
+	// The installation is published instead of created, the hub waits for it
+	result, err := r.ensureRestrictedMultiClusterEngine(ctx, hub)
+	if err != nil {
+		t.Fatalf("ensureRestrictedMultiClusterEngine() error = %v", err)
+	}
+	if result.RequeueAfter != resyncPeriod {
+		t.Errorf("ensureRestrictedMultiClusterEngine() = %v, want a requeue while the bundle is not applied", result)
+	}
+	bundle := restrictedBundle(hub, restrictedMCEBundle)
+	if bundle == nil || len(bundle.Missing) != 5 {
+		t.Fatalf("expected the namespace, installer RBAC, ClusterExtension and MCE to be missing, got %v", bundle)
+	}
+	ces := &ocv1.ClusterExtensionList{}
+	if err := r.Client.List(ctx, ces); err != nil || len(ces.Items) != 0 {
+		t.Errorf("expected no ClusterExtension to be created, got %v (%v)", ces.Items, err)
+	}
+
+	// Once an admin applies the bundle the hub proceeds
+	cm := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: restrictedBundlePrefix + restrictedMCEBundle,
+		Namespace: hub.Namespace}, cm); err != nil {
+		t.Fatalf("failed to get the bundle ConfigMap: %v", err)
+	}
+	for key, doc := range cm.Data {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
+			t.Fatalf("failed to read %s: %v", key, err)
+		}
+		if err := r.Client.Create(ctx, obj); err != nil {
+			t.Fatalf("failed to apply %s: %v", key, err)
+		}
+	}
+	result, err = r.ensureRestrictedMultiClusterEngine(ctx, hub)
+	if err != nil || result != (ctrl.Result{}) {
+		t.Errorf("ensureRestrictedMultiClusterEngine() = %v, %v, want no requeue once applied", result, err)
+	}
+	if bundle := restrictedBundle(hub, restrictedMCEBundle); len(bundle.Missing) != 0 {
+		t.Errorf("expected no missing resources, got %v", bundle.Missing)
+	}
+}
+
+func Test_ensureRestrictedLocalCluster(t *testing.T) {
+	ctx := context.TODO()
+	hub := &operatorv1.MultiClusterHub{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "operator.open-cluster-management.io/v1", Kind: "MultiClusterHub"},
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management", UID: "uid"},
+		Spec: operatorv1.MultiClusterHubSpec{
+			LocalClusterName: "local-cluster",
+			LocalCluster: &operatorv1.LocalClusterConfig{
+				Labels:          map[string]string{"env": "hub"},
+				SearchCollector: &operatorv1.LocalClusterAddonConfig{InstallNamespace: "search-agent"},
+				PolicyController: &operatorv1.LocalClusterAddonConfig{
+					InstallNamespace: "policy-agent",
+				},
+			},
+		},
+	}
+	mc := testObject("cluster.open-cluster-management.io/v1", "ManagedCluster", "", "local-cluster")
+	addon := testObject("addon.open-cluster-management.io/v1alpha1", "ManagedClusterAddOn", "local-cluster",
+		"search-collector")
+
+	s := testScheme(t, scheme.AddToScheme, operatorv1.AddToScheme)
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(s).WithObjects(mc, addon).Build(),
+		Scheme: s,
+		Log:    clog.Log.WithName("test"),
+	}
+
+	if err := r.ensureRestrictedLocalCluster(ctx, hub); err != nil {
+		t.Fatalf("ensureRestrictedLocalCluster() error = %v", err)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: restrictedBundlePrefix + restrictedLocalClusterBundle,
+		Namespace: hub.Namespace}, cm); err != nil {
+		t.Fatalf("failed to get the bundle ConfigMap: %v", err)
+	}
+	// The add-ons that are not created yet are not published
+	keys := []string{}
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	want := []string{
+		"klusterletaddonconfig_local-cluster_local-cluster.yaml",
+		"managedcluster_local-cluster.yaml",
+		"managedclusteraddon_local-cluster_search-collector.yaml",
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("published %v, want %v", keys, want)
+	}
+	if published := cm.Data["managedcluster_local-cluster.yaml"]; !strings.Contains(published, "env: hub") {
+		t.Errorf("expected the ManagedCluster labels to be published, got %s", published)
+	}
+
+	// The ManagedCluster is not changed by the operator, it is missing until its labels are applied
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(mc.GroupVersionKind())
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: "local-cluster"}, got); err != nil {
+		t.Fatalf("failed to get the ManagedCluster: %v", err)
+	}
+	if len(got.GetLabels()) != 0 {
+		t.Errorf("expected the ManagedCluster to be left to the admin, got labels %v", got.GetLabels())
+	}
+	bundle := restrictedBundle(hub, restrictedLocalClusterBundle)
+	if bundle == nil || len(bundle.Missing) != 2 {
+		t.Errorf("expected the KlusterletAddonConfig and ManagedCluster to be missing, got %v", bundle)
+	}
+
+	// Disabling self management removes the bundle
+	hub.Spec.DisableHubSelfManagement = true
+	if err := r.ensureRestrictedLocalCluster(ctx, hub); err != nil {
+		t.Fatalf("ensureRestrictedLocalCluster() error = %v", err)
+	}
+	if bundle := restrictedBundle(hub, restrictedLocalClusterBundle); bundle != nil {
+		t.Errorf("expected the bundle to be removed, got %v", bundle)
+	}
+}
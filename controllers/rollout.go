@@ -0,0 +1,444 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+	"github.com/stolostron/multiclusterhub-operator/pkg/version"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// rolloutHistoryName is the ConfigMap in the hub namespace that keeps the manifests the component resources had
+	// before they were rolled out to the running release
+	rolloutHistoryName = "multiclusterhub-rollout-history"
+
+	// rolloutTimeout is how long a deployment has to become ready after being rolled out
+	rolloutTimeout = 15 * time.Minute
+
+	// ComponentRolloutBlockedReason is added when a component deployment fails to roll out to a new release
+	ComponentRolloutBlockedReason = "ComponentRolloutBlocked"
+)
+
+// rolloutManifest is the manifest a component resource had before it was rolled out. Resources created by the rollout
+// are only recorded by their identity, they are removed when their component is rolled back.
+type rolloutManifest struct {
+	Component string                 `json:"component"`
+	Created   bool                   `json:"created,omitempty"`
+	Manifest  map[string]interface{} `json:"manifest"`
+}
+
+// rolloutHistory is stored in the rollout history ConfigMap, manifests are keyed by kind, namespace and name
+type rolloutHistory struct {
+	TargetVersion string                      `json:"targetVersion"`
+	Manifests     map[string]*rolloutManifest `json:"manifests,omitempty"`
+}
+
+// canaryRolloutEnabled returns true when the component deployments are rolled out one at a time, which is the case
+// when a highly available hub is upgraded
+func canaryRolloutEnabled(m *operatorv1.MultiClusterHub) bool {
+	return m.EffectiveAvailabilityConfig() != operatorv1.HABasic && m.Status.CurrentVersion != "" &&
+		m.Status.CurrentVersion != version.Version
+}
+
+// rolloutPending returns true while the component deployments are not all rolled out to the running release
+func rolloutPending(m *operatorv1.MultiClusterHub) bool {
+	rollout := m.Status.ComponentRollout
+	return rollout != nil && rollout.TargetVersion == version.Version &&
+		rollout.Phase != operatorv1.ComponentRolloutCompleted
+}
+
+func rolloutManifestKey(obj *unstructured.Unstructured) string {
+	return obj.GroupVersionKind().GroupKind().String() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// deploymentRolledOut returns true when the latest generation of a deployment is observed, all of its replicas are
+// updated and it passes the readiness checks of the hub status
+func deploymentRolledOut(d *appsv1.Deployment) bool {
+	if d.Status.ObservedGeneration < d.Generation {
+		return false
+	}
+	if d.Spec.Replicas != nil && d.Status.UpdatedReplicas < *d.Spec.Replicas {
+		return false
+	}
+	return successfulDeploy(d)
+}
+
+// deploymentProgressDeadlineExceeded returns true when a deployment reports that its rollout stopped progressing
+func deploymentProgressDeadlineExceeded(d *appsv1.Deployment) bool {
+	return progressingDeployCondition(d.Status.Conditions).Reason == "ProgressDeadlineExceeded"
+}
+
+// readRolloutHistory returns the rollout history ConfigMap of the hub and the manifests recorded for the running release
+func (r *MultiClusterHubReconciler) readRolloutHistory(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	*corev1.ConfigMap, *rolloutHistory, error) {
+	history := &rolloutHistory{}
+	cm, err := r.readHistoryConfigMap(ctx, m, rolloutHistoryName, history)
+	if err != nil {
+		return nil, nil, err
+	}
+	if history.TargetVersion != version.Version || history.Manifests == nil {
+		history = &rolloutHistory{TargetVersion: version.Version, Manifests: map[string]*rolloutManifest{}}
+	}
+	return cm, history, nil
+}
+
+// getRolloutDeployment returns a deployment of the rollout, or nil if it does not exist
+func (r *MultiClusterHubReconciler) getRolloutDeployment(ctx context.Context,
+	d operatorv1.RolloutDeployment) (*appsv1.Deployment, error) {
+	dep := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: d.Name, Namespace: d.Namespace}, dep)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return dep, err
+}
+
+/*
+progressRollout advances the rollout of the component deployments to the running release on highly available hubs.
+Once the deployment being rolled out is ready the next one can be updated. If it does not become ready, or a deployment
+rolled out before it stops being ready, the component of the failing deployment is rolled back to its previously
+applied manifests and the rollout is blocked until the rollout-retry annotation is set to a new value.
+*/
+func (r *MultiClusterHubReconciler) progressRollout(ctx context.Context, m *operatorv1.MultiClusterHub) error {
+	r.rolloutHeld = false
+	if !canaryRolloutEnabled(m) {
+		// Deployments are applied at once when the hub is not highly available or not being upgraded
+		if rolloutPending(m) || (m.Status.ComponentRollout != nil &&
+			m.Status.ComponentRollout.TargetVersion != version.Version) {
+			m.Status.ComponentRollout = nil
+		}
+		removeRolloutBlockedCondition(m)
+		return nil
+	}
+
+	rollout := m.Status.ComponentRollout
+	if rollout == nil || rollout.TargetVersion != version.Version {
+		log.Info("Starting the component rollout", "TargetVersion", version.Version)
+		rollout = &operatorv1.ComponentRolloutStatus{
+			TargetVersion: version.Version,
+			Phase:         operatorv1.ComponentRolloutProgressing,
+			LastRetry:     utils.GetRolloutRetry(m),
+		}
+		m.Status.ComponentRollout = rollout
+	}
+
+	if token := utils.GetRolloutRetry(m); token != rollout.LastRetry {
+		rollout.LastRetry = token
+		if rollout.Phase == operatorv1.ComponentRolloutBlocked {
+			log.Info("Retrying the blocked component rollout", "RolledBackComponents", rollout.RolledBackComponents)
+			rollout.Phase = operatorv1.ComponentRolloutProgressing
+			rollout.Message = ""
+			rollout.RolledBackComponents = nil
+			removeRolloutBlockedCondition(m)
+		}
+	}
+	if rollout.Phase == operatorv1.ComponentRolloutBlocked {
+		return nil
+	}
+
+	// A deployment that stops being ready after it was rolled out is a regression of its component
+	for _, d := range rollout.Updated {
+		dep, err := r.getRolloutDeployment(ctx, d)
+		if err != nil {
+			return err
+		}
+		if dep != nil && !successfulDeploy(dep) {
+			return r.blockRollout(ctx, m, d.Component, fmt.Sprintf(
+				"deployment %s/%s is no longer ready after it was rolled out", d.Namespace, d.Name))
+		}
+	}
+
+	current := rollout.Current
+	if current == nil {
+		return nil
+	}
+	dep, err := r.getRolloutDeployment(ctx, *current)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case dep == nil:
+		log.Info("Component deployment removed during its rollout", "Namespace", current.Namespace,
+			"Name", current.Name)
+		rollout.Current = nil
+
+	case deploymentRolledOut(dep):
+		log.Info("Component deployment rolled out", "Component", current.Component, "Namespace",
+			current.Namespace, "Name", current.Name)
+		rollout.Updated = append(rollout.Updated, *current)
+		rollout.Current = nil
+
+	case deploymentProgressDeadlineExceeded(dep) || time.Since(current.StartTime.Time) > rolloutTimeout:
+		return r.blockRollout(ctx, m, current.Component, fmt.Sprintf(
+			"deployment %s/%s did not become ready after it was rolled out", current.Namespace, current.Name))
+	}
+	return nil
+}
+
+/*
+holdRolloutTemplate returns true when a component resource has to keep its current manifest. The resources of a
+component that was rolled back are held until the rollout is retried, and a deployment is held while another deployment
+is being rolled out or the rollout is blocked. Otherwise the current manifest of the resource is added to recorded, to be
+written by recordRolloutManifests so that its component can be rolled back, and a deployment becomes the one being
+rolled out.
+*/
+func (r *MultiClusterHubReconciler) holdRolloutTemplate(ctx context.Context, m *operatorv1.MultiClusterHub,
+	component string, template *unstructured.Unstructured, recorded map[string]*rolloutManifest) (bool, error) {
+	rollout := m.Status.ComponentRollout
+	if rollout == nil || !canaryRolloutEnabled(m) {
+		return false, nil
+	}
+	if rollout.Phase == operatorv1.ComponentRolloutBlocked && utils.Contains(rollout.RolledBackComponents, component) {
+		r.rolloutHeld = true
+		return true, nil
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(template.GroupVersionKind())
+	err := r.Client.Get(ctx, types.NamespacedName{Name: template.GetName(), Namespace: template.GetNamespace()},
+		existing)
+	if meta.IsNoMatchError(err) {
+		// Resources whose kind is not served yet are left to applyTemplate
+		return false, nil
+	}
+	created := errors.IsNotFound(err)
+	if err != nil && !created {
+		return false, err
+	}
+	if !created && existing.GetAnnotations()[utils.AnnotationReleaseVersion] == rollout.TargetVersion {
+		return false, nil
+	}
+
+	// New deployments are not rolled out, they are created right away
+	rolledOut := template.GetKind() == "Deployment" && !created
+	if rolledOut {
+		if current := rollout.Current; current != nil && current.Namespace == template.GetNamespace() &&
+			current.Name == template.GetName() {
+			return false, nil
+		}
+		if rollout.Phase == operatorv1.ComponentRolloutBlocked || rollout.Current != nil {
+			r.rolloutHeld = true
+			return true, nil
+		}
+	}
+
+	if created {
+		recorded[rolloutManifestKey(template)] = &rolloutManifest{Component: component, Created: true,
+			Manifest: manifestIdentity(template).Object}
+	} else {
+		recorded[rolloutManifestKey(template)] = &rolloutManifest{Component: component,
+			Manifest: appliedManifest(existing)}
+	}
+	if !rolledOut {
+		return false, nil
+	}
+
+	log.Info("Rolling out component deployment", "Component", component, "Namespace", existing.GetNamespace(),
+		"Name", existing.GetName(), "TargetVersion", rollout.TargetVersion)
+	rollout.Phase = operatorv1.ComponentRolloutProgressing
+	rollout.Current = &operatorv1.RolloutDeployment{
+		Component: component,
+		Namespace: existing.GetNamespace(),
+		Name:      existing.GetName(),
+		StartTime: metav1.Now(),
+	}
+	return false, nil
+}
+
+/*
+recordRolloutManifests adds the manifests collected by holdRolloutTemplate for a component to the rollout history,
+except those already recorded for the running release. The history is written once, before any resource of the
+component is applied.
+*/
+func (r *MultiClusterHubReconciler) recordRolloutManifests(ctx context.Context, m *operatorv1.MultiClusterHub,
+	recorded map[string]*rolloutManifest) error {
+	if len(recorded) == 0 {
+		return nil
+	}
+	historyCM, history, err := r.readRolloutHistory(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	added := false
+	for key, manifest := range recorded {
+		if _, ok := history.Manifests[key]; !ok {
+			history.Manifests[key] = manifest
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	return r.writeHistoryConfigMap(ctx, m, historyCM, history)
+}
+
+// completeRollout marks the rollout completed once every component deployment was rolled out during a full pass over
+// the hub components
+func (r *MultiClusterHubReconciler) completeRollout(m *operatorv1.MultiClusterHub) {
+	rollout := m.Status.ComponentRollout
+	if !canaryRolloutEnabled(m) || rollout == nil || rollout.Phase != operatorv1.ComponentRolloutProgressing ||
+		rollout.Current != nil || r.rolloutHeld {
+		return
+	}
+	log.Info("Component rollout completed", "TargetVersion", rollout.TargetVersion)
+	rollout.Phase = operatorv1.ComponentRolloutCompleted
+}
+
+// blockRollout rolls back the resources of a component whose rollout failed and blocks the rollout
+func (r *MultiClusterHubReconciler) blockRollout(ctx context.Context, m *operatorv1.MultiClusterHub,
+	component, message string) error {
+	log.Info("Blocking the component rollout", "Component", component, "Reason", message)
+	if err := r.rollbackComponentManifests(ctx, m, component); err != nil {
+		return err
+	}
+
+	rollout := m.Status.ComponentRollout
+	rollout.Phase = operatorv1.ComponentRolloutBlocked
+	rollout.Message = message
+	rollout.Current = nil
+	updated := []operatorv1.RolloutDeployment{}
+	for _, d := range rollout.Updated {
+		if d.Component != component {
+			updated = append(updated, d)
+		}
+	}
+	rollout.Updated = updated
+	if !utils.Contains(rollout.RolledBackComponents, component) {
+		rollout.RolledBackComponents = append(rollout.RolledBackComponents, component)
+	}
+
+	condition := NewHubCondition(operatorv1.Blocked, metav1.ConditionTrue, ComponentRolloutBlockedReason,
+		fmt.Sprintf("Rollout to %s blocked, component %s was rolled back: %s", rollout.TargetVersion, component,
+			message))
+	SetHubCondition(&m.Status, *condition)
+	return nil
+}
+
+/*
+rollbackComponentManifests restores the resources of a component to the manifests recorded before the rollout. Resources
+the rollout created for the component are removed.
+*/
+func (r *MultiClusterHubReconciler) rollbackComponentManifests(ctx context.Context, m *operatorv1.MultiClusterHub,
+	component string) error {
+	_, history, err := r.readRolloutHistory(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{}
+	for key, saved := range history.Manifests {
+		if saved.Component == component {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		saved := history.Manifests[key]
+		previous := &unstructured.Unstructured{Object: saved.Manifest}
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(previous.GroupVersionKind())
+		err := r.Client.Get(ctx, types.NamespacedName{Name: previous.GetName(), Namespace: previous.GetNamespace()},
+			existing)
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if saved.Created {
+			if err := r.Client.Delete(ctx, existing); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			log.Info("Removed component resource created by the rollout", "Component", component,
+				"Kind", existing.GetKind(), "Namespace", existing.GetNamespace(), "Name", existing.GetName())
+			continue
+		}
+
+		existing.SetLabels(previous.GetLabels())
+		existing.SetAnnotations(previous.GetAnnotations())
+		for field := range existing.Object {
+			if _, ok := previous.Object[field]; !ok && !rolloutIdentityFields[field] {
+				delete(existing.Object, field)
+			}
+		}
+		for field, value := range previous.Object {
+			if !rolloutIdentityFields[field] {
+				existing.Object[field] = value
+			}
+		}
+		if err := r.Client.Update(ctx, existing); err != nil {
+			return err
+		}
+		log.Info("Rolled back component resource", "Component", component, "Kind", existing.GetKind(),
+			"Namespace", existing.GetNamespace(), "Name", existing.GetName())
+	}
+	return nil
+}
+
+// rolloutIdentityFields are the top-level fields of a resource that are not restored by a rollback
+var rolloutIdentityFields = map[string]bool{"apiVersion": true, "kind": true, "metadata": true, "status": true}
+
+// manifestIdentity returns the kind, namespace and name of a resource
+func manifestIdentity(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	manifest := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	manifest.SetAPIVersion(obj.GetAPIVersion())
+	manifest.SetKind(obj.GetKind())
+	manifest.SetNamespace(obj.GetNamespace())
+	manifest.SetName(obj.GetName())
+	return manifest
+}
+
+// appliedManifest returns the applied part of a resource: its identity, labels, annotations and every top-level field
+// other than its metadata and status, such as the spec of a deployment or the data of a ConfigMap
+func appliedManifest(obj *unstructured.Unstructured) map[string]interface{} {
+	manifest := manifestIdentity(obj)
+	manifest.SetLabels(obj.GetLabels())
+	manifest.SetAnnotations(obj.GetAnnotations())
+	for field, value := range obj.Object {
+		if !rolloutIdentityFields[field] {
+			manifest.Object[field] = runtime.DeepCopyJSONValue(value)
+		}
+	}
+	return manifest.Object
+}
+
+// removeRolloutBlockedCondition removes the Blocked condition set by a failed component rollout
+func removeRolloutBlockedCondition(m *operatorv1.MultiClusterHub) {
+	if c := GetHubCondition(m.Status, operatorv1.Blocked); c != nil && c.Reason == ComponentRolloutBlockedReason {
+		RemoveHubCondition(&m.Status, operatorv1.Blocked)
+	}
+}
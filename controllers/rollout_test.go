@@ -0,0 +1,388 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+	"github.com/stolostron/multiclusterhub-operator/pkg/version"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func rolloutDeployment(name, releaseVersion, image string) *appsv1.Deployment {
+	replicas := int32(2)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "open-cluster-management",
+			Generation:  1,
+			Annotations: map[string]string{utils.AnnotationReleaseVersion: releaseVersion},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: image}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+	}
+}
+
+func rolloutTemplate(t *testing.T, d *appsv1.Deployment) *unstructured.Unstructured {
+	t.Helper()
+	d = d.DeepCopy()
+	d.Status = appsv1.DeploymentStatus{}
+	d.Annotations = map[string]string{utils.AnnotationReleaseVersion: version.Version}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetAPIVersion("apps/v1")
+	u.SetKind("Deployment")
+	return u
+}
+
+// holdAndRecordTemplate stands in for the rollout checks of ensureComponent for a single template
+func holdAndRecordTemplate(ctx context.Context, r *MultiClusterHubReconciler, m *operatorv1.MultiClusterHub,
+	component string, template *unstructured.Unstructured) (bool, error) {
+	recorded := map[string]*rolloutManifest{}
+	hold, err := r.holdRolloutTemplate(ctx, m, component, template, recorded)
+	if err != nil {
+		return hold, err
+	}
+	return hold, r.recordRolloutManifests(ctx, m, recorded)
+}
+
+// applyRolloutTemplate stands in for applyTemplate and the deployment controller: the deployment is updated to the
+// template and reports the given status
+func applyRolloutTemplate(t *testing.T, c client.Client, template *unstructured.Unstructured,
+	status appsv1.DeploymentStatus) {
+	t.Helper()
+	d := &appsv1.Deployment{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: template.GetName(),
+		Namespace: template.GetNamespace()}, d); err != nil {
+		t.Fatal(err)
+	}
+	desired := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, desired); err != nil {
+		t.Fatal(err)
+	}
+	d.Annotations = desired.Annotations
+	d.Spec = desired.Spec
+	d.Generation++
+	if err := c.Update(context.TODO(), d); err != nil {
+		t.Fatal(err)
+	}
+	d.Status = status
+	d.Status.ObservedGeneration = d.Generation
+	if err := c.Status().Update(context.TODO(), d); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// applyRolloutResource stands in for applyTemplate for resources other than deployments
+func applyRolloutResource(t *testing.T, c client.Client, template *unstructured.Unstructured) {
+	t.Helper()
+	template = template.DeepCopy()
+	existing := template.DeepCopy()
+	err := c.Get(context.TODO(), types.NamespacedName{Name: template.GetName(), Namespace: template.GetNamespace()},
+		existing)
+	if errors.IsNotFound(err) {
+		err = c.Create(context.TODO(), template)
+	} else if err == nil {
+		template.SetResourceVersion(existing.GetResourceVersion())
+		err = c.Update(context.TODO(), template)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_rollout(t *testing.T) {
+	registerScheme()
+	ctx := context.TODO()
+
+	console := rolloutDeployment("console-chart-console-v2", "2.0.0", "console:old")
+	grc := rolloutDeployment("grc-policy-propagator", "2.0.0", "grc:old")
+	grcConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grc-config",
+			Namespace:   "open-cluster-management",
+			Annotations: map[string]string{utils.AnnotationReleaseVersion: "2.0.0"},
+		},
+		Data: map[string]string{"mode": "old"},
+	}
+	m := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+		Spec:       operatorv1.MultiClusterHubSpec{AvailabilityConfig: operatorv1.HAHigh},
+		Status:     operatorv1.MultiClusterHubStatus{CurrentVersion: "2.0.0"},
+	}
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(m, console, grc, grcConfig).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+
+	consoleTemplate := rolloutTemplate(t, console)
+	_ = unstructured.SetNestedSlice(consoleTemplate.Object, []interface{}{
+		map[string]interface{}{"name": "console-chart-console-v2", "image": "console:new"},
+	}, "spec", "template", "spec", "containers")
+	grcTemplate := rolloutTemplate(t, grc)
+	_ = unstructured.SetNestedSlice(grcTemplate.Object, []interface{}{
+		map[string]interface{}{"name": "grc-policy-propagator", "image": "grc:new"},
+	}, "spec", "template", "spec", "containers")
+	grcConfigTemplate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":        grcConfig.Name,
+			"namespace":   grcConfig.Namespace,
+			"annotations": map[string]interface{}{utils.AnnotationReleaseVersion: version.Version},
+		},
+		"data": map[string]interface{}{"mode": "new"},
+	}}
+	grcAccountTemplate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata": map[string]interface{}{
+			"name":        "grc-new-account",
+			"namespace":   "open-cluster-management",
+			"annotations": map[string]interface{}{utils.AnnotationReleaseVersion: version.Version},
+		},
+	}}
+
+	// The first deployment is rolled out, the next one waits for it
+	if err := r.progressRollout(ctx, m); err != nil {
+		t.Fatalf("progressRollout() error = %v", err)
+	}
+	if hold, err := holdAndRecordTemplate(ctx, r, m, operatorv1.Console, consoleTemplate); err != nil || hold {
+		t.Fatalf("holdRolloutTemplate() = %v, %v, want the console deployment rolled out", hold, err)
+	}
+	applyRolloutTemplate(t, r.Client, consoleTemplate, appsv1.DeploymentStatus{Replicas: 2, UnavailableReplicas: 2})
+	if hold, err := holdAndRecordTemplate(ctx, r, m, operatorv1.GRC, grcTemplate); err != nil || !hold {
+		t.Fatalf("holdRolloutTemplate() = %v, %v, want the grc deployment held", hold, err)
+	}
+	r.completeRollout(m)
+	if rollout := m.Status.ComponentRollout; rollout.Phase != operatorv1.ComponentRolloutProgressing ||
+		rollout.Current == nil || rollout.Current.Name != console.Name {
+		t.Fatalf("rollout = %+v, want the console deployment in progress", rollout)
+	}
+
+	// Once the deployment is ready the next one is rolled out
+	applyRolloutTemplate(t, r.Client, consoleTemplate, appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2,
+		AvailableReplicas: 2})
+	if err := r.progressRollout(ctx, m); err != nil {
+		t.Fatalf("progressRollout() error = %v", err)
+	}
+	if hold, err := holdAndRecordTemplate(ctx, r, m, operatorv1.Console, consoleTemplate); err != nil || hold {
+		t.Fatalf("holdRolloutTemplate() = %v, %v, want the rolled out console deployment applied", hold, err)
+	}
+	if hold, err := holdAndRecordTemplate(ctx, r, m, operatorv1.GRC, grcTemplate); err != nil || hold {
+		t.Fatalf("holdRolloutTemplate() = %v, %v, want the grc deployment rolled out", hold, err)
+	}
+	if rollout := m.Status.ComponentRollout; len(rollout.Updated) != 1 || rollout.Current.Name != grc.Name {
+		t.Fatalf("rollout = %+v, want the console deployment updated and the grc deployment in progress", rollout)
+	}
+
+	// The other resources of the component are recorded and applied right away
+	for _, template := range []*unstructured.Unstructured{grcConfigTemplate, grcAccountTemplate} {
+		if hold, err := holdAndRecordTemplate(ctx, r, m, operatorv1.GRC, template); err != nil || hold {
+			t.Fatalf("holdRolloutTemplate() = %v, %v, want the %s applied", hold, err, template.GetKind())
+		}
+		applyRolloutResource(t, r.Client, template)
+	}
+
+	// A deployment that does not become ready rolls its component back and blocks the rollout
+	applyRolloutTemplate(t, r.Client, grcTemplate, appsv1.DeploymentStatus{Replicas: 2, UnavailableReplicas: 2,
+		Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing,
+			Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"}}})
+	if err := r.progressRollout(ctx, m); err != nil {
+		t.Fatalf("progressRollout() error = %v", err)
+	}
+	rollout := m.Status.ComponentRollout
+	if rollout.Phase != operatorv1.ComponentRolloutBlocked || rollout.Current != nil ||
+		len(rollout.RolledBackComponents) != 1 || rollout.RolledBackComponents[0] != operatorv1.GRC {
+		t.Fatalf("rollout = %+v, want it blocked with grc rolled back", rollout)
+	}
+	if c := GetHubCondition(m.Status, operatorv1.Blocked); c == nil || c.Reason != ComponentRolloutBlockedReason {
+		t.Errorf("Blocked condition = %+v, want %s", c, ComponentRolloutBlockedReason)
+	}
+	restored := &appsv1.Deployment{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: grc.Name, Namespace: grc.Namespace},
+		restored); err != nil {
+		t.Fatal(err)
+	}
+	if image := restored.Spec.Template.Spec.Containers[0].Image; image != "grc:old" ||
+		restored.Annotations[utils.AnnotationReleaseVersion] != "2.0.0" {
+		t.Errorf("grc deployment = %s %v, want the previous manifest restored", image, restored.Annotations)
+	}
+	if hold, err := holdAndRecordTemplate(ctx, r, m, operatorv1.GRC, grcTemplate); err != nil || !hold {
+		t.Errorf("holdRolloutTemplate() = %v, %v, want the rolled back deployment held", hold, err)
+	}
+	config := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: grcConfig.Name, Namespace: grcConfig.Namespace},
+		config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Data["mode"] != "old" || config.Annotations[utils.AnnotationReleaseVersion] != "2.0.0" {
+		t.Errorf("grc configmap = %v %v, want the previous manifest restored", config.Data, config.Annotations)
+	}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: grcAccountTemplate.GetName(),
+		Namespace: grcAccountTemplate.GetNamespace()}, &corev1.ServiceAccount{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the service account created by the rollout to be removed, got %v", err)
+	}
+	if hold, err := holdAndRecordTemplate(ctx, r, m, operatorv1.GRC, grcConfigTemplate); err != nil || !hold {
+		t.Errorf("holdRolloutTemplate() = %v, %v, want the rolled back configmap held", hold, err)
+	}
+	r.completeRollout(m)
+	if !rolloutPending(m) {
+		t.Error("rolloutPending() = false, want the blocked rollout pending")
+	}
+
+	// A new rollout-retry value resumes the rollout
+	m.SetAnnotations(map[string]string{utils.AnnotationRolloutRetry: "retry-1"})
+	if err := r.progressRollout(ctx, m); err != nil {
+		t.Fatalf("progressRollout() error = %v", err)
+	}
+	if rollout := m.Status.ComponentRollout; rollout.Phase != operatorv1.ComponentRolloutProgressing ||
+		rollout.LastRetry != "retry-1" || len(rollout.RolledBackComponents) != 0 {
+		t.Errorf("rollout = %+v, want it resumed", rollout)
+	}
+	if HubConditionPresent(m.Status, operatorv1.Blocked) {
+		t.Error("expected the Blocked condition to be removed")
+	}
+}
+
+func Test_recordRolloutManifests(t *testing.T) {
+	registerScheme()
+	ctx := context.TODO()
+
+	grc := rolloutDeployment("grc-policy-propagator", "2.0.0", "grc:old")
+	grcConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grc-config",
+			Namespace:   "open-cluster-management",
+			Annotations: map[string]string{utils.AnnotationReleaseVersion: "2.0.0"},
+		},
+	}
+	m := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+		Spec:       operatorv1.MultiClusterHubSpec{AvailabilityConfig: operatorv1.HAHigh},
+		Status:     operatorv1.MultiClusterHubStatus{CurrentVersion: "2.0.0"},
+	}
+	writes := 0
+	countWrites := func(obj client.Object) {
+		if obj.GetName() == rolloutHistoryName {
+			writes++
+		}
+	}
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(m, grc, grcConfig).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object,
+					opts ...client.CreateOption) error {
+					countWrites(obj)
+					return c.Create(ctx, obj, opts...)
+				},
+				Update: func(ctx context.Context, c client.WithWatch, obj client.Object,
+					opts ...client.UpdateOption) error {
+					countWrites(obj)
+					return c.Update(ctx, obj, opts...)
+				},
+			}).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+	if err := r.progressRollout(ctx, m); err != nil {
+		t.Fatalf("progressRollout() error = %v", err)
+	}
+
+	templates := []*unstructured.Unstructured{rolloutTemplate(t, grc), {Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": grcConfig.Name, "namespace": grcConfig.Namespace},
+	}}, {Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata":   map[string]interface{}{"name": "grc-new-account", "namespace": "open-cluster-management"},
+	}}}
+
+	// The manifests of every resource of the component are written in a single update of the history
+	for pass := 0; pass < 2; pass++ {
+		recorded := map[string]*rolloutManifest{}
+		for _, template := range templates {
+			if _, err := r.holdRolloutTemplate(ctx, m, operatorv1.GRC, template, recorded); err != nil {
+				t.Fatalf("holdRolloutTemplate() error = %v", err)
+			}
+		}
+		if err := r.recordRolloutManifests(ctx, m, recorded); err != nil {
+			t.Fatalf("recordRolloutManifests() error = %v", err)
+		}
+	}
+	if writes != 1 {
+		t.Errorf("rollout history written %d times, want once", writes)
+	}
+
+	_, history, err := r.readRolloutHistory(ctx, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Manifests) != 3 {
+		t.Errorf("history = %+v, want the three resources of the component recorded", history.Manifests)
+	}
+}
+
+func Test_rolloutBasicHub(t *testing.T) {
+	registerScheme()
+	ctx := context.TODO()
+
+	grc := rolloutDeployment("grc-policy-propagator", "2.0.0", "grc:old")
+	m := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+		Spec:       operatorv1.MultiClusterHubSpec{AvailabilityConfig: operatorv1.HABasic},
+		Status: operatorv1.MultiClusterHubStatus{
+			CurrentVersion: "2.0.0",
+			ComponentRollout: &operatorv1.ComponentRolloutStatus{
+				TargetVersion: version.Version,
+				Phase:         operatorv1.ComponentRolloutBlocked,
+			},
+		},
+	}
+	SetHubCondition(&m.Status, *NewHubCondition(operatorv1.Blocked, metav1.ConditionTrue,
+		ComponentRolloutBlockedReason, "blocked"))
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(m, grc).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+
+	// Deployments of hubs that are not highly available are applied at once
+	if err := r.progressRollout(ctx, m); err != nil {
+		t.Fatalf("progressRollout() error = %v", err)
+	}
+	if m.Status.ComponentRollout != nil || HubConditionPresent(m.Status, operatorv1.Blocked) {
+		t.Errorf("status = %+v, want the rollout cleared", m.Status)
+	}
+	if hold, err := holdAndRecordTemplate(ctx, r, m, operatorv1.GRC, rolloutTemplate(t, grc)); err != nil || hold {
+		t.Errorf("holdRolloutTemplate() = %v, %v, want the deployment applied", hold, err)
+	}
+}
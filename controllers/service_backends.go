@@ -0,0 +1,325 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ServiceNotFoundReason is reported when the service of a conversion webhook or an APIService does not exist
+	ServiceNotFoundReason = "ServiceNotFound"
+
+	// NoReadyEndpointsReason is reported when no endpoint of the service is ready
+	NoReadyEndpointsReason = "NoReadyEndpoints"
+
+	// MissingCABundleReason is reported when no CA bundle is injected for the service
+	MissingCABundleReason = "MissingCABundle"
+
+	// APIServiceUnavailableReason is reported when the API server reports an APIService unavailable
+	APIServiceUnavailableReason = "Unavailable"
+
+	// crdServiceBackends is the registry key of the conversion webhook of the MultiClusterHub CRD, and the prefix of
+	// the keys of the conversion webhooks of the hub CRDs, per CRD directory
+	crdServiceBackends = "crds"
+)
+
+// serviceBackend is a CRD conversion webhook or an APIService the API server calls a service for
+type serviceBackend struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Service   string
+	// Components are the components the resource is installed for. The service is checked while any of them is
+	// enabled, or always when there is none.
+	Components []string
+}
+
+// listServiceBackends returns the recorded service backends sorted by kind and name
+func listServiceBackends(recorded *registry[[]serviceBackend]) []serviceBackend {
+	backends := []serviceBackend{}
+	for _, b := range recorded.list() {
+		backends = append(backends, b...)
+	}
+	sort.Slice(backends, func(i, j int) bool {
+		if backends[i].Kind != backends[j].Kind {
+			return backends[i].Kind < backends[j].Kind
+		}
+		return backends[i].Name < backends[j].Name
+	})
+	return backends
+}
+
+/*
+recordServiceBackends records the conversion webhooks of the rendered CRDs and the APIServices of the rendered
+templates that call a service. Templates that cannot be read are logged and skipped.
+*/
+func (r *MultiClusterHubReconciler) recordServiceBackends(key string, components []string,
+	templates []*unstructured.Unstructured) {
+	backends := []serviceBackend{}
+	for _, template := range templates {
+		backend, ok, err := renderedServiceBackend(template)
+		if err != nil {
+			r.Log.Error(err, "Unable to read the service of a rendered template", "Kind", template.GetKind(),
+				"Name", template.GetName())
+			continue
+		}
+		if ok {
+			backend.Components = components
+			backends = append(backends, backend)
+		}
+	}
+	if r.serviceBackends == nil {
+		r.serviceBackends = newRegistry[[]serviceBackend]()
+	}
+	if len(backends) == 0 {
+		r.serviceBackends.remove(key)
+		return
+	}
+	r.serviceBackends.set(key, backends)
+}
+
+/*
+recordHubServiceBackend records the conversion webhook of the MultiClusterHub CRD, which is not rendered: main.go points
+the CRD at the webhook service of the operator at startup, or an admin does in restricted mode.
+*/
+func (r *MultiClusterHubReconciler) recordHubServiceBackend() {
+	if r.serviceBackends == nil {
+		r.serviceBackends = newRegistry[[]serviceBackend]()
+	}
+	namespace, err := utils.OperatorNamespace()
+	if err != nil || !webhooksEnabled() {
+		r.serviceBackends.remove(crdServiceBackends)
+		return
+	}
+	service := operatorv1.CRDConversion(namespace).Webhook.ClientConfig.Service
+	r.serviceBackends.set(crdServiceBackends, []serviceBackend{{
+		Kind: "CustomResourceDefinition", Name: mchCRDName, Namespace: service.Namespace, Service: service.Name,
+	}})
+}
+
+// webhooksEnabled returns false when the webhooks of the operator are disabled with ENABLE_WEBHOOKS
+func webhooksEnabled() bool {
+	return os.Getenv("ENABLE_WEBHOOKS") != "false"
+}
+
+// renderedServiceBackend returns the service a rendered CRD or APIService makes the API server call, if any
+func renderedServiceBackend(template *unstructured.Unstructured) (serviceBackend, bool, error) {
+	switch template.GetKind() {
+	case "CustomResourceDefinition":
+		crd := &apixv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, crd); err != nil {
+			return serviceBackend{}, false, err
+		}
+		conversion := crd.Spec.Conversion
+		if conversion == nil || conversion.Strategy != apixv1.WebhookConverter || conversion.Webhook == nil ||
+			conversion.Webhook.ClientConfig == nil || conversion.Webhook.ClientConfig.Service == nil {
+			return serviceBackend{}, false, nil
+		}
+		service := conversion.Webhook.ClientConfig.Service
+		return serviceBackend{
+			Kind: template.GetKind(), Name: crd.Name, Namespace: service.Namespace, Service: service.Name,
+		}, true, nil
+
+	case "APIService":
+		apiService := &apiregistrationv1.APIService{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, apiService); err != nil {
+			return serviceBackend{}, false, err
+		}
+		// APIServices without a service are served by the API server itself
+		if apiService.Spec.Service == nil {
+			return serviceBackend{}, false, nil
+		}
+		return serviceBackend{
+			Kind: template.GetKind(), Name: apiService.Name, Namespace: apiService.Spec.Service.Namespace,
+			Service: apiService.Spec.Service.Name,
+		}, true, nil
+	}
+	return serviceBackend{}, false, nil
+}
+
+/*
+verifyServiceBackends checks the services the API server calls for the conversion webhooks of the hub CRDs and the
+APIServices of the enabled components, and reports them in the hub status. When such a service cannot be reached, the
+API server fails to list and watch the resources served through it. Failures to read are logged, they do not fail the
+reconcile.
+*/
+func (r *MultiClusterHubReconciler) verifyServiceBackends(ctx context.Context, m *operatorv1.MultiClusterHub) {
+	previous := map[string]operatorv1.ServiceBackendStatus{}
+	for _, status := range m.Status.ServiceBackends {
+		previous[status.Kind+"/"+status.Name] = status
+	}
+
+	statuses := []operatorv1.ServiceBackendStatus{}
+	for _, backend := range listServiceBackends(r.serviceBackends) {
+		if !anyEnabled(m, backend.Components) {
+			continue
+		}
+		status, err := r.serviceBackendStatus(ctx, backend)
+		if err != nil {
+			r.Log.Info("Unable to verify the service of a resource", "Kind", backend.Kind, "Name", backend.Name,
+				"Error", err.Error())
+			if last, ok := previous[backend.Kind+"/"+backend.Name]; ok {
+				statuses = append(statuses, last)
+			}
+			continue
+		}
+		if status == nil {
+			continue
+		}
+
+		status.LastTransitionTime = metav1.Now()
+		if last, ok := previous[status.Kind+"/"+status.Name]; ok && last.Ready == status.Ready {
+			status.LastTransitionTime = last.LastTransitionTime
+		}
+		if !status.Ready {
+			r.Log.Info("The API server cannot reach the service of a resource", "Kind", status.Kind,
+				"Name", status.Name, "Service", status.Service, "Reason", status.Reason)
+		}
+		statuses = append(statuses, *status)
+	}
+
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	m.Status.ServiceBackends = statuses
+}
+
+// serviceBackendStatus checks a service backend, it returns no status while the resource is not installed
+func (r *MultiClusterHubReconciler) serviceBackendStatus(ctx context.Context, backend serviceBackend) (
+	*operatorv1.ServiceBackendStatus, error) {
+	status := &operatorv1.ServiceBackendStatus{
+		Kind:       backend.Kind,
+		Name:       backend.Name,
+		Service:    backend.Namespace + "/" + backend.Service,
+		Components: append([]string(nil), backend.Components...),
+	}
+
+	// The CA bundle and the service are read from the installed resource, where they are injected
+	var caBundle []byte
+	var unavailable *apiregistrationv1.APIServiceCondition
+	switch backend.Kind {
+	case "CustomResourceDefinition":
+		crd := &apixv1.CustomResourceDefinition{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: backend.Name}, crd); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if c := crd.Spec.Conversion; c != nil && c.Webhook != nil && c.Webhook.ClientConfig != nil {
+			caBundle = c.Webhook.ClientConfig.CABundle
+		}
+
+	case "APIService":
+		apiService := &apiregistrationv1.APIService{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: backend.Name}, apiService); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		caBundle = apiService.Spec.CABundle
+		if apiService.Spec.InsecureSkipTLSVerify {
+			caBundle = []byte("insecure")
+		}
+		for i, c := range apiService.Status.Conditions {
+			if c.Type == apiregistrationv1.Available && c.Status == apiregistrationv1.ConditionFalse {
+				unavailable = &apiService.Status.Conditions[i]
+			}
+		}
+	}
+
+	service := &corev1.Service{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: backend.Service, Namespace: backend.Namespace}, service)
+	if errors.IsNotFound(err) {
+		status.Reason = ServiceNotFoundReason
+		status.Message = fmt.Sprintf("service %s does not exist", status.Service)
+		return status, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ready, err := r.hasReadyEndpoints(ctx, backend.Namespace, backend.Service)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !ready:
+		status.Reason = NoReadyEndpointsReason
+		status.Message = fmt.Sprintf("service %s has no ready endpoints", status.Service)
+	case len(caBundle) == 0:
+		status.Reason = MissingCABundleReason
+		status.Message = fmt.Sprintf("no CA bundle is injected in %s %s", backend.Kind, backend.Name)
+	case unavailable != nil:
+		status.Reason = APIServiceUnavailableReason
+		status.Message = fmt.Sprintf("%s: %s", unavailable.Reason, unavailable.Message)
+	default:
+		status.Ready = true
+	}
+	return status, nil
+}
+
+// hasReadyEndpoints returns true when an endpoint of the service is ready
+func (r *MultiClusterHubReconciler) hasReadyEndpoints(ctx context.Context, namespace, service string) (bool, error) {
+	slices := &discoveryv1.EndpointSliceList{}
+	if err := r.Client.List(ctx, slices, client.InNamespace(namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: service}); err != nil {
+		return false, err
+	}
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			// A nil ready condition is to be interpreted as ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// anyEnabled returns true when any of the components is enabled, or when there is none
+func anyEnabled(m *operatorv1.MultiClusterHub, components []string) bool {
+	if len(components) == 0 {
+		return true
+	}
+	for _, component := range components {
+		if m.Enabled(component) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,267 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func webhookCRD(caBundle []byte) *apixv1.CustomResourceDefinition {
+	crd := widgetCRD(nil, "v1", "v2")
+	crd.Spec.Conversion = &apixv1.CustomResourceConversion{
+		Strategy: apixv1.WebhookConverter,
+		Webhook: &apixv1.WebhookConversion{
+			ClientConfig: &apixv1.WebhookClientConfig{
+				Service:  &apixv1.ServiceReference{Namespace: "ocm", Name: "widget-webhook"},
+				CABundle: caBundle,
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+	return crd
+}
+
+func widgetAPIService(service bool, caBundle []byte) *apiregistrationv1.APIService {
+	apiService := &apiregistrationv1.APIService{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiregistration.k8s.io/v1", Kind: "APIService"},
+		ObjectMeta: metav1.ObjectMeta{Name: "v1.widgets.example.com"},
+		Spec: apiregistrationv1.APIServiceSpec{
+			Group: "widgets.example.com", Version: "v1", CABundle: caBundle,
+			GroupPriorityMinimum: 1000, VersionPriority: 15,
+		},
+	}
+	if service {
+		apiService.Spec.Service = &apiregistrationv1.ServiceReference{Namespace: "ocm", Name: "widget-api"}
+	}
+	return apiService
+}
+
+func serviceEndpoints(service string, ready bool) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: service + "-abcde", Namespace: "ocm",
+			Labels: map[string]string{discoveryv1.LabelServiceName: service},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+		},
+	}
+}
+
+// serviceBackendsScheme holds the kinds of the service backends
+var serviceBackendsScheme = []func(*runtime.Scheme) error{
+	clientgoscheme.AddToScheme, apixv1.AddToScheme, apiregistrationv1.AddToScheme,
+}
+
+func Test_renderedServiceBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		template runtime.Object
+		want     serviceBackend
+		wantOk   bool
+	}{
+		{
+			name:     "conversion webhook",
+			template: webhookCRD(nil),
+			want: serviceBackend{
+				Kind: "CustomResourceDefinition", Name: "widgets.example.com", Namespace: "ocm",
+				Service: "widget-webhook",
+			},
+			wantOk: true,
+		},
+		{
+			name:     "no conversion",
+			template: widgetCRD(nil, "v1"),
+		},
+		{
+			name:     "APIService with a service",
+			template: widgetAPIService(true, nil),
+			want: serviceBackend{
+				Kind: "APIService", Name: "v1.widgets.example.com", Namespace: "ocm", Service: "widget-api",
+			},
+			wantOk: true,
+		},
+		{
+			name:     "local APIService",
+			template: widgetAPIService(false, nil),
+		},
+		{
+			name:     "other kind",
+			template: &corev1.Service{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := renderedServiceBackend(toUnstructured(t, tt.template))
+			if err != nil {
+				t.Fatalf("renderedServiceBackend() error = %v", err)
+			}
+			if ok != tt.wantOk || got.Kind != tt.want.Kind || got.Name != tt.want.Name ||
+				got.Namespace != tt.want.Namespace || got.Service != tt.want.Service {
+				t.Errorf("renderedServiceBackend() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_verifyServiceBackends(t *testing.T) {
+	ctx := context.TODO()
+	webhookService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "widget-webhook", Namespace: "ocm"}}
+	apiService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "widget-api", Namespace: "ocm"}}
+
+	tests := []struct {
+		name       string
+		objs       []client.Object
+		wantReady  bool
+		wantReason string
+	}{
+		{
+			name:       "service not found",
+			objs:       []client.Object{webhookCRD([]byte("ca"))},
+			wantReason: ServiceNotFoundReason,
+		},
+		{
+			name: "no ready endpoints",
+			objs: []client.Object{
+				webhookCRD([]byte("ca")), webhookService, serviceEndpoints("widget-webhook", false),
+			},
+			wantReason: NoReadyEndpointsReason,
+		},
+		{
+			name: "missing CA bundle",
+			objs: []client.Object{
+				webhookCRD(nil), webhookService, serviceEndpoints("widget-webhook", true),
+			},
+			wantReason: MissingCABundleReason,
+		},
+		{
+			name: "ready",
+			objs: []client.Object{
+				webhookCRD([]byte("ca")), webhookService, serviceEndpoints("widget-webhook", true),
+			},
+			wantReady: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &operatorv1.MultiClusterHub{}
+			hub.Enable(operatorv1.Search)
+			r := &MultiClusterHubReconciler{
+				Client: testClientBuilder(t, serviceBackendsScheme...).WithObjects(tt.objs...).Build(),
+				Log:    clog.Log.WithName("test"),
+			}
+			r.recordServiceBackends(crdServiceBackends+"/search", []string{operatorv1.Search},
+				[]*unstructured.Unstructured{toUnstructured(t, webhookCRD(nil))})
+
+			r.verifyServiceBackends(ctx, hub)
+			if len(hub.Status.ServiceBackends) != 1 {
+				t.Fatalf("verifyServiceBackends() statuses = %+v, want one", hub.Status.ServiceBackends)
+			}
+			got := hub.Status.ServiceBackends[0]
+			if got.Ready != tt.wantReady || got.Reason != tt.wantReason {
+				t.Errorf("verifyServiceBackends() = %v, %s, want %v, %s", got.Ready, got.Reason, tt.wantReady,
+					tt.wantReason)
+			}
+			if got.Service != "ocm/widget-webhook" || !reflect.DeepEqual(got.Components, []string{operatorv1.Search}) {
+				t.Errorf("verifyServiceBackends() service = %s, components = %v", got.Service, got.Components)
+			}
+		})
+	}
+
+	t.Run("unavailable APIService", func(t *testing.T) {
+		unavailable := widgetAPIService(true, []byte("ca"))
+		unavailable.Status.Conditions = []apiregistrationv1.APIServiceCondition{{
+			Type: apiregistrationv1.Available, Status: apiregistrationv1.ConditionFalse,
+			Reason: "FailedDiscoveryCheck", Message: "no response",
+		}}
+		hub := &operatorv1.MultiClusterHub{}
+		hub.Enable(operatorv1.Search)
+		r := &MultiClusterHubReconciler{
+			Client: testClientBuilder(t, serviceBackendsScheme...).
+				WithObjects(unavailable, apiService, serviceEndpoints("widget-api", true)).Build(),
+			Log: clog.Log.WithName("test"),
+		}
+		r.recordServiceBackends(operatorv1.Search, []string{operatorv1.Search},
+			[]*unstructured.Unstructured{toUnstructured(t, widgetAPIService(true, nil))})
+
+		r.verifyServiceBackends(ctx, hub)
+		if len(hub.Status.ServiceBackends) != 1 || hub.Status.ServiceBackends[0].Reason != APIServiceUnavailableReason {
+			t.Errorf("verifyServiceBackends() statuses = %+v, want the APIService unavailable",
+				hub.Status.ServiceBackends)
+		}
+	})
+
+	t.Run("shared CRD", func(t *testing.T) {
+		hub := &operatorv1.MultiClusterHub{}
+		hub.Disable(operatorv1.Search)
+		hub.Enable(operatorv1.GRC)
+		r := &MultiClusterHubReconciler{
+			Client: testClientBuilder(t, serviceBackendsScheme...).
+				WithObjects(webhookCRD([]byte("ca")), webhookService, serviceEndpoints("widget-webhook", true)).Build(),
+			Log: clog.Log.WithName("test"),
+		}
+		r.recordServiceBackends(crdServiceBackends+"/shared", []string{operatorv1.Search, operatorv1.GRC},
+			[]*unstructured.Unstructured{toUnstructured(t, webhookCRD(nil))})
+
+		r.verifyServiceBackends(ctx, hub)
+		if len(hub.Status.ServiceBackends) != 1 || !reflect.DeepEqual(hub.Status.ServiceBackends[0].Components,
+			[]string{operatorv1.Search, operatorv1.GRC}) {
+			t.Errorf("verifyServiceBackends() statuses = %+v, want every component of the CRD",
+				hub.Status.ServiceBackends)
+		}
+	})
+
+	t.Run("disabled component and uninstalled resource", func(t *testing.T) {
+		hub := &operatorv1.MultiClusterHub{}
+		hub.Disable(operatorv1.Search)
+		r := &MultiClusterHubReconciler{
+			Client: testClientBuilder(t, serviceBackendsScheme...).Build(),
+			Log:    clog.Log.WithName("test"),
+		}
+		r.recordServiceBackends(crdServiceBackends+"/search", []string{operatorv1.Search},
+			[]*unstructured.Unstructured{toUnstructured(t, webhookCRD(nil))})
+		r.recordServiceBackends(crdServiceBackends, nil,
+			[]*unstructured.Unstructured{toUnstructured(t, widgetAPIService(true, nil))})
+
+		r.verifyServiceBackends(ctx, hub)
+		if hub.Status.ServiceBackends != nil {
+			t.Errorf("verifyServiceBackends() statuses = %+v, want none", hub.Status.ServiceBackends)
+		}
+	})
+}
+
+func Test_recordHubServiceBackend(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "open-cluster-management")
+	r := &MultiClusterHubReconciler{Log: clog.Log.WithName("test")}
+
+	r.recordHubServiceBackend()
+	want := []serviceBackend{{
+		Kind: "CustomResourceDefinition", Name: mchCRDName, Namespace: "open-cluster-management",
+		Service: "multiclusterhub-operator-webhook",
+	}}
+	if got := listServiceBackends(r.serviceBackends); !reflect.DeepEqual(got, want) {
+		t.Errorf("recordHubServiceBackend() backends = %+v, want %+v", got, want)
+	}
+
+	t.Setenv("ENABLE_WEBHOOKS", "false")
+	r.recordHubServiceBackend()
+	if got := listServiceBackends(r.serviceBackends); len(got) != 0 {
+		t.Errorf("recordHubServiceBackend() backends = %+v, want none without webhooks", got)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/capabilities"
 	"github.com/stolostron/multiclusterhub-operator/pkg/predicate"
 	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
 	ctrlpredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -31,6 +32,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
@@ -96,7 +98,7 @@ func (r *MultiClusterHubReconciler) SetupWithManager(mgr ctrl.Manager) (controll
 		controllerName = fmt.Sprintf("multiclusterhub-%d", time.Now().UnixNano())
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named(controllerName).
 		For(
 			&operatorv1.MultiClusterHub{},
@@ -176,6 +178,38 @@ func (r *MultiClusterHubReconciler) SetupWithManager(mgr ctrl.Manager) (controll
 				},
 			),
 		).
+		Watches(
+			// Changes to the cluster proxy are propagated to the component deployments
+			&configv1.Proxy{},
+			handler.EnqueueRequestsFromMapFunc(
+				func(ctx context.Context, a client.Object) []reconcile.Request {
+					return r.firstHubRequest(ctx)
+				},
+			),
+		).
+		Watches(
+			// A new TLS security profile is applied to the webhook and metrics servers without a restart
+			&configv1.APIServer{},
+			handler.EnqueueRequestsFromMapFunc(
+				func(ctx context.Context, a client.Object) []reconcile.Request {
+					apiServer, ok := a.(*configv1.APIServer)
+					if !ok || apiServer.GetName() != "cluster" {
+						return []reconcile.Request{}
+					}
+					r.observeTLSProfile(apiServer)
+					return r.firstHubRequest(ctx)
+				},
+			),
+			builder.WithPredicates(ctrlpredicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			// Edits to the override configmaps are applied right away. Only the metadata is cached, the configmaps
+			// are matched to the hubs referencing them by name.
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.overridesConfigMapRequests),
+			builder.OnlyMetadata,
+			builder.WithPredicates(ctrlpredicate.ResourceVersionChangedPredicate{}),
+		).
 		Watches(
 			&corev1.Namespace{},
 			handler.EnqueueRequestsFromMapFunc(
@@ -209,6 +243,32 @@ func (r *MultiClusterHubReconciler) SetupWithManager(mgr ctrl.Manager) (controll
 				DeleteFunc:  func(e event.DeleteEvent) bool { return false },
 				GenericFunc: func(e event.GenericEvent) bool { return false },
 			}),
+		)
+
+	if r.Capabilities == nil {
+		return b.Build(r)
+	}
+
+	// Keep the capability discovery current as optional APIs are installed or removed. CRDs are watched by their
+	// metadata only, as caching their schemas takes a lot of memory on large hubs.
+	c, err := b.
+		Watches(
+			&apixv1.CustomResourceDefinition{},
+			r.capabilityEventHandler(capabilities.CRDSource),
+			builder.OnlyMetadata,
+			builder.WithPredicates(capabilityPredicate(capabilities.CRDSource)),
+		).
+		Watches(
+			&apiregistrationv1.APIService{},
+			r.capabilityEventHandler(capabilities.APIServiceSource),
+			builder.WithPredicates(capabilityPredicate(capabilities.APIServiceSource)),
 		).
 		Build(r)
+	if err != nil {
+		return nil, err
+	}
+
+	r.watchMultiClusterEngineWhenAvailable(c, mgr.GetCache())
+	r.watchInternalHubComponentsWhenAvailable(c, mgr.GetCache())
+	return c, nil
 }
@@ -22,6 +22,7 @@ import (
 	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -77,6 +78,10 @@ const (
 	// RequirementsNotMetReason is when there is something missing or misconfigured
 	// that is preventing progress
 	RequirementsNotMetReason = "RequirementsNotMet"
+	// MCEOLMMigrationBlockedReason is added when moving MCE between OLM versions cannot proceed without intervention
+	MCEOLMMigrationBlockedReason = "MCEOLMMigrationBlocked"
+	// RestrictedPrerequisitesMissingReason is added when an admin has not applied a restricted mode bundle yet
+	RestrictedPrerequisitesMissingReason = "RestrictedPrerequisitesMissing"
 
 	FailedApplyingComponent = "FailedApplyingComponent"
 )
@@ -85,10 +90,13 @@ var (
 	prevAvailability = make(map[string]bool)
 )
 
-func newComponentList(m *operatorsv1.MultiClusterHub, ocpConsole, isSTSEnabled bool, olmVersion string) map[string]operatorsv1.StatusCondition {
+func newComponentList(m *operatorsv1.MultiClusterHub, workloads *registry[[]statusWorkload], ocpConsole, isSTSEnabled bool,
+	olmVersion string) map[string]operatorsv1.StatusCondition {
 	components := make(map[string]operatorsv1.StatusCondition)
-	for _, d := range utils.GetDeploymentsForStatus(m, ocpConsole, isSTSEnabled) {
-		components[d.Name] = unknownStatus(d.Name, "Deployment")
+	for _, component := range utils.StatusComponents(m) {
+		for _, w := range componentStatusWorkloads(workloads, m, component, ocpConsole, isSTSEnabled) {
+			components[w.Name] = unknownStatus(w.Name, w.Kind)
+		}
 	}
 
 	for _, cr := range utils.GetCustomResourcesForStatus(m, olmVersion) {
@@ -112,8 +120,10 @@ func (r *MultiClusterHubReconciler) ComponentsAreRunning(m *operatorsv1.MultiClu
 	trackedNamespaces := utils.TrackedNamespaces(m)
 
 	deployList, _ := r.listDeployments(trackedNamespaces)
-	crList, _ := r.listCustomResources()
-	componentStatuses := getComponentStatuses(m, deployList, crList, ocpConsole, isSTSEnabled, r.OLMVersion)
+	crList, _ := r.listCustomResources(m)
+	componentStatuses := getComponentStatuses(m, r.statusWorkloads, deployList, crList, ocpConsole, isSTSEnabled,
+		r.mceOLMVersion(m))
+	r.mapStatusWorkloads(context.TODO(), m, componentStatuses, ocpConsole, isSTSEnabled)
 
 	delete(componentStatuses, m.Spec.LocalClusterName)
 	return allComponentsSuccessful(componentStatuses)
@@ -161,29 +171,62 @@ func (r *MultiClusterHubReconciler) calculateStatus(ctx context.Context, hub *op
 
 	components := map[string]operatorsv1.StatusCondition{}
 	if paused := utils.IsPaused(hub); !paused {
-		components = getComponentStatuses(hub, allDeps, allCRs, ocpConsole, isSTSEnabled, r.OLMVersion)
+		components = getComponentStatuses(hub, r.statusWorkloads, allDeps, allCRs, ocpConsole, isSTSEnabled,
+			r.mceOLMVersion(hub))
+		r.mapStatusWorkloads(ctx, hub, components, ocpConsole, isSTSEnabled)
 	}
 
 	// Calculate MCE version compliance
 	mceVersionCompliance := r.calculateMCEVersionCompliance(ctx)
 
 	status := operatorsv1.MultiClusterHubStatus{
-		CurrentVersion:       hub.Status.CurrentVersion,
-		DesiredVersion:       version.Version,
-		Components:           components,
-		MCEVersionCompliance: mceVersionCompliance,
-	}
-
-	// Set current version
+		CurrentVersion:            hub.Status.CurrentVersion,
+		DesiredVersion:            version.Version,
+		Components:                components,
+		MCEVersionCompliance:      mceVersionCompliance,
+		MCEOLMMigration:           hub.Status.MCEOLMMigration,
+		Capabilities:              r.capabilitiesStatus(),
+		ComponentReconcile:        enabledComponentReconcile(hub),
+		TrustBundle:               hub.Status.TrustBundle,
+		Adoption:                  hub.Status.Adoption,
+		RejectedTemplateOverrides: hub.Status.RejectedTemplateOverrides,
+		OverrideRevisions:         hub.Status.OverrideRevisions,
+		ComponentRollout:          hub.Status.ComponentRollout,
+		Health:                    r.observeHealth(ctx, hub, components, ocpConsole, isSTSEnabled),
+		TLSProfile:                r.tlsProfileStatus(hub),
+		Profile:                   hub.Status.Profile,
+		RBACAudit:                 hub.Status.RBACAudit,
+		Restricted:                r.restrictedStatus(hub),
+		CRDLifecycle:              hub.Status.CRDLifecycle,
+		ServiceBackends:           hub.Status.ServiceBackends,
+	}
+
+	// Set current version, deployments that are still to be rolled out run the previous version
 	successful := allComponentsSuccessful(components)
-	if successful && isMinorVersionWithinRange(mceVersionCompliance.CurrentVersion, version.Version, 5) {
+	if successful && isMinorVersionWithinRange(mceVersionCompliance.CurrentVersion, version.Version, 5) &&
+		!rolloutPending(hub) {
 		status.CurrentVersion = version.Version
 	}
 
-	// Copy conditions one by one to not affect original object
-	conditions := hub.Status.HubConditions
+	// Copy conditions one by one to not affect original object. Per-object ComponentFailure conditions set by
+	// earlier releases are dropped, component failures are now reported in status.componentReconcile.
+	conditions := filterOutConditionWithSubstring(hub.Status.HubConditions, string(operatorsv1.ComponentFailure)+": ")
 	status.HubConditions = append(status.HubConditions, conditions...)
 
+	// Derive the component failure condition from the component reconcile results
+	componentFailure := componentFailureCondition(status.ComponentReconcile)
+	if componentFailure != nil {
+		// Keep the message current as the set of failing components changes
+		if current := GetHubCondition(status, operatorsv1.ComponentFailure); current != nil &&
+			current.Status == componentFailure.Status && current.Message != componentFailure.Message {
+			componentFailure.LastTransitionTime = current.LastTransitionTime
+			RemoveHubCondition(&status, operatorsv1.ComponentFailure)
+		}
+		SetHubCondition(&status, *componentFailure)
+	} else {
+		RemoveHubCondition(&status, operatorsv1.ComponentFailure)
+	}
+
 	// Update hub conditions
 	if successful {
 		// don't label as complete until component pruning succeeds
@@ -220,11 +263,10 @@ func (r *MultiClusterHubReconciler) calculateStatus(ctx context.Context, hub *op
 
 	// Set overall phase
 	isHubMarkedToBeDeleted := hub.GetDeletionTimestamp() != nil
-	hasComponentFailure := HubConditionPresentWithSubstring(status, string(operatorsv1.ComponentFailure))
 	if isHubMarkedToBeDeleted {
 		// Hub cleaning up
 		status.Phase = operatorsv1.HubUninstalling
-	} else if hasComponentFailure {
+	} else if componentFailure != nil {
 		status.Phase = operatorsv1.HubError
 	} else {
 		status.Phase = aggregatePhase(status)
@@ -234,12 +276,12 @@ func (r *MultiClusterHubReconciler) calculateStatus(ctx context.Context, hub *op
 }
 
 // getComponentStatuses populates a complete list of the hub component statuses
-func getComponentStatuses(hub *operatorsv1.MultiClusterHub, allDeps []*appsv1.Deployment,
+func getComponentStatuses(hub *operatorsv1.MultiClusterHub, workloads *registry[[]statusWorkload], allDeps []*appsv1.Deployment,
 	allCRs map[string]*unstructured.Unstructured, ocpConsole, isSTSEnabled bool, olmVersion string) map[string]operatorsv1.StatusCondition {
-	components := newComponentList(hub, ocpConsole, isSTSEnabled, olmVersion)
+	components := newComponentList(hub, workloads, ocpConsole, isSTSEnabled, olmVersion)
 
 	for _, d := range allDeps {
-		if _, ok := components[d.Name]; ok {
+		if c, ok := components[d.Name]; ok && c.Kind == "Deployment" {
 			components[d.Name] = mapDeployment(d)
 		}
 	}
@@ -257,6 +299,23 @@ func getComponentStatuses(hub *operatorsv1.MultiClusterHub, allDeps []*appsv1.De
 			components["multicluster-engine-clusterextension"] = mapClusterExtension(cr)
 		case "mce":
 			components["multicluster-engine"] = mapMultiClusterEngine(cr)
+		case "oadp-sub":
+			components["redhat-oadp-operator-sub"] = mapSubscription(cr)
+		case "oadp-csv":
+			version, _, _ := unstructured.NestedString(cr.Object, "spec", "version")
+			components["redhat-oadp-operator-csv"] = mapOADPVersion(mapCSV(cr), version)
+		case "oadp-clusterextension":
+			version, _, _ := unstructured.NestedString(cr.Object, "status", "install", "bundle", "version")
+			components["redhat-oadp-operator-clusterextension"] = mapOADPVersion(mapClusterExtension(cr), version)
+		default:
+			// Components whose operand reports status are tracked by their InternalHubComponent instead of by
+			// their deployments
+			if component, ok := strings.CutPrefix(key, internalHubComponentKeyPrefix); ok {
+				for _, w := range componentStatusWorkloads(workloads, hub, component, ocpConsole, isSTSEnabled) {
+					delete(components, w.Name)
+				}
+				components[component] = mapInternalHubComponent(cr)
+			}
 		}
 	}
 
@@ -341,6 +400,94 @@ func mapDeployment(ds *appsv1.Deployment) operatorsv1.StatusCondition {
 	return ret
 }
 
+func mapStatefulSet(ss *appsv1.StatefulSet) operatorsv1.StatusCondition {
+	if ss.Status.ObservedGeneration == 0 {
+		return unknownStatus(ss.Name, "StatefulSet")
+	}
+
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	// Pods of an OnDelete StatefulSet are only updated when they are deleted
+	updated := ss.Status.ObservedGeneration >= ss.Generation &&
+		(ss.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType || ss.Status.UpdatedReplicas >= replicas)
+	return replicaStatus(ss.Name, "StatefulSet", updated, ss.Status.ReadyReplicas, replicas)
+}
+
+func mapDaemonSet(ds *appsv1.DaemonSet) operatorsv1.StatusCondition {
+	if ds.Status.ObservedGeneration == 0 {
+		return unknownStatus(ds.Name, "DaemonSet")
+	}
+
+	desired := ds.Status.DesiredNumberScheduled
+	updated := ds.Status.ObservedGeneration >= ds.Generation &&
+		(ds.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType || ds.Status.UpdatedNumberScheduled >= desired)
+	return replicaStatus(ds.Name, "DaemonSet", updated, ds.Status.NumberAvailable, desired)
+}
+
+// replicaStatus maps the rollout and readiness of the replicas of a StatefulSet or DaemonSet
+func replicaStatus(name, kind string, updated bool, ready, desired int32) operatorsv1.StatusCondition {
+	ret := operatorsv1.StatusCondition{
+		Name:    name,
+		Kind:    kind,
+		Type:    "Available",
+		Status:  metav1.ConditionFalse,
+		Reason:  "MinimumReplicasUnavailable",
+		Message: fmt.Sprintf("%d of %d replicas ready", ready, desired),
+	}
+	switch {
+	case !updated:
+		ret.Type = "Progressing"
+		ret.Status = metav1.ConditionTrue
+		ret.Reason = "RollingUpdate"
+	case ready >= desired:
+		ret.Status = metav1.ConditionTrue
+		ret.Reason = "MinimumReplicasAvailable"
+		ret.Message = ""
+		ret.Available = true
+	}
+	return ret
+}
+
+func mapJob(job *batchv1.Job) operatorsv1.StatusCondition {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue || (c.Type != batchv1.JobComplete && c.Type != batchv1.JobFailed) {
+			continue
+		}
+		ret := operatorsv1.StatusCondition{
+			Name:               job.Name,
+			Kind:               "Job",
+			Type:               string(c.Type),
+			Status:             metav1.ConditionTrue,
+			LastUpdateTime:     c.LastProbeTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		}
+		if c.Type == batchv1.JobComplete {
+			ret.Available = true
+			ret.Message = ""
+		}
+		return ret
+	}
+
+	if job.Status.StartTime == nil {
+		return unknownStatus(job.Name, "Job")
+	}
+	return operatorsv1.StatusCondition{
+		Name:               job.Name,
+		Kind:               "Job",
+		Type:               "Progressing",
+		Status:             metav1.ConditionTrue,
+		LastUpdateTime:     *job.Status.StartTime,
+		LastTransitionTime: *job.Status.StartTime,
+		Reason:             "JobRunning",
+		Message: fmt.Sprintf("%d active, %d succeeded and %d failed pods", job.Status.Active, job.Status.Succeeded,
+			job.Status.Failed),
+	}
+}
+
 func mapSubscription(sub *unstructured.Unstructured) operatorsv1.StatusCondition {
 	if sub == nil {
 		return unknownStatus("", "Subscription")
@@ -912,7 +912,7 @@ func Test_getComponentStatuses(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getComponentStatuses(tt.args.hub, tt.args.allDeps, tt.args.allCRs, true, false, ""); len(got) == 0 {
+			if got := getComponentStatuses(tt.args.hub, nil, tt.args.allDeps, tt.args.allCRs, true, false, ""); len(got) == 0 {
 				t.Errorf("getComponentStatuses() = %v, want %v", got, tt.want)
 			}
 		})
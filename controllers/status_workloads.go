@@ -0,0 +1,269 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// statusWorkloadKinds are the workload kinds whose readiness is reported in the hub status
+var statusWorkloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"Job":         true,
+}
+
+/*
+operandStatusWorkloads lists by component the workloads created at runtime by the operator a component deploys, in the
+format of the status-workloads annotation. It is kept here rather than on the templates of charts generated by the
+bundle automation, which regenerating them would drop.
+*/
+var operandStatusWorkloads = map[string]string{
+	operatorv1.Search: "Deployment/search-api,Deployment/search-collector,Deployment/search-indexer," +
+		"Deployment/search-postgres",
+}
+
+// statusWorkload is a workload whose readiness is reported in the hub status
+type statusWorkload struct {
+	Kind string
+	types.NamespacedName
+}
+
+/*
+recordStatusWorkloads records the workloads of the rendered templates of a component that are reported in the hub
+status, in the registry and in the component reconcile status. Invalid entries of the status-workloads annotation are
+logged and skipped.
+*/
+func (r *MultiClusterHubReconciler) recordStatusWorkloads(m *operatorv1.MultiClusterHub, component string,
+	templates []*unstructured.Unstructured) {
+	workloads, err := renderedStatusWorkloads(m, component, templates)
+	if err != nil {
+		r.Log.Error(err, "Ignoring invalid status workloads", "Component", component)
+	}
+	if r.statusWorkloads == nil {
+		r.statusWorkloads = newRegistry[[]statusWorkload]()
+	}
+	r.statusWorkloads.set(component, workloads)
+
+	if m.Status.ComponentReconcile == nil {
+		m.Status.ComponentReconcile = map[string]operatorv1.ComponentReconcileStatus{}
+	}
+	status := m.Status.ComponentReconcile[component]
+	status.StatusWorkloads = nil
+	for _, w := range workloads {
+		status.StatusWorkloads = append(status.StatusWorkloads, operatorv1.StatusWorkloadReference{
+			Kind:      w.Kind,
+			Namespace: w.Namespace,
+			Name:      w.Name,
+		})
+	}
+	m.Status.ComponentReconcile[component] = status
+}
+
+/*
+renderedStatusWorkloads returns the status workloads of the rendered templates of a component: the Deployments,
+StatefulSets, DaemonSets and Jobs that are not opted out, the workloads listed in the status-workloads annotation of
+any template, and the operand workloads of the component.
+*/
+func renderedStatusWorkloads(m *operatorv1.MultiClusterHub, component string,
+	templates []*unstructured.Unstructured) ([]statusWorkload, error) {
+	workloads := []statusWorkload{}
+	errs := []error{}
+	for _, template := range templates {
+		namespace := template.GetNamespace()
+		if namespace == "" {
+			namespace = m.GetNamespace()
+		}
+
+		annotations := template.GetAnnotations()
+		if statusWorkloadKinds[template.GetKind()] && tracksStatus(annotations) {
+			workloads = append(workloads, statusWorkload{
+				Kind:           template.GetKind(),
+				NamespacedName: types.NamespacedName{Name: template.GetName(), Namespace: namespace},
+			})
+		}
+
+		listed, err := parseStatusWorkloads(annotations[utils.AnnotationStatusWorkloads], namespace)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", template.GetKind(), template.GetName(), err))
+		}
+		workloads = append(workloads, listed...)
+	}
+
+	operands, err := parseStatusWorkloads(operandStatusWorkloads[component], m.ComponentNamespace(component))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, w := range operands {
+		if !containsStatusWorkload(workloads, w) {
+			workloads = append(workloads, w)
+		}
+	}
+	return workloads, utilerrors.NewAggregate(errs)
+}
+
+// containsStatusWorkload returns true if the workload is in the list
+func containsStatusWorkload(workloads []statusWorkload, w statusWorkload) bool {
+	for _, existing := range workloads {
+		if existing == w {
+			return true
+		}
+	}
+	return false
+}
+
+// tracksStatus returns true unless the workload is opted out of the status, Helm hooks are only tracked on opt-in
+func tracksStatus(annotations map[string]string) bool {
+	switch strings.ToLower(annotations[utils.AnnotationStatusTracking]) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	_, hook := annotations["helm.sh/hook"]
+	return !hook
+}
+
+// parseStatusWorkloads parses a comma separated list of Kind/name or Kind/namespace/name workloads
+func parseStatusWorkloads(value, namespace string) ([]statusWorkload, error) {
+	workloads := []statusWorkload{}
+	errs := []error{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "/")
+		w := statusWorkload{Kind: parts[0], NamespacedName: types.NamespacedName{Namespace: namespace}}
+		switch len(parts) {
+		case 2:
+			w.Name = parts[1]
+		case 3:
+			w.Namespace, w.Name = parts[1], parts[2]
+		}
+		if !statusWorkloadKinds[w.Kind] || w.Name == "" || w.Namespace == "" {
+			errs = append(errs, fmt.Errorf("invalid status workload %q", entry))
+			continue
+		}
+		workloads = append(workloads, w)
+	}
+	return workloads, utilerrors.NewAggregate(errs)
+}
+
+/*
+componentStatusWorkloads returns the workloads reported in the hub status for a component. Until the chart of the
+component is rendered by the running operator, the workloads recorded in the status at the last render are reported.
+The known deployments of the component are only reported when its chart was never rendered successfully.
+*/
+func componentStatusWorkloads(rendered *registry[[]statusWorkload], m *operatorv1.MultiClusterHub, component string,
+	ocpConsole, isSTSEnabled bool) []statusWorkload {
+	if workloads, ok := rendered.get(component); ok {
+		return workloads
+	}
+
+	workloads := []statusWorkload{}
+	if recorded, ok := m.Status.ComponentReconcile[component]; ok && (recorded.StatusWorkloads != nil ||
+		(recorded.LastRender != nil && recorded.LastRender.Succeeded)) {
+		for _, w := range recorded.StatusWorkloads {
+			workloads = append(workloads, statusWorkload{
+				Kind:           w.Kind,
+				NamespacedName: types.NamespacedName{Name: w.Name, Namespace: w.Namespace},
+			})
+		}
+		return workloads
+	}
+
+	for _, d := range utils.GetComponentDeploymentsForStatus(m, component, ocpConsole, isSTSEnabled) {
+		workloads = append(workloads, statusWorkload{Kind: "Deployment", NamespacedName: d})
+	}
+	return workloads
+}
+
+/*
+mapStatusWorkloads reports the readiness of the StatefulSets, DaemonSets and Jobs in the component statuses. Deployments
+are mapped from the listed deployments. The transition time of a status is kept while the status does not change.
+*/
+func (r *MultiClusterHubReconciler) mapStatusWorkloads(ctx context.Context, hub *operatorv1.MultiClusterHub,
+	components map[string]operatorv1.StatusCondition, ocpConsole, isSTSEnabled bool) {
+	for _, component := range utils.StatusComponents(hub) {
+		for _, w := range componentStatusWorkloads(r.statusWorkloads, hub, component, ocpConsole, isSTSEnabled) {
+			if c, ok := components[w.Name]; !ok || c.Kind != w.Kind || w.Kind == "Deployment" {
+				continue
+			}
+
+			status := r.workloadStatus(ctx, w)
+			if prev, ok := hub.Status.Components[w.Name]; ok && prev.Kind == status.Kind && prev.Type == status.Type &&
+				prev.Status == status.Status && !prev.LastTransitionTime.IsZero() {
+				status.LastTransitionTime = prev.LastTransitionTime
+			} else if status.LastTransitionTime.IsZero() {
+				status.LastTransitionTime = metav1.Now()
+			}
+			components[w.Name] = status
+		}
+	}
+}
+
+// workloadStatus returns the status of a StatefulSet, DaemonSet or Job
+func (r *MultiClusterHubReconciler) workloadStatus(ctx context.Context, w statusWorkload) operatorv1.StatusCondition {
+	var obj client.Object
+	switch w.Kind {
+	case "StatefulSet":
+		obj = &appsv1.StatefulSet{}
+	case "DaemonSet":
+		obj = &appsv1.DaemonSet{}
+	case "Job":
+		obj = &batchv1.Job{}
+	default:
+		return unknownStatus(w.Name, w.Kind)
+	}
+
+	// Read directly from the API server, so the cache does not watch every workload of these kinds in the cluster
+	if err := r.apiReader().Get(ctx, w.NamespacedName, obj); err != nil {
+		if !errors.IsNotFound(err) {
+			r.Log.Error(err, "Failed to get status workload", "Kind", w.Kind, "Name", w.Name, "Namespace", w.Namespace)
+		}
+		return unknownStatus(w.Name, w.Kind)
+	}
+
+	switch o := obj.(type) {
+	case *appsv1.StatefulSet:
+		return mapStatefulSet(o)
+	case *appsv1.DaemonSet:
+		return mapDaemonSet(o)
+	case *batchv1.Job:
+		return mapJob(o)
+	}
+	return unknownStatus(w.Name, w.Kind)
+}
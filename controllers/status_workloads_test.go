@@ -0,0 +1,202 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_renderedStatusWorkloads(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+	hook := map[string]string{"helm.sh/hook": "pre-install"}
+	templates := []*unstructured.Unstructured{
+		testObject("apps/v1", "Deployment", "open-cluster-management", "operator"),
+		testObject("apps/v1", "StatefulSet", "", "database"),
+		withAnnotations(testObject("apps/v1", "DaemonSet", "open-cluster-management", "agent"),
+			map[string]string{utils.AnnotationStatusTracking: "false"}),
+		withAnnotations(testObject("batch/v1", "Job", "open-cluster-management", "pre-install"), hook),
+		withAnnotations(testObject("batch/v1", "Job", "open-cluster-management", "migration"),
+			map[string]string{"helm.sh/hook": "post-upgrade", utils.AnnotationStatusTracking: "true"}),
+		testObject("v1", "ConfigMap", "open-cluster-management", "config"),
+		withAnnotations(testObject("operators.coreos.com/v1alpha1", "Subscription", "operand", "operand-operator"),
+			map[string]string{utils.AnnotationStatusWorkloads: "Deployment/operand, StatefulSet/other/store,Pod/bad"}),
+	}
+
+	got, err := renderedStatusWorkloads(hub, operatorv1.GRC, templates)
+	if err == nil {
+		t.Error("expected an error for the invalid status workload")
+	}
+	want := []statusWorkload{
+		{Kind: "Deployment", NamespacedName: types.NamespacedName{Name: "operator", Namespace: hub.Namespace}},
+		{Kind: "StatefulSet", NamespacedName: types.NamespacedName{Name: "database", Namespace: hub.Namespace}},
+		{Kind: "Job", NamespacedName: types.NamespacedName{Name: "migration", Namespace: hub.Namespace}},
+		{Kind: "Deployment", NamespacedName: types.NamespacedName{Name: "operand", Namespace: "operand"}},
+		{Kind: "StatefulSet", NamespacedName: types.NamespacedName{Name: "store", Namespace: "other"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderedStatusWorkloads() = %v, want %v", got, want)
+	}
+
+	// The operands of search are tracked without annotating the generated chart, and only once
+	searchNamespace := hub.ComponentNamespace(operatorv1.Search)
+	got, err = renderedStatusWorkloads(hub, operatorv1.Search, []*unstructured.Unstructured{
+		testObject("apps/v1", "Deployment", searchNamespace, "search-api"),
+	})
+	if err != nil {
+		t.Errorf("renderedStatusWorkloads() error = %v", err)
+	}
+	want = []statusWorkload{}
+	for _, name := range []string{"search-api", "search-collector", "search-indexer", "search-postgres"} {
+		want = append(want, statusWorkload{Kind: "Deployment",
+			NamespacedName: types.NamespacedName{Name: name, Namespace: searchNamespace}})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderedStatusWorkloads() = %v, want %v", got, want)
+	}
+}
+
+func Test_mapStatusWorkloads(t *testing.T) {
+	registerScheme()
+	ctx := context.TODO()
+	replicas := int32(2)
+	namespace := "open-cluster-management"
+
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: namespace},
+		Spec: operatorv1.MultiClusterHubSpec{
+			Overrides: &operatorv1.Overrides{
+				Components: []operatorv1.ComponentConfig{
+					{Name: operatorv1.Insights, Enabled: true},
+					{Name: operatorv1.GRC, Enabled: true},
+				},
+			},
+		},
+	}
+	objs := []client.Object{
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "insights-store", Namespace: namespace, Generation: 1},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 2, UpdatedReplicas: 2},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "insights-agent", Namespace: namespace, Generation: 2},
+			Status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, NumberAvailable: 3,
+				UpdatedNumberScheduled: 1},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "insights-migration", Namespace: namespace},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, Reason: "CompletionsReached"},
+			}},
+		},
+	}
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+	r.recordStatusWorkloads(hub, operatorv1.Insights, []*unstructured.Unstructured{
+		withAnnotations(testObject("apps/v1", "Deployment", namespace, "insights-v2-operator-controller-manager"),
+			map[string]string{utils.AnnotationStatusWorkloads: "StatefulSet/insights-store,Deployment/insights-api"}),
+		testObject("apps/v1", "DaemonSet", namespace, "insights-agent"),
+		testObject("batch/v1", "Job", namespace, "insights-migration"),
+		testObject("batch/v1", "Job", namespace, "insights-cleanup"),
+	})
+
+	components := getComponentStatuses(hub, r.statusWorkloads, nil, map[string]*unstructured.Unstructured{}, true,
+		false, "")
+	r.mapStatusWorkloads(ctx, hub, components, true, false)
+
+	tests := []struct {
+		name          string
+		kind          string
+		wantAvailable bool
+		wantType      string
+	}{
+		{name: "insights-v2-operator-controller-manager", kind: "Deployment", wantType: "Unknown"},
+		{name: "insights-api", kind: "Deployment", wantType: "Unknown"},
+		{name: "insights-store", kind: "StatefulSet", wantAvailable: true, wantType: "Available"},
+		{name: "insights-agent", kind: "DaemonSet", wantType: "Progressing"},
+		{name: "insights-migration", kind: "Job", wantAvailable: true, wantType: string(batchv1.JobComplete)},
+		{name: "insights-cleanup", kind: "Job", wantType: "Unknown"},
+		// GRC is not rendered yet, its known deployments are reported
+		{name: "grc-policy-propagator", kind: "Deployment", wantType: "Unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := components[tt.name]
+			if !ok {
+				t.Fatalf("expected a status for %s, got %v", tt.name, components)
+			}
+			if got.Kind != tt.kind || got.Available != tt.wantAvailable || got.Type != tt.wantType {
+				t.Errorf("status = %+v, want kind %s, type %s and available %v", got, tt.kind, tt.wantType,
+					tt.wantAvailable)
+			}
+			if got.LastTransitionTime.IsZero() {
+				t.Error("expected a transition time")
+			}
+		})
+	}
+	if _, ok := components["insights-metrics"]; ok {
+		t.Error("expected the known insights deployments to be replaced by the rendered workloads")
+	}
+
+	// The transition time is kept while the status does not change
+	since := metav1.NewTime(components["insights-store"].LastTransitionTime.Add(-time.Hour))
+	prev := components["insights-store"]
+	prev.LastTransitionTime = since
+	hub.Status.Components = map[string]operatorv1.StatusCondition{"insights-store": prev}
+	r.mapStatusWorkloads(ctx, hub, components, true, false)
+	if got := components["insights-store"].LastTransitionTime; !got.Equal(&since) {
+		t.Errorf("LastTransitionTime = %v, want %v", got, since)
+	}
+}
+
+func Test_componentStatusWorkloads_Recorded(t *testing.T) {
+	namespace := "open-cluster-management"
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: namespace},
+	}
+	r := &MultiClusterHubReconciler{Log: clog.Log.WithName("test")}
+	r.recordStatusWorkloads(hub, operatorv1.Insights, []*unstructured.Unstructured{
+		testObject("apps/v1", "Deployment", namespace, "insights-api"),
+		testObject("apps/v1", "StatefulSet", namespace, "insights-store"),
+	})
+	setComponentRenderResult(hub, operatorv1.GRC, nil)
+
+	// A restarted operator reports the workloads recorded in the status until the charts are rendered again
+	want := []statusWorkload{
+		{Kind: "Deployment", NamespacedName: types.NamespacedName{Name: "insights-api", Namespace: namespace}},
+		{Kind: "StatefulSet", NamespacedName: types.NamespacedName{Name: "insights-store", Namespace: namespace}},
+	}
+	if got := componentStatusWorkloads(nil, hub, operatorv1.Insights, true, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("componentStatusWorkloads(insights) = %v, want the recorded workloads %v", got, want)
+	}
+	if got := componentStatusWorkloads(nil, hub, operatorv1.GRC, true, false); len(got) != 0 {
+		t.Errorf("componentStatusWorkloads(grc) = %v, want none for a chart rendered without workloads", got)
+	}
+
+	// The known deployments are only reported for charts that were never rendered
+	if got := componentStatusWorkloads(nil, hub, operatorv1.Console, true, false); len(got) != 2 {
+		t.Errorf("componentStatusWorkloads(console) = %v, want the known console deployments", got)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"path"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/overrideschema"
+)
+
+/*
+validateTemplateOverrides drops the template overrides that no component chart supports, or whose value does not
+match the chart schema, and reports them in the hub status. It returns the remaining overrides with the chart schemas.
+The overrides are kept as they are when the schemas cannot be read.
+*/
+func (r *MultiClusterHubReconciler) validateTemplateOverrides(m *operatorv1.MultiClusterHub,
+	templateOverrides map[string]string) (map[string]string, overrideschema.Schemas) {
+	schemas, err := overrideschema.LoadToggleCharts()
+	if err != nil {
+		r.Log.Error(err, "Failed to read the template override schemas of the component charts")
+		return templateOverrides, nil
+	}
+
+	valid, rejected := schemas.Validate(templateOverrides)
+	m.Status.RejectedTemplateOverrides = nil
+	for _, rejection := range rejected {
+		r.Log.Info("Ignoring template override", "Key", rejection.Key, "Reason", rejection.Reason)
+		m.Status.RejectedTemplateOverrides = append(m.Status.RejectedTemplateOverrides,
+			operatorv1.RejectedTemplateOverride{Key: rejection.Key, Reason: rejection.Reason})
+	}
+	return valid, schemas
+}
+
+// setComponentTemplateOverrides records the template overrides read by the chart of a component in the hub status
+func setComponentTemplateOverrides(m *operatorv1.MultiClusterHub, component, chartLocation string,
+	cachespec CacheSpec) {
+	var effective map[string]string
+	if schema, ok := cachespec.TemplateOverrideSchemas[path.Base(chartLocation)]; ok {
+		effective = schema.Effective(cachespec.TemplateOverrides)
+	}
+
+	if m.Status.ComponentReconcile == nil {
+		m.Status.ComponentReconcile = map[string]operatorv1.ComponentReconcileStatus{}
+	}
+	status := m.Status.ComponentReconcile[component]
+	status.TemplateOverrides = effective
+	m.Status.ComponentReconcile[component] = status
+}
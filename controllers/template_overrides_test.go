@@ -0,0 +1,52 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_validateTemplateOverrides(t *testing.T) {
+	t.Setenv("TEMPLATES_PATH", "../pkg/templates")
+	hub := &operatorv1.MultiClusterHub{}
+	r := &MultiClusterHubReconciler{Log: clog.Log.WithName("test")}
+
+	overrides, schemas := r.validateTemplateOverrides(hub, map[string]string{
+		"console_deployment_container_memory_limit": "2Gi",
+		"console_deployment_container_cpu_limit":    "fast",
+		"console_deployment_container_memory_limt":  "1Gi",
+	})
+
+	if want := map[string]string{"console_deployment_container_memory_limit": "2Gi"}; !reflect.DeepEqual(overrides,
+		want) {
+		t.Errorf("validateTemplateOverrides() = %v, want %v", overrides, want)
+	}
+	rejected := hub.Status.RejectedTemplateOverrides
+	if len(rejected) != 2 || rejected[0].Key != "console_deployment_container_cpu_limit" ||
+		rejected[1].Key != "console_deployment_container_memory_limt" {
+		t.Errorf("RejectedTemplateOverrides = %+v, want the invalid value and the unknown key", rejected)
+	}
+
+	cachespec := CacheSpec{TemplateOverrides: overrides, TemplateOverrideSchemas: schemas}
+	setComponentTemplateOverrides(hub, operatorv1.Console, utils.ConsoleChartLocation, cachespec)
+	setComponentTemplateOverrides(hub, operatorv1.GRC, utils.GRCChartLocation, cachespec)
+	if got := hub.Status.ComponentReconcile[operatorv1.Console].TemplateOverrides; !reflect.DeepEqual(got, overrides) {
+		t.Errorf("console TemplateOverrides = %v, want %v", got, overrides)
+	}
+	if got := hub.Status.ComponentReconcile[operatorv1.GRC].TemplateOverrides; got != nil {
+		t.Errorf("grc TemplateOverrides = %v, want none", got)
+	}
+
+	// Overrides are kept when the chart schemas cannot be read
+	t.Setenv("TEMPLATES_PATH", "/nonexistent")
+	overrides, schemas = r.validateTemplateOverrides(hub, map[string]string{"unknown": "1"})
+	if len(overrides) != 1 || schemas != nil {
+		t.Errorf("validateTemplateOverrides() = %v, %v, want the overrides unchanged", overrides, schemas)
+	}
+}
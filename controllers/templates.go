@@ -77,11 +77,11 @@ func (r *MultiClusterHubReconciler) applyTemplate(ctx context.Context, m *operat
 			// Template resource does not exist
 			if errors.IsNotFound(err) {
 				if err := r.Client.Create(ctx, template, &client.CreateOptions{}); err != nil {
-					return r.logAndSetCondition(err, "failed to create resource", template, m)
+					return r.logApplyError(err, "failed to create resource", template)
 				}
 				log.Info("Creating resource", "Kind", template.GetKind(), "Name", template.GetName())
 			} else {
-				return r.logAndSetCondition(err, "failed to get resource", existing, m)
+				return r.logApplyError(err, "failed to get resource", existing)
 			}
 		} else {
 			// Resource exists - ensure we should manage it (adds labels to template if adopting)
@@ -160,6 +160,13 @@ func (r *MultiClusterHubReconciler) applyTemplate(ctx context.Context, m *operat
 				}
 			}
 
+			// Keep the fields the hub lists as preserved at their value in the cluster
+			if paths := preservedFieldPaths(m, r.CacheSpec.PreservedFields, template); len(paths) > 0 {
+				if err := preserveFields(existing, template, paths); err != nil {
+					return r.logApplyError(err, "failed to preserve fields of resource", template)
+				}
+			}
+
 			if !utils.IsTemplateAnnotationTrue(template, utils.AnnotationEditable) {
 				// Check if we need to use Update instead of Patch due to container changes
 				useUpdate := false
@@ -179,14 +186,14 @@ func (r *MultiClusterHubReconciler) applyTemplate(ctx context.Context, m *operat
 					// Use Update to replace entire spec when containers are added/removed
 					// Server-side apply cannot remove elements from arrays
 					if err := r.Client.Update(ctx, template); err != nil {
-						return r.logAndSetCondition(err, "failed to update resource", template, m)
+						return r.logApplyError(err, "failed to update resource", template)
 					}
 				} else {
 					// Use server-side apply for normal updates
 					force := true
 					if err := r.Client.Patch(ctx, template, client.Apply, &client.PatchOptions{
 						Force: &force, FieldManager: "multiclusterhub-operator"}); err != nil {
-						return r.logAndSetCondition(err, "failed to update resource", template, m)
+						return r.logApplyError(err, "failed to update resource", template)
 					}
 				}
 			}
@@ -211,9 +218,10 @@ func (r *MultiClusterHubReconciler) deleteTemplate(ctx context.Context, m *opera
 		return ctrl.Result{}, err
 	}
 
-	// Only delete resources managed by this MCH instance
-	// In deleteTemplate, template is both existing and desired state
-	if !r.ensureResourceOwnership(template, template, m) {
+	// Only delete resources already labeled by this MCH instance. Adoption rules and adopt-now are not applied here, as
+	// they would adopt unowned resources of a disabled component only to delete them.
+	labels := template.GetLabels()
+	if labels["installer.name"] != m.GetName() || labels["installer.namespace"] != m.GetNamespace() {
 		r.Log.Info("Skipping deletion of resource not managed by this operator",
 			"Kind", template.GetKind(),
 			"Name", template.GetName(),
@@ -251,10 +259,12 @@ func (r *MultiClusterHubReconciler) deleteTemplate(ctx context.Context, m *opera
 }
 
 // ensureResourceOwnership checks if a resource should be managed by MCH and adds
-// installer labels to the template if adopting based on the resource adoption policy annotation.
+// installer labels to the template if adopting based on the adoption rules of the MCH spec,
+// the resource adoption policy annotation and a pending adopt-now request.
 // existing: the current state in cluster (checked for labels)
 // template: the desired state (labels added here if adopting)
 // Returns true if the resource should be managed, false otherwise.
+// Resources that are not owned by this MCH are recorded in the adoption report.
 func (r *MultiClusterHubReconciler) ensureResourceOwnership(existing, template *unstructured.Unstructured, m *operatorv1.MultiClusterHub) bool {
 	existingLabels := existing.GetLabels()
 	if existingLabels == nil {
@@ -262,48 +272,59 @@ func (r *MultiClusterHubReconciler) ensureResourceOwnership(existing, template *
 	}
 
 	// Check if existing resource already has installer labels
-	_, hasInstallerName := existingLabels["installer.name"]
-	_, hasInstallerNamespace := existingLabels["installer.namespace"]
+	installerName, hasInstallerName := existingLabels["installer.name"]
+	installerNamespace, hasInstallerNamespace := existingLabels["installer.namespace"]
 
 	if hasInstallerName && hasInstallerNamespace {
 		// Already labeled - manage it
+		if installerName != m.GetName() || installerNamespace != m.GetNamespace() {
+			r.recordAdoptionCandidate(existing, operatorv1.AdoptionStateOwnedByOtherHub,
+				installerNamespace+"/"+installerName, operatorv1.AdoptionActionManaged)
+		}
 		return true
 	}
 
-	// Partial labels indicate corrupted/ambiguous state - don't manage
+	// Adoption rules take precedence over the annotation, and a Strict rule also excludes resources from adopt-now
+	adoptionPolicy, fromRule := r.resourceAdoptionPolicy(existing, m)
+	adoptNow := r.adoptingNow() && !(fromRule && adoptionPolicy == operatorv1.AdoptionStrict)
+
+	state := operatorv1.AdoptionStateUnowned
 	if hasInstallerName != hasInstallerNamespace {
-		r.Log.Info("Resource has partial installer labels - skipping",
-			"Kind", existing.GetKind(),
-			"Name", existing.GetName(),
-			"hasName", hasInstallerName,
-			"hasNamespace", hasInstallerNamespace)
+		// Partial labels indicate corrupted/ambiguous state - only an adopt-now request repairs them
+		state = operatorv1.AdoptionStatePartiallyLabeled
+		if !adoptNow {
+			r.Log.Info("Resource has partial installer labels - skipping",
+				"Kind", existing.GetKind(),
+				"Name", existing.GetName(),
+				"hasName", hasInstallerName,
+				"hasNamespace", hasInstallerNamespace)
+			r.recordAdoptionCandidate(existing, state, "", operatorv1.AdoptionActionSkipped)
+			return false
+		}
+	} else if adoptionPolicy != operatorv1.AdoptionAdopt && !adoptNow {
+		// Strict mode (default) - only manage resources with installer labels
+		r.recordAdoptionCandidate(existing, state, "", operatorv1.AdoptionActionSkipped)
 		return false
 	}
 
-	// No installer labels on existing resource - check adoption policy from MCH annotation
-	adoptionPolicy := r.getAdoptionPolicy(m)
-
-	if adoptionPolicy == "Adopt" {
-		// Adopt the resource by adding installer labels to template (desired state)
-		templateLabels := template.GetLabels()
-		if templateLabels == nil {
-			templateLabels = make(map[string]string)
-		}
-		templateLabels["installer.name"] = m.GetName()
-		templateLabels["installer.namespace"] = m.GetNamespace()
-		template.SetLabels(templateLabels)
-
-		r.Log.Info("Adopting resource by adding installer labels to desired state",
-			"Kind", existing.GetKind(),
-			"Name", existing.GetName(),
-			"Namespace", existing.GetNamespace(),
-			"Policy", adoptionPolicy)
-
-		return true
+	// Adopt the resource by adding installer labels to template (desired state)
+	templateLabels := template.GetLabels()
+	if templateLabels == nil {
+		templateLabels = make(map[string]string)
 	}
-
-	// Strict mode (default) - only manage resources with installer labels
-	return false
+	templateLabels["installer.name"] = m.GetName()
+	templateLabels["installer.namespace"] = m.GetNamespace()
+	template.SetLabels(templateLabels)
+
+	r.Log.Info("Adopting resource by adding installer labels to desired state",
+		"Kind", existing.GetKind(),
+		"Name", existing.GetName(),
+		"Namespace", existing.GetNamespace(),
+		"Policy", adoptionPolicy,
+		"AdoptNow", adoptNow)
+	r.recordAdoptionCandidate(existing, state, "", operatorv1.AdoptionActionAdopted)
+
+	return true
 }
 
 // getAdoptionPolicy retrieves the resource adoption policy from MCH annotations.
@@ -359,19 +380,13 @@ func (r *MultiClusterHubReconciler) ensureResourceVersionAlignment(template *uns
 	return true // Resource is aligned with the desired version
 }
 
-func (r *MultiClusterHubReconciler) logAndSetCondition(err error, message string,
-	template *unstructured.Unstructured, m *operatorv1.MultiClusterHub) (ctrl.Result, error) {
+// logApplyError logs a failure to apply a template and returns the error wrapped with the template kind and name
+func (r *MultiClusterHubReconciler) logApplyError(err error, message string,
+	template *unstructured.Unstructured) (ctrl.Result, error) {
 
 	log.Error(err, message, "Kind", template.GetKind(), "Name", template.GetName())
-	wrappedError := pkgerrors.Wrapf(err, "%s Kind: %s Name: %s", message, template.GetKind(), template.GetName())
-
-	condType := fmt.Sprintf("%v: %v (Kind:%v)", operatorv1.ComponentFailure, template.GetName(),
-		template.GetKind())
-
-	SetHubCondition(&m.Status, *NewHubCondition(operatorv1.HubConditionType(condType), metav1.ConditionTrue,
-		FailedApplyingComponent, wrappedError.Error()))
-
-	return ctrl.Result{}, wrappedError
+	return ctrl.Result{}, pkgerrors.Wrapf(err, "%s Kind: %s Name: %s", message, template.GetKind(),
+		template.GetName())
 }
 
 // detectContainerChanges checks if containers have been added or removed between existing and desired deployments.
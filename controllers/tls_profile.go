@@ -0,0 +1,78 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"slices"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	utils "github.com/stolostron/multiclusterhub-operator/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// observeTLSProfile swaps the TLS profile to the one set on the APIServer resource. The webhook and metrics servers and
+// the catalogd client follow the profile on their next connection.
+func (r *MultiClusterHubReconciler) observeTLSProfile(apiServer *configv1.APIServer) {
+	if r.TLSProfile == nil {
+		return
+	}
+	profileType, spec := utils.TLSProfileFromAPIServer(apiServer)
+	if r.TLSProfile.Update(profileType, *spec) {
+		r.Log.Info("TLS profile changed", "type", profileType, "minTLSVersion", spec.MinTLSVersion)
+	}
+}
+
+// refreshTLSProfile reads the APIServer resource in case a change was missed. The current profile is kept if the
+// resource cannot be read.
+func (r *MultiClusterHubReconciler) refreshTLSProfile(ctx context.Context) {
+	if r.TLSProfile == nil {
+		return
+	}
+	apiServer := &configv1.APIServer{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: "cluster"}, apiServer); err != nil {
+		r.Log.Error(err, "Unable to read the TLS profile, keeping the current profile")
+		return
+	}
+	r.observeTLSProfile(apiServer)
+}
+
+// tlsProfileStatus returns the TLS profile in effect for the MCH status
+func (r *MultiClusterHubReconciler) tlsProfileStatus(hub *operatorv1.MultiClusterHub) *operatorv1.TLSProfileStatus {
+	if r.TLSProfile == nil {
+		return hub.Status.TLSProfile
+	}
+	profileType, spec := r.TLSProfile.Get()
+	status := &operatorv1.TLSProfileStatus{
+		Type:               string(profileType),
+		MinTLSVersion:      string(spec.MinTLSVersion),
+		LastTransitionTime: metav1.Now(),
+	}
+	if len(spec.Ciphers) > 0 {
+		status.Ciphers = spec.Ciphers
+	}
+
+	if previous := hub.Status.TLSProfile; previous != nil && previous.Type == status.Type &&
+		previous.MinTLSVersion == status.MinTLSVersion && slices.Equal(previous.Ciphers, status.Ciphers) {
+		status.LastTransitionTime = previous.LastTransitionTime
+	}
+	return status
+}
@@ -0,0 +1,53 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/tlsprofile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_tlsProfileStatus(t *testing.T) {
+	hub := &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+	}
+	r := &MultiClusterHubReconciler{Log: clog.Log.WithName("test")}
+	if got := r.tlsProfileStatus(hub); got != nil {
+		t.Errorf("tlsProfileStatus() = %+v without a profile, want the previous status", got)
+	}
+
+	r.TLSProfile = tlsprofile.Default()
+	hub.Status.TLSProfile = r.tlsProfileStatus(hub)
+	if got := hub.Status.TLSProfile; got.Type != string(configv1.TLSProfileIntermediateType) ||
+		got.MinTLSVersion != string(configv1.VersionTLS12) || len(got.Ciphers) == 0 {
+		t.Errorf("tlsProfileStatus() = %+v, want the Intermediate profile", got)
+	}
+
+	// The transition time only changes with the profile
+	since := metav1.NewTime(hub.Status.TLSProfile.LastTransitionTime.Add(-time.Hour))
+	hub.Status.TLSProfile.LastTransitionTime = since
+	if got := r.tlsProfileStatus(hub); !got.LastTransitionTime.Equal(&since) {
+		t.Errorf("LastTransitionTime = %v, want %v for the same profile", got.LastTransitionTime, since)
+	}
+
+	r.observeTLSProfile(&configv1.APIServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: configv1.APIServerSpec{
+			TLSSecurityProfile: &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType},
+		},
+	})
+	got := r.tlsProfileStatus(hub)
+	if got.Type != string(configv1.TLSProfileModernType) || got.MinTLSVersion != string(configv1.VersionTLS13) {
+		t.Errorf("tlsProfileStatus() = %+v, want the Modern profile", got)
+	}
+	if got.LastTransitionTime.Equal(&since) {
+		t.Error("LastTransitionTime was not updated with the profile")
+	}
+}
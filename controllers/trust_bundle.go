@@ -0,0 +1,369 @@
+// Copyright Contributors to the Open Cluster Management project
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// hubTrustBundleName is the ConfigMap holding the hub trust bundle in every component namespace
+	hubTrustBundleName = "hub-trusted-ca-bundle"
+
+	// trustBundleKey is the key of the PEM encoded certificates in trust bundle ConfigMaps and Secrets
+	trustBundleKey = "ca-bundle.crt"
+
+	// trustBundleMountPath is where a trust bundle holding the cluster trust bundle is mounted in component
+	// containers. It replaces the system trust store, which the cluster trust bundle already contains, so it is only
+	// mounted where the bundle exists.
+	trustBundleMountPath = "/etc/pki/ca-trust/extracted/pem"
+
+	// customCAMountPath is where a trust bundle holding only the spec.trustedCA certificates is mounted. It is added
+	// to the certificate directories of the containers so the system trust store stays in place.
+	customCAMountPath = "/etc/pki/ca-trust/hub"
+
+	// certDirsEnvVar lists the directories Go and OpenSSL load CA certificates from next to the system bundle
+	certDirsEnvVar = "SSL_CERT_DIR"
+
+	// systemCertDirs are the certificate directories used when SSL_CERT_DIR is not set
+	systemCertDirs = "/etc/ssl/certs:/etc/pki/tls/certs"
+
+	// AnnotationTrustBundleHash records the hash of the trust bundle a ConfigMap or pod template was built from
+	AnnotationTrustBundleHash = "installer.open-cluster-management.io/trust-bundle-hash"
+)
+
+// trustBundleConfigMapName returns the name of the ConfigMap the cluster network operator injects the cluster trust
+// bundle into
+func trustBundleConfigMapName() string {
+	if name, ok := os.LookupEnv(trustBundleNameEnvVar); ok && name != "" {
+		return name
+	}
+	return defaultTrustBundleName
+}
+
+// hubTrustConfig is the trust bundle and proxy configuration propagated to the hub components
+type hubTrustConfig struct {
+	bundle        string
+	clusterBundle bool
+	customCA      bool
+	proxy         *operatorv1.ProxyStatus
+	messages      []string
+}
+
+// hash returns a hash of the trust bundle content, or an empty string if there is no bundle
+func (c *hubTrustConfig) hash() string {
+	if c.bundle == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(c.bundle))
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+readTrustConfig reads the cluster trust bundle injected by the cluster network operator, the CA certificates of
+spec.trustedCA and the cluster-wide proxy. Problems with the optional inputs are reported as messages rather than
+errors so the hub keeps reconciling with the configuration that could be read.
+*/
+func (r *MultiClusterHubReconciler) readTrustConfig(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	*hubTrustConfig, error) {
+	cfg := &hubTrustConfig{}
+	var parts []string
+
+	cm := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: trustBundleConfigMapName(), Namespace: m.GetNamespace()}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if bundle := strings.TrimSpace(cm.Data[trustBundleKey]); bundle != "" {
+		parts = append(parts, bundle)
+		cfg.clusterBundle = true
+	}
+
+	if m.Spec.TrustedCA != nil && m.Spec.TrustedCA.SecretName != "" {
+		secret := &corev1.Secret{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: m.Spec.TrustedCA.SecretName,
+			Namespace: m.GetNamespace()}, secret)
+		switch {
+		case errors.IsNotFound(err):
+			cfg.messages = append(cfg.messages, fmt.Sprintf("trusted CA secret %s not found",
+				m.Spec.TrustedCA.SecretName))
+		case err != nil:
+			return nil, err
+		case !strings.Contains(string(secret.Data[trustBundleKey]), "-----BEGIN CERTIFICATE-----"):
+			cfg.messages = append(cfg.messages, fmt.Sprintf("trusted CA secret %s has no PEM certificates under %s",
+				m.Spec.TrustedCA.SecretName, trustBundleKey))
+		default:
+			parts = append(parts, strings.TrimSpace(string(secret.Data[trustBundleKey])))
+			cfg.customCA = true
+		}
+	}
+	if len(parts) > 0 {
+		cfg.bundle = strings.Join(parts, "\n") + "\n"
+	}
+
+	proxy := &configv1.Proxy{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: "cluster"}, proxy)
+	switch {
+	case err == nil:
+		if proxy.Status.HTTPProxy != "" || proxy.Status.HTTPSProxy != "" || proxy.Status.NoProxy != "" {
+			cfg.proxy = &operatorv1.ProxyStatus{
+				HTTPProxy:  proxy.Status.HTTPProxy,
+				HTTPSProxy: proxy.Status.HTTPSProxy,
+				NoProxy:    proxy.Status.NoProxy,
+			}
+		}
+	case errors.IsNotFound(err) || meta.IsNoMatchError(err):
+		// Not an OpenShift cluster proxy, fall back to the proxy the operator was started with
+		if utils.ProxyEnvVarsAreSet() {
+			cfg.proxy = &operatorv1.ProxyStatus{
+				HTTPProxy:  os.Getenv("HTTP_PROXY"),
+				HTTPSProxy: os.Getenv("HTTPS_PROXY"),
+				NoProxy:    os.Getenv("NO_PROXY"),
+			}
+		}
+	default:
+		return nil, err
+	}
+	return cfg, nil
+}
+
+/*
+ensureTrustBundle replicates the hub trust bundle into every component namespace and records the trust bundle and
+proxy configuration in the hub status, where ensureComponent picks them up to configure the component deployments.
+Bundles modified outside the operator are restored and reported as drifted.
+*/
+func (r *MultiClusterHubReconciler) ensureTrustBundle(ctx context.Context, m *operatorv1.MultiClusterHub) (
+	ctrl.Result, error) {
+	cfg, err := r.readTrustConfig(ctx, m)
+	if err != nil {
+		log.Error(err, "Failed to read the hub trust configuration")
+		return ctrl.Result{}, err
+	}
+
+	previous := m.Status.TrustBundle
+	status := &operatorv1.TrustBundleStatus{
+		Hash:          cfg.hash(),
+		ClusterBundle: cfg.clusterBundle,
+		CustomCA:      cfg.customCA,
+		Proxy:         cfg.proxy,
+		Message:       strings.Join(cfg.messages, "; "),
+	}
+	if previous != nil {
+		status.LastSyncTime = previous.LastSyncTime
+	}
+
+	if status.Hash != "" {
+		for _, ns := range utils.TrackedNamespaces(m) {
+			synced, drifted, err := r.syncTrustBundle(ctx, m, ns, cfg.bundle, status.Hash)
+			if err != nil {
+				log.Error(err, "Failed to sync the hub trust bundle", "Namespace", ns)
+				return ctrl.Result{}, err
+			}
+			// A bundle removed since the last sync has drifted as well
+			if drifted || (synced && previous != nil && previous.Hash == status.Hash &&
+				slices.Contains(previous.Namespaces, ns)) {
+				status.DriftedNamespaces = append(status.DriftedNamespaces, ns)
+			}
+			if synced {
+				status.LastSyncTime = metav1.Now()
+			}
+			status.Namespaces = append(status.Namespaces, ns)
+		}
+	}
+
+	if len(status.DriftedNamespaces) > 0 {
+		log.Info("Restored drifted hub trust bundle", "Namespaces", status.DriftedNamespaces)
+	}
+	m.Status.TrustBundle = status
+	return ctrl.Result{}, nil
+}
+
+/*
+syncTrustBundle writes the hub trust bundle to a component namespace. It returns whether the bundle had to be written
+and whether the existing bundle had been modified outside the operator.
+*/
+func (r *MultiClusterHubReconciler) syncTrustBundle(ctx context.Context, m *operatorv1.MultiClusterHub, ns, bundle,
+	hash string) (synced, drifted bool, err error) {
+	existing := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, types.NamespacedName{Name: hubTrustBundleName, Namespace: ns}, existing)
+	if errors.IsNotFound(err) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        hubTrustBundleName,
+				Namespace:   ns,
+				Labels:      map[string]string{"installer.name": m.GetName(), "installer.namespace": m.GetNamespace()},
+				Annotations: map[string]string{AnnotationTrustBundleHash: hash},
+			},
+			Data: map[string]string{trustBundleKey: bundle},
+		}
+		if ns == m.GetNamespace() {
+			if err := ctrl.SetControllerReference(m, cm, r.Scheme); err != nil {
+				return false, false, err
+			}
+		}
+		return true, false, r.Client.Create(ctx, cm)
+	} else if err != nil {
+		return false, false, err
+	}
+
+	if existing.Data[trustBundleKey] == bundle && existing.GetAnnotations()[AnnotationTrustBundleHash] == hash {
+		return false, false, nil
+	}
+
+	// The content no longer matches the hash it was written with, so it was changed outside the operator
+	recorded := existing.GetAnnotations()[AnnotationTrustBundleHash]
+	sum := sha256.Sum256([]byte(existing.Data[trustBundleKey]))
+	drifted = recorded != "" && recorded != hex.EncodeToString(sum[:])
+
+	annotations := existing.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationTrustBundleHash] = hash
+	existing.SetAnnotations(annotations)
+	existing.Data = map[string]string{trustBundleKey: bundle}
+	return true, drifted, r.Client.Update(ctx, existing)
+}
+
+/*
+injectTrustConfig mounts the hub trust bundle into the containers of a rendered Deployment and sets the cluster proxy
+environment variables. The bundle hash is recorded on the pod template so the pods roll whenever the bundle changes.
+A bundle holding the cluster trust bundle replaces the system trust store of the containers, so it is only mounted in
+the namespaces it was synced to, and the pods do not start without it. A bundle holding only the spec.trustedCA
+certificates is mounted next to the system trust store instead.
+*/
+func injectTrustConfig(template *unstructured.Unstructured, status *operatorv1.TrustBundleStatus) error {
+	if status == nil || (status.Hash == "" && status.Proxy == nil) {
+		return nil
+	}
+	mountBundle := status.Hash != "" && slices.Contains(status.Namespaces, template.GetNamespace())
+
+	podSpec, found, err := unstructured.NestedMap(template.Object, "spec", "template", "spec")
+	if err != nil || !found {
+		log.Error(err, "Failed to get pod spec from template", "Kind", template.GetKind(), "Name", template.GetName())
+		return err
+	}
+	containers, _ := podSpec["containers"].([]interface{})
+
+	if mountBundle {
+		volumes, _ := podSpec["volumes"].([]interface{})
+		podSpec["volumes"] = setNamedEntry(volumes, map[string]interface{}{
+			"name": hubTrustBundleName,
+			"configMap": map[string]interface{}{
+				"name": hubTrustBundleName,
+				"items": []interface{}{
+					map[string]interface{}{"key": trustBundleKey, "path": "tls-ca-bundle.pem"},
+				},
+			},
+		})
+
+		mountPath := trustBundleMountPath
+		if !status.ClusterBundle {
+			mountPath = customCAMountPath
+		}
+		for i, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			mounts, _ := container["volumeMounts"].([]interface{})
+			container["volumeMounts"] = setNamedEntry(mounts, map[string]interface{}{
+				"name":      hubTrustBundleName,
+				"mountPath": mountPath,
+				"readOnly":  true,
+			})
+			if !status.ClusterBundle {
+				env, _ := container["env"].([]interface{})
+				container["env"] = setNamedEntry(env, map[string]interface{}{
+					"name":  certDirsEnvVar,
+					"value": systemCertDirs + ":" + customCAMountPath,
+				})
+			}
+			containers[i] = container
+		}
+
+		annotations, _, _ := unstructured.NestedStringMap(template.Object, "spec", "template", "metadata",
+			"annotations")
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[AnnotationTrustBundleHash] = status.Hash
+		if err := unstructured.SetNestedStringMap(template.Object, annotations, "spec", "template", "metadata",
+			"annotations"); err != nil {
+			return err
+		}
+	}
+
+	if proxy := status.Proxy; proxy != nil {
+		for i, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			env, _ := container["env"].([]interface{})
+			for _, v := range []struct{ name, value string }{
+				{"HTTP_PROXY", proxy.HTTPProxy},
+				{"HTTPS_PROXY", proxy.HTTPSProxy},
+				{"NO_PROXY", proxy.NoProxy},
+			} {
+				if v.value != "" {
+					env = setNamedEntry(env, map[string]interface{}{"name": v.name, "value": v.value})
+				}
+			}
+			container["env"] = env
+			containers[i] = container
+		}
+	}
+
+	podSpec["containers"] = containers
+	if err := unstructured.SetNestedMap(template.Object, podSpec, "spec", "template", "spec"); err != nil {
+		log.Error(err, "Failed to set pod spec in template", "Template", template.GetName())
+		return err
+	}
+	return nil
+}
+
+// setNamedEntry replaces the entry with the same name in a list of named entries, or appends it
+func setNamedEntry(entries []interface{}, entry map[string]interface{}) []interface{} {
+	for i, e := range entries {
+		if existing, ok := e.(map[string]interface{}); ok && existing["name"] == entry["name"] {
+			entries[i] = entry
+			return entries
+		}
+	}
+	return append(entries, entry)
+}
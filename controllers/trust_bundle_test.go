@@ -0,0 +1,254 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	clusterCA = "-----BEGIN CERTIFICATE-----\ncluster\n-----END CERTIFICATE-----"
+	customCA  = "-----BEGIN CERTIFICATE-----\ncustom\n-----END CERTIFICATE-----"
+)
+
+func trustBundleHub() *operatorv1.MultiClusterHub {
+	return &operatorv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterhub", Namespace: "open-cluster-management"},
+		Spec: operatorv1.MultiClusterHubSpec{
+			TrustedCA: &operatorv1.TrustedCAConfig{SecretName: "custom-ca"},
+			Overrides: &operatorv1.Overrides{
+				Components: []operatorv1.ComponentConfig{{Name: operatorv1.ClusterBackup, Enabled: true}},
+			},
+		},
+	}
+}
+
+func trustBundleObjects() []client.Object {
+	return []client.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTrustBundleName, Namespace: "open-cluster-management"},
+			Data:       map[string]string{trustBundleKey: clusterCA},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "custom-ca", Namespace: "open-cluster-management"},
+			Data:       map[string][]byte{trustBundleKey: []byte(customCA)},
+		},
+		&configv1.Proxy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status: configv1.ProxyStatus{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "http://proxy.example.com:3128",
+				NoProxy:    ".cluster.local",
+			},
+		},
+	}
+}
+
+func Test_ensureTrustBundle(t *testing.T) {
+	registerScheme()
+	hub := trustBundleHub()
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(trustBundleObjects()...).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+
+	if _, err := r.ensureTrustBundle(context.Background(), hub); err != nil {
+		t.Fatalf("ensureTrustBundle() error = %v", err)
+	}
+
+	status := hub.Status.TrustBundle
+	if status == nil || status.Hash == "" || !status.ClusterBundle || !status.CustomCA || status.Message != "" {
+		t.Fatalf("expected a trust bundle with the custom CA, got %+v", status)
+	}
+	if status.Proxy == nil || status.Proxy.NoProxy != ".cluster.local" {
+		t.Errorf("expected the cluster proxy in the status, got %+v", status.Proxy)
+	}
+	if len(status.Namespaces) != 2 || len(status.DriftedNamespaces) != 0 {
+		t.Errorf("expected the bundle in both component namespaces without drift, got %+v", status)
+	}
+
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: hubTrustBundleName, Namespace: "open-cluster-management-backup"}
+	if err := r.Client.Get(context.Background(), key, cm); err != nil {
+		t.Fatalf("failed to get replicated trust bundle: %v", err)
+	}
+	if cm.Data[trustBundleKey] != clusterCA+"\n"+customCA+"\n" {
+		t.Errorf("replicated bundle = %q, want the cluster and custom CAs", cm.Data[trustBundleKey])
+	}
+
+	// Modifying a replicated bundle is reported as drift and restored
+	cm.Data[trustBundleKey] = "tampered"
+	if err := r.Client.Update(context.Background(), cm); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ensureTrustBundle(context.Background(), hub); err != nil {
+		t.Fatalf("ensureTrustBundle() error = %v", err)
+	}
+	if got := hub.Status.TrustBundle.DriftedNamespaces; len(got) != 1 || got[0] != key.Namespace {
+		t.Errorf("DriftedNamespaces = %v, want [%s]", got, key.Namespace)
+	}
+	if err := r.Client.Get(context.Background(), key, cm); err != nil {
+		t.Fatal(err)
+	}
+	if cm.Data[trustBundleKey] != clusterCA+"\n"+customCA+"\n" {
+		t.Errorf("expected the drifted bundle to be restored, got %q", cm.Data[trustBundleKey])
+	}
+
+	// The drift is cleared once the bundles match again
+	if _, err := r.ensureTrustBundle(context.Background(), hub); err != nil {
+		t.Fatalf("ensureTrustBundle() error = %v", err)
+	}
+	if got := hub.Status.TrustBundle.DriftedNamespaces; len(got) != 0 {
+		t.Errorf("DriftedNamespaces = %v, want none", got)
+	}
+}
+
+func Test_readTrustConfig_missingSecret(t *testing.T) {
+	registerScheme()
+	hub := trustBundleHub()
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(trustBundleObjects()[0]).Build(),
+		Log:    clog.Log.WithName("test"),
+	}
+
+	cfg, err := r.readTrustConfig(context.Background(), hub)
+	if err != nil {
+		t.Fatalf("readTrustConfig() error = %v", err)
+	}
+	if cfg.customCA || cfg.bundle != clusterCA+"\n" || len(cfg.messages) != 1 {
+		t.Errorf("expected only the cluster bundle and a message about the secret, got %+v", cfg)
+	}
+}
+
+func Test_injectTrustConfig(t *testing.T) {
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "search-api", "namespace": "open-cluster-management"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name": "search-api",
+							"env": []interface{}{
+								map[string]interface{}{"name": "HTTP_PROXY", "value": "http://old:3128"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	status := &operatorv1.TrustBundleStatus{
+		Hash:          "abc",
+		ClusterBundle: true,
+		Namespaces:    []string{"open-cluster-management"},
+		Proxy:         &operatorv1.ProxyStatus{HTTPProxy: "http://proxy:3128", NoProxy: ".svc"},
+	}
+	unsynced := deploy.DeepCopy()
+	unsynced.SetNamespace("open-cluster-management-observability")
+	if err := injectTrustConfig(deploy, status); err != nil {
+		t.Fatalf("injectTrustConfig() error = %v", err)
+	}
+	// Injecting twice does not duplicate entries
+	if err := injectTrustConfig(deploy, status); err != nil {
+		t.Fatalf("injectTrustConfig() error = %v", err)
+	}
+
+	volumes, _, _ := unstructured.NestedSlice(deploy.Object, "spec", "template", "spec", "volumes")
+	if len(volumes) != 1 {
+		t.Errorf("volumes = %v, want the trust bundle volume", volumes)
+	}
+	containers, _, _ := unstructured.NestedSlice(deploy.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	if mounts := container["volumeMounts"].([]interface{}); len(mounts) != 1 ||
+		mounts[0].(map[string]interface{})["mountPath"] != trustBundleMountPath {
+		t.Errorf("volumeMounts = %v, want the trust bundle mount", mounts)
+	}
+	env := container["env"].([]interface{})
+	if len(env) != 2 || env[0].(map[string]interface{})["value"] != "http://proxy:3128" {
+		t.Errorf("env = %v, want the cluster proxy", env)
+	}
+	hash, _, _ := unstructured.NestedString(deploy.Object, "spec", "template", "metadata", "annotations",
+		AnnotationTrustBundleHash)
+	if hash != "abc" {
+		t.Errorf("pod template hash annotation = %q, want abc", hash)
+	}
+
+	// The bundle is not mounted in a namespace it was not synced to, so the system trust store stays in place
+	if err := injectTrustConfig(unsynced, status); err != nil {
+		t.Fatalf("injectTrustConfig() error = %v", err)
+	}
+	if volumes, _, _ := unstructured.NestedSlice(unsynced.Object, "spec", "template", "spec",
+		"volumes"); len(volumes) != 0 {
+		t.Errorf("volumes = %v, want none outside the synced namespaces", volumes)
+	}
+	containers, _, _ = unstructured.NestedSlice(unsynced.Object, "spec", "template", "spec", "containers")
+	if env := containers[0].(map[string]interface{})["env"].([]interface{}); len(env) != 2 {
+		t.Errorf("env = %v, want the cluster proxy outside the synced namespaces", env)
+	}
+}
+
+func Test_injectTrustConfig_customCAOnly(t *testing.T) {
+	registerScheme()
+	hub := trustBundleHub()
+	r := &MultiClusterHubReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(trustBundleObjects()[1]).Build(),
+		Scheme: scheme.Scheme,
+		Log:    clog.Log.WithName("test"),
+	}
+
+	// Without the cluster trust bundle, the synced bundle only holds the custom CA
+	if _, err := r.ensureTrustBundle(context.Background(), hub); err != nil {
+		t.Fatalf("ensureTrustBundle() error = %v", err)
+	}
+	status := hub.Status.TrustBundle
+	if status == nil || status.Hash == "" || status.ClusterBundle || !status.CustomCA {
+		t.Fatalf("expected a trust bundle with only the custom CA, got %+v", status)
+	}
+
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "search-api", "namespace": "open-cluster-management"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "search-api"}},
+				},
+			},
+		},
+	}}
+	if err := injectTrustConfig(deploy, status); err != nil {
+		t.Fatalf("injectTrustConfig() error = %v", err)
+	}
+
+	// The system trust store is kept and the custom CA is added next to it
+	containers, _, _ := unstructured.NestedSlice(deploy.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	if mounts := container["volumeMounts"].([]interface{}); len(mounts) != 1 ||
+		mounts[0].(map[string]interface{})["mountPath"] != customCAMountPath {
+		t.Errorf("volumeMounts = %v, want the bundle mounted at %s", mounts, customCAMountPath)
+	}
+	env := container["env"].([]interface{})
+	if len(env) != 1 || env[0].(map[string]interface{})["name"] != certDirsEnvVar ||
+		env[0].(map[string]interface{})["value"] != systemCertDirs+":"+customCAMountPath {
+		t.Errorf("env = %v, want %s to include %s", env, certDirsEnvVar, customCAMountPath)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -42,7 +43,12 @@ import (
 	consolev1 "github.com/openshift/api/operator/v1"
 	mcev1 "github.com/stolostron/backplane-operator/api/v1"
 	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	operatorv2 "github.com/stolostron/multiclusterhub-operator/api/v2"
 	"github.com/stolostron/multiclusterhub-operator/controllers"
+	"github.com/stolostron/multiclusterhub-operator/pkg/capabilities"
+	"github.com/stolostron/multiclusterhub-operator/pkg/health"
+	"github.com/stolostron/multiclusterhub-operator/pkg/servingcert"
+	"github.com/stolostron/multiclusterhub-operator/pkg/tlsprofile"
 	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
 	"github.com/stolostron/multiclusterhub-operator/pkg/version"
 	searchv2v1alpha1 "github.com/stolostron/search-v2-operator/api/v1alpha1"
@@ -57,45 +63,42 @@ import (
 	olmapi "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
 
 	admissionregistration "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networking "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
-	"k8s.io/client-go/util/workqueue"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/config"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/event"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
 const (
-	crdName            = "multiclusterhubs.operator.open-cluster-management.io"
-	OperatorVersionEnv = "OPERATOR_VERSION"
+	crdName                  = "multiclusterhubs.operator.open-cluster-management.io"
+	injectCABundleAnnotation = "service.beta.openshift.io/inject-cabundle"
+	OperatorVersionEnv       = "OPERATOR_VERSION"
 )
 
 var (
 	cacheDuration time.Duration = time.Minute * 5
 	scheme                      = runtime.NewScheme()
 	setupLog                    = ctrl.Log.WithName("setup")
-	mchController controller.Controller
 )
 
 func init() {
@@ -107,6 +110,8 @@ func init() {
 
 	utilruntime.Must(operatorv1.AddToScheme(scheme))
 
+	utilruntime.Must(operatorv2.AddToScheme(scheme))
+
 	utilruntime.Must(searchv2v1alpha1.AddToScheme(scheme))
 
 	utilruntime.Must(apiregistrationv1.AddToScheme(scheme))
@@ -144,12 +149,16 @@ func init() {
 
 func main() {
 	var metricsAddr string
+	var secureMetrics bool
 	var enableLeaderElection bool
 	var probeAddr string
 	var leaseDuration time.Duration
 	var renewDeadline time.Duration
 	var retryPeriod time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8383", "The address the metric endpoint binds to.")
+	flag.BoolVar(&secureMetrics, "metrics-secure", true,
+		"Serve the metric endpoint over HTTPS and only to authorized clients. "+
+			"Set to false to serve it over plain HTTP.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", true,
 		"Enable leader election for controller manager. "+
@@ -197,14 +206,18 @@ func main() {
 					&olmapi.PackageManifest{},
 					&ocmapi.ClusterManagementAddOn{},
 					&subv1alpha1.ClusterServiceVersion{},
+					&apixv1.CustomResourceDefinition{},
+					&discoveryv1.EndpointSlice{},
 				},
 			},
 		},
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
+			BindAddress:   metricsAddr,
+			SecureServing: secureMetrics,
 		},
-		HealthProbeBindAddress:  probeAddr,
+		// The probe server is added below, it also serves the hub health report
+		HealthProbeBindAddress:  "0",
 		LeaderElection:          enableLeaderElection,
 		LeaderElectionID:        "multicloudhub-operator-lock",
 		LeaderElectionNamespace: ns,
@@ -226,45 +239,34 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Get TLS configuration from OpenShift APIServer profile
-	tlsProfile, err := utils.GetAPIServerTLSProfile(ctx, uncachedClient)
+	// Get TLS configuration from OpenShift APIServer profile. The MultiClusterHub controller watches the APIServer
+	// resource and swaps the profile of the webhook and metrics servers when it changes.
+	tlsProfile, err := tlsprofile.Load(ctx, uncachedClient)
 	if err != nil {
-		setupLog.Error(err, "unable to get APIServer TLS profile, using default TLS 1.2")
-		// Continue with default if we can't get the profile
-		// Use Intermediate profile ciphers as secure default for TLS 1.2
-		tlsProfile = &configv1.TLSProfileSpec{
-			MinTLSVersion: configv1.VersionTLS12,
-			Ciphers: []string{
-				"TLS_AES_128_GCM_SHA256",
-				"TLS_AES_256_GCM_SHA384",
-				"TLS_CHACHA20_POLY1305_SHA256",
-				"ECDHE-ECDSA-AES128-GCM-SHA256",
-				"ECDHE-RSA-AES128-GCM-SHA256",
-				"ECDHE-ECDSA-AES256-GCM-SHA384",
-				"ECDHE-RSA-AES256-GCM-SHA384",
-				"ECDHE-ECDSA-CHACHA20-POLY1305",
-				"ECDHE-RSA-CHACHA20-POLY1305",
-				"DHE-RSA-AES128-GCM-SHA256",
-				"DHE-RSA-AES256-GCM-SHA384",
-			},
+		setupLog.Error(err, "unable to get APIServer TLS profile, using the Intermediate profile")
+		tlsProfile = tlsprofile.Default()
+	}
+	profileType, profileSpec := tlsProfile.Get()
+	setupLog.Info("Configuring webhook and metrics server TLS", "profile", profileType,
+		"minTLSVersion", profileSpec.MinTLSVersion, "cipherCount", len(profileSpec.Ciphers))
+
+	// Metrics are served with the certificate the service CA issues for the metrics service, and only to clients
+	// allowed to get the /metrics URL
+	var metricsCert *servingcert.Provider
+	if secureMetrics {
+		metricsCert, err = servingcert.NewProvider()
+		if err != nil {
+			setupLog.Error(err, "unable to create the metrics serving certificate")
+			os.Exit(1)
 		}
+		mgrOptions.Metrics.FilterProvider = filters.WithAuthenticationAndAuthorization
+		mgrOptions.Metrics.TLSOpts = []func(*tls.Config){tlsProfile.ServerTLSOpts, metricsCert.TLSOpts}
+	} else {
+		setupLog.Info("Serving metrics over plain HTTP")
 	}
-
-	minTLSVersion := utils.ConvertTLSVersion(tlsProfile.MinTLSVersion)
-	cipherSuites := utils.ConvertCipherSuites(tlsProfile.Ciphers)
-	setupLog.Info("Configuring webhook server TLS", "minTLSVersion", tlsProfile.MinTLSVersion, "numericValue", minTLSVersion, "cipherCount", len(cipherSuites))
-
-	// Configure webhook server with dynamic TLS settings from OpenShift
 	mgrOptions.WebhookServer = webhook.NewServer(webhook.Options{
-		Port: 9443,
-		TLSOpts: []func(*tls.Config){func(config *tls.Config) {
-			config.MinVersion = minTLSVersion
-			// Only set CipherSuites for TLS ≤ 1.2
-			// TLS 1.3 cipher suites are managed automatically by Go
-			if minTLSVersion < tls.VersionTLS13 && len(cipherSuites) > 0 {
-				config.CipherSuites = cipherSuites
-			}
-		}},
+		Port:    9443,
+		TLSOpts: []func(*tls.Config){tlsProfile.ServerTLSOpts},
 	})
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
@@ -273,19 +275,21 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Detect OLM version (v0, v1, or none)
-	// OpenShift 5.0+ is expected to support both OLM v0 and v1, so we must detect
-	// which version is present to handle MCE installation appropriately
-	olmVersion, err := detectOLMVersion(ctx, uncachedClient)
-	if err != nil {
-		setupLog.Error(err, "failed to detect OLM version")
-		os.Exit(1)
+	// Detect the optional cluster APIs (OLM v0/v1, MCE, console, monitoring). The initial snapshot is kept current
+	// by the MultiClusterHub controller's CRD and APIService watches, so APIs installed after startup are picked up
+	// without a restart. OpenShift 5.0+ is expected to support both OLM v0 and v1, so the OLM version is part of it.
+	clusterCapabilities := capabilities.New()
+	if err := clusterCapabilities.Refresh(ctx, uncachedClient); err != nil {
+		// The capabilities that could not be checked are filled in by the watches once the manager starts
+		setupLog.Error(err, "failed to detect some cluster capabilities")
 	}
+	olmVersion := clusterCapabilities.OLMVersion()
 	if olmVersion == "" {
-		setupLog.Info("No OLM detected - MCE subscription management will be skipped")
+		setupLog.Info("No OLM detected - MCE subscription management will be skipped until OLM is available")
 	} else {
 		setupLog.Info("OLM version detected", "version", olmVersion)
 	}
+	setupLog.Info("Cluster capabilities detected", "available", clusterCapabilities.Available())
 
 	// OperatorCondition is only used by OLM v0
 	// OLM v1 and non-OLM deployments don't use this mechanism
@@ -337,16 +341,38 @@ func main() {
 		setupLog.Info("Skipping OperatorCondition (OLM v0 only)")
 	}
 
+	// Availability history of the hub components, published in the hub status, as metrics and on the probe server
+	hubHealth := health.NewTracker(health.DefaultObjective)
+
 	mchReconciler := &controllers.MultiClusterHubReconciler{
 		Client:          mgr.GetClient(),
 		Scheme:          mgr.GetScheme(),
 		UncachedClient:  uncachedClient,
+		APIReader:       mgr.GetAPIReader(),
 		Log:             ctrl.Log.WithName("Controller").WithName("Multiclusterhub"),
 		UpgradeableCond: upgradeableCondition,
 		OLMVersion:      olmVersion,
+		Capabilities:    clusterCapabilities,
+		Health:          hubHealth,
+		TLSProfile:      tlsProfile,
+		MetricsCert:     metricsCert,
+		RestrictedMode:  utils.IsRestrictedMode(),
 	}
 
-	mchController, err = mchReconciler.SetupWithManager(mgr)
+	// Each replica serves its own metrics, so the serving certificate is loaded outside the leader-elected reconciler
+	if metricsCert != nil {
+		if err := mgr.Add(&servingcert.SecretLoader{
+			Provider: metricsCert,
+			Reader:   mgr.GetAPIReader(),
+			Secret:   types.NamespacedName{Name: utils.MCHOperatorMetricsServingCertSecretName, Namespace: ns},
+			Log:      ctrl.Log.WithName("servingcert"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up the metrics serving certificate loader")
+			os.Exit(1)
+		}
+	}
+
+	_, err = mchReconciler.SetupWithManager(mgr)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MultiClusterHub")
 		os.Exit(1)
@@ -354,7 +380,11 @@ func main() {
 
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		// https://book.kubebuilder.io/cronjob-tutorial/running.html#running-webhooks-locally, https://book.kubebuilder.io/multiversion-tutorial/webhooks.html#and-maingo
-		if err = ensureWebhooks(uncachedClient); err != nil {
+		// In restricted mode an admin applies the webhook configuration and the CRD conversion, the reconciler
+		// publishes them in the webhooks bundle
+		if utils.IsRestrictedMode() {
+			setupLog.Info("Restricted mode, leaving the webhook configuration and the MCH CRD conversion to an admin")
+		} else if err = ensureWebhooks(uncachedClient); err != nil {
 			setupLog.Error(err, "unable to ensure webhook", "webhook", "MultiClusterHub")
 			os.Exit(1)
 		}
@@ -363,21 +393,21 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "MultiClusterHub")
 			os.Exit(1)
 		}
+
+		if err = (&operatorv2.MultiClusterHub{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "MultiClusterHub")
+			os.Exit(1)
+		}
 	}
 	//+kubebuilder:scaffold:builder
 
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up health check")
-		os.Exit(1)
-	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
-		os.Exit(1)
+	if probeAddr != "0" {
+		if err := mgr.Add(newProbeServer(probeAddr, hubHealth)); err != nil {
+			setupLog.Error(err, "unable to set up health probe server")
+			os.Exit(1)
+		}
 	}
 
-	// go routine to check if mce exist, if it does add watch
-	go addMultiClusterEngineWatch(ctx, mgr, uncachedClient)
-
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -385,55 +415,36 @@ func main() {
 	}
 }
 
+/*
+newProbeServer returns the server of the liveness and readiness probes. It also serves the hub health report, which the
+probe server of the manager cannot be extended with.
+*/
+func newProbeServer(addr string, hubHealth *health.Tracker) *manager.Server {
+	mux := http.NewServeMux()
+	for _, probe := range []string{"healthz", "readyz"} {
+		path := "/" + probe
+		handler := http.StripPrefix(path, &healthz.Handler{Checks: map[string]healthz.Checker{probe: healthz.Ping}})
+		mux.Handle(path, handler)
+		// Append '/' suffix to handle subpaths
+		mux.Handle(path+"/", handler)
+	}
+	mux.Handle(health.Path, hubHealth)
+
+	return &manager.Server{
+		Name: "health probe",
+		Server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 32 * time.Second,
+		},
+	}
+}
+
 const (
 	ForceRunModeEnv = "OSDK_FORCE_RUN_MODE"
 	LocalRunMode    = "local"
 )
 
-func addMultiClusterEngineWatch(ctx context.Context, mgr ctrl.Manager, uncachedClient client.Client) {
-	for {
-		crd := &apixv1.CustomResourceDefinition{}
-		mceName := "multiclusterengines.multicluster.openshift.io"
-		err := uncachedClient.Get(ctx, types.NamespacedName{Name: mceName}, crd)
-		//crdKey := client.ObjectKey{Name: multiclusterengine.Namespace().GetObjectMeta().GetName()}
-		//err := uncachedClient.Get(ctx, crdKey, &mcev1.MultiClusterEngine{})
-		if err == nil {
-			err := mchController.Watch(source.Kind(mgr.GetCache(), &mcev1.MultiClusterEngine{},
-				handler.TypedFuncs[*mcev1.MultiClusterEngine, reconcile.Request]{
-					UpdateFunc: func(ctx context.Context, e event.TypedUpdateEvent[*mcev1.MultiClusterEngine], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
-						labels := e.ObjectNew.GetLabels()
-						name := labels["installer.name"]
-						if name == "" {
-							name = labels["multiclusterhub.name"]
-						}
-						namespace := labels["installer.namespace"]
-						if namespace == "" {
-							namespace = labels["multiclusterhub.namespace"]
-						}
-						if name == "" || namespace == "" {
-							l := log.Log.WithName("mce")
-							l.Info(fmt.Sprintf("MCE updated but missing installer.name or installer.namespace labels. Current labels: %v", labels))
-							return
-						}
-						q.Add(
-							reconcile.Request{
-								NamespacedName: types.NamespacedName{
-									Name:      name,
-									Namespace: namespace,
-								},
-							},
-						)
-					},
-				}))
-			if err == nil {
-				setupLog.Info("mce watch added")
-				return
-			}
-		}
-		time.Sleep(30 * time.Second)
-	}
-}
-
 func isRunModeLocal() bool {
 	return os.Getenv(ForceRunModeEnv) == LocalRunMode
 }
@@ -454,41 +465,6 @@ func getOperatorNamespace() (string, error) {
 	return ns, nil
 }
 
-// detectOLMVersion determines which version of OLM (Operator Lifecycle Manager) is running on the cluster.
-// OLM is the OpenShift mechanism for installing and managing operators. OpenShift uses different OLM versions:
-//   - OLM v0: Uses Subscription, CSV, and OperatorGroup resources (OpenShift 4.x)
-//   - OLM v1: Uses ClusterExtension resources (OpenShift 5.0+)
-//   - No OLM: Operator installed manually (bare Kubernetes, pre-installed MCE)
-func detectOLMVersion(ctx context.Context, cl client.Client) (string, error) {
-	// Check for OLM v0 via environment variable (fastest check)
-	// OLM v0 injects OPERATOR_CONDITION_NAME into operator pods at deployment time
-	if os.Getenv("OPERATOR_CONDITION_NAME") != "" {
-		return "v0", nil
-	}
-
-	// Check for OLM v1 by looking for ClusterExtension CRD
-	// If this CRD exists, the cluster is running OLM v1
-	crd := &apixv1.CustomResourceDefinition{}
-	err := cl.Get(ctx, types.NamespacedName{
-		Name: "clusterextensions.olm.operatorframework.io",
-	}, crd)
-
-	switch {
-	case err == nil:
-		// ClusterExtension CRD exists - OLM v1 present
-		return "v1", nil
-
-	case errors.IsNotFound(err):
-		// CRD not found - no OLM on cluster (legitimate case for bare Kubernetes)
-		return "", nil
-
-	default:
-		// API error (timeout, permission, etc) - fail fast and let Kubernetes retry
-		// Kubernetes will restart the pod with exponential backoff
-		return "", fmt.Errorf("failed to check for OLM v1 CRD: %w", err)
-	}
-}
-
 func ensureWebhooks(k8sClient client.Client) error {
 	ctx := context.Background()
 
@@ -513,6 +489,11 @@ func ensureWebhooks(k8sClient client.Client) error {
 			time.Sleep(5 * time.Second)
 			continue
 		}
+		if err := ensureCRDConversion(ctx, k8sClient, owner, deploymentNamespace); err != nil {
+			setupLog.Error(err, "Failed to configure MCH CRD conversion webhook")
+			time.Sleep(5 * time.Second)
+			continue
+		}
 		validatingWebhook.SetOwnerReferences([]metav1.OwnerReference{
 			{
 				APIVersion: "apiextensions.k8s.io/v1",
@@ -559,3 +540,42 @@ func ensureWebhooks(k8sClient client.Client) error {
 	}
 	return fmt.Errorf("unable to ensure validatingwebhook exists in allotted time")
 }
+
+// ensureCRDConversion points the MCH CRD at the conversion webhook so v2 objects are converted to and from the
+// v1 storage version. The CA bundle is injected by the service CA operator and is kept across updates. The CRD ships
+// with v2 unserved, as the API server would drop the fields v1 does not define without conversion, so v2 is served
+// in the same update.
+func ensureCRDConversion(ctx context.Context, k8sClient client.Client, crd *apixv1.CustomResourceDefinition,
+	namespace string) error {
+	desired := operatorv1.CRDConversion(namespace)
+	if current := crd.Spec.Conversion; current != nil && current.Webhook != nil && current.Webhook.ClientConfig != nil {
+		desired.Webhook.ClientConfig.CABundle = current.Webhook.ClientConfig.CABundle
+	}
+
+	served := true
+	for _, version := range crd.Spec.Versions {
+		if version.Name == operatorv2.GroupVersion.Version {
+			served = version.Served
+		}
+	}
+
+	annotations := crd.GetAnnotations()
+	if equality.Semantic.DeepEqual(crd.Spec.Conversion, desired) && annotations[injectCABundleAnnotation] == "true" &&
+		served {
+		return nil
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[injectCABundleAnnotation] = "true"
+	crd.SetAnnotations(annotations)
+	crd.Spec.Conversion = desired
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == operatorv2.GroupVersion.Version {
+			crd.Spec.Versions[i].Served = true
+		}
+	}
+
+	setupLog.Info("Configuring MCH CRD conversion webhook")
+	return k8sClient.Update(ctx, crd)
+}
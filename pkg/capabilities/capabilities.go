@@ -0,0 +1,230 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package capabilities tracks which optional cluster APIs are served by the hub.
+//
+// The operator changes behaviour depending on whether OLM, the MultiClusterEngine API, the OpenShift
+// console and the monitoring stack are installed. Rather than probing for these once at startup, a
+// Discovery is seeded with an initial Refresh and then kept current from CustomResourceDefinition and
+// APIService watch events, notifying registered handlers whenever a capability appears or disappears.
+package capabilities
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Capability names an optional cluster API the operator reacts to
+type Capability string
+
+const (
+	// OLMv0 is available when the OLM v0 Subscription API is served
+	OLMv0 Capability = "OLMv0"
+	// OLMv1 is available when the OLM v1 ClusterExtension API is served
+	OLMv1 Capability = "OLMv1"
+	// PackageManifests is available when the OLM v0 package server is serving catalog content
+	PackageManifests Capability = "PackageManifests"
+	// MultiClusterEngine is available when the MultiClusterEngine API is served
+	MultiClusterEngine Capability = "MultiClusterEngine"
+	// Console is available when the OpenShift console operator API is served
+	Console Capability = "Console"
+	// ConsoleNotification is available when console banners can be created
+	ConsoleNotification Capability = "ConsoleNotification"
+	// ServiceMonitor is available when the Prometheus operator ServiceMonitor API is served
+	ServiceMonitor Capability = "ServiceMonitor"
+	// InternalHubComponent is available once the hub has installed the InternalHubComponent API
+	InternalHubComponent Capability = "InternalHubComponent"
+)
+
+// SourceKind is the kind of cluster object that signals a capability
+type SourceKind string
+
+const (
+	CRDSource        SourceKind = "CustomResourceDefinition"
+	APIServiceSource SourceKind = "APIService"
+)
+
+// Source identifies the object whose presence makes a capability available
+type Source struct {
+	Capability Capability
+	Kind       SourceKind
+	Name       string
+}
+
+// Sources lists every capability the operator tracks and the object that signals it
+var Sources = []Source{
+	{Capability: OLMv0, Kind: CRDSource, Name: "subscriptions.operators.coreos.com"},
+	{Capability: OLMv1, Kind: CRDSource, Name: "clusterextensions.olm.operatorframework.io"},
+	{Capability: PackageManifests, Kind: APIServiceSource, Name: "v1.packages.operators.coreos.com"},
+	{Capability: MultiClusterEngine, Kind: CRDSource, Name: "multiclusterengines.multicluster.openshift.io"},
+	{Capability: Console, Kind: CRDSource, Name: "consoles.operator.openshift.io"},
+	{Capability: ConsoleNotification, Kind: CRDSource, Name: "consolenotifications.console.openshift.io"},
+	{Capability: ServiceMonitor, Kind: CRDSource, Name: "servicemonitors.monitoring.coreos.com"},
+	{Capability: InternalHubComponent, Kind: CRDSource,
+		Name: "internalhubcomponents.operator.open-cluster-management.io"},
+}
+
+// Lookup returns the capability signalled by the object of the given kind and name
+func Lookup(kind SourceKind, name string) (Capability, bool) {
+	for _, s := range Sources {
+		if s.Kind == kind && s.Name == name {
+			return s.Capability, true
+		}
+	}
+	return "", false
+}
+
+// ChangeFunc is called after a capability becomes available or unavailable
+type ChangeFunc func(c Capability, available bool)
+
+// Discovery holds the current set of available capabilities. It is safe for concurrent use.
+type Discovery struct {
+	mu        sync.RWMutex
+	available map[Capability]bool
+	handlers  []ChangeFunc
+}
+
+// New returns an empty Discovery. Call Refresh to seed it before use.
+func New() *Discovery {
+	return &Discovery{available: map[Capability]bool{}}
+}
+
+// Refresh reads every tracked source with the given reader and records the result. Capabilities whose
+// source could not be read keep their previous value and the errors are returned together.
+func (d *Discovery) Refresh(ctx context.Context, c client.Reader) error {
+	var errs []error
+	for _, s := range Sources {
+		available, err := SourceAvailable(ctx, c, s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to check %s %s: %w", s.Kind, s.Name, err))
+			continue
+		}
+		d.Set(s.Capability, available)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// SourceAvailable reads the object of a source and reports whether it is ready to serve requests
+func SourceAvailable(ctx context.Context, c client.Reader, s Source) (bool, error) {
+	var obj client.Object
+	switch s.Kind {
+	case CRDSource:
+		obj = &apixv1.CustomResourceDefinition{}
+	case APIServiceSource:
+		obj = &apiregistrationv1.APIService{}
+	default:
+		return false, fmt.Errorf("unknown source kind %q", s.Kind)
+	}
+
+	if err := c.Get(ctx, types.NamespacedName{Name: s.Name}, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return ObjectAvailable(obj), nil
+}
+
+// ObjectAvailable reports whether a CRD or APIService is ready to serve requests. CRDs must be
+// established and APIServices must be reporting Available.
+func ObjectAvailable(obj client.Object) bool {
+	if obj.GetDeletionTimestamp() != nil {
+		return false
+	}
+	switch o := obj.(type) {
+	case *apixv1.CustomResourceDefinition:
+		for _, c := range o.Status.Conditions {
+			if c.Type == apixv1.Established {
+				return c.Status == apixv1.ConditionTrue
+			}
+		}
+		return false
+	case *apiregistrationv1.APIService:
+		for _, c := range o.Status.Conditions {
+			if c.Type == apiregistrationv1.Available {
+				return c.Status == apiregistrationv1.ConditionTrue
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Observe records the state of a watched object. It returns the capability the object signals and
+// whether its availability changed.
+func (d *Discovery) Observe(kind SourceKind, obj client.Object, available bool) (Capability, bool) {
+	c, ok := Lookup(kind, obj.GetName())
+	if !ok {
+		return "", false
+	}
+	return c, d.Set(c, available)
+}
+
+// Set records the availability of a capability and notifies handlers if it changed
+func (d *Discovery) Set(c Capability, available bool) bool {
+	d.mu.Lock()
+	previous, known := d.available[c]
+	if known && previous == available {
+		d.mu.Unlock()
+		return false
+	}
+	d.available[c] = available
+	handlers := append([]ChangeFunc(nil), d.handlers...)
+	d.mu.Unlock()
+
+	for _, h := range handlers {
+		h(c, available)
+	}
+	return true
+}
+
+// Has reports whether a capability is currently available
+func (d *Discovery) Has(c Capability) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.available[c]
+}
+
+// Available returns the names of all currently available capabilities in sorted order
+func (d *Discovery) Available() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	names := []string{}
+	for c, ok := range d.available {
+		if ok {
+			names = append(names, string(c))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OnChange registers a handler that is called whenever a capability changes availability
+func (d *Discovery) OnChange(h ChangeFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers = append(d.handlers, h)
+}
+
+// OLMVersion returns the OLM version that should manage operator installs: "v0", "v1", or "" when no OLM
+// is present. An operator deployed by OLM v0 keeps using v0, since OLM v0 injects OPERATOR_CONDITION_NAME
+// into the pods it manages; otherwise OLM v1 is used once the ClusterExtension API is served.
+func (d *Discovery) OLMVersion() string {
+	if os.Getenv("OPERATOR_CONDITION_NAME") != "" {
+		return "v0"
+	}
+	if d.Has(OLMv1) {
+		return "v1"
+	}
+	return ""
+}
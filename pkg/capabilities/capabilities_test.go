@@ -0,0 +1,122 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package capabilities
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apixv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func establishedCRD(name string) *apixv1.CustomResourceDefinition {
+	return &apixv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: apixv1.CustomResourceDefinitionStatus{
+			Conditions: []apixv1.CustomResourceDefinitionCondition{
+				{Type: apixv1.Established, Status: apixv1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func Test_Refresh(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apixv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := apiregistrationv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	notEstablished := establishedCRD("servicemonitors.monitoring.coreos.com")
+	notEstablished.Status.Conditions[0].Status = apixv1.ConditionFalse
+	unavailableAPIService := &apiregistrationv1.APIService{
+		ObjectMeta: metav1.ObjectMeta{Name: "v1.packages.operators.coreos.com"},
+		Status: apiregistrationv1.APIServiceStatus{
+			Conditions: []apiregistrationv1.APIServiceCondition{
+				{Type: apiregistrationv1.Available, Status: apiregistrationv1.ConditionFalse},
+			},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		establishedCRD("clusterextensions.olm.operatorframework.io"),
+		establishedCRD("multiclusterengines.multicluster.openshift.io"),
+		notEstablished,
+		unavailableAPIService,
+	).Build()
+
+	d := New()
+	if err := d.Refresh(context.Background(), cl); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	want := []string{string(MultiClusterEngine), string(OLMv1)}
+	if got := d.Available(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Available() = %v, want %v", got, want)
+	}
+	if d.Has(ServiceMonitor) {
+		t.Error("expected ServiceMonitor to be unavailable while its CRD is not established")
+	}
+	if d.Has(PackageManifests) {
+		t.Error("expected PackageManifests to be unavailable while its APIService is not available")
+	}
+}
+
+func Test_Observe(t *testing.T) {
+	d := New()
+	d.Set(MultiClusterEngine, false)
+
+	var notified []Capability
+	d.OnChange(func(c Capability, available bool) {
+		notified = append(notified, c)
+	})
+
+	crd := establishedCRD("multiclusterengines.multicluster.openshift.io")
+	if c, changed := d.Observe(CRDSource, crd, ObjectAvailable(crd)); c != MultiClusterEngine || !changed {
+		t.Errorf("Observe() = %v, %v, want %v, true", c, changed, MultiClusterEngine)
+	}
+	if _, changed := d.Observe(CRDSource, crd, ObjectAvailable(crd)); changed {
+		t.Error("expected repeated observation not to report a change")
+	}
+	if _, changed := d.Observe(CRDSource, establishedCRD("foos.example.com"), true); changed {
+		t.Error("expected untracked CRD to be ignored")
+	}
+	if _, changed := d.Observe(CRDSource, crd, false); !changed {
+		t.Error("expected CRD removal to report a change")
+	}
+
+	if !reflect.DeepEqual(notified, []Capability{MultiClusterEngine, MultiClusterEngine}) {
+		t.Errorf("handlers notified for %v, want two MultiClusterEngine changes", notified)
+	}
+}
+
+func Test_OLMVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		conditionEnv string
+		olmv1        bool
+		want         string
+	}{
+		{name: "no OLM", want: ""},
+		{name: "OLM v1 available", olmv1: true, want: "v1"},
+		{name: "deployed by OLM v0", conditionEnv: "multiclusterhub-operator.v2.15.0", olmv1: true, want: "v0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OPERATOR_CONDITION_NAME", tt.conditionEnv)
+			d := New()
+			d.Set(OLMv1, tt.olmv1)
+			if got := d.OLMVersion(); got != tt.want {
+				t.Errorf("OLMVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
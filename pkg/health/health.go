@@ -0,0 +1,426 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package health tracks the availability of the hub components over time.
+//
+// Each time the hub status is calculated the availability of its components is recorded in a Tracker. The tracker
+// keeps the periods each component was unavailable and derives a Report from them: a weighted score of the available
+// components, a state that tells an unavailable optional add-on apart from an unavailable hub, and the availability
+// of the hub and the burn rate of its error budget over the SLO windows. The hub is available while all of its
+// critical components are. Reports are published as metrics and served as JSON by the Tracker.
+//
+// The history of a Tracker is kept in memory. A Snapshot of it can be persisted and restored into a new Tracker, so
+// the SLO windows are not reset when the operator restarts.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// Path is where the probe server serves the hub health report
+	Path = "/hub-health"
+
+	// DefaultObjective is the share of time the hub is expected to be available
+	DefaultObjective = 0.99
+
+	// Retention is how long the unavailable periods of a component are kept, the longest SLO window
+	Retention = 24 * time.Hour
+
+	criticalWeight = 3
+	optionalWeight = 1
+)
+
+// Windows are the time windows the availability and the error budget burn rate are reported over
+var Windows = []time.Duration{time.Hour, 6 * time.Hour, Retention}
+
+// State summarizes the availability of the hub components
+type State string
+
+const (
+	// Healthy is reported when all components are available
+	Healthy State = "Healthy"
+	// Degraded is reported when only optional components are unavailable
+	Degraded State = "Degraded"
+	// Unavailable is reported when a critical component is unavailable
+	Unavailable State = "Unavailable"
+)
+
+var (
+	scoreGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "multiclusterhub_health_score",
+		Help: "Weighted share of available hub components, from 0 to 100",
+	})
+	componentGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "multiclusterhub_health_component_available",
+		Help: "Whether a hub component is available (1) or not (0)",
+	}, []string{"component", "critical"})
+	availabilityGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "multiclusterhub_health_slo_availability_ratio",
+		Help: "Share of the window the hub was available",
+	}, []string{"window"})
+	burnRateGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "multiclusterhub_health_slo_burn_rate",
+		Help: "Rate the hub availability error budget is consumed at over the window",
+	}, []string{"window"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(scoreGauge, componentGauge, availabilityGauge, burnRateGauge)
+}
+
+// Sample is the availability of a component when the hub status was calculated
+type Sample struct {
+	Name      string
+	Critical  bool
+	Available bool
+}
+
+// Report is the health of the hub derived from the availability history of its components
+type Report struct {
+	Hub        string            `json:"hub"`
+	Time       time.Time         `json:"time"`
+	Score      int               `json:"score"`
+	State      State             `json:"state"`
+	Objective  float64           `json:"objective"`
+	SLO        []WindowReport    `json:"slo"`
+	Components []ComponentReport `json:"components"`
+}
+
+// WindowReport is the availability of the hub over a window
+type WindowReport struct {
+	Window       string  `json:"window"`
+	Availability float64 `json:"availability"`
+	BurnRate     float64 `json:"burnRate"`
+}
+
+// ComponentReport is the availability of a component now and over each window
+type ComponentReport struct {
+	Name         string             `json:"name"`
+	Critical     bool               `json:"critical"`
+	Available    bool               `json:"available"`
+	Since        time.Time          `json:"since"`
+	Availability map[string]float64 `json:"availability"`
+}
+
+// UnavailableComponents returns the names of the components that are not available
+func (r Report) UnavailableComponents() []string {
+	names := []string{}
+	for _, c := range r.Components {
+		if !c.Available {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// WindowName formats a window as a number of hours, for example 6h
+func WindowName(window time.Duration) string {
+	return fmt.Sprintf("%dh", int(window.Hours()))
+}
+
+type outage struct {
+	start, end time.Time
+}
+
+// history is the availability of a component since it was first observed
+type history struct {
+	critical  bool
+	available bool
+	// since is when the component entered its current state
+	since     time.Time
+	firstSeen time.Time
+	// outages are the ended periods the component was unavailable
+	outages []outage
+}
+
+// Snapshot is the availability history of a Tracker
+type Snapshot struct {
+	Hub        *HistorySnapshot            `json:"hub,omitempty"`
+	Components map[string]*HistorySnapshot `json:"components,omitempty"`
+}
+
+// HistorySnapshot is the availability history of the hub or of a component
+type HistorySnapshot struct {
+	Critical  bool      `json:"critical,omitempty"`
+	Available bool      `json:"available"`
+	Since     time.Time `json:"since"`
+	FirstSeen time.Time `json:"firstSeen"`
+	Outages   []Outage  `json:"outages,omitempty"`
+}
+
+// Outage is an ended period the hub or a component was unavailable
+type Outage struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (h *history) snapshot() *HistorySnapshot {
+	s := &HistorySnapshot{Critical: h.critical, Available: h.available, Since: h.since, FirstSeen: h.firstSeen}
+	for _, o := range h.outages {
+		s.Outages = append(s.Outages, Outage{Start: o.start, End: o.end})
+	}
+	return s
+}
+
+func historyFromSnapshot(s *HistorySnapshot) *history {
+	h := &history{critical: s.Critical, available: s.Available, since: s.Since, firstSeen: s.FirstSeen}
+	for _, o := range s.Outages {
+		h.outages = append(h.outages, outage{start: o.Start, end: o.End})
+	}
+	return h
+}
+
+func (h *history) observe(available bool, now time.Time) {
+	if available == h.available {
+		return
+	}
+	if !h.available {
+		h.outages = append(h.outages, outage{start: h.since, end: now})
+	}
+	h.available = available
+	h.since = now
+}
+
+func (h *history) prune(now time.Time) {
+	kept := h.outages[:0]
+	for _, o := range h.outages {
+		if o.end.After(now.Add(-Retention)) {
+			kept = append(kept, o)
+		}
+	}
+	h.outages = kept
+}
+
+// availability returns the share of the window, or of the time since the component was first observed if shorter,
+// the component was available
+func (h *history) availability(window time.Duration, now time.Time) float64 {
+	start := now.Add(-window)
+	if h.firstSeen.After(start) {
+		start = h.firstSeen
+	}
+	total := now.Sub(start)
+	if total <= 0 {
+		if h.available {
+			return 1
+		}
+		return 0
+	}
+
+	var down time.Duration
+	for _, o := range h.outages {
+		down += overlap(o.start, o.end, start, now)
+	}
+	if !h.available {
+		down += overlap(h.since, now, start, now)
+	}
+	return 1 - float64(down)/float64(total)
+}
+
+func overlap(start, end, windowStart, windowEnd time.Time) time.Duration {
+	if start.Before(windowStart) {
+		start = windowStart
+	}
+	if end.After(windowEnd) {
+		end = windowEnd
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// Tracker records the availability of the hub components and reports the health of the hub
+type Tracker struct {
+	mu         sync.RWMutex
+	objective  float64
+	hub        *history
+	components map[string]*history
+	report     *Report
+
+	// now returns the current time, it is replaced in tests
+	now func() time.Time
+}
+
+// NewTracker returns a tracker reporting against the availability objective, for example 0.99
+func NewTracker(objective float64) *Tracker {
+	return &Tracker{
+		objective:  objective,
+		components: map[string]*history{},
+		now:        time.Now,
+	}
+}
+
+/*
+Observe records the availability of the hub components and returns the updated report. Components that are not part of
+the samples anymore, for example because they were disabled, are dropped from the history.
+*/
+func (t *Tracker) Observe(hub string, samples []Sample) Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+
+	observed := map[string]bool{}
+	hubAvailable := true
+	for _, s := range samples {
+		observed[s.Name] = true
+		if s.Critical && !s.Available {
+			hubAvailable = false
+		}
+
+		h, ok := t.components[s.Name]
+		if !ok {
+			h = &history{available: s.Available, since: now, firstSeen: now}
+			t.components[s.Name] = h
+		}
+		h.critical = s.Critical
+		h.observe(s.Available, now)
+		h.prune(now)
+	}
+	for name := range t.components {
+		if !observed[name] {
+			delete(t.components, name)
+		}
+	}
+
+	if t.hub == nil {
+		t.hub = &history{available: hubAvailable, since: now, firstSeen: now}
+	}
+	t.hub.observe(hubAvailable, now)
+	t.hub.prune(now)
+
+	report := t.buildReport(hub, now)
+	t.report = &report
+	publish(report)
+	return report
+}
+
+// Snapshot returns the availability history of the tracker
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	s := Snapshot{Components: map[string]*HistorySnapshot{}}
+	if t.hub != nil {
+		s.Hub = t.hub.snapshot()
+	}
+	for name, h := range t.components {
+		s.Components[name] = h.snapshot()
+	}
+	return s
+}
+
+/*
+Restore seeds the tracker with a persisted history. The time between the snapshot and the next observation counts in the
+state the hub and its components had in the snapshot. It returns false, leaving the tracker unchanged, when the
+tracker already observed the hub or the snapshot is empty.
+*/
+func (t *Tracker) Restore(s Snapshot) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.hub != nil || s.Hub == nil {
+		return false
+	}
+	t.hub = historyFromSnapshot(s.Hub)
+	for name, h := range s.Components {
+		if h != nil {
+			t.components[name] = historyFromSnapshot(h)
+		}
+	}
+	return true
+}
+
+func (t *Tracker) buildReport(hub string, now time.Time) Report {
+	report := Report{Hub: hub, Time: now, State: Healthy, Objective: t.objective}
+
+	var weight, availableWeight int
+	for name, h := range t.components {
+		w := optionalWeight
+		if h.critical {
+			w = criticalWeight
+		}
+		weight += w
+		if h.available {
+			availableWeight += w
+		} else if h.critical {
+			report.State = Unavailable
+		} else if report.State == Healthy {
+			report.State = Degraded
+		}
+
+		c := ComponentReport{Name: name, Critical: h.critical, Available: h.available, Since: h.since,
+			Availability: map[string]float64{}}
+		for _, window := range Windows {
+			c.Availability[WindowName(window)] = round(h.availability(window, now))
+		}
+		report.Components = append(report.Components, c)
+	}
+	sort.Slice(report.Components, func(i, j int) bool { return report.Components[i].Name < report.Components[j].Name })
+	if weight > 0 {
+		report.Score = int(math.Round(100 * float64(availableWeight) / float64(weight)))
+	}
+
+	for _, window := range Windows {
+		availability := t.hub.availability(window, now)
+		report.SLO = append(report.SLO, WindowReport{
+			Window:       WindowName(window),
+			Availability: round(availability),
+			BurnRate:     round((1 - availability) / (1 - t.objective)),
+		})
+	}
+	return report
+}
+
+// Report returns the last report, or false if no availability was observed yet
+func (t *Tracker) Report() (Report, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.report == nil {
+		return Report{}, false
+	}
+	return *t.report, true
+}
+
+// ServeHTTP serves the last report as JSON
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	report, ok := t.Report()
+	if !ok {
+		http.Error(w, "the hub health has not been observed yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func publish(report Report) {
+	scoreGauge.Set(float64(report.Score))
+	componentGauge.Reset()
+	for _, c := range report.Components {
+		available := 0.0
+		if c.Available {
+			available = 1
+		}
+		componentGauge.WithLabelValues(c.Name, fmt.Sprint(c.Critical)).Set(available)
+	}
+	for _, w := range report.SLO {
+		availabilityGauge.WithLabelValues(w.Window).Set(w.Availability)
+		burnRateGauge.WithLabelValues(w.Window).Set(w.BurnRate)
+	}
+}
+
+// round keeps four decimals so that reports do not change with every observation
+func round(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
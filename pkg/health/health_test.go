@@ -0,0 +1,151 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestTracker(start time.Time) (*Tracker, *time.Time) {
+	now := start
+	t := NewTracker(DefaultObjective)
+	t.now = func() time.Time { return now }
+	return t, &now
+}
+
+func Test_Observe(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker, now := newTestTracker(start)
+	samples := func(search, grc bool) []Sample {
+		return []Sample{
+			{Name: "grc-policy-propagator", Critical: true, Available: grc},
+			{Name: "search-api", Available: search},
+			{Name: "console-chart-console-v2", Available: true},
+		}
+	}
+
+	report := tracker.Observe("open-cluster-management/multiclusterhub", samples(true, true))
+	if report.State != Healthy || report.Score != 100 {
+		t.Fatalf("report = %s %d, want Healthy 100", report.State, report.Score)
+	}
+
+	// An unavailable optional component degrades the hub without consuming the error budget
+	*now = start.Add(30 * time.Minute)
+	report = tracker.Observe("open-cluster-management/multiclusterhub", samples(false, true))
+	if report.State != Degraded || report.Score != 80 {
+		t.Errorf("report = %s %d, want Degraded 80", report.State, report.Score)
+	}
+	if got := report.UnavailableComponents(); !reflect.DeepEqual(got, []string{"search-api"}) {
+		t.Errorf("UnavailableComponents() = %v, want search-api", got)
+	}
+
+	// An unavailable critical component makes the hub unavailable
+	*now = start.Add(50 * time.Minute)
+	report = tracker.Observe("open-cluster-management/multiclusterhub", samples(false, false))
+	if report.State != Unavailable || report.Score != 20 {
+		t.Errorf("report = %s %d, want Unavailable 20", report.State, report.Score)
+	}
+
+	*now = start.Add(60 * time.Minute)
+	report = tracker.Observe("open-cluster-management/multiclusterhub", samples(true, true))
+	if report.State != Healthy {
+		t.Errorf("State = %s, want Healthy", report.State)
+	}
+
+	// The hub was unavailable 10 of the 60 minutes it was observed
+	hour := report.SLO[0]
+	if hour.Window != "1h" || hour.Availability != 0.8333 || hour.BurnRate != 16.6667 {
+		t.Errorf("SLO = %+v, want 1h availability 0.8333 and burn rate 16.6667", hour)
+	}
+	for _, c := range report.Components {
+		if c.Name == "search-api" && c.Availability["1h"] != 0.5 {
+			t.Errorf("search-api availability = %v, want 0.5 over 1h", c.Availability)
+		}
+	}
+
+	// Outages leave the shorter windows as they age
+	*now = start.Add(3 * time.Hour)
+	report = tracker.Observe("open-cluster-management/multiclusterhub", samples(true, true))
+	if report.SLO[0].Availability != 1 || report.SLO[1].Availability != 0.9444 {
+		t.Errorf("SLO = %+v, want the outage only in the 6h and 24h windows", report.SLO)
+	}
+
+	// Components that are no longer observed are dropped
+	report = tracker.Observe("open-cluster-management/multiclusterhub", samples(true, true)[:1])
+	if len(report.Components) != 1 {
+		t.Errorf("Components = %v, want only grc-policy-propagator", report.Components)
+	}
+}
+
+func Test_ServeHTTP(t *testing.T) {
+	tracker, _ := newTestTracker(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	rec := httptest.NewRecorder()
+	tracker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d before the first observation, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	tracker.Observe("open-cluster-management/multiclusterhub", []Sample{{Name: "search-api", Available: false}})
+	rec = httptest.NewRecorder()
+	tracker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	report := Report{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.State != Degraded || len(report.SLO) != len(Windows) {
+		t.Errorf("report = %+v, want a Degraded report with every window", report)
+	}
+
+	rec = httptest.NewRecorder()
+	tracker.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func Test_Restore(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker, now := newTestTracker(start)
+	samples := func(grc bool) []Sample {
+		return []Sample{{Name: "grc-policy-propagator", Critical: true, Available: grc}}
+	}
+	tracker.Observe("open-cluster-management/multiclusterhub", samples(true))
+	*now = start.Add(20 * time.Minute)
+	tracker.Observe("open-cluster-management/multiclusterhub", samples(false))
+	*now = start.Add(30 * time.Minute)
+	tracker.Observe("open-cluster-management/multiclusterhub", samples(true))
+
+	data, err := json.Marshal(tracker.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := Snapshot{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted tracker keeps the outage observed before the restart
+	restarted, later := newTestTracker(start.Add(time.Hour))
+	if !restarted.Restore(snapshot) {
+		t.Fatal("Restore() = false, want the snapshot restored into the new tracker")
+	}
+	report := restarted.Observe("open-cluster-management/multiclusterhub", samples(true))
+	if hour := report.SLO[0]; hour.Availability != 0.8333 {
+		t.Errorf("SLO = %+v, want 1h availability 0.8333 including the outage before the restart", hour)
+	}
+
+	*later = start.Add(2 * time.Hour)
+	if restarted.Restore(Snapshot{}) || restarted.Restore(snapshot) {
+		t.Error("Restore() = true, want an observed tracker left unchanged")
+	}
+}
@@ -68,7 +68,7 @@ func NewMultiClusterEngine(m *operatorv1.MultiClusterHub, targetNamespace string
 	}
 	annotations := GetSupportedAnnotations(m)
 	availConfig := mcev1.HAHigh
-	if m.Spec.AvailabilityConfig == operatorv1.HABasic {
+	if m.EffectiveAvailabilityConfig() == operatorv1.HABasic {
 		availConfig = mcev1.HABasic
 	}
 
@@ -118,7 +118,7 @@ func RenderMultiClusterEngine(existingMCE *mcev1.MultiClusterEngine, m *operator
 		RemoveSupportedAnnotations(copy)
 	}
 
-	if m.Spec.AvailabilityConfig == operatorv1.HABasic {
+	if m.EffectiveAvailabilityConfig() == operatorv1.HABasic {
 		copy.Spec.AvailabilityConfig = mcev1.HABasic
 	} else {
 		copy.Spec.AvailabilityConfig = mcev1.HAHigh
@@ -8,16 +8,14 @@ package v1
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
-	configv1 "github.com/openshift/api/config/v1"
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
-	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+	"github.com/stolostron/multiclusterhub-operator/pkg/tlsprofile"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -135,23 +133,17 @@ func GetClusterCatalog(ctx context.Context, k8sClient client.Client, desiredPack
 func catalogContainsPackage(ctx context.Context, cl client.Client, catalogName, packageName string) (bool, error) {
 	url := fmt.Sprintf("https://catalogd-service.openshift-catalogd.svc/catalogs/%s/api/v1/all", catalogName)
 
-	// Get TLS configuration from cluster APIServer profile
-	tlsProfile, err := utils.GetAPIServerTLSProfile(ctx, cl)
+	// Follow the cluster TLS security profile, like the webhook and metrics servers
+	profile, err := tlsprofile.Load(ctx, cl)
 	if err != nil {
-		// Fallback to TLS 1.2 if profile unavailable
-		tlsProfile = &configv1.TLSProfileSpec{MinTLSVersion: configv1.VersionTLS12}
+		profile = tlsprofile.Default()
 	}
-
-	// Convert configv1.TLSProtocolVersion to tls version constant
-	minTLSVersion := tlsVersionToUint16(tlsProfile.MinTLSVersion)
-
+	tlsConfig := profile.ClientConfig()
 	// Skip TLS verification for in-cluster service communication
 	// #nosec G402 -- catalogd service uses self-signed cert for in-cluster communication
+	tlsConfig.InsecureSkipVerify = true
 	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-			MinVersion:         minTLSVersion,
-		},
+		TLSClientConfig: tlsConfig,
 	}
 	client := &http.Client{
 		Transport: tr,
@@ -196,19 +188,3 @@ func catalogContainsPackage(ctx context.Context, cl client.Client, catalogName,
 
 	return false, nil
 }
-
-// tlsVersionToUint16 converts configv1.TLSProtocolVersion to crypto/tls version constant
-func tlsVersionToUint16(version configv1.TLSProtocolVersion) uint16 {
-	switch version {
-	case configv1.VersionTLS10:
-		return tls.VersionTLS10
-	case configv1.VersionTLS11:
-		return tls.VersionTLS11
-	case configv1.VersionTLS12:
-		return tls.VersionTLS12
-	case configv1.VersionTLS13:
-		return tls.VersionTLS13
-	default:
-		return tls.VersionTLS12 // Default to TLS 1.2
-	}
-}
@@ -0,0 +1,166 @@
+// Copyright Contributors to the Open Cluster Management project
+
+// Package oadp tracks the OADP operator that cluster-backup depends on.
+//
+// cluster-backup installs OADP into the backup namespace, but OADP may also have been installed
+// beforehand, either by a previous hub or independently by the cluster admin. Detect finds the
+// installation through OLM, whether it was made with an OLM v0 Subscription or an OLM v1
+// ClusterExtension, so the operator can adopt the version checks and status reporting for it
+// without installing a second copy.
+package oadp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/Masterminds/semver/v3"
+	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Namespace is the namespace cluster-backup installs OADP into
+	Namespace = "open-cluster-management-backup"
+	// SubscriptionName is the name of the OLM v0 Subscription created by cluster-backup
+	SubscriptionName = "redhat-oadp-operator-subscription"
+	// ClusterExtensionName is the name of the OLM v1 ClusterExtension created by cluster-backup
+	ClusterExtensionName = "redhat-oadp-operator"
+	// MinimumVersion is the oldest OADP release cluster-backup works with
+	MinimumVersion = "1.4.0"
+)
+
+var channelVersion = regexp.MustCompile(`^stable-(\d+\.\d+)$`)
+
+// Installation describes an OADP operator found on the cluster
+type Installation struct {
+	// OLMVersion is "v0" for a Subscription install and "v1" for a ClusterExtension install
+	OLMVersion string
+	// Managed is true when the installation was created by cluster-backup
+	Managed bool
+
+	Subscription     *subv1alpha1.Subscription
+	CSV              *subv1alpha1.ClusterServiceVersion
+	ClusterExtension *ocv1.ClusterExtension
+}
+
+// Version returns the installed OADP version, or "" if it is not known yet
+func (i *Installation) Version() string {
+	switch {
+	case i.CSV != nil:
+		return i.CSV.Spec.Version.String()
+	case i.ClusterExtension != nil && i.ClusterExtension.Status.Install != nil:
+		return i.ClusterExtension.Status.Install.Bundle.Version
+	}
+	return ""
+}
+
+// Channel returns the Subscription channel of an OLM v0 installation
+func (i *Installation) Channel() string {
+	if i.Subscription == nil || i.Subscription.Spec == nil {
+		return ""
+	}
+	return i.Subscription.Spec.Channel
+}
+
+/*
+Detect looks for an OADP installation of the given package. An installation created by cluster-backup is
+preferred over any other. APIs that are not served (e.g. OLM v1 on an OLM v0 cluster) are skipped. Returns nil
+if OADP is not installed.
+*/
+func Detect(ctx context.Context, c client.Client, packageName string) (*Installation, error) {
+	subList := &subv1alpha1.SubscriptionList{}
+	if err := c.List(ctx, subList); err != nil && !apimeta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	var found *Installation
+	for i := range subList.Items {
+		sub := &subList.Items[i]
+		if sub.Spec == nil || sub.Spec.Package != packageName {
+			continue
+		}
+		managed := sub.Namespace == Namespace && sub.Name == SubscriptionName
+		if found == nil || managed {
+			found = &Installation{OLMVersion: "v0", Managed: managed, Subscription: sub}
+		}
+	}
+
+	if found != nil {
+		if err := found.getCSV(ctx, c); err != nil {
+			return nil, err
+		}
+		return found, nil
+	}
+
+	ceList := &ocv1.ClusterExtensionList{}
+	if err := c.List(ctx, ceList); err != nil && !apimeta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to list clusterextensions: %w", err)
+	}
+	for i := range ceList.Items {
+		ce := &ceList.Items[i]
+		if ce.Spec.Source.Catalog == nil || ce.Spec.Source.Catalog.PackageName != packageName {
+			continue
+		}
+		managed := ce.Name == ClusterExtensionName
+		if found == nil || managed {
+			found = &Installation{OLMVersion: "v1", Managed: managed, ClusterExtension: ce}
+		}
+	}
+	return found, nil
+}
+
+// getCSV fetches the CSV installed by the Subscription, if it has been installed yet
+func (i *Installation) getCSV(ctx context.Context, c client.Client) error {
+	installed := i.Subscription.Status.InstalledCSV
+	if installed == "" {
+		return nil
+	}
+
+	csv := &subv1alpha1.ClusterServiceVersion{}
+	err := c.Get(ctx, types.NamespacedName{Name: installed, Namespace: i.Subscription.Namespace}, csv)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	i.CSV = csv
+	return nil
+}
+
+// CheckCompatible returns an error if the OADP version is older than cluster-backup supports
+func CheckCompatible(version string) error {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return fmt.Errorf("unable to parse OADP version %q: %w", version, err)
+	}
+	if v.LessThan(semver.MustParse(MinimumVersion)) {
+		return fmt.Errorf("OADP %s is older than %s, the minimum version supported by cluster-backup",
+			version, MinimumVersion)
+	}
+	return nil
+}
+
+/*
+IsDowngrade reports whether switching an installation to the given channel would move OADP to an older minor
+release, e.g. from an installed 1.5.1 to the stable-1.4 channel. Channels without a version, like "stable", always
+track the latest release and are never a downgrade.
+*/
+func IsDowngrade(installedVersion, channel string) bool {
+	m := channelVersion.FindStringSubmatch(channel)
+	if m == nil {
+		return false
+	}
+	installed, err := semver.NewVersion(installedVersion)
+	if err != nil {
+		return false
+	}
+	target, err := semver.NewVersion(m[1])
+	if err != nil {
+		return false
+	}
+
+	installedMinor := semver.New(installed.Major(), installed.Minor(), 0, "", "")
+	return target.LessThan(installedMinor)
+}
@@ -0,0 +1,155 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package oadp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/api/pkg/lib/version"
+	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := subv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := ocv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func subscription(namespace, name, pkg, installedCSV string) *subv1alpha1.Subscription {
+	return &subv1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       &subv1alpha1.SubscriptionSpec{Package: pkg, Channel: "stable"},
+		Status:     subv1alpha1.SubscriptionStatus{InstalledCSV: installedCSV},
+	}
+}
+
+func TestDetect(t *testing.T) {
+	csv := &subv1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "oadp-operator.v1.5.2", Namespace: Namespace},
+		Spec: subv1alpha1.ClusterServiceVersionSpec{
+			Version: version.OperatorVersion{Version: semver.MustParse("1.5.2")},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		wantNil     bool
+		wantManaged bool
+		wantOLM     string
+		wantVersion string
+	}{
+		{
+			name:    "not installed",
+			objs:    []client.Object{subscription("other", "other-sub", "other-operator", "")},
+			wantNil: true,
+		},
+		{
+			name: "managed subscription preferred",
+			objs: []client.Object{
+				subscription("openshift-adp", "oadp", "redhat-oadp-operator", ""),
+				subscription(Namespace, SubscriptionName, "redhat-oadp-operator", csv.Name),
+				csv,
+			},
+			wantManaged: true,
+			wantOLM:     "v0",
+			wantVersion: "1.5.2",
+		},
+		{
+			name:    "external subscription",
+			objs:    []client.Object{subscription("openshift-adp", "oadp", "redhat-oadp-operator", "")},
+			wantOLM: "v0",
+		},
+		{
+			name: "cluster extension",
+			objs: []client.Object{&ocv1.ClusterExtension{
+				ObjectMeta: metav1.ObjectMeta{Name: ClusterExtensionName},
+				Spec: ocv1.ClusterExtensionSpec{
+					Source: ocv1.SourceConfig{
+						SourceType: ocv1.SourceTypeCatalog,
+						Catalog:    &ocv1.CatalogFilter{PackageName: "redhat-oadp-operator"},
+					},
+				},
+				Status: ocv1.ClusterExtensionStatus{
+					Install: &ocv1.ClusterExtensionInstallStatus{
+						Bundle: ocv1.BundleMetadata{Name: "oadp-operator.v1.4.1", Version: "1.4.1"},
+					},
+				},
+			}},
+			wantManaged: true,
+			wantOLM:     "v1",
+			wantVersion: "1.4.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(tt.objs...).Build()
+			got, err := Detect(context.Background(), c, "redhat-oadp-operator")
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("Detect() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("Detect() = nil, want an installation")
+			}
+			if got.Managed != tt.wantManaged || got.OLMVersion != tt.wantOLM || got.Version() != tt.wantVersion {
+				t.Errorf("Detect() = managed %v, OLM %s, version %q, want managed %v, OLM %s, version %q",
+					got.Managed, got.OLMVersion, got.Version(), tt.wantManaged, tt.wantOLM, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{version: "1.4.0", wantErr: false},
+		{version: "1.5.2", wantErr: false},
+		{version: "1.3.9", wantErr: true},
+		{version: "not-a-version", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := CheckCompatible(tt.version); (err != nil) != tt.wantErr {
+			t.Errorf("CheckCompatible(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsDowngrade(t *testing.T) {
+	tests := []struct {
+		installed string
+		channel   string
+		want      bool
+	}{
+		{installed: "1.5.1", channel: "stable-1.4", want: true},
+		{installed: "1.4.3", channel: "stable-1.4", want: false},
+		{installed: "1.4.3", channel: "stable-1.5", want: false},
+		{installed: "1.5.1", channel: "stable", want: false},
+		{installed: "", channel: "stable-1.4", want: false},
+	}
+	for _, tt := range tests {
+		if got := IsDowngrade(tt.installed, tt.channel); got != tt.want {
+			t.Errorf("IsDowngrade(%q, %q) = %v, want %v", tt.installed, tt.channel, got, tt.want)
+		}
+	}
+}
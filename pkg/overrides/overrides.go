@@ -14,9 +14,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
+	api "github.com/stolostron/multiclusterhub-operator/api/v1"
 	"github.com/stolostron/multiclusterhub-operator/pkg/manifest"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -38,6 +40,16 @@ const (
 	TemplateOverridePrefix = "TEMPLATE_OVERRIDE_"
 )
 
+var (
+	// imageReferenceRegexp matches a registry/repository reference, following the distribution reference grammar
+	imageReferenceRegexp = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?` +
+		`(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?` +
+		`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+
+	imageTagRegexp    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	imageDigestRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+)
+
 /*
 ConvertImageOverrides converts manifest images to overrides in a map. It iterates through the provided slice of
 manifest images, constructs overrides based on digests or tags, and updates the given overrides map. It returns an
@@ -50,6 +62,10 @@ func ConvertImageOverrides(overrides map[string]string, manifestImages []manifes
 			return fmt.Errorf("unexpected manifest image format: missing or empty ImageKey %v", m)
 		}
 
+		if err := ValidateManifestImage(m); err != nil {
+			return err
+		}
+
 		// Check if either ImageDigest or ImageTag is provided.
 		if m.ImageDigest != "" {
 			overrides[m.ImageKey] = fmt.Sprintf("%s/%s@%s", m.ImageRemote, m.ImageName, m.ImageDigest)
@@ -62,6 +78,24 @@ func ConvertImageOverrides(overrides map[string]string, manifestImages []manifes
 	return nil
 }
 
+/*
+ValidateManifestImage returns an error if the image reference built from a manifest image is not valid. The remote
+and name must form a registry/repository reference, and the digest or tag must be well formed.
+*/
+func ValidateManifestImage(m manifest.ManifestImage) error {
+	repository := fmt.Sprintf("%s/%s", m.ImageRemote, m.ImageName)
+	if m.ImageRemote == "" || m.ImageName == "" || !imageReferenceRegexp.MatchString(repository) {
+		return fmt.Errorf("invalid image reference for %s: %q is not a valid repository", m.ImageKey, repository)
+	}
+	if m.ImageDigest != "" && !imageDigestRegexp.MatchString(m.ImageDigest) {
+		return fmt.Errorf("invalid image reference for %s: %q is not a valid digest", m.ImageKey, m.ImageDigest)
+	}
+	if m.ImageDigest == "" && m.ImageTag != "" && !imageTagRegexp.MatchString(m.ImageTag) {
+		return fmt.Errorf("invalid image reference for %s: %q is not a valid tag", m.ImageKey, m.ImageTag)
+	}
+	return nil
+}
+
 /*
 convertTemplateOverrides converts manifest templates to overrides in a map. It iterates through the provided
 manifest template, converts each template override to a string, and updates the given overrides map. It returns an
@@ -126,35 +160,76 @@ func GetOverridesFromConfigmap(k8sClient client.Client, overrides map[string]str
 		return overrides, err
 	}
 
-	if len(configmap.Data) != 1 {
-		return overrides, fmt.Errorf("Unexpected number of keys in ConfigMap %s: expected 1 key, found %d keys",
-			configmapName, len(configmap.Data))
+	_, data, err := GetOverridesData(configmap)
+	if err != nil {
+		return overrides, err
 	}
+	return ParseOverrides(overrides, data, isTemplate)
+}
 
-	for _, v := range configmap.Data {
-		if isTemplate {
-			var manifestTemplate manifest.ManifestTemplate
-			if err := json.Unmarshal([]byte(v), &manifestTemplate); err != nil {
-				return overrides, err
-			}
+/*
+GetOverridesData returns the key and the content of an image or template override ConfigMap. It returns an error if
+the ConfigMap does not have exactly one key.
+*/
+func GetOverridesData(configmap *corev1.ConfigMap) (string, string, error) {
+	if len(configmap.Data) != 1 {
+		return "", "", fmt.Errorf("Unexpected number of keys in ConfigMap %s: expected 1 key, found %d keys",
+			configmap.GetName(), len(configmap.Data))
+	}
 
-			if err := ConvertTemplateOverrides(overrides, manifestTemplate); err != nil {
-				return overrides, err
-			}
+	for k, v := range configmap.Data {
+		return k, v, nil
+	}
+	return "", "", nil
+}
 
-		} else {
-			var manifestImage []manifest.ManifestImage
-			if err := json.Unmarshal([]byte(v), &manifestImage); err != nil {
-				return overrides, err
-			}
-
-			if err := ConvertImageOverrides(overrides, manifestImage); err != nil {
-				return overrides, err
-			}
+/*
+ParseOverrides parses the content of an image or template override ConfigMap into the overrides map. Image overrides
+replace existing values, while template overrides keep the values already set by environment variables.
+*/
+func ParseOverrides(overrides map[string]string, data string, isTemplate bool) (map[string]string, error) {
+	if isTemplate {
+		var manifestTemplate manifest.ManifestTemplate
+		if err := json.Unmarshal([]byte(data), &manifestTemplate); err != nil {
+			return overrides, err
 		}
+		return overrides, ConvertTemplateOverrides(overrides, manifestTemplate)
+	}
+
+	var manifestImage []manifest.ManifestImage
+	if err := json.Unmarshal([]byte(data), &manifestImage); err != nil {
+		return overrides, err
+	}
+	return overrides, ConvertImageOverrides(overrides, manifestImage)
+}
+
+/*
+GetPreservedFieldsFromConfigmap reads the preservedFields section of a template override ConfigMap. It lists the
+fields of rendered resources that keep their value from the cluster, in the format of
+spec.overrides.preservedFields of the MultiClusterHub.
+*/
+func GetPreservedFieldsFromConfigmap(k8sClient client.Client, namespace, configmapName string) (
+	[]api.PreservedFields, error) {
+	configmap := &corev1.ConfigMap{}
+	err := k8sClient.Get(context.TODO(), types.NamespacedName{
+		Name:      configmapName,
+		Namespace: namespace,
+	}, configmap)
+	if err != nil {
+		return nil, err
 	}
 
-	return overrides, nil
+	var preserved []api.PreservedFields
+	for _, v := range configmap.Data {
+		var template struct {
+			PreservedFields []api.PreservedFields `json:"preservedFields"`
+		}
+		if err := json.Unmarshal([]byte(v), &template); err != nil {
+			return nil, err
+		}
+		preserved = append(preserved, template.PreservedFields...)
+	}
+	return preserved, nil
 }
 
 /*
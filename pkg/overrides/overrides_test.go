@@ -122,6 +122,78 @@ func Test_ConvertImageOverrides(t *testing.T) {
 	})
 }
 
+func Test_ValidateManifestImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   manifest.ManifestImage
+		wantErr bool
+	}{
+		{
+			name: "Tag",
+			image: manifest.ManifestImage{ImageKey: "foo", ImageRemote: "quay.io/stolostron", ImageName: "foo",
+				ImageTag: "2.9.0"},
+		},
+		{
+			name: "Registry with port and digest",
+			image: manifest.ManifestImage{ImageKey: "foo", ImageRemote: "registry.example.com:5000/acm", ImageName: "foo",
+				ImageDigest: "sha256:4e1a295760c9f2fc7b2b143e6933a625892fda6fe2b3c597d4318d1b1ab3b276"},
+		},
+		{
+			name:    "Uppercase repository",
+			image:   manifest.ManifestImage{ImageKey: "foo", ImageRemote: "quay.io/Stolostron", ImageName: "foo", ImageTag: "1"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing remote",
+			image:   manifest.ManifestImage{ImageKey: "foo", ImageName: "foo", ImageTag: "1"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid tag",
+			image:   manifest.ManifestImage{ImageKey: "foo", ImageRemote: "quay.io", ImageName: "foo", ImageTag: "v1:latest"},
+			wantErr: true,
+		},
+		{
+			name: "Invalid digest",
+			image: manifest.ManifestImage{ImageKey: "foo", ImageRemote: "quay.io", ImageName: "foo",
+				ImageDigest: "sha256:xyz"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateManifestImage(tt.image); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateManifestImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ParseOverrides(t *testing.T) {
+	overrides, err := ParseOverrides(map[string]string{"console": "quay.io/foo/console:1"},
+		`[{"image-key": "console", "image-remote": "quay.io/bar", "image-name": "console", "image-tag": "2"}]`, false)
+	if err != nil || overrides["console"] != "quay.io/bar/console:2" {
+		t.Errorf("ParseOverrides() = %v, %v, want the image override to replace the existing value", overrides, err)
+	}
+
+	if _, err := ParseOverrides(map[string]string{},
+		`[{"image-key": "console", "image-remote": "quay.io/bar", "image-name": "Console", "image-tag": "2"}]`,
+		false); err == nil {
+		t.Error("ParseOverrides() expected an error for an invalid image reference")
+	}
+
+	overrides, err = ParseOverrides(map[string]string{"console_cpu_limit": "1"},
+		`{"templateOverrides": {"console_cpu_limit": "2", "console_memory_limit": "1Gi"}}`, true)
+	if err != nil || overrides["console_cpu_limit"] != "1" || overrides["console_memory_limit"] != "1Gi" {
+		t.Errorf("ParseOverrides() = %v, %v, want environment template overrides to be kept", overrides, err)
+	}
+
+	if _, err := ParseOverrides(map[string]string{}, `{"templateOverrides": `, true); err == nil {
+		t.Error("ParseOverrides() expected an error for malformed JSON")
+	}
+}
+
 func Test_ConvertTemplateOverrides(t *testing.T) {
 	t.Run("Convert template overrides with preexisting values", func(t *testing.T) {
 		overrides := map[string]string{
@@ -293,3 +365,33 @@ func Test_GetOverridesFromConfigmap(t *testing.T) {
 		// fakeclient.Delete(context.TODO(), &cm, &client.DeleteOptions{})
 	})
 }
+
+func Test_GetPreservedFieldsFromConfigmap(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "configmapName",
+			Namespace: "namespace",
+		},
+		Data: map[string]string{
+			"template-override.json": `
+				{
+					"templateOverrides": {
+						"console_limit_cpu": "30Mi"
+					},
+					"preservedFields": [
+						{"kind": "Deployment", "name": "search-api", "paths": ["spec.replicas"]}
+					]
+				}
+			`,
+		},
+	}
+	fakeclient := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	preserved, err := GetPreservedFieldsFromConfigmap(fakeclient, "namespace", "configmapName")
+	if err != nil {
+		t.Fatalf("Failed to get preserved fields from configmap: %v", err)
+	}
+	if len(preserved) != 1 || preserved[0].Name != "search-api" || preserved[0].Paths[0] != "spec.replicas" {
+		t.Errorf("Failed to get correct preserved fields: %v", preserved)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package overrideschema validates template overrides against the schemas published by the component charts.
+//
+// Each toggle chart lists the template-override keys its templates read in a template-overrides.schema.json file
+// next to its Chart.yaml. The file is a JSON schema of an object whose properties are the supported keys:
+//
+//	{
+//	  "type": "object",
+//	  "additionalProperties": false,
+//	  "properties": {
+//	    "console_deployment_container_cpu_limit": {"type": "string", "format": "quantity"}
+//	  }
+//	}
+//
+// Only the type, format and enum keywords of a property are evaluated. This package only depends on the standard
+// library and apimachinery so the MultiClusterHub webhook can use it.
+package overrideschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// SchemaFile is the name of the template-override schema file of a chart
+	SchemaFile = "template-overrides.schema.json"
+
+	// ToggleChartsLocation is the location of the component charts
+	ToggleChartsLocation = "/charts/toggle"
+
+	// FormatQuantity marks string properties that must be a Kubernetes resource quantity
+	FormatQuantity = "quantity"
+)
+
+// Property describes a supported template-override key
+type Property struct {
+	// Type is one of string, integer, number or boolean. Empty accepts any value.
+	Type        string   `json:"type,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// Schema lists the template-override keys supported by a chart
+type Schema struct {
+	Properties map[string]Property `json:"properties"`
+}
+
+// Schemas maps chart names to their template-override schema
+type Schemas map[string]*Schema
+
+// Rejection is a template override that is not applied
+type Rejection struct {
+	Key    string
+	Reason string
+}
+
+// ChartPath resolves a chart location, such as /charts/toggle/console, to its path on disk in the same way the
+// renderer does.
+func ChartPath(chartLocation string) string {
+	if val, ok := os.LookupEnv("DIRECTORY_OVERRIDE"); ok {
+		return path.Join(val, chartLocation)
+	}
+	value, _ := os.LookupEnv("TEMPLATES_PATH")
+	return path.Join(value, chartLocation)
+}
+
+// LoadChart reads the template-override schema of the chart at chartPath. A chart without a schema file supports no
+// template overrides.
+func LoadChart(chartPath string) (*Schema, error) {
+	data, err := os.ReadFile(filepath.Join(chartPath, SchemaFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Schema{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("failed to parse %s of chart %s: %w", SchemaFile, filepath.Base(chartPath), err)
+	}
+	return schema, nil
+}
+
+// LoadToggleCharts reads the template-override schemas of all component charts
+func LoadToggleCharts() (Schemas, error) {
+	chartsDir := ChartPath(ToggleChartsLocation)
+	entries, err := os.ReadDir(chartsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := Schemas{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		schema, err := LoadChart(filepath.Join(chartsDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		schemas[entry.Name()] = schema
+	}
+	return schemas, nil
+}
+
+// Lookup returns the property of a template-override key and whether any chart supports it
+func (s Schemas) Lookup(key string) (Property, bool) {
+	for _, schema := range s {
+		if p, ok := schema.Properties[key]; ok {
+			return p, true
+		}
+	}
+	return Property{}, false
+}
+
+/*
+Validate splits template overrides into the ones supported by a chart with a valid value and the rejected ones. The
+rejections are sorted by key.
+*/
+func (s Schemas) Validate(overrides map[string]string) (map[string]string, []Rejection) {
+	valid := map[string]string{}
+	var rejected []Rejection
+	for key, value := range overrides {
+		p, ok := s.Lookup(key)
+		if !ok {
+			rejected = append(rejected, Rejection{Key: key, Reason: "not supported by any component chart"})
+			continue
+		}
+		if err := p.Validate(value); err != nil {
+			rejected = append(rejected, Rejection{Key: key, Reason: err.Error()})
+			continue
+		}
+		valid[key] = value
+	}
+
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i].Key < rejected[j].Key })
+	return valid, rejected
+}
+
+// Effective returns the template overrides read by the chart, or nil if it reads none of them
+func (s *Schema) Effective(overrides map[string]string) map[string]string {
+	var effective map[string]string
+	for key, value := range overrides {
+		if _, ok := s.Properties[key]; !ok {
+			continue
+		}
+		if effective == nil {
+			effective = map[string]string{}
+		}
+		effective[key] = value
+	}
+	return effective
+}
+
+// Validate returns an error if value does not match the property
+func (p Property) Validate(value string) error {
+	switch p.Type {
+	case "", "string":
+	case "integer":
+		if !isInteger(value) {
+			return fmt.Errorf("invalid value %q: expected an integer", value)
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("invalid value %q: expected a number", value)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid value %q: expected a boolean", value)
+		}
+	default:
+		return fmt.Errorf("unsupported schema type %q", p.Type)
+	}
+
+	if p.Format == FormatQuantity {
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("invalid value %q: expected a resource quantity", value)
+		}
+	}
+
+	if len(p.Enum) > 0 {
+		for _, allowed := range p.Enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q: expected one of %v", value, p.Enum)
+	}
+	return nil
+}
+
+// isInteger accepts integral numbers, including the float formatting of ConfigMap values such as 3.000000
+func isInteger(value string) bool {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return true
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	return err == nil && f == math.Trunc(f) && !math.IsInf(f, 0)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package overrideschema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+// templateOverrideRef matches the template-override keys read by chart templates
+var templateOverrideRef = regexp.MustCompile(
+	`(?:\.Values\.global\.templateOverrides\.(\w+))|(?:hasKey \.Values\.global\.templateOverrides "(\w+)")`)
+
+func Test_LoadToggleCharts(t *testing.T) {
+	t.Setenv("TEMPLATES_PATH", "../templates")
+
+	schemas, err := LoadToggleCharts()
+	if err != nil {
+		t.Fatalf("LoadToggleCharts() error = %v", err)
+	}
+
+	chartsDir := ChartPath(ToggleChartsLocation)
+	for chart, schema := range schemas {
+		t.Run(chart, func(t *testing.T) {
+			if _, err := os.Stat(filepath.Join(chartsDir, chart, SchemaFile)); err != nil {
+				t.Fatalf("expected chart to publish %s: %v", SchemaFile, err)
+			}
+
+			// Every key read by the chart templates is published and every published key is read
+			used := map[string]bool{}
+			files, _ := filepath.Glob(filepath.Join(chartsDir, chart, "templates", "*"))
+			for _, f := range files {
+				data, err := os.ReadFile(f)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, m := range templateOverrideRef.FindAllStringSubmatch(string(data), -1) {
+					used[m[1]+m[2]] = true
+				}
+			}
+
+			var want, got []string
+			for key := range used {
+				want = append(want, key)
+			}
+			for key := range schema.Properties {
+				got = append(got, key)
+			}
+			sort.Strings(want)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("schema keys = %v, template keys = %v", got, want)
+			}
+		})
+	}
+
+	if _, ok := schemas["console"].Properties["console_deployment_container_cpu_limit"]; !ok {
+		t.Error("expected the console chart to publish its resource overrides")
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	schemas := Schemas{
+		"console": {Properties: map[string]Property{
+			"console_cpu_limit": {Type: "string", Format: FormatQuantity},
+			"console_replicas":  {Type: "integer"},
+		}},
+		"grc": {Properties: map[string]Property{
+			"grc_log_level": {Type: "string", Enum: []string{"info", "debug"}},
+			"grc_debug":     {Type: "boolean"},
+		}},
+	}
+
+	valid, rejected := schemas.Validate(map[string]string{
+		"console_cpu_limit":  "500m",
+		"console_replicas":   "3.000000",
+		"grc_log_level":      "trace",
+		"grc_debug":          "true",
+		"console_cpu_limitt": "1",
+		"console_memory":     "1Gi",
+	})
+
+	wantValid := map[string]string{"console_cpu_limit": "500m", "console_replicas": "3.000000", "grc_debug": "true"}
+	if !reflect.DeepEqual(valid, wantValid) {
+		t.Errorf("valid = %v, want %v", valid, wantValid)
+	}
+	var keys []string
+	for _, r := range rejected {
+		keys = append(keys, r.Key)
+	}
+	if want := []string{"console_cpu_limitt", "console_memory", "grc_log_level"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("rejected = %v, want %v", rejected, want)
+	}
+
+	if got := schemas["grc"].Effective(valid); !reflect.DeepEqual(got, map[string]string{"grc_debug": "true"}) {
+		t.Errorf("Effective() = %v, want the grc override", got)
+	}
+	if got := (&Schema{}).Effective(valid); got != nil {
+		t.Errorf("Effective() = %v, want nil for a chart without overrides", got)
+	}
+}
+
+func Test_PropertyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Property
+		value   string
+		wantErr bool
+	}{
+		{name: "quantity", p: Property{Type: "string", Format: FormatQuantity}, value: "512Mi"},
+		{name: "bad quantity", p: Property{Type: "string", Format: FormatQuantity}, value: "lots", wantErr: true},
+		{name: "integer", p: Property{Type: "integer"}, value: "2"},
+		{name: "fraction", p: Property{Type: "integer"}, value: "2.5", wantErr: true},
+		{name: "number", p: Property{Type: "number"}, value: "0.5"},
+		{name: "boolean", p: Property{Type: "boolean"}, value: "yes", wantErr: true},
+		{name: "any", p: Property{}, value: "anything"},
+		{name: "unsupported type", p: Property{Type: "array"}, value: "[]", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.p.Validate(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
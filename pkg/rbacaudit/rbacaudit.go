@@ -0,0 +1,306 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package rbacaudit compares the permissions the rendered component charts need with the permissions granted to the
+// operator.
+//
+// Applying a rendered template takes the get, create, update, patch and delete verbs on its resource. Creating a Role
+// or ClusterRole also takes every permission the role grants, as the API server prevents privilege escalation, unless
+// the operator holds the escalate verb on roles or clusterroles. This package only depends on apimachinery and the
+// rbac API so the RBAC generator can use it.
+package rbacaudit
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ApplyVerbs are the verbs the operator uses to apply and remove a rendered template
+var ApplyVerbs = []string{"get", "create", "update", "patch", "delete"}
+
+// Permission is a single verb on a resource, a named resource or a non-resource URL
+type Permission struct {
+	Verb           string
+	Group          string
+	Resource       string
+	ResourceName   string
+	NonResourceURL string
+}
+
+// String returns the permission as "<verb> <resource>.<group>", followed by the resource name when it is limited to one
+func (p Permission) String() string {
+	if p.NonResourceURL != "" {
+		return p.Verb + " " + p.NonResourceURL
+	}
+	s := p.Verb + " " + p.Resource
+	if p.Group != "" {
+		s += "." + p.Group
+	}
+	if p.ResourceName != "" {
+		s += " named " + p.ResourceName
+	}
+	return s
+}
+
+// Covers returns true when holding the permission grants q
+func (p Permission) Covers(q Permission) bool {
+	if p.Verb != rbacv1.VerbAll && p.Verb != q.Verb {
+		return false
+	}
+	if p.NonResourceURL != "" || q.NonResourceURL != "" {
+		if p.NonResourceURL == "" || q.NonResourceURL == "" {
+			return false
+		}
+		if prefix, ok := strings.CutSuffix(p.NonResourceURL, "*"); ok {
+			return strings.HasPrefix(q.NonResourceURL, prefix)
+		}
+		return p.NonResourceURL == q.NonResourceURL
+	}
+	return (p.Group == rbacv1.APIGroupAll || p.Group == q.Group) && resourceCovers(p.Resource, q.Resource) &&
+		(p.ResourceName == "" || p.ResourceName == q.ResourceName)
+}
+
+// resourceCovers matches resources like the API server, "*/<subresource>" matches the subresource of any resource
+func resourceCovers(granted, resource string) bool {
+	if granted == rbacv1.ResourceAll || granted == resource {
+		return true
+	}
+	if sub, ok := strings.CutPrefix(granted, "*/"); ok {
+		_, resourceSub, found := strings.Cut(resource, "/")
+		return found && resourceSub == sub
+	}
+	return false
+}
+
+// Permissions expands policy rules into the permissions they grant
+func Permissions(rules []rbacv1.PolicyRule) []Permission {
+	permissions := []Permission{}
+	for _, rule := range rules {
+		for _, verb := range rule.Verbs {
+			for _, url := range rule.NonResourceURLs {
+				permissions = append(permissions, Permission{Verb: verb, NonResourceURL: url})
+			}
+			names := rule.ResourceNames
+			if len(names) == 0 {
+				names = []string{""}
+			}
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					for _, name := range names {
+						permissions = append(permissions, Permission{
+							Verb: verb, Group: group, Resource: resource, ResourceName: name,
+						})
+					}
+				}
+			}
+		}
+	}
+	return permissions
+}
+
+// Requirements are the permissions the rendered templates of a chart need
+type Requirements struct {
+	// Apply are the permissions needed to apply and remove the templates
+	Apply []Permission
+
+	// Roles are the permissions granted by the rendered Roles
+	Roles []Permission
+
+	// ClusterRoles are the permissions granted by the rendered ClusterRoles
+	ClusterRoles []Permission
+
+	// WildcardRules lists the rules of the rendered ClusterRoles that grant every api group, resource or verb
+	WildcardRules []string
+}
+
+// Required returns the permissions needed by the rendered templates of a chart
+func Required(templates []*unstructured.Unstructured) (Requirements, error) {
+	req := Requirements{}
+	for _, template := range templates {
+		gvk := template.GroupVersionKind()
+		if gvk.Kind == "" {
+			continue
+		}
+		resource, _ := meta.UnsafeGuessKindToResource(gvk)
+		for _, verb := range ApplyVerbs {
+			req.Apply = append(req.Apply, Permission{Verb: verb, Group: gvk.Group, Resource: resource.Resource})
+		}
+
+		if gvk.Group != rbacv1.GroupName {
+			continue
+		}
+		switch gvk.Kind {
+		case "Role":
+			role := &rbacv1.Role{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, role); err != nil {
+				return req, fmt.Errorf("Role %s: %w", template.GetName(), err)
+			}
+			req.Roles = append(req.Roles, Permissions(role.Rules)...)
+		case "ClusterRole":
+			clusterRole := &rbacv1.ClusterRole{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.Object, clusterRole); err != nil {
+				return req, fmt.Errorf("ClusterRole %s: %w", template.GetName(), err)
+			}
+			req.ClusterRoles = append(req.ClusterRoles, Permissions(clusterRole.Rules)...)
+			for _, rule := range clusterRole.Rules {
+				if isWildcard(rule) {
+					req.WildcardRules = append(req.WildcardRules,
+						fmt.Sprintf("ClusterRole %s: %s", clusterRole.Name, RuleString(rule)))
+				}
+			}
+		}
+	}
+
+	req.Apply = unique(req.Apply)
+	req.Roles = unique(req.Roles)
+	req.ClusterRoles = unique(req.ClusterRoles)
+	sort.Strings(req.WildcardRules)
+	req.WildcardRules = slices.Compact(req.WildcardRules)
+	return req, nil
+}
+
+// Missing returns the required permissions the granted rules do not cover. The permissions of the rendered roles are
+// not required when the rules grant the escalate verb on the kind of role.
+func (r Requirements) Missing(granted []rbacv1.PolicyRule) []Permission {
+	grants := Permissions(granted)
+
+	required := append([]Permission{}, r.Apply...)
+	if !covered(grants, Permission{Verb: "escalate", Group: rbacv1.GroupName, Resource: "roles"}) {
+		required = append(required, r.Roles...)
+	}
+	if !covered(grants, Permission{Verb: "escalate", Group: rbacv1.GroupName, Resource: "clusterroles"}) {
+		required = append(required, r.ClusterRoles...)
+	}
+
+	missing := []Permission{}
+	for _, p := range required {
+		if !covered(grants, p) {
+			missing = append(missing, p)
+		}
+	}
+	return unique(missing)
+}
+
+// Excess returns the granted permissions that none of the requirements need
+func Excess(granted []rbacv1.PolicyRule, requirements ...Requirements) []Permission {
+	required := []Permission{}
+	for _, r := range requirements {
+		required = append(required, r.Apply...)
+		required = append(required, r.Roles...)
+		required = append(required, r.ClusterRoles...)
+	}
+
+	excess := []Permission{}
+	for _, p := range Permissions(granted) {
+		needed := false
+		for _, q := range required {
+			if p.Covers(q) {
+				needed = true
+				break
+			}
+		}
+		if !needed {
+			excess = append(excess, p)
+		}
+	}
+	return unique(excess)
+}
+
+// Strings returns the permissions as strings
+func Strings(permissions []Permission) []string {
+	s := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		s = append(s, p.String())
+	}
+	return s
+}
+
+// Summarize returns the permissions grouped by resource, as "<resource>.<group>: <verb>,<verb>"
+func Summarize(permissions []Permission) []string {
+	summary := []string{}
+	var last Permission
+	verbs := []string{}
+	flush := func() {
+		if len(verbs) > 0 {
+			summary = append(summary, strings.TrimSpace(last.String())+": "+strings.Join(verbs, ","))
+		}
+	}
+	for _, p := range unique(permissions) {
+		key := p
+		key.Verb = ""
+		if key != last {
+			flush()
+			last, verbs = key, nil
+		}
+		verbs = append(verbs, p.Verb)
+	}
+	flush()
+	return summary
+}
+
+// RuleString returns a policy rule as "apiGroups=[...] resources=[...] verbs=[...]"
+func RuleString(rule rbacv1.PolicyRule) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return fmt.Sprintf("nonResourceURLs=[%s] verbs=[%s]", strings.Join(rule.NonResourceURLs, ","),
+			strings.Join(rule.Verbs, ","))
+	}
+	return fmt.Sprintf("apiGroups=[%s] resources=[%s] verbs=[%s]", strings.Join(rule.APIGroups, ","),
+		strings.Join(rule.Resources, ","), strings.Join(rule.Verbs, ","))
+}
+
+// isWildcard returns true when the rule grants every api group, resource or verb
+func isWildcard(rule rbacv1.PolicyRule) bool {
+	for _, values := range [][]string{rule.APIGroups, rule.Resources, rule.Verbs} {
+		for _, v := range values {
+			if v == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func covered(grants []Permission, p Permission) bool {
+	for _, g := range grants {
+		if g.Covers(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// unique returns the permissions sorted by resource and verb without duplicates
+func unique(permissions []Permission) []Permission {
+	seen := map[Permission]bool{}
+	out := []Permission{}
+	for _, p := range permissions {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.NonResourceURL != b.NonResourceURL {
+			return a.NonResourceURL < b.NonResourceURL
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.ResourceName != b.ResourceName {
+			return a.ResourceName < b.ResourceName
+		}
+		return a.Verb < b.Verb
+	})
+	return out
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package rbacaudit
+
+import (
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func template(apiVersion, kind, name string, rules ...interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name},
+	}}
+	if len(rules) > 0 {
+		u.Object["rules"] = rules
+	}
+	return u
+}
+
+func rule(groups, resources, verbs []interface{}) map[string]interface{} {
+	return map[string]interface{}{"apiGroups": groups, "resources": resources, "verbs": verbs}
+}
+
+func Test_Covers(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted Permission
+		p       Permission
+		want    bool
+	}{
+		{
+			name:    "same permission",
+			granted: Permission{Verb: "get", Group: "apps", Resource: "deployments"},
+			p:       Permission{Verb: "get", Group: "apps", Resource: "deployments"},
+			want:    true,
+		},
+		{
+			name:    "other verb",
+			granted: Permission{Verb: "get", Group: "apps", Resource: "deployments"},
+			p:       Permission{Verb: "delete", Group: "apps", Resource: "deployments"},
+		},
+		{
+			name:    "wildcards",
+			granted: Permission{Verb: "*", Group: "*", Resource: "*"},
+			p:       Permission{Verb: "delete", Group: "apps", Resource: "deployments"},
+			want:    true,
+		},
+		{
+			name:    "wildcard subresource",
+			granted: Permission{Verb: "update", Group: "apps", Resource: "*/status"},
+			p:       Permission{Verb: "update", Group: "apps", Resource: "deployments/status"},
+			want:    true,
+		},
+		{
+			name:    "limited to a resource name",
+			granted: Permission{Verb: "get", Resource: "secrets", ResourceName: "pull-secret"},
+			p:       Permission{Verb: "get", Resource: "secrets"},
+		},
+		{
+			name:    "named resource",
+			granted: Permission{Verb: "get", Resource: "secrets"},
+			p:       Permission{Verb: "get", Resource: "secrets", ResourceName: "pull-secret"},
+			want:    true,
+		},
+		{
+			name:    "non-resource URL prefix",
+			granted: Permission{Verb: "get", NonResourceURL: "/metrics*"},
+			p:       Permission{Verb: "get", NonResourceURL: "/metrics/cadvisor"},
+			want:    true,
+		},
+		{
+			name:    "non-resource URL against a resource",
+			granted: Permission{Verb: "*", Group: "*", Resource: "*"},
+			p:       Permission{Verb: "get", NonResourceURL: "/metrics"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.granted.Covers(tt.p); got != tt.want {
+				t.Errorf("Covers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Required(t *testing.T) {
+	req, err := Required([]*unstructured.Unstructured{
+		template("apps/v1", "Deployment", "console"),
+		template("rbac.authorization.k8s.io/v1", "ClusterRole", "console",
+			rule([]interface{}{""}, []interface{}{"configmaps"}, []interface{}{"get"}),
+			rule([]interface{}{"*"}, []interface{}{"*"}, []interface{}{"list"})),
+	})
+	if err != nil {
+		t.Fatalf("Required() error = %v", err)
+	}
+
+	if got := Strings(req.Apply); !reflect.DeepEqual(got, []string{
+		"create deployments.apps", "delete deployments.apps", "get deployments.apps", "patch deployments.apps",
+		"update deployments.apps",
+		"create clusterroles.rbac.authorization.k8s.io", "delete clusterroles.rbac.authorization.k8s.io",
+		"get clusterroles.rbac.authorization.k8s.io", "patch clusterroles.rbac.authorization.k8s.io",
+		"update clusterroles.rbac.authorization.k8s.io",
+	}) {
+		t.Errorf("Apply = %v", got)
+	}
+	if got := Strings(req.ClusterRoles); !reflect.DeepEqual(got, []string{"get configmaps", "list *.*"}) {
+		t.Errorf("ClusterRoles = %v", got)
+	}
+	if want := []string{"ClusterRole console: apiGroups=[*] resources=[*] verbs=[list]"}; !reflect.DeepEqual(
+		req.WildcardRules, want) {
+		t.Errorf("WildcardRules = %v, want %v", req.WildcardRules, want)
+	}
+}
+
+func Test_Missing(t *testing.T) {
+	req, err := Required([]*unstructured.Unstructured{
+		template("v1", "ConfigMap", "config"),
+		template("rbac.authorization.k8s.io/v1", "ClusterRole", "reader",
+			rule([]interface{}{""}, []interface{}{"pods"}, []interface{}{"list"})),
+	})
+	if err != nil {
+		t.Fatalf("Required() error = %v", err)
+	}
+
+	granted := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "create", "patch"}},
+		{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, Verbs: []string{"*"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+	}
+
+	// The operator may escalate on ClusterRoles, it does not need the permissions they grant
+	if got := Strings(req.Missing(granted)); !reflect.DeepEqual(got, []string{
+		"delete configmaps", "update configmaps",
+	}) {
+		t.Errorf("Missing() = %v", got)
+	}
+
+	granted[1].Verbs = []string{"get", "create", "update", "patch", "delete"}
+	if got := Strings(req.Missing(granted)); !reflect.DeepEqual(got, []string{
+		"delete configmaps", "update configmaps", "list pods",
+	}) {
+		t.Errorf("Missing() without escalate = %v", got)
+	}
+
+	if got := Strings(Excess(granted, req)); !reflect.DeepEqual(got, []string{"get deployments.apps"}) {
+		t.Errorf("Excess() = %v", got)
+	}
+}
+
+func Test_Summarize(t *testing.T) {
+	got := Summarize(Permissions([]rbacv1.PolicyRule{
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"list", "get"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"pull"}, Verbs: []string{"get"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "watch"}},
+	}))
+	want := []string{"secrets named pull: get", "deployments.apps: get,list,watch"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize() = %v, want %v", got, want)
+	}
+}
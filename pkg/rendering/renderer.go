@@ -30,6 +30,7 @@ import (
 	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	v1 "github.com/stolostron/multiclusterhub-operator/api/v1"
 	"github.com/stolostron/multiclusterhub-operator/pkg/helpers"
+	"github.com/stolostron/multiclusterhub-operator/pkg/oadp"
 	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
 	"github.com/stolostron/multiclusterhub-operator/pkg/version"
 	"helm.sh/helm/v3/pkg/engine"
@@ -63,6 +64,10 @@ const (
 
 	// defaultOADPCatalogSourceNamespace defines the default namespace where the OADP operator catalog source is located.
 	defaultOADPCatalogSourceNamespace = "openshift-marketplace"
+
+	// defaultOADPVersion is the version range installed by the OADP ClusterExtension (OLM v1), matching the oldest
+	// OADP release supported by cluster-backup.
+	defaultOADPVersion = ">=" + oadp.MinimumVersion
 )
 
 var log = logf.Log.WithName("reconcile")
@@ -81,61 +86,6 @@ func convertTolerations(tols []corev1.Toleration) []Toleration {
 	return tolerations
 }
 
-// parseProbeConfigFromAnnotations reads probe config from MCH annotations
-func parseProbeConfigFromAnnotations(mch *v1.MultiClusterHub) *ProbeConfig {
-	if mch.Annotations == nil {
-		return nil
-	}
-
-	var config ProbeConfig
-	hasAny := false
-
-	if val, ok := mch.Annotations[utils.AnnotationProbeTimeoutSeconds]; ok {
-		timeout, err := strconv.ParseInt(val, 10, 32)
-		if err != nil {
-			log.Info(fmt.Sprintf("Invalid probe-timeout-seconds annotation value %q: %v", val, err))
-		} else if timeout <= 0 {
-			log.Info(fmt.Sprintf("Invalid probe-timeout-seconds annotation value %q: must be positive", val))
-		} else {
-			timeout32 := int32(timeout)
-			config.TimeoutSeconds = &timeout32
-			hasAny = true
-		}
-	}
-
-	if val, ok := mch.Annotations[utils.AnnotationProbeFailureThreshold]; ok {
-		threshold, err := strconv.ParseInt(val, 10, 32)
-		if err != nil {
-			log.Info(fmt.Sprintf("Invalid probe-failure-threshold annotation value %q: %v", val, err))
-		} else if threshold <= 0 {
-			log.Info(fmt.Sprintf("Invalid probe-failure-threshold annotation value %q: must be positive", val))
-		} else {
-			threshold32 := int32(threshold)
-			config.FailureThreshold = &threshold32
-			hasAny = true
-		}
-	}
-
-	if val, ok := mch.Annotations[utils.AnnotationProbeSuccessThreshold]; ok {
-		threshold, err := strconv.ParseInt(val, 10, 32)
-		if err != nil {
-			log.Info(fmt.Sprintf("Invalid probe-success-threshold annotation value %q: %v", val, err))
-		} else if threshold <= 0 {
-			log.Info(fmt.Sprintf("Invalid probe-success-threshold annotation value %q: must be positive", val))
-		} else {
-			threshold32 := int32(threshold)
-			config.SuccessThreshold = &threshold32
-			hasAny = true
-		}
-	}
-
-	if !hasAny {
-		return nil
-	}
-
-	return &config
-}
-
 func (u *Toleration) MarshalJSON() ([]byte, error) {
 
 	v := reflect.ValueOf(u)
@@ -249,6 +199,27 @@ func RenderCRDs(crdDir string, mch *v1.MultiClusterHub) ([]*unstructured.Unstruc
 	return crds, errs
 }
 
+// RenderComponentCRDs renders the CRDs of each subdirectory of the crd directory, keyed by the subdirectory. The
+// subdirectories are named after the component charts the CRDs belong to.
+func RenderComponentCRDs(crdDir string, mch *v1.MultiClusterHub) (map[string][]*unstructured.Unstructured, []error) {
+	entries, err := os.ReadDir(crdDir)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	crds := map[string][]*unstructured.Unstructured{}
+	errs := []error{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dirCRDs, dirErrs := RenderCRDs(filepath.Join(crdDir, entry.Name()), mch)
+		crds[entry.Name()] = dirCRDs
+		errs = append(errs, dirErrs...)
+	}
+	return crds, errs
+}
+
 // RenderCharts renders all Helm charts in the specified directory.
 // olmVersion: OLM version detected at runtime ("v0", "v1", or "" for no OLM).
 // Passed to chart templates as .Values.global.olmVersion for conditional rendering.
@@ -272,7 +243,7 @@ func RenderCharts(chartDir string, mch *v1.MultiClusterHub, images map[string]st
 
 	for _, chart := range charts {
 		chartPath := filepath.Join(chartDir, chart.Name())
-		chartTemplates, errs := renderTemplates(chartPath, mch, images, tpl, isSTSEnabled, olmVersion)
+		chartTemplates, errs := renderTemplates(chartPath, mch, mch.Namespace, images, tpl, isSTSEnabled, olmVersion)
 		if len(errs) > 0 {
 			for _, err := range errs {
 				log.Info(err.Error())
@@ -289,6 +260,14 @@ func RenderCharts(chartDir string, mch *v1.MultiClusterHub, images map[string]st
 // Passed to chart templates as .Values.global.olmVersion for conditional rendering.
 func RenderChart(chartPath string, mch *v1.MultiClusterHub, images map[string]string, templates map[string]string,
 	isSTSEnabled bool, olmVersion string) ([]*unstructured.Unstructured, []error) {
+	return RenderComponentChart(chartPath, mch, mch.Namespace, images, templates, isSTSEnabled, olmVersion)
+}
+
+// RenderComponentChart renders a single Helm chart from the specified path into the given namespace.
+// The chart is rendered with the namespace as .Values.global.namespace, and namespaced resources without a namespace
+// are placed in it. The resources are still labelled with the MultiClusterHub that installs them.
+func RenderComponentChart(chartPath string, mch *v1.MultiClusterHub, namespace string, images map[string]string,
+	templates map[string]string, isSTSEnabled bool, olmVersion string) ([]*unstructured.Unstructured, []error) {
 
 	if val, ok := os.LookupEnv("DIRECTORY_OVERRIDE"); ok {
 		chartPath = path.Join(val, chartPath)
@@ -298,7 +277,7 @@ func RenderChart(chartPath string, mch *v1.MultiClusterHub, images map[string]st
 
 	}
 
-	chartTemplates, errs := renderTemplates(chartPath, mch, images, templates, isSTSEnabled, olmVersion)
+	chartTemplates, errs := renderTemplates(chartPath, mch, namespace, images, templates, isSTSEnabled, olmVersion)
 	if len(errs) > 0 {
 		for _, err := range errs {
 			log.Info(err.Error())
@@ -310,8 +289,8 @@ func RenderChart(chartPath string, mch *v1.MultiClusterHub, images map[string]st
 
 }
 
-func renderTemplates(chartPath string, mch *v1.MultiClusterHub, images map[string]string, tpl map[string]string,
-	isSTSEnabled bool, olmVersion string) ([]*unstructured.Unstructured, []error) {
+func renderTemplates(chartPath string, mch *v1.MultiClusterHub, namespace string, images map[string]string,
+	tpl map[string]string, isSTSEnabled bool, olmVersion string) ([]*unstructured.Unstructured, []error) {
 
 	var templates []*unstructured.Unstructured
 	errs := []error{}
@@ -324,6 +303,7 @@ func renderTemplates(chartPath string, mch *v1.MultiClusterHub, images map[strin
 
 	valuesYaml := &Values{}
 	injectValuesOverrides(valuesYaml, mch, images, tpl, isSTSEnabled, olmVersion)
+	valuesYaml.Global.Namespace = namespace
 	helmEngine := engine.Engine{
 		Strict:   true,
 		LintMode: false,
@@ -368,9 +348,10 @@ func renderTemplates(chartPath string, mch *v1.MultiClusterHub, images map[strin
 
 			// Add namespace to namespaced resources
 			switch unstructured.GetKind() {
-			case "Deployment", "ServiceAccount", "Role", "RoleBinding", "Service", "ConfigMap", "Ingress", "Channel", "Subscription", "NetworkPolicy":
+			case "Deployment", "StatefulSet", "DaemonSet", "Job", "ServiceAccount", "Role", "RoleBinding", "Service", "ConfigMap",
+				"Ingress", "Channel", "Subscription", "NetworkPolicy":
 				if unstructured.GetNamespace() == "" {
-					unstructured.SetNamespace(mch.Namespace)
+					unstructured.SetNamespace(namespace)
 				}
 			}
 			utils.AddInstallerLabel(unstructured, mch.Name, mch.Namespace)
@@ -407,11 +388,12 @@ func injectValuesOverrides(values *Values, mch *v1.MultiClusterHub, images map[s
 		values.Global.OLMVersion = olmVersion
 	}
 
-	// OADP doesn't yet publish OLM v1-compatible bundles (AllNamespaces install mode
-	// not declared, and package missing from some OCP 5.0 EC catalog indexes).
-	// Force v0 Subscription for OADP until OADP team ships v1-ready bundles.
-	// TODO: set to values.Global.OLMVersion once OADP bundles are v1-ready
+	// Not every OADP bundle supports OLM v1 (AllNamespaces install mode not declared, and package missing from
+	// some OCP 5.0 EC catalog indexes), so OADP uses a v0 Subscription unless v1 is requested on an OLM v1 cluster.
 	values.Global.OADPOLMVersion = "v0"
+	if values.Global.OLMVersion == "v1" {
+		values.Global.OADPOLMVersion = utils.GetOADPOLMVersion(mch)
+	}
 
 	values.HubConfig.ClusterSTSEnabled = isSTSEnabled
 
@@ -421,8 +403,6 @@ func injectValuesOverrides(values *Values, mch *v1.MultiClusterHub, images map[s
 
 	values.HubConfig.Tolerations = convertTolerations(utils.GetTolerations(mch))
 
-	values.HubConfig.ProbeConfig = parseProbeConfigFromAnnotations(mch)
-
 	values.HubConfig.OCPVersion = os.Getenv("ACM_HUB_OCP_VERSION")
 
 	values.HubConfig.HubVersion = version.Version
@@ -462,19 +442,12 @@ func injectValuesOverrides(values *Values, mch *v1.MultiClusterHub, images map[s
 	}
 
 	// Apply OADP ClusterExtension overrides (OLM v1) if annotation present and effective OADP OLM version is v1
+	values.Global.OADPVersion = defaultOADPVersion
 	if values.Global.OADPOLMVersion == "v1" {
 		if overrides := parseOADPClusterExtensionAnnotation(mch); overrides != nil {
-			// Override catalog settings for OLM v1
-			if len(overrides.Channels) > 0 {
-				// Use first channel from override
-				values.Global.Channel = overrides.Channels[0]
-			}
-			if overrides.Version != "" {
-				values.Global.StartingCSV = overrides.Version
-			}
-			if overrides.Source != "" {
-				values.Global.Source = overrides.Source
-			}
+			values.Global.OADPChannels = overrides.Channels
+			values.Global.OADPVersion = valueOrDefault(overrides.Version, defaultOADPVersion)
+			values.Global.OADPCatalog = overrides.Source
 		}
 	}
 
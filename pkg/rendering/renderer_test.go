@@ -306,6 +306,35 @@ func TestRenderCRDs(t *testing.T) {
 
 }
 
+func TestRenderComponentCRDs(t *testing.T) {
+	testMCH := &v1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Name: "testmch", Namespace: "default"}}
+
+	got, errs := RenderComponentCRDs("../templates/crds", testMCH)
+	if len(errs) > 0 {
+		t.Fatalf("RenderComponentCRDs() errs = %v", errs)
+	}
+	all, _ := RenderCRDs("../templates/crds", testMCH)
+	count := 0
+	for _, crds := range got {
+		count += len(crds)
+	}
+	if count != len(all) {
+		t.Errorf("RenderComponentCRDs() rendered %d CRDs, want %d", count, len(all))
+	}
+
+	names := []string{}
+	for _, crd := range got["grc"] {
+		names = append(names, crd.GetName())
+	}
+	if !utils.Contains(names, "policies.policy.open-cluster-management.io") {
+		t.Errorf("RenderComponentCRDs() grc CRDs = %v, want the policies CRD", names)
+	}
+
+	if _, errs := RenderComponentCRDs("pkg/doesnotexist", testMCH); len(errs) == 0 {
+		t.Errorf("RenderComponentCRDs() should fail on a missing directory")
+	}
+}
+
 func TestOADPAnnotation(t *testing.T) {
 	oadp := `{"channel": "stable-1.0", "installPlanApproval": "Manual", "name": "redhat-oadp-operator2", "source": "redhat-operators2", "sourceNamespace": "openshift-marketplace2", "startingCSV": "test-csv"}`
 	mch := &v1.MultiClusterHub{
@@ -504,7 +533,7 @@ func TestRenderChartOLMv1(t *testing.T) {
 	templateOverrides := map[string]string{}
 
 	// Render cluster-backup chart with OLM v1 — OADP should still use v0 Subscription
-	// because OADP bundles don't yet support OLM v1
+	// unless OLM v1 is requested for OADP
 	chartPath := utils.ClusterBackupChartLocation
 	templates, errs := RenderChart(chartPath, testMCH, testImages, templateOverrides, false, "v1")
 	if len(errs) > 0 {
@@ -514,7 +543,7 @@ func TestRenderChartOLMv1(t *testing.T) {
 		t.Fatalf("failed to render cluster-backup with OLM v1")
 	}
 
-	// OADP forced to v0: expect Subscription and OperatorGroup, not ClusterExtension
+	// OADP defaults to v0: expect Subscription and OperatorGroup, not ClusterExtension
 	foundClusterExtension := false
 	foundSubscription := false
 	foundOperatorGroup := false
@@ -534,165 +563,92 @@ func TestRenderChartOLMv1(t *testing.T) {
 		}
 	}
 
-	// v0 resources should be present (OADP forced to v0)
+	// v0 resources should be present (OADP defaults to v0)
 	if !foundSubscription {
-		t.Error("Expected Subscription for OADP (forced v0), not found")
+		t.Error("Expected Subscription for OADP (default v0), not found")
 	}
 	if !foundOperatorGroup {
-		t.Error("Expected OperatorGroup for OADP (forced v0), not found")
+		t.Error("Expected OperatorGroup for OADP (default v0), not found")
 	}
 
 	// v1 resources should be absent
 	if foundClusterExtension {
 		t.Error("Found ClusterExtension in render, OADP should use v0 Subscription")
 	}
-}
-
-func TestParseProbeConfigFromAnnotations(t *testing.T) {
-	t.Run("No annotations returns nil", func(t *testing.T) {
-		mch := &v1.MultiClusterHub{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-mch",
-				Namespace: "default",
-			},
-		}
-
-		result := parseProbeConfigFromAnnotations(mch)
-		if result != nil {
-			t.Error("Expected nil when no annotations present")
-		}
-	})
-
-	t.Run("All three annotations are parsed correctly", func(t *testing.T) {
-		mch := &v1.MultiClusterHub{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-mch",
-				Namespace: "default",
-				Annotations: map[string]string{
-					utils.AnnotationProbeTimeoutSeconds:   "10",
-					utils.AnnotationProbeFailureThreshold: "5",
-					utils.AnnotationProbeSuccessThreshold: "2",
-				},
-			},
-		}
-
-		result := parseProbeConfigFromAnnotations(mch)
-		if result == nil {
-			t.Fatal("Expected ProbeConfig, got nil")
-		}
-
-		if result.TimeoutSeconds == nil || *result.TimeoutSeconds != 10 {
-			t.Errorf("Expected TimeoutSeconds=10, got %v", result.TimeoutSeconds)
-		}
-		if result.FailureThreshold == nil || *result.FailureThreshold != 5 {
-			t.Errorf("Expected FailureThreshold=5, got %v", result.FailureThreshold)
-		}
-		if result.SuccessThreshold == nil || *result.SuccessThreshold != 2 {
-			t.Errorf("Expected SuccessThreshold=2, got %v", result.SuccessThreshold)
-		}
-	})
-
-	t.Run("Partial annotations are parsed correctly", func(t *testing.T) {
-		mch := &v1.MultiClusterHub{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-mch",
-				Namespace: "default",
-				Annotations: map[string]string{
-					utils.AnnotationProbeTimeoutSeconds: "15",
-				},
-			},
-		}
-
-		result := parseProbeConfigFromAnnotations(mch)
-		if result == nil {
-			t.Fatal("Expected ProbeConfig, got nil")
-		}
 
-		if result.TimeoutSeconds == nil || *result.TimeoutSeconds != 15 {
-			t.Errorf("Expected TimeoutSeconds=15, got %v", result.TimeoutSeconds)
-		}
-		if result.FailureThreshold != nil {
-			t.Errorf("Expected FailureThreshold=nil, got %v", *result.FailureThreshold)
-		}
-		if result.SuccessThreshold != nil {
-			t.Errorf("Expected SuccessThreshold=nil, got %v", *result.SuccessThreshold)
-		}
-	})
-
-	t.Run("Invalid values are ignored", func(t *testing.T) {
-		mch := &v1.MultiClusterHub{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-mch",
-				Namespace: "default",
-				Annotations: map[string]string{
-					utils.AnnotationProbeTimeoutSeconds:   "not-a-number",
-					utils.AnnotationProbeFailureThreshold: "10",
-				},
-			},
-		}
+	// Requesting OLM v1 for OADP installs it with a ClusterExtension built from the overrides
+	testMCH.Annotations = map[string]string{
+		utils.AnnotationOADPOLMVersion:           "v1",
+		utils.AnnotationOADPClusterExtensionSpec: `{"channels": ["stable-1.5"], "source": "custom-catalog"}`,
+	}
+	templates, errs = RenderChart(chartPath, testMCH, testImages, templateOverrides, false, "v1")
+	if len(errs) > 0 {
+		t.Fatalf("failed to render cluster-backup with OADP OLM v1: %v", errs)
+	}
 
-		result := parseProbeConfigFromAnnotations(mch)
-		if result == nil {
-			t.Fatal("Expected ProbeConfig, got nil")
+	var clusterExtension *unstructured.Unstructured
+	for _, template := range templates {
+		switch template.GetKind() {
+		case "ClusterExtension":
+			clusterExtension = template
+		case "Subscription", "OperatorGroup":
+			t.Errorf("Found %s in render, OADP should use a ClusterExtension", template.GetKind())
 		}
+	}
+	if clusterExtension == nil {
+		t.Fatal("Expected ClusterExtension for OADP, not found")
+	}
 
-		if result.TimeoutSeconds != nil {
-			t.Errorf("Expected TimeoutSeconds=nil (invalid value), got %v", *result.TimeoutSeconds)
-		}
-		if result.FailureThreshold == nil || *result.FailureThreshold != 10 {
-			t.Errorf("Expected FailureThreshold=10, got %v", result.FailureThreshold)
-		}
-	})
-
-	t.Run("Zero and negative values are ignored", func(t *testing.T) {
-		mch := &v1.MultiClusterHub{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-mch",
-				Namespace: "default",
-				Annotations: map[string]string{
-					utils.AnnotationProbeTimeoutSeconds:   "0",
-					utils.AnnotationProbeFailureThreshold: "-5",
-					utils.AnnotationProbeSuccessThreshold: "3",
-				},
-			},
-		}
+	catalog, _, _ := unstructured.NestedMap(clusterExtension.Object, "spec", "source", "catalog")
+	if catalog["version"] != ">=1.4.0" {
+		t.Errorf("ClusterExtension version = %v, want the default >=1.4.0", catalog["version"])
+	}
+	if channels, _, _ := unstructured.NestedStringSlice(catalog, "channels"); !reflect.DeepEqual(channels,
+		[]string{"stable-1.5"}) {
+		t.Errorf("ClusterExtension channels = %v, want [stable-1.5]", channels)
+	}
+	if name, _, _ := unstructured.NestedString(catalog, "selector", "matchLabels",
+		"olm.operatorframework.io/metadata.name"); name != "custom-catalog" {
+		t.Errorf("ClusterExtension catalog selector = %q, want custom-catalog", name)
+	}
+}
 
-		result := parseProbeConfigFromAnnotations(mch)
-		if result == nil {
-			t.Fatal("Expected ProbeConfig, got nil")
-		}
+func TestRenderComponentChart(t *testing.T) {
+	os.Setenv("DIRECTORY_OVERRIDE", "../templates")
+	os.Setenv("ACM_HUB_OCP_VERSION", "4.16.0")
+	defer os.Unsetenv("DIRECTORY_OVERRIDE")
+	defer os.Unsetenv("ACM_HUB_OCP_VERSION")
 
-		if result.TimeoutSeconds != nil {
-			t.Errorf("Expected TimeoutSeconds=nil (zero value), got %v", *result.TimeoutSeconds)
-		}
-		if result.FailureThreshold != nil {
-			t.Errorf("Expected FailureThreshold=nil (negative value), got %v", *result.FailureThreshold)
-		}
-		if result.SuccessThreshold == nil || *result.SuccessThreshold != 3 {
-			t.Errorf("Expected SuccessThreshold=3, got %v", result.SuccessThreshold)
-		}
-	})
-
-	t.Run("Other annotations don't interfere", func(t *testing.T) {
-		mch := &v1.MultiClusterHub{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-mch",
-				Namespace: "default",
-				Annotations: map[string]string{
-					"installer.open-cluster-management.io/pause": "true",
-					utils.AnnotationProbeTimeoutSeconds:          "20",
-					"some-other-annotation":                      "value",
-				},
-			},
-		}
+	testMCH := &v1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "testmch", Namespace: "open-cluster-management"},
+	}
+	testImages := map[string]string{}
+	for _, v := range utils.GetTestImages() {
+		testImages[v] = "quay.io/test/test:Test"
+	}
 
-		result := parseProbeConfigFromAnnotations(mch)
-		if result == nil {
-			t.Fatal("Expected ProbeConfig, got nil")
-		}
+	templates, errs := RenderComponentChart(utils.SearchV2ChartLocation, testMCH, "search", testImages,
+		map[string]string{}, false, "v0")
+	if len(errs) > 0 {
+		t.Fatalf("failed to render the search chart: %v", errs)
+	}
 
-		if result.TimeoutSeconds == nil || *result.TimeoutSeconds != 20 {
-			t.Errorf("Expected TimeoutSeconds=20, got %v", result.TimeoutSeconds)
+	for _, template := range templates {
+		if ns := template.GetNamespace(); ns != "" && ns != "search" {
+			t.Errorf("%s %s rendered into namespace %s, want search", template.GetKind(), template.GetName(), ns)
+		}
+		// The resources are still installed by the hub
+		if labels := template.GetLabels(); labels["installer.namespace"] != testMCH.Namespace {
+			t.Errorf("%s %s installer namespace = %q, want %s", template.GetKind(), template.GetName(),
+				labels["installer.namespace"], testMCH.Namespace)
+		}
+		if template.GetKind() == "ClusterRoleBinding" {
+			subjects, _, _ := unstructured.NestedSlice(template.Object, "subjects")
+			for _, s := range subjects {
+				if ns := s.(map[string]interface{})["namespace"]; ns != "search" {
+					t.Errorf("ClusterRoleBinding %s subject namespace = %v, want search", template.GetName(), ns)
+				}
+			}
 		}
-	})
+	}
 }
@@ -33,7 +33,10 @@ type Global struct {
 	StorageClassName     string               `json:"storageClassName" structs:"storageClassName"`
 	StartingCSV          string               `json:"startingCSV" structs:"startingCSV"`
 	OLMVersion           string               `json:"olmVersion" structs:"olmVersion"`         // "v0" or "v1" - detected at runtime by main.go detectOLMVersion
-	OADPOLMVersion       string               `json:"oadpOlmVersion" structs:"oadpOlmVersion"` // forced to v0 until OADP ships OLM v1-ready bundles
+	OADPOLMVersion       string               `json:"oadpOlmVersion" structs:"oadpOlmVersion"` // v0 unless OLM v1 is requested by annotation
+	OADPChannels         []string             `json:"oadpChannels" structs:"oadpChannels"`
+	OADPVersion          string               `json:"oadpVersion" structs:"oadpVersion"`
+	OADPCatalog          string               `json:"oadpCatalog" structs:"oadpCatalog"`
 	NetworkPolicies      NetworkPoliciesValue `json:"networkPolicies" structs:"networkPolicies"`
 }
 
@@ -54,6 +57,8 @@ type HubConfig struct {
 	SubscriptionPause string            `json:"subscriptionPause" structs:"subscriptionPause"`
 }
 
+// ProbeConfig is read by the probe settings the chart generator emits. It is left unset because spec.probes is applied
+// to the rendered Deployments instead.
 type ProbeConfig struct {
 	TimeoutSeconds   *int32 `json:"timeoutSeconds,omitempty"`
 	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
@@ -0,0 +1,150 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package servingcert serves the certificate issued by the OpenShift service CA for a Service.
+//
+// The service CA writes the certificate to the Secret named by the serving-cert-secret-name annotation of the Service.
+// The operator creates the Service itself, so the Secret does not exist when the operator starts: a Provider serves a
+// self-signed certificate until a SecretLoader reads the Secret, and picks up the certificate again whenever the service
+// CA rotates it.
+package servingcert
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ServingCertSecretAnnotation asks the service CA to issue a serving certificate for a Service into a Secret
+	ServingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+
+	// ReloadInterval is how often a SecretLoader reads the Secret of the serving certificate
+	ReloadInterval = time.Minute
+
+	// ServiceCAFile is where the Prometheus of the OpenShift monitoring stack mounts the service CA bundle
+	ServiceCAFile = "/etc/prometheus/configmaps/serving-certs-ca-bundle/service-ca.crt"
+)
+
+// Provider provides the certificate of a server. It is safe for concurrent use.
+type Provider struct {
+	mu              sync.RWMutex
+	cert            *tls.Certificate
+	fallback        *tls.Certificate
+	resourceVersion string
+}
+
+// NewProvider returns a provider serving a self-signed certificate until a serving certificate is loaded
+func NewProvider() (*Provider, error) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", []net.IP{{127, 0, 0, 1}}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate self-signed certificate: %w", err)
+	}
+	fallback, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse self-signed certificate: %w", err)
+	}
+	return &Provider{fallback: &fallback}, nil
+}
+
+// Load serves the certificate of a kubernetes.io/tls Secret. It returns true if the certificate changed.
+func (p *Provider) Load(secret *corev1.Secret) (bool, error) {
+	p.mu.RLock()
+	loaded := p.cert != nil && p.resourceVersion == secret.GetResourceVersion()
+	p.mu.RUnlock()
+	if loaded {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the certificate of secret %s/%s: %w", secret.GetNamespace(),
+			secret.GetName(), err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cert = &cert
+	p.resourceVersion = secret.GetResourceVersion()
+	return true, nil
+}
+
+// Loaded returns true once a serving certificate replaced the self-signed certificate
+func (p *Provider) Loaded() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cert != nil
+}
+
+// GetCertificate returns the serving certificate, or the self-signed certificate until one is loaded
+func (p *Provider) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.cert != nil {
+		return p.cert, nil
+	}
+	return p.fallback, nil
+}
+
+// TLSOpts sets the provider as the certificate source of a server
+func (p *Provider) TLSOpts(config *tls.Config) {
+	config.GetCertificate = p.GetCertificate
+}
+
+/*
+SecretLoader loads the certificate of a Secret into a Provider and reloads it when the Secret changes. Every replica of
+the operator serves its own metrics, so the loader runs on every replica rather than only on the leader.
+*/
+type SecretLoader struct {
+	Provider *Provider
+	Reader   client.Reader
+	Secret   types.NamespacedName
+	Log      logr.Logger
+}
+
+// Start reads the Secret every ReloadInterval until the context is cancelled
+func (l *SecretLoader) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, l.Load, ReloadInterval)
+	return nil
+}
+
+// NeedLeaderElection returns false so the loader runs on every replica
+func (l *SecretLoader) NeedLeaderElection() bool {
+	return false
+}
+
+// Load reads the Secret and serves its certificate. The current certificate is kept when the Secret is missing or
+// invalid.
+func (l *SecretLoader) Load(ctx context.Context) {
+	secret := &corev1.Secret{}
+	err := l.Reader.Get(ctx, l.Secret, secret)
+	if errors.IsNotFound(err) {
+		if !l.Provider.Loaded() {
+			l.Log.V(1).Info("Waiting for the service CA to issue the serving certificate", "Secret", l.Secret)
+		}
+		return
+	} else if err != nil {
+		l.Log.Error(err, "error getting the serving certificate", "Secret", l.Secret)
+		return
+	}
+
+	changed, err := l.Provider.Load(secret)
+	if err != nil {
+		l.Log.Error(err, "error loading the serving certificate", "Secret", l.Secret)
+		return
+	}
+	if changed {
+		l.Log.Info("Loaded the serving certificate", "Secret", l.Secret)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package servingcert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func servingSecret(t *testing.T, host, resourceVersion string) *corev1.Secret {
+	t.Helper()
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey(host, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "multiclusterhub-operator-metrics-tls",
+			Namespace:       "open-cluster-management",
+			ResourceVersion: resourceVersion,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+	}
+}
+
+func servedHost(t *testing.T, p *Provider) string {
+	t.Helper()
+	cert, err := p.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Self-signed certificates are issued to host@timestamp
+	return strings.Split(leaf.Subject.CommonName, "@")[0]
+}
+
+func Test_Provider(t *testing.T) {
+	p, err := NewProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &tls.Config{}
+	p.TLSOpts(config)
+	if config.GetCertificate == nil {
+		t.Fatal("TLSOpts() did not set GetCertificate")
+	}
+
+	// The self-signed certificate is served until the service CA issues one
+	if p.Loaded() || servedHost(t, p) != "localhost" {
+		t.Error("expected the self-signed certificate before a certificate is loaded")
+	}
+
+	const host = "multiclusterhub-operator-metrics.open-cluster-management.svc"
+	if changed, err := p.Load(servingSecret(t, host, "1")); err != nil || !changed {
+		t.Fatalf("Load() = %v, %v, want the certificate loaded", changed, err)
+	}
+	if !p.Loaded() || servedHost(t, p) != host {
+		t.Errorf("served %s, want the certificate of %s", servedHost(t, p), host)
+	}
+
+	// The same revision of the secret is not parsed again
+	if changed, err := p.Load(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"}}); err != nil ||
+		changed {
+		t.Errorf("Load() = %v, %v for the loaded revision, want it skipped", changed, err)
+	}
+
+	// A secret without a valid key pair keeps the current certificate
+	if _, err := p.Load(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "2"}}); err == nil {
+		t.Error("Load() of an empty secret succeeded, want an error")
+	}
+	if servedHost(t, p) != host {
+		t.Errorf("served %s after a failed load, want the certificate of %s", servedHost(t, p), host)
+	}
+}
+
+func Test_SecretLoader(t *testing.T) {
+	p, err := NewProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	l := &SecretLoader{
+		Provider: p,
+		Reader:   c,
+		Secret:   types.NamespacedName{Name: "multiclusterhub-operator-metrics-tls", Namespace: "open-cluster-management"},
+		Log:      logr.Discard(),
+	}
+	if l.NeedLeaderElection() {
+		t.Error("NeedLeaderElection() = true, want the loader to run on every replica")
+	}
+
+	// The self-signed certificate is served until the service CA issues one
+	l.Load(context.TODO())
+	if p.Loaded() {
+		t.Error("expected the self-signed certificate before the secret exists")
+	}
+
+	const host = "multiclusterhub-operator-metrics.open-cluster-management.svc"
+	if err := c.Create(context.TODO(), servingSecret(t, host, "")); err != nil {
+		t.Fatal(err)
+	}
+	l.Load(context.TODO())
+	if !p.Loaded() || servedHost(t, p) != host {
+		t.Errorf("served %s, want the certificate of %s", servedHost(t, p), host)
+	}
+}
@@ -5,16 +5,23 @@
 //
 // This is a build-time tool that processes CRD files to generate
 // ClusterRole and ClusterRoleBinding manifests for the operator.
+//
+// With -audit, it instead reports for each chart the permissions it needs
+// that the operator ClusterRole does not grant and the wildcard rules of its
+// ClusterRoles, followed by the granted permissions no chart needs.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"text/template"
 
 	operatorv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/rbacaudit"
 	renderer "github.com/stolostron/multiclusterhub-operator/pkg/rendering"
 	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -23,13 +30,17 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
 )
 
 const (
 	chartsDir = "pkg/templates/charts/toggle"
 	crdsDir   = "pkg/templates/crds"
+	roleFile  = "config/rbac/role.yaml"
 )
 
+var audit = flag.Bool("audit", false, "report the permissions of the charts against "+roleFile)
+
 var resources = []string{
 	"APIService",
 	"ClusterManagementAddOn",
@@ -73,6 +84,8 @@ var resources = []string{
 }
 
 func main() {
+	flag.Parse()
+
 	// os.Setenv("DIRECTORY_OVERRIDE", "../../.git")
 	// defer os.Unsetenv("DIRECTORY_OVERRIDE")
 	os.Setenv("ACM_HUB_OCP_VERSION", "4.12.0")
@@ -88,6 +101,16 @@ func main() {
 	testImages := map[string]string{}
 	for _, v := range utils.GetTestImages() {
 		testImages[v] = "quay.io/test/test:Test"
+	}
+
+	if *audit {
+		if !auditCharts(testMCH, testImages) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	for _, v := range utils.GetTestImages() {
 		fmt.Printf("%v = %v\n", v, testImages[v])
 	}
 
@@ -188,6 +211,62 @@ func main() {
 	}
 }
 
+// auditCharts prints the RBAC findings of each chart against the operator ClusterRole. It returns false when a
+// chart needs a permission the ClusterRole does not grant.
+func auditCharts(mch *operatorv1.MultiClusterHub, images map[string]string) bool {
+	data, err := os.ReadFile(roleFile)
+	if err != nil {
+		panic(err)
+	}
+	role := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(data, role); err != nil {
+		panic(err)
+	}
+
+	charts, err := os.ReadDir(chartsDir)
+	if err != nil {
+		panic(err)
+	}
+
+	ok := true
+	all := []rbacaudit.Requirements{}
+	for _, chart := range charts {
+		var templates []*unstructured.Unstructured
+		for _, olmVersion := range []string{"v0", "v1"} {
+			rendered, errs := renderer.RenderChart(filepath.Join(chartsDir, chart.Name()), mch, images,
+				map[string]string{}, false, olmVersion)
+			if len(errs) > 0 {
+				panic(errs)
+			}
+			templates = append(templates, rendered...)
+		}
+
+		req, err := rbacaudit.Required(templates)
+		if err != nil {
+			panic(err)
+		}
+		all = append(all, req)
+
+		missing := req.Missing(role.Rules)
+		fmt.Printf("%s:\n", chart.Name())
+		for _, p := range missing {
+			fmt.Printf("  missing: %s\n", p)
+		}
+		for _, rule := range req.WildcardRules {
+			fmt.Printf("  wildcard: %s\n", rule)
+		}
+		if len(missing) > 0 {
+			ok = false
+		}
+	}
+
+	fmt.Printf("granted to %s but not needed by any chart:\n", role.Name)
+	for _, resource := range rbacaudit.Summarize(rbacaudit.Excess(role.Rules, all...)) {
+		fmt.Printf("  %s\n", resource)
+	}
+	return ok
+}
+
 func extractFromRules(rules []rbacv1.PolicyRule) []string {
 	lines := []string{}
 	for _, rule := range rules {
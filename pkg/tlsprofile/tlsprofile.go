@@ -0,0 +1,107 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package tlsprofile keeps the TLS settings of the operator in line with the cluster TLS security profile.
+//
+// The profile is read from the APIServer resource at startup and refreshed by the MultiClusterHub controller when the
+// resource changes. Servers configured with ServerTLSOpts read the current profile on every handshake, so a new profile
+// applies to new connections without restarting the operator. Clients build their configuration with ClientConfig.
+package tlsprofile
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"slices"
+	"sync"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stolostron/multiclusterhub-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Profile is the TLS security profile the operator serves and connects with. It is safe for concurrent use.
+type Profile struct {
+	mu          sync.RWMutex
+	profileType configv1.TLSProfileType
+	spec        configv1.TLSProfileSpec
+}
+
+// New returns a profile of the given type and spec
+func New(profileType configv1.TLSProfileType, spec configv1.TLSProfileSpec) *Profile {
+	p := &Profile{}
+	p.Update(profileType, spec)
+	return p
+}
+
+// Default returns the Intermediate profile, used when the cluster profile cannot be read
+func Default() *Profile {
+	return New(configv1.TLSProfileIntermediateType, *configv1.TLSProfiles[configv1.TLSProfileIntermediateType])
+}
+
+// Load returns the profile set on the APIServer resource
+func Load(ctx context.Context, cl client.Client) (*Profile, error) {
+	apiServer := &configv1.APIServer{}
+	if err := cl.Get(ctx, types.NamespacedName{Name: "cluster"}, apiServer); err != nil {
+		return nil, fmt.Errorf("failed to get APIServer resource: %w", err)
+	}
+	profileType, spec := utils.TLSProfileFromAPIServer(apiServer)
+	return New(profileType, *spec), nil
+}
+
+// Get returns the type and a copy of the spec of the profile
+func (p *Profile) Get() (configv1.TLSProfileType, configv1.TLSProfileSpec) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	spec := p.spec
+	spec.Ciphers = slices.Clone(p.spec.Ciphers)
+	return p.profileType, spec
+}
+
+// Update replaces the profile and returns true if it changed
+func (p *Profile) Update(profileType configv1.TLSProfileType, spec configv1.TLSProfileSpec) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.profileType == profileType && p.spec.MinTLSVersion == spec.MinTLSVersion &&
+		slices.Equal(p.spec.Ciphers, spec.Ciphers) {
+		return false
+	}
+	p.profileType = profileType
+	p.spec = configv1.TLSProfileSpec{MinTLSVersion: spec.MinTLSVersion, Ciphers: slices.Clone(spec.Ciphers)}
+	return true
+}
+
+// apply sets the minimum version and, below TLS 1.3, the cipher suites of the profile on the config
+func (p *Profile) apply(config *tls.Config) {
+	_, spec := p.Get()
+	config.MinVersion = utils.ConvertTLSVersion(spec.MinTLSVersion)
+	config.CipherSuites = nil
+	// TLS 1.3 cipher suites are managed automatically by Go
+	if cipherSuites := utils.ConvertCipherSuites(spec.Ciphers); config.MinVersion < tls.VersionTLS13 &&
+		len(cipherSuites) > 0 {
+		config.CipherSuites = cipherSuites
+	}
+}
+
+/*
+ServerTLSOpts configures a server with the profile. It is meant for the TLSOpts of the webhook and metrics servers: the
+config is completed by the server after the options run, so it is cloned on each handshake and the current profile is
+applied to the clone.
+*/
+func (p *Profile) ServerTLSOpts(config *tls.Config) {
+	p.apply(config)
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := config.Clone()
+		c.GetConfigForClient = nil
+		p.apply(c)
+		return c, nil
+	}
+}
+
+// ClientConfig returns a client config with the current profile
+func (p *Profile) ClientConfig() *tls.Config {
+	config := &tls.Config{}
+	p.apply(config)
+	return config
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package tlsprofile
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func Test_Update(t *testing.T) {
+	p := Default()
+	intermediate := *configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+	if p.Update(configv1.TLSProfileIntermediateType, intermediate) {
+		t.Error("Update() = true, want false for the same profile")
+	}
+	if !p.Update(configv1.TLSProfileModernType, *configv1.TLSProfiles[configv1.TLSProfileModernType]) {
+		t.Error("Update() = false, want true for a new profile")
+	}
+
+	// The profile keeps its own copy of the ciphers
+	_, spec := p.Get()
+	spec.Ciphers[0] = "changed"
+	if _, got := p.Get(); got.Ciphers[0] == "changed" {
+		t.Error("Get() returned the ciphers of the profile instead of a copy")
+	}
+}
+
+func Test_ServerTLSOpts(t *testing.T) {
+	p := Default()
+	// The test server clones the config it is given, so the options are run on the config it listens with
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	p.ServerTLSOpts(server.TLS)
+
+	get := func(maxVersion uint16) error {
+		// #nosec G402 -- the test server uses a self-signed certificate
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			MaxVersion:         maxVersion,
+		}}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(tls.VersionTLS12); err != nil {
+		t.Fatalf("TLS 1.2 request failed with the Intermediate profile: %v", err)
+	}
+
+	// The Modern profile applies to new connections without restarting the server
+	p.Update(configv1.TLSProfileModernType, *configv1.TLSProfiles[configv1.TLSProfileModernType])
+	if err := get(tls.VersionTLS12); err == nil {
+		t.Error("TLS 1.2 request succeeded with the Modern profile, want it rejected")
+	}
+	if err := get(tls.VersionTLS13); err != nil {
+		t.Errorf("TLS 1.3 request failed with the Modern profile: %v", err)
+	}
+
+	if config := p.ClientConfig(); config.MinVersion != tls.VersionTLS13 || config.CipherSuites != nil {
+		t.Errorf("ClientConfig() = %v %v, want TLS 1.3 without cipher suites", config.MinVersion, config.CipherSuites)
+	}
+}
@@ -5,6 +5,7 @@ package utils
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	operatorsv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
@@ -63,6 +64,13 @@ var (
 	*/
 	AnnotationMCEClusterExtensionSpec = "installer.open-cluster-management.io/mce-clusterextension-spec"
 
+	/*
+		AnnotationMCEOLMVersion is an annotation used in multiclusterhub to choose the OLM version ("v0" or "v1")
+		used to install the multiclusterengine. Changing it starts a migration of the existing installation, and
+		setting it back to the original version rolls the migration back.
+	*/
+	AnnotationMCEOLMVersion = "installer.open-cluster-management.io/mce-olm-version"
+
 	/*
 		AnnotationOADPSubscriptionSpec is an annotation used to override the OADP subscription used in cluster-backup (OLM v0).
 	*/
@@ -73,6 +81,12 @@ var (
 	*/
 	AnnotationOADPClusterExtensionSpec = "installer.open-cluster-management.io/oadp-clusterextension-spec"
 
+	/*
+		AnnotationOADPOLMVersion is an annotation used in multiclusterhub to choose the OLM version ("v0" or "v1")
+		used by cluster-backup to install OADP. OADP is installed with an OLM v0 Subscription unless it is set to "v1".
+	*/
+	AnnotationOADPOLMVersion = "installer.open-cluster-management.io/oadp-olm-version"
+
 	/*
 		AnnotationReleaseVersion is an annotation used to indicate the release version that should be applied to all
 		resources managed by the MCH operator.
@@ -85,6 +99,39 @@ var (
 	*/
 	AnnotationTemplateOverridesCM = "installer.open-cluster-management.io/template-override-configmap"
 
+	/*
+		AnnotationOverridesRollback is an annotation used in multiclusterhub to restore the previous content of the
+		image and template override ConfigMaps. Each new value is acted on once.
+	*/
+	AnnotationOverridesRollback = "installer.open-cluster-management.io/overrides-rollback"
+
+	/*
+		AnnotationRolloutRetry is an annotation used in multiclusterhub to resume a blocked rollout of the component
+		deployments. Each new value is acted on once.
+	*/
+	AnnotationRolloutRetry = "installer.open-cluster-management.io/rollout-retry"
+
+	/*
+		AnnotationRemoveDisabledCRDs is an annotation used in multiclusterhub to remove the CRDs of components that
+		are disabled for good. The value is a comma separated list of components; the CRDs of the listed components
+		that are disabled are removed once no custom resources remain.
+	*/
+	AnnotationRemoveDisabledCRDs = "installer.open-cluster-management.io/remove-disabled-crds"
+
+	/*
+		AnnotationStatusTracking is an annotation used in chart templates to opt a rendered workload in ("true") or
+		out ("false") of the hub status. Deployments, StatefulSets, DaemonSets and Jobs are tracked by default, except
+		Helm hooks.
+	*/
+	AnnotationStatusTracking = "installer.open-cluster-management.io/status-tracking"
+
+	/*
+		AnnotationStatusWorkloads is an annotation used in chart templates to track workloads that are not rendered
+		but created at runtime from the annotated resource, such as the operands of an operator. The value is a comma
+		separated list of Kind/name or Kind/namespace/name; the namespace defaults to the one of the resource.
+	*/
+	AnnotationStatusWorkloads = "installer.open-cluster-management.io/status-workloads"
+
 	/*
 		AnnotationDefaultStorageClass is an annotation used to set the default storage class name for multiclusterhub
 		operand resources to use.
@@ -117,19 +164,19 @@ var (
 
 	/*
 		AnnotationProbeTimeoutSeconds is an annotation used to configure probe timeout in seconds for exec probes
-		in components deployed by multiclusterhub.
+		in components deployed by multiclusterhub. Deprecated: migrated into spec.probes.components.
 	*/
 	AnnotationProbeTimeoutSeconds = "installer.open-cluster-management.io/probe-timeout-seconds"
 
 	/*
 		AnnotationProbeFailureThreshold is an annotation used to configure probe failure threshold for exec probes
-		in components deployed by multiclusterhub.
+		in components deployed by multiclusterhub. Deprecated: migrated into spec.probes.components.
 	*/
 	AnnotationProbeFailureThreshold = "installer.open-cluster-management.io/probe-failure-threshold"
 
 	/*
 		AnnotationProbeSuccessThreshold is an annotation used to configure probe success threshold for exec probes
-		in components deployed by multiclusterhub.
+		in components deployed by multiclusterhub. Deprecated: migrated into spec.probes.components.
 	*/
 	AnnotationProbeSuccessThreshold = "installer.open-cluster-management.io/probe-success-threshold"
 )
@@ -167,12 +214,124 @@ func MigrateDeprecatedAnnotations(instance *operatorsv1.MultiClusterHub) bool {
 		modified = true
 	}
 
+	if migrateProbeAnnotations(instance, annotations) {
+		modified = true
+	}
+
 	if modified {
 		instance.SetAnnotations(annotations)
 	}
 	return modified
 }
 
+/*
+probeAnnotationDeployments lists the deployments whose exec probes the probe annotations were templated into, by
+component. No other probe was tuned by the annotations.
+*/
+var probeAnnotationDeployments = []struct {
+	component   string
+	deployments []string
+}{
+	{component: operatorsv1.GRC, deployments: []string{"grc-policy-propagator"}},
+	{component: operatorsv1.Appsub, deployments: []string{
+		"multicluster-integrations",
+		"multicluster-operators-application",
+		"multicluster-operators-channel",
+		"multicluster-operators-hub-subscription",
+		"multicluster-operators-standalone-subscription",
+		"multicluster-operators-subscription-report",
+	}},
+}
+
+/*
+migrateProbeAnnotations moves the probe annotations into the spec.probes settings of the deployments they were
+templated into. The timeout and failure threshold apply to both liveness and readiness probes, while the success
+threshold only applies to readiness probes since liveness probes require a success threshold of 1. Values already set
+in spec.probes are kept, and values that are not positive integers are dropped, as they were ignored before.
+*/
+func migrateProbeAnnotations(instance *operatorsv1.MultiClusterHub, annotations map[string]string) bool {
+	modified := false
+	values := map[string]int32{}
+	for _, key := range []string{AnnotationProbeTimeoutSeconds, AnnotationProbeFailureThreshold,
+		AnnotationProbeSuccessThreshold} {
+		val, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		delete(annotations, key)
+		modified = true
+
+		n, err := strconv.ParseInt(val, 10, 32)
+		if err != nil || n <= 0 {
+			continue
+		}
+		values[key] = int32(n)
+	}
+	if len(values) == 0 {
+		return modified
+	}
+
+	if instance.Spec.Probes == nil {
+		instance.Spec.Probes = &operatorsv1.ProbesConfig{}
+	}
+	for _, c := range probeAnnotationDeployments {
+		for _, deployment := range c.deployments {
+			settings := deploymentProbeSettings(instance.Spec.Probes, c.component, deployment)
+			if settings.Readiness == nil {
+				settings.Readiness = &operatorsv1.ProbeTuning{}
+			}
+			if v, ok := values[AnnotationProbeTimeoutSeconds]; ok {
+				if settings.Liveness == nil {
+					settings.Liveness = &operatorsv1.ProbeTuning{}
+				}
+				setIfUnset(&settings.Liveness.TimeoutSeconds, v)
+				setIfUnset(&settings.Readiness.TimeoutSeconds, v)
+			}
+			if v, ok := values[AnnotationProbeFailureThreshold]; ok {
+				if settings.Liveness == nil {
+					settings.Liveness = &operatorsv1.ProbeTuning{}
+				}
+				setIfUnset(&settings.Liveness.FailureThreshold, v)
+				setIfUnset(&settings.Readiness.FailureThreshold, v)
+			}
+			if v, ok := values[AnnotationProbeSuccessThreshold]; ok {
+				setIfUnset(&settings.Readiness.SuccessThreshold, v)
+			}
+		}
+	}
+	return modified
+}
+
+// deploymentProbeSettings returns the probe settings of a deployment of a component, adding them when missing
+func deploymentProbeSettings(probes *operatorsv1.ProbesConfig, component,
+	deployment string) *operatorsv1.ProbeSettings {
+	c := -1
+	for i := range probes.Components {
+		if probes.Components[i].Name == component {
+			c = i
+		}
+	}
+	if c < 0 {
+		probes.Components = append(probes.Components, operatorsv1.ComponentProbeConfig{Name: component})
+		c = len(probes.Components) - 1
+	}
+
+	deployments := &probes.Components[c].Deployments
+	for i := range *deployments {
+		if (*deployments)[i].Name == deployment {
+			return &(*deployments)[i].ProbeSettings
+		}
+	}
+	*deployments = append(*deployments, operatorsv1.DeploymentProbeConfig{Name: deployment})
+	return &(*deployments)[len(*deployments)-1].ProbeSettings
+}
+
+func setIfUnset(field **int32, value int32) {
+	if *field == nil {
+		*field = &value
+	}
+}
+
 /*
 IsPaused checks if the MultiClusterHub instance is labeled as paused.
 It returns true if the instance is paused, otherwise false.
@@ -232,18 +391,33 @@ func AnnotationsMatch(old, new map[string]string) bool {
 	if !getAnnotationOrDefaultForMap(old, new, AnnotationTemplateOverridesCM, "") {
 		return false
 	}
+	if !getAnnotationOrDefaultForMap(old, new, AnnotationOverridesRollback, "") {
+		return false
+	}
+	if !getAnnotationOrDefaultForMap(old, new, AnnotationRolloutRetry, "") {
+		return false
+	}
+	if !getAnnotationOrDefaultForMap(old, new, AnnotationRemoveDisabledCRDs, "") {
+		return false
+	}
 	if !getAnnotationOrDefaultForMap(old, new, AnnotationMCESubscriptionSpec, "") {
 		return false
 	}
 	if !getAnnotationOrDefaultForMap(old, new, AnnotationMCEClusterExtensionSpec, "") {
 		return false
 	}
+	if !getAnnotationOrDefaultForMap(old, new, AnnotationMCEOLMVersion, "") {
+		return false
+	}
 	if !getAnnotationOrDefaultForMap(old, new, AnnotationOADPSubscriptionSpec, "") {
 		return false
 	}
 	if !getAnnotationOrDefaultForMap(old, new, AnnotationOADPClusterExtensionSpec, "") {
 		return false
 	}
+	if !getAnnotationOrDefaultForMap(old, new, AnnotationOADPOLMVersion, "") {
+		return false
+	}
 	if !getAnnotationOrDefaultForMap(old, new, AnnotationResourceAdoptionPolicy, "") {
 		return false
 	}
@@ -264,10 +438,15 @@ func AnnotationsMatch(old, new map[string]string) bool {
 		AnnotationImageOverridesCM:           true,
 		AnnotationKubeconfig:                 true,
 		AnnotationTemplateOverridesCM:        true,
+		AnnotationOverridesRollback:          true,
+		AnnotationRolloutRetry:               true,
+		AnnotationRemoveDisabledCRDs:         true,
 		AnnotationMCESubscriptionSpec:        true,
 		AnnotationMCEClusterExtensionSpec:    true,
+		AnnotationMCEOLMVersion:              true,
 		AnnotationOADPSubscriptionSpec:       true,
 		AnnotationOADPClusterExtensionSpec:   true,
+		AnnotationOADPOLMVersion:             true,
 		AnnotationResourceAdoptionPolicy:     true,
 		AnnotationProbeTimeoutSeconds:        true,
 		AnnotationProbeFailureThreshold:      true,
@@ -391,6 +570,14 @@ func GetMCEClusterExtensionAnnotationOverrides(instance *operatorsv1.MultiCluste
 	return getAnnotation(instance, AnnotationMCEClusterExtensionSpec)
 }
 
+/*
+GetMCEOLMVersion returns the OLM version requested for the multiclusterengine installation,
+or an empty string if not set.
+*/
+func GetMCEOLMVersion(instance *operatorsv1.MultiClusterHub) string {
+	return getAnnotation(instance, AnnotationMCEOLMVersion)
+}
+
 /*
 GetOADPAnnotationOverrides returns the OADP subscription spec annotation value (OLM v0),
 or an empty string if not set.
@@ -407,6 +594,17 @@ func GetOADPClusterExtensionAnnotationOverrides(instance *operatorsv1.MultiClust
 	return getAnnotation(instance, AnnotationOADPClusterExtensionSpec)
 }
 
+/*
+GetOADPOLMVersion returns the OLM version used to install OADP, "v1" if requested by annotation and "v0"
+otherwise.
+*/
+func GetOADPOLMVersion(instance *operatorsv1.MultiClusterHub) string {
+	if getAnnotation(instance, AnnotationOADPOLMVersion) == "v1" {
+		return "v1"
+	}
+	return "v0"
+}
+
 /*
 GetTemplateOverridesConfigmapName returns the template overrides ConfigMap annotation value,
 or an empty string if not set.
@@ -415,6 +613,33 @@ func GetTemplateOverridesConfigmapName(instance *operatorsv1.MultiClusterHub) st
 	return getAnnotation(instance, AnnotationTemplateOverridesCM)
 }
 
+/*
+GetOverridesRollback returns the overrides rollback annotation value, or an empty string if not set.
+*/
+func GetOverridesRollback(instance *operatorsv1.MultiClusterHub) string {
+	return getAnnotation(instance, AnnotationOverridesRollback)
+}
+
+/*
+GetRolloutRetry returns the rollout retry annotation value, or an empty string if not set.
+*/
+func GetRolloutRetry(instance *operatorsv1.MultiClusterHub) string {
+	return getAnnotation(instance, AnnotationRolloutRetry)
+}
+
+/*
+GetRemoveDisabledCRDs returns the components listed in the remove disabled CRDs annotation.
+*/
+func GetRemoveDisabledCRDs(instance *operatorsv1.MultiClusterHub) []string {
+	components := []string{}
+	for _, component := range strings.Split(getAnnotation(instance, AnnotationRemoveDisabledCRDs), ",") {
+		if component = strings.TrimSpace(component); component != "" {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
 /*
 HasAnnotation checks if a specific annotation key exists in the instance's annotations.
 */
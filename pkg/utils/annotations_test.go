@@ -96,6 +96,18 @@ func Test_AnnotationMatch(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "Overrides rollback requested",
+			new:  map[string]string{AnnotationOverridesRollback: "undo-2"},
+			old:  map[string]string{AnnotationOverridesRollback: "undo-1"},
+			want: false,
+		},
+		{
+			name: "Rollout retry requested",
+			new:  map[string]string{AnnotationRolloutRetry: "retry-1"},
+			old:  map[string]string{},
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -187,6 +199,21 @@ func Test_GetTemplateOverridesConfigmapName(t *testing.T) {
 	})
 }
 
+func Test_GetRemoveDisabledCRDs(t *testing.T) {
+	mch := &operatorsv1.MultiClusterHub{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			AnnotationRemoveDisabledCRDs: "submariner-addon, siteconfig,,",
+		}},
+	}
+	want := []string{"submariner-addon", "siteconfig"}
+	if got := GetRemoveDisabledCRDs(mch); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRemoveDisabledCRDs() = %v, want %v", got, want)
+	}
+	if got := GetRemoveDisabledCRDs(&operatorsv1.MultiClusterHub{}); len(got) != 0 {
+		t.Errorf("GetRemoveDisabledCRDs() without the annotation = %v, want none", got)
+	}
+}
+
 func TestOverrideImageRepository(t *testing.T) {
 	tests := []struct {
 		ImageOverrides map[string]string
@@ -261,6 +288,26 @@ func Test_GetOADPAnnotationOverrides(t *testing.T) {
 	})
 }
 
+func Test_GetOADPOLMVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{name: "defaults to v0", annotations: nil, want: "v0"},
+		{name: "v1 requested", annotations: map[string]string{AnnotationOADPOLMVersion: "v1"}, want: "v1"},
+		{name: "unknown value", annotations: map[string]string{AnnotationOADPOLMVersion: "v2"}, want: "v0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mch := &operatorsv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := GetOADPOLMVersion(mch); got != tt.want {
+				t.Errorf("GetOADPOLMVersion(mch) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_GetDefaultStorageClassOverride(t *testing.T) {
 	t.Run("Get Default storage class annotation override for MCH", func(t *testing.T) {
 		mch := &operatorsv1.MultiClusterHub{
@@ -357,6 +404,101 @@ func TestMigrateDeprecatedAnnotations(t *testing.T) {
 	}
 }
 
+func TestMigrateProbeAnnotations(t *testing.T) {
+	ptr := func(v int32) *int32 { return &v }
+
+	// migrated returns the probe settings of every deployment the annotations were templated into
+	migrated := func(settings operatorsv1.ProbeSettings) *operatorsv1.ProbesConfig {
+		probes := &operatorsv1.ProbesConfig{}
+		for _, c := range probeAnnotationDeployments {
+			component := operatorsv1.ComponentProbeConfig{Name: c.component}
+			for _, d := range c.deployments {
+				component.Deployments = append(component.Deployments, operatorsv1.DeploymentProbeConfig{
+					Name: d, ProbeSettings: *settings.DeepCopy(),
+				})
+			}
+			probes.Components = append(probes.Components, component)
+		}
+		return probes
+	}
+
+	kept := migrated(operatorsv1.ProbeSettings{
+		Liveness:  &operatorsv1.ProbeTuning{TimeoutSeconds: ptr(10)},
+		Readiness: &operatorsv1.ProbeTuning{TimeoutSeconds: ptr(10)},
+	})
+	kept.Global = &operatorsv1.ProbeSettings{Readiness: &operatorsv1.ProbeTuning{TimeoutSeconds: ptr(1)}}
+	kept.Components[0].Deployments[0].Readiness.TimeoutSeconds = ptr(3)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		probes      *operatorsv1.ProbesConfig
+		want        *operatorsv1.ProbesConfig
+	}{
+		{
+			name: "All probe annotations migrated",
+			annotations: map[string]string{
+				AnnotationProbeTimeoutSeconds:   "10",
+				AnnotationProbeFailureThreshold: "5",
+				AnnotationProbeSuccessThreshold: "2",
+			},
+			want: migrated(operatorsv1.ProbeSettings{
+				Liveness: &operatorsv1.ProbeTuning{TimeoutSeconds: ptr(10), FailureThreshold: ptr(5)},
+				Readiness: &operatorsv1.ProbeTuning{
+					TimeoutSeconds: ptr(10), FailureThreshold: ptr(5), SuccessThreshold: ptr(2),
+				},
+			}),
+		},
+		{
+			name: "Existing spec values are kept",
+			annotations: map[string]string{
+				AnnotationProbeTimeoutSeconds: "10",
+			},
+			probes: &operatorsv1.ProbesConfig{
+				Global: &operatorsv1.ProbeSettings{
+					Readiness: &operatorsv1.ProbeTuning{TimeoutSeconds: ptr(1)},
+				},
+				Components: []operatorsv1.ComponentProbeConfig{{
+					Name: operatorsv1.GRC,
+					Deployments: []operatorsv1.DeploymentProbeConfig{{
+						Name: "grc-policy-propagator",
+						ProbeSettings: operatorsv1.ProbeSettings{
+							Readiness: &operatorsv1.ProbeTuning{TimeoutSeconds: ptr(3)},
+						},
+					}},
+				}},
+			},
+			want: kept,
+		},
+		{
+			name: "Invalid values are dropped",
+			annotations: map[string]string{
+				AnnotationProbeTimeoutSeconds:   "not-a-number",
+				AnnotationProbeFailureThreshold: "-5",
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mch := &operatorsv1.MultiClusterHub{}
+			mch.SetAnnotations(tt.annotations)
+			mch.Spec.Probes = tt.probes
+
+			if !MigrateDeprecatedAnnotations(mch) {
+				t.Fatal("MigrateDeprecatedAnnotations() = false, want true")
+			}
+			if len(mch.GetAnnotations()) != 0 {
+				t.Errorf("annotations = %v, want probe annotations removed", mch.GetAnnotations())
+			}
+			if !reflect.DeepEqual(mch.Spec.Probes, tt.want) {
+				t.Errorf("spec.probes = %+v, want %+v", mch.Spec.Probes, tt.want)
+			}
+		})
+	}
+}
+
 func TestShouldIgnoreOCPVersion(t *testing.T) {
 	tests := []struct {
 		name     string
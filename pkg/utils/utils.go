@@ -34,6 +34,9 @@ const (
 	// UnitTestEnvVar is the environment variable for unit testing.
 	UnitTestEnvVar = "UNIT_TEST"
 
+	// RestrictedModeEnvVar is the environment variable that runs the operator in restricted mode.
+	RestrictedModeEnvVar = "RESTRICTED_MODE"
+
 	// MCHOperatorName is the name of the Multicluster Hub operator deployment.
 	MCHOperatorName = "multiclusterhub-operator"
 
@@ -110,6 +113,12 @@ const (
 	   the metrics for the multiclusterhub-operator.
 	*/
 	MCHOperatorMetricsServiceMonitorName = "multiclusterhub-operator-metrics"
+
+	/*
+	   MCHOperatorMetricsServingCertSecretName is the name of the secret the service CA issues the serving
+	   certificate of the metrics service into.
+	*/
+	MCHOperatorMetricsServingCertSecretName = "multiclusterhub-operator-metrics-tls"
 )
 
 // AddInstallerLabel adds Installer Labels ...
@@ -125,14 +134,18 @@ func AddInstallerLabel(u *unstructured.Unstructured, name string, ns string) {
 }
 
 // MchIsValid Checks if the optional default parameters need to be set
+// An unset availability is valid for a hub with a profile, the hub runs with the availability of the profile.
 func MchIsValid(m *operatorsv1.MultiClusterHub) bool {
+	if m.Spec.AvailabilityConfig == "" && m.Spec.Profile != nil {
+		return true
+	}
 	return operatorsv1.AvailabilityConfigIsValid(m.Spec.AvailabilityConfig)
 }
 
 // DefaultReplicaCount returns an integer corresponding to the default number of replicas
 // for HA or non-HA modes
 func DefaultReplicaCount(mch *operatorsv1.MultiClusterHub) int {
-	if mch.Spec.AvailabilityConfig == operatorsv1.HABasic {
+	if mch.EffectiveAvailabilityConfig() == operatorsv1.HABasic {
 		return 1
 	}
 	return 2
@@ -155,6 +168,11 @@ func IsUnitTest() bool {
 	return false
 }
 
+// IsRestrictedMode returns true when the operator leaves the cluster-scoped resources of the hub to an admin
+func IsRestrictedMode() bool {
+	return os.Getenv(RestrictedModeEnvVar) == "true"
+}
+
 func GetTestImages() []string {
 	return []string{
 		"LIFECYCLE_BACKEND_E2E", "BAILER", "CERT_POLICY_CONTROLLER", "CLUSTER_BACKUP_CONTROLLER",
@@ -187,9 +205,24 @@ func TrackedNamespaces(m *operatorsv1.MultiClusterHub) []string {
 	if m.Enabled(operatorsv1.ClusterBackup) {
 		trackedNamespaces = append(trackedNamespaces, ClusterSubscriptionNamespace)
 	}
+	trackedNamespaces = append(trackedNamespaces, ComponentNamespaces(m)...)
 	return trackedNamespaces
 }
 
+// ComponentNamespaces returns the custom namespaces the enabled components are installed into, in component order
+func ComponentNamespaces(m *operatorsv1.MultiClusterHub) []string {
+	namespaces := []string{}
+	for _, component := range operatorsv1.CustomNamespaceComponents {
+		if !m.Enabled(component) {
+			continue
+		}
+		if ns := m.ComponentNamespace(component); ns != m.Namespace && !Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
 // GetDisableClusterImageSets returns true or false for whether auto update for clusterImageSets should be disabled
 func GetDisableClusterImageSets(m *operatorsv1.MultiClusterHub) string {
 	if m.Spec.DisableUpdateClusterImageSets {
@@ -236,64 +269,108 @@ func GetDeployments(m *operatorsv1.MultiClusterHub) []types.NamespacedName {
 	return nn
 }
 
+// statusComponents lists the components whose deployments are reported in the hub status, in reporting order
+var statusComponents = []string{
+	operatorsv1.Insights,
+	operatorsv1.SiteConfig,
+	operatorsv1.Search,
+	operatorsv1.Appsub,
+	operatorsv1.ClusterLifecycle,
+	operatorsv1.ClusterBackup,
+	operatorsv1.GRC,
+	operatorsv1.Console,
+	operatorsv1.Volsync,
+	operatorsv1.SubmarinerAddon,
+	operatorsv1.MultiClusterObservability,
+	operatorsv1.FineGrainedRbac,
+	operatorsv1.MTVIntegrations,
+}
+
+// StatusComponents returns the enabled components whose workloads are reported in the hub status, in reporting order
+func StatusComponents(m *operatorsv1.MultiClusterHub) []string {
+	components := []string{}
+	for _, component := range statusComponents {
+		if m.Enabled(component) {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
 func GetDeploymentsForStatus(m *operatorsv1.MultiClusterHub, ocpConsole, isSTSEnabled bool) []types.NamespacedName {
 	nn := []types.NamespacedName{}
-	if m.Enabled(operatorsv1.Insights) {
-		nn = append(nn, types.NamespacedName{Name: "insights-client", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "insights-metrics", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.SiteConfig) {
-		nn = append(nn, types.NamespacedName{Name: "siteconfig-controller-manager", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.Search) {
-		nn = append(nn, types.NamespacedName{Name: "search-v2-operator-controller-manager", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "search-api", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "search-collector", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "search-indexer", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "search-postgres", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.Appsub) {
-		nn = append(nn, types.NamespacedName{Name: "multicluster-operators-application", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "multicluster-operators-channel", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "multicluster-operators-hub-subscription", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "multicluster-operators-standalone-subscription", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "multicluster-operators-subscription-report", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.ClusterLifecycle) {
-		nn = append(nn, types.NamespacedName{Name: "klusterlet-addon-controller-v2", Namespace: m.Namespace})
+	for _, component := range StatusComponents(m) {
+		nn = append(nn, GetComponentDeploymentsForStatus(m, component, ocpConsole, isSTSEnabled)...)
 	}
-	if m.Enabled(operatorsv1.ClusterBackup) {
-		nn = append(nn, types.NamespacedName{Name: "cluster-backup-chart-clusterbackup", Namespace: ClusterSubscriptionNamespace})
+	return nn
+}
 
+/*
+GetComponentDeploymentsForStatus returns the deployments whose status is reported for the given component until its
+chart is rendered for the first time. Once rendered, the status tracks the workloads of the rendered templates, which
+are recorded in the component reconcile status.
+*/
+func GetComponentDeploymentsForStatus(m *operatorsv1.MultiClusterHub, component string, ocpConsole,
+	isSTSEnabled bool) []types.NamespacedName {
+	namespace := m.ComponentNamespace(component)
+	switch component {
+	case operatorsv1.Insights:
+		return []types.NamespacedName{
+			{Name: "insights-client", Namespace: namespace},
+			{Name: "insights-metrics", Namespace: namespace},
+		}
+	case operatorsv1.SiteConfig:
+		return []types.NamespacedName{{Name: "siteconfig-controller-manager", Namespace: namespace}}
+	case operatorsv1.Search:
+		return []types.NamespacedName{
+			{Name: "search-v2-operator-controller-manager", Namespace: namespace},
+			{Name: "search-api", Namespace: namespace},
+			{Name: "search-collector", Namespace: namespace},
+			{Name: "search-indexer", Namespace: namespace},
+			{Name: "search-postgres", Namespace: namespace},
+		}
+	case operatorsv1.Appsub:
+		return []types.NamespacedName{
+			{Name: "multicluster-operators-application", Namespace: namespace},
+			{Name: "multicluster-operators-channel", Namespace: namespace},
+			{Name: "multicluster-operators-hub-subscription", Namespace: namespace},
+			{Name: "multicluster-operators-standalone-subscription", Namespace: namespace},
+			{Name: "multicluster-operators-subscription-report", Namespace: namespace},
+		}
+	case operatorsv1.ClusterLifecycle:
+		return []types.NamespacedName{{Name: "klusterlet-addon-controller-v2", Namespace: namespace}}
+	case operatorsv1.ClusterBackup:
+		nn := []types.NamespacedName{{Name: "cluster-backup-chart-clusterbackup", Namespace: ClusterSubscriptionNamespace}}
 		if !isSTSEnabled {
-			nn = append(nn, types.NamespacedName{Name: "openshift-adp-controller-manager", Namespace: ClusterSubscriptionNamespace})
+			nn = append(nn, types.NamespacedName{Name: "openshift-adp-controller-manager",
+				Namespace: ClusterSubscriptionNamespace})
 		}
-	}
-	if m.Enabled(operatorsv1.GRC) {
-		nn = append(nn, types.NamespacedName{Name: "grc-policy-addon-controller", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "grc-policy-propagator", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.Console) && ocpConsole {
-		nn = append(nn, types.NamespacedName{Name: "console-chart-console-v2", Namespace: m.Namespace})
-		nn = append(nn, types.NamespacedName{Name: "acm-cli-downloads", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.Volsync) {
-		nn = append(nn, types.NamespacedName{Name: "volsync-addon-controller", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.SubmarinerAddon) {
-		nn = append(nn, types.NamespacedName{Name: "submariner-addon", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.MultiClusterObservability) {
-		nn = append(nn, types.NamespacedName{Name: "multicluster-observability-operator", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.FineGrainedRbac) {
-		nn = append(nn, types.NamespacedName{Name: "multicluster-role-assignment-controller", Namespace: m.Namespace})
-	}
-	if m.Enabled(operatorsv1.MTVIntegrations) {
-		nn = append(nn, types.NamespacedName{Name: "mtv-integrations-controller", Namespace: m.Namespace})
-	}
-
-	return nn
+		return nn
+	case operatorsv1.GRC:
+		return []types.NamespacedName{
+			{Name: "grc-policy-addon-controller", Namespace: namespace},
+			{Name: "grc-policy-propagator", Namespace: namespace},
+		}
+	case operatorsv1.Console:
+		if !ocpConsole {
+			return nil
+		}
+		return []types.NamespacedName{
+			{Name: "console-chart-console-v2", Namespace: namespace},
+			{Name: "acm-cli-downloads", Namespace: namespace},
+		}
+	case operatorsv1.Volsync:
+		return []types.NamespacedName{{Name: "volsync-addon-controller", Namespace: namespace}}
+	case operatorsv1.SubmarinerAddon:
+		return []types.NamespacedName{{Name: "submariner-addon", Namespace: namespace}}
+	case operatorsv1.MultiClusterObservability:
+		return []types.NamespacedName{{Name: "multicluster-observability-operator", Namespace: namespace}}
+	case operatorsv1.FineGrainedRbac:
+		return []types.NamespacedName{{Name: "multicluster-role-assignment-controller", Namespace: namespace}}
+	case operatorsv1.MTVIntegrations:
+		return []types.NamespacedName{{Name: "mtv-integrations-controller", Namespace: namespace}}
+	}
+	return nil
 }
 
 func GetCustomResourcesForStatus(m *operatorsv1.MultiClusterHub, olmVersion string) []types.NamespacedName {
@@ -386,6 +463,9 @@ func SetDefaultComponents(m *operatorsv1.MultiClusterHub) (bool, error) {
 // DeduplicateComponents removes duplicate componentconfigs by name, keeping the config of the last
 // componentconfig in the list. Returns true if changes are made.
 func DeduplicateComponents(m *operatorsv1.MultiClusterHub) bool {
+	if m.Spec.Overrides == nil {
+		return false
+	}
 	config := m.Spec.Overrides.Components
 	newConfig := deduplicate(m.Spec.Overrides.Components)
 	if len(newConfig) != len(config) {
@@ -468,25 +548,32 @@ func GetAPIServerTLSProfile(ctx context.Context, cl client.Client) (*configv1.TL
 		return nil, fmt.Errorf("failed to get APIServer resource: %w", err)
 	}
 
+	_, spec := TLSProfileFromAPIServer(apiServer)
+	return spec, nil
+}
+
+// TLSProfileFromAPIServer returns the type and the spec of the TLS security profile set on the APIServer resource.
+// If no profile is set, or a custom profile has no spec, the Intermediate profile is returned.
+func TLSProfileFromAPIServer(apiServer *configv1.APIServer) (configv1.TLSProfileType, *configv1.TLSProfileSpec) {
 	// If no TLS profile is set, use the default (Intermediate)
 	if apiServer.Spec.TLSSecurityProfile == nil {
-		return configv1.TLSProfiles[configv1.TLSProfileIntermediateType], nil
+		return configv1.TLSProfileIntermediateType, configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
 	}
 
 	profile := apiServer.Spec.TLSSecurityProfile
 
 	// For predefined profiles (Old, Intermediate, Modern), use the map
 	if profileSpec, ok := configv1.TLSProfiles[profile.Type]; ok {
-		return profileSpec, nil
+		return profile.Type, profileSpec
 	}
 
 	// For custom profile, return the inline spec
 	if profile.Type == configv1.TLSProfileCustomType && profile.Custom != nil {
-		return &profile.Custom.TLSProfileSpec, nil
+		return profile.Type, &profile.Custom.TLSProfileSpec
 	}
 
 	// Fallback to Intermediate if something unexpected
-	return configv1.TLSProfiles[configv1.TLSProfileIntermediateType], nil
+	return configv1.TLSProfileIntermediateType, configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
 }
 
 // ConvertTLSVersion converts OpenShift TLSProtocolVersion string to crypto/tls uint16 constant.
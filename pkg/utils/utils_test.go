@@ -250,6 +250,15 @@ func TestMchIsValid(t *testing.T) {
 			args{&mchv1.MultiClusterHub{}},
 			false,
 		},
+		{
+			"Valid MCH with empty AvailabilityConfig and a profile",
+			args{&mchv1.MultiClusterHub{
+				Spec: mchv1.MultiClusterHubSpec{
+					Profile: &mchv1.HubProfile{Name: mchv1.ProfileEdgeRAN},
+				},
+			}},
+			true,
+		},
 		{
 			"Invalid MCH with invalid AvailabilityConfig",
 			args{&mchv1.MultiClusterHub{
@@ -332,6 +341,24 @@ func TestTrackedNamespaces(t *testing.T) {
 			mch:  &mchv1.MultiClusterHub{ObjectMeta: metav1.ObjectMeta{Namespace: "test"}},
 			want: []string{"test"},
 		},
+		{
+			name: "Watching the custom namespaces of enabled components",
+			mch: &mchv1.MultiClusterHub{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test"},
+				Spec: mchv1.MultiClusterHubSpec{
+					Overrides: &mchv1.Overrides{
+						Components: []mchv1.ComponentConfig{
+							{Name: mchv1.ClusterBackup, Enabled: true, Namespace: "backup"},
+							{Name: mchv1.Search, Enabled: true, Namespace: "tenant"},
+							{Name: mchv1.Insights, Enabled: true, Namespace: "tenant"},
+							{Name: mchv1.GRC, Enabled: false, Namespace: "grc"},
+							{Name: mchv1.Console, Enabled: true, Namespace: "test"},
+						},
+					},
+				},
+			},
+			want: []string{"test", ClusterSubscriptionNamespace, "tenant"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -505,6 +532,21 @@ func Test_GetDeploymentsForStatus(t *testing.T) {
 	}
 }
 
+func Test_GetComponentDeploymentsForStatus(t *testing.T) {
+	mch := resources.EmptyMCH()
+
+	got := GetComponentDeploymentsForStatus(&mch, mchv1.ClusterBackup, true, false)
+	if len(got) != 2 || !containsDeployment(got, "openshift-adp-controller-manager", ClusterSubscriptionNamespace) {
+		t.Errorf("GetComponentDeploymentsForStatus(cluster-backup) = %v, want the backup and OADP deployments", got)
+	}
+	if got := GetComponentDeploymentsForStatus(&mch, mchv1.Console, false, false); len(got) != 0 {
+		t.Errorf("GetComponentDeploymentsForStatus(console) = %v, want none without the OCP console", got)
+	}
+	if got := GetComponentDeploymentsForStatus(&mch, "unknown", true, false); len(got) != 0 {
+		t.Errorf("GetComponentDeploymentsForStatus(unknown) = %v, want none", got)
+	}
+}
+
 // containsDeployment checks if a deployment with the given name and namespace exists in the list
 func containsDeployment(deployments []types.NamespacedName, name, namespace string) bool {
 	for _, d := range deployments {